@@ -23,6 +23,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/kelseyhightower/envconfig"
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/types"
 
 	kubeclient "knative.dev/pkg/client/injection/kube/client"
 	configmap "knative.dev/pkg/configmap/informer"
@@ -37,7 +38,9 @@ import (
 	tracingconfig "knative.dev/pkg/tracing/config"
 
 	broker "knative.dev/eventing/cmd/broker"
+	"knative.dev/eventing/pkg/auth"
 	"knative.dev/eventing/pkg/broker/filter"
+	"knative.dev/eventing/pkg/kncloudevents"
 	"knative.dev/eventing/pkg/reconciler/names"
 
 	eventingclientset "knative.dev/eventing/pkg/client/clientset/versioned"
@@ -55,6 +58,19 @@ type envConfig struct {
 	PodName       string `envconfig:"POD_NAME" required:"true"`
 	ContainerName string `envconfig:"CONTAINER_NAME" required:"true"`
 	Port          int    `envconfig:"FILTER_PORT" default:"8080"`
+
+	// EnableHTTP2 makes the filter deliver events to subscribers using HTTP/2 (h2c for plain http).
+	EnableHTTP2 bool `envconfig:"ENABLE_HTTP2" default:"false"`
+	// HTTPDestinations is a JSON object with per subscriber host transport settings.
+	HTTPDestinations string `envconfig:"HTTP_DESTINATIONS"`
+
+	// TLSPort is the port serving HTTPS, when a TLS certificate is configured.
+	TLSPort int `envconfig:"FILTER_TLS_PORT" default:"8443"`
+	kncloudevents.TLSArgs
+
+	// ServiceAccountName is the service account the filter runs as, for which tokens are minted
+	// when a Trigger's subscriber requires an audience.
+	ServiceAccountName string `envconfig:"SERVICE_ACCOUNT_NAME"`
 }
 
 func main() {
@@ -69,6 +85,9 @@ func main() {
 	if err := envconfig.Process("", &env); err != nil {
 		log.Fatal("Failed to process env var", zap.Error(err))
 	}
+	if err := env.TLSArgs.Validate(); err != nil {
+		log.Fatal("Invalid TLS configuration: ", err)
+	}
 
 	ctx, _ = injection.Default.SetupInformers(ctx, cfg)
 	kubeClient := kubeclient.Get(ctx)
@@ -112,7 +131,16 @@ func main() {
 
 	// We are running both the receiver (takes messages in from the Broker) and the dispatcher (send
 	// the messages to the triggers' subscribers) in this binary.
-	handler, err := filter.NewHandler(logger, triggerInformer.Lister(), reporter, env.Port)
+	destinations, err := kncloudevents.ParseDestinationArgs(env.HTTPDestinations)
+	if err != nil {
+		logger.Fatal("Invalid HTTP_DESTINATIONS", zap.Error(err))
+	}
+	handler, err := filter.NewHandler(logger, triggerInformer.Lister(), reporter, env.Port,
+		filter.WithHTTP2(env.EnableHTTP2), filter.WithDestinations(destinations), filter.WithTLS(env.TLSPort, env.TLSArgs),
+		filter.WithCredentials(auth.NewCredentialsResolver(kubeClient, types.NamespacedName{
+			Namespace: system.Namespace(),
+			Name:      env.ServiceAccountName,
+		})))
 	if err != nil {
 		logger.Fatal("Error creating Handler", zap.Error(err))
 	}
@@ -17,6 +17,7 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 
@@ -26,6 +27,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/kelseyhightower/envconfig"
 	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	kubeclient "knative.dev/pkg/client/injection/kube/client"
 	configmap "knative.dev/pkg/configmap/informer"
@@ -40,6 +43,7 @@ import (
 	tracingconfig "knative.dev/pkg/tracing/config"
 
 	cmdbroker "knative.dev/eventing/cmd/broker"
+	"knative.dev/eventing/pkg/auth"
 	broker "knative.dev/eventing/pkg/broker"
 	"knative.dev/eventing/pkg/broker/ingress"
 	brokerinformer "knative.dev/eventing/pkg/client/injection/informers/eventing/v1/broker"
@@ -66,6 +70,28 @@ type envConfig struct {
 	ContainerName string `envconfig:"CONTAINER_NAME" required:"true"`
 	Port          int    `envconfig:"INGRESS_PORT" default:"8080"`
 	MaxTTL        int    `envconfig:"MAX_TTL" default:"255"`
+
+	// EnableHTTP2 makes the ingress send events to the channel using HTTP/2 (h2c for plain http).
+	EnableHTTP2 bool `envconfig:"ENABLE_HTTP2" default:"false"`
+	// HTTPDestinations is a JSON object with per destination host transport settings.
+	HTTPDestinations string `envconfig:"HTTP_DESTINATIONS"`
+
+	// AuthTokenReview makes the ingress authenticate senders using Kubernetes service account
+	// tokens, validated through the TokenReview API.
+	AuthTokenReview bool `envconfig:"AUTH_TOKEN_REVIEW" default:"false"`
+	// AuthClusterIssuer is the issuer of the Kubernetes service account tokens. Only its tokens
+	// may authenticate Kubernetes users, e.g. system:serviceaccount:<namespace>:<name>.
+	AuthClusterIssuer string `envconfig:"AUTH_CLUSTER_ISSUER" default:"https://kubernetes.default.svc.cluster.local"`
+	// AuthJWTIssuer and AuthJWKSFile make the ingress authenticate senders using JWTs issued by
+	// AuthJWTIssuer and signed by one of the keys in AuthJWKSFile.
+	AuthJWTIssuer string `envconfig:"AUTH_JWT_ISSUER"`
+	AuthJWKSFile  string `envconfig:"AUTH_JWKS_FILE"`
+	// AuthAudiences are the token audiences accepted, comma separated.
+	AuthAudiences []string `envconfig:"AUTH_AUDIENCES"`
+
+	// TLSPort is the port serving HTTPS, when a TLS certificate is configured.
+	TLSPort int `envconfig:"INGRESS_TLS_PORT" default:"8443"`
+	kncloudevents.TLSArgs
 }
 
 func main() {
@@ -81,6 +107,10 @@ func main() {
 		log.Fatal("Failed to process env var", zap.Error(err))
 	}
 
+	if err := env.TLSArgs.Validate(); err != nil {
+		log.Fatal("Invalid TLS configuration: ", err)
+	}
+
 	if env.MaxTTL <= 0 {
 		log.Fatalf("Invalid MaxTTL value, must be >=0, was: %d", env.MaxTTL)
 	}
@@ -123,9 +153,16 @@ func main() {
 		logger.Fatal("Error setting up trace publishing", zap.Error(err))
 	}
 
+	destinations, err := kncloudevents.ParseDestinationArgs(env.HTTPDestinations)
+	if err != nil {
+		logger.Fatal("Invalid HTTP_DESTINATIONS", zap.Error(err))
+	}
 	connectionArgs := kncloudevents.ConnectionArgs{
 		MaxIdleConns:        defaultMaxIdleConnections,
 		MaxIdleConnsPerHost: defaultMaxIdleConnectionsPerHost,
+		EnableHTTP2:         env.EnableHTTP2,
+		Destinations:        destinations,
+		TLS:                 env.TLSArgs,
 	}
 	kncloudevents.ConfigureConnectionArgs(&connectionArgs)
 	sender, err := kncloudevents.NewHTTPMessageSenderWithTarget("")
@@ -133,15 +170,29 @@ func main() {
 		logger.Fatal("Unable to create message sender", zap.Error(err))
 	}
 
+	authenticator, err := newAuthenticator(ctx, env)
+	if err != nil {
+		logger.Fatal("Unable to create authenticator", zap.Error(err))
+	}
+
+	// Watch the ingress limits config map and dynamically update the rate limits.
+	rateLimiter := ingress.NewRateLimiter(ingress.LimitsConfig{})
+	configMapWatcher.WatchWithDefault(corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: ingress.LimitsConfigName},
+	}, rateLimiter.UpdateFromConfigMap(logger))
+
 	reporter := ingress.NewStatsReporter(env.ContainerName, kmeta.ChildName(env.PodName, uuid.New().String()))
 
 	h := &ingress.Handler{
-		Receiver:     kncloudevents.NewHTTPMessageReceiver(env.Port),
-		Sender:       sender,
-		Defaulter:    broker.TTLDefaulter(logger, int32(env.MaxTTL)),
-		Reporter:     reporter,
-		Logger:       logger,
-		BrokerLister: brokerLister,
+		Receiver:      kncloudevents.NewHTTPMessageReceiver(env.Port, kncloudevents.WithTLS(env.TLSPort, env.TLSArgs)),
+		Sender:        sender,
+		Defaulter:     broker.TTLDefaulter(logger, int32(env.MaxTTL)),
+		Reporter:      reporter,
+		Logger:        logger,
+		BrokerLister:  brokerLister,
+		Authenticator: authenticator,
+		ClusterIssuer: env.AuthClusterIssuer,
+		RateLimiter:   rateLimiter,
 	}
 
 	// configMapWatcher does not block, so start it first.
@@ -166,3 +217,22 @@ func flush(logger *zap.SugaredLogger) {
 	_ = logger.Sync()
 	metrics.FlushExporter()
 }
+
+// newAuthenticator returns the authenticator configured in env, or nil when authentication is disabled.
+func newAuthenticator(ctx context.Context, env envConfig) (auth.Authenticator, error) {
+	var chain auth.Chain
+	if env.AuthTokenReview {
+		chain = append(chain, auth.NewTokenReviewAuthenticator(kubeclient.Get(ctx), env.AuthClusterIssuer, env.AuthAudiences, auth.DefaultTokenReviewCacheTTL))
+	}
+	if env.AuthJWTIssuer != "" {
+		a, err := auth.NewJWTAuthenticator(env.AuthJWTIssuer, env.AuthAudiences, env.AuthJWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, a)
+	}
+	if len(chain) == 0 {
+		return nil, nil
+	}
+	return chain, nil
+}
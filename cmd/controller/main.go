@@ -20,11 +20,14 @@ import (
 	// Uncomment the following line to load the gcp plugin (only required to authenticate against GKE clusters).
 	// _ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 
+	filteredFactory "knative.dev/pkg/client/injection/kube/informers/factory/filtered"
 	"knative.dev/pkg/injection/sharedmain"
+	"knative.dev/pkg/signals"
 
 	"knative.dev/eventing/pkg/reconciler/apiserversource"
 	"knative.dev/eventing/pkg/reconciler/channel"
 	"knative.dev/eventing/pkg/reconciler/containersource"
+	containersourceresources "knative.dev/eventing/pkg/reconciler/containersource/resources"
 	"knative.dev/eventing/pkg/reconciler/eventtype"
 	"knative.dev/eventing/pkg/reconciler/parallel"
 	"knative.dev/eventing/pkg/reconciler/pingsource"
@@ -34,7 +37,10 @@ import (
 )
 
 func main() {
-	sharedmain.Main("controller",
+	// The ContainerSource controller watches the pods of the ContainerSources only.
+	ctx := filteredFactory.WithSelectors(signals.NewContext(), containersourceresources.Selector)
+
+	sharedmain.MainWithContext(ctx, "controller",
 		// Messaging
 		channel.NewController,
 		subscription.NewController,
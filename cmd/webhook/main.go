@@ -24,6 +24,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"knative.dev/eventing/pkg/apis/feature"
 
+	filteredFactory "knative.dev/pkg/client/injection/kube/informers/factory/filtered"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/injection"
@@ -183,7 +184,8 @@ func NewSinkBindingWebhook(opts ...psbinding.ReconcilerOption) injection.Control
 	return func(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
 		sbresolver := sinkbinding.WithContextFactory(ctx, func(types.NamespacedName) {})
 
-		return psbinding.NewAdmissionController(ctx,
+		// CronJobs are not PodSpecable, their job template is bound instead.
+		return sinkbinding.WithCronJobs(psbinding.NewAdmissionController(ctx,
 
 			// Name of the resource webhook.
 			"sinkbindings.webhook.sources.knative.dev",
@@ -197,7 +199,7 @@ func NewSinkBindingWebhook(opts ...psbinding.ReconcilerOption) injection.Control
 			// How to setup the context prior to invoking Do/Undo.
 			sbresolver,
 			opts...,
-		)
+		))
 	}
 }
 
@@ -249,8 +251,10 @@ func main() {
 	if os.Getenv("SINK_BINDING_SELECTION_MODE") == "inclusion" {
 		sbSelector = psbinding.WithSelector(psbinding.InclusionSelector)
 	}
-	// Set up a signal context with our webhook options
-	ctx := webhook.WithOptions(signals.NewContext(), webhook.Options{
+	// Set up a signal context with our webhook options and the selector of
+	// the sink ConfigMaps the SinkBinding controller watches.
+	ctx := filteredFactory.WithSelectors(signals.NewContext(), sinkbinding.ConfigMapSelector)
+	ctx = webhook.WithOptions(ctx, webhook.Options{
 		ServiceName: webhook.NameFromEnv(),
 		Port:        webhook.PortFromEnv(8443),
 		// SecretName must match the name of the Secret created in the configuration.
@@ -26,6 +26,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
@@ -51,6 +52,9 @@ type apiServerAdapter struct {
 	k8s      dynamic.Interface
 	source   string // TODO: who dis?
 	name     string // TODO: who dis?
+
+	// checkpoints are the resource versions of the watches, when they are resumed.
+	checkpoints *checkpoints
 }
 
 func (a *apiServerAdapter) Start(ctx context.Context) error {
@@ -63,13 +67,26 @@ func (a *apiServerAdapter) start(ctx context.Context, stopCh <-chan struct{}) er
 
 	resyncPeriod := 10 * time.Hour
 
-	var delegate cache.Store = &resourceDelegate{
+	rd := &resourceDelegate{
 		ce:                  a.ce,
 		source:              a.source,
 		logger:              a.logger,
 		ref:                 a.config.EventMode == v1.ReferenceMode,
 		apiServerSourceName: a.name,
+		kubernetesEvents:    a.config.KubernetesEvents,
+	}
+	if a.config.EventMode == v1.DiffMode {
+		rd.diff = newDiffState(a.config.IgnoredFields)
 	}
+	if len(a.config.Filters) > 0 {
+		filter, err := newObjectFilter(a.config.Filters, a.logger)
+		if err != nil {
+			return err
+		}
+		rd.filter = filter
+	}
+
+	var delegate cache.Store = rd
 	if a.config.ResourceOwner != nil {
 		a.logger.Infow("will be filtered",
 			zap.String("APIVersion", a.config.ResourceOwner.APIVersion),
@@ -83,6 +100,19 @@ func (a *apiServerAdapter) start(ctx context.Context, stopCh <-chan struct{}) er
 
 	a.logger.Infof("STARTING -- %#v", a.config)
 
+	if a.checkpoints != nil {
+		if err := a.checkpoints.load(ctx); err != nil {
+			return err
+		}
+		go a.checkpoints.run(ctx, checkpointPeriod, stop)
+	}
+	resume := func(gvr schema.GroupVersionResource, namespace string) *resumableWatch {
+		return a.resumableWatch(rd, gvr, namespace)
+	}
+
+	// The namespaced resources watched in the namespaces matching the namespace selector.
+	var namespaced []ResourceWatch
+
 	for _, configRes := range a.config.Resources {
 
 		resources, err := a.discover.ServerResourcesForGroupVersion(configRes.GVR.GroupVersion().String())
@@ -96,19 +126,19 @@ func (a *apiServerAdapter) start(ctx context.Context, stopCh <-chan struct{}) er
 			if apires.Name == configRes.GVR.Resource {
 
 				var res dynamic.ResourceInterface
-				if apires.Namespaced {
-					res = a.k8s.Resource(configRes.GVR).Namespace(a.config.Namespace)
+				namespace := ""
+				if apires.Namespaced && a.config.NamespaceSelector != nil {
+					namespaced = append(namespaced, configRes)
+				} else if apires.Namespaced {
+					namespace = a.config.Namespace
+					res = a.k8s.Resource(configRes.GVR).Namespace(namespace)
 				} else {
 					res = a.k8s.Resource(configRes.GVR)
 				}
 
-				lw := &cache.ListWatch{
-					ListFunc:  asUnstructuredLister(ctx, res.List, configRes.LabelSelector),
-					WatchFunc: asUnstructuredWatcher(ctx, res.Watch, configRes.LabelSelector),
+				if res != nil {
+					go runReflector(ctx, res, configRes, delegate, resyncPeriod, stop, resume(configRes.GVR, namespace))
 				}
-
-				reflector := cache.NewReflector(lw, &unstructured.Unstructured{}, delegate, resyncPeriod)
-				go reflector.Run(stop)
 				exists = true
 				break
 			}
@@ -125,21 +155,77 @@ func (a *apiServerAdapter) start(ctx context.Context, stopCh <-chan struct{}) er
 			w.WriteHeader(http.StatusOK)
 		}),
 	}
+	if len(namespaced) > 0 {
+		nw := &namespaceWatcher{
+			k8s:       a.k8s,
+			logger:    a.logger,
+			resources: namespaced,
+			delegate:  delegate,
+			resync:    resyncPeriod,
+			resume:    resume,
+			stops:     make(map[string]chan struct{}),
+		}
+		go nw.run(ctx, *a.config.NamespaceSelector, stop)
+	}
+
 	go srv.ListenAndServe()
 
 	<-stopCh
-	stop <- struct{}{}
+	close(stop)
+	if a.checkpoints != nil {
+		// ctx is done by now.
+		flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := a.checkpoints.flush(flushCtx); err != nil {
+			a.logger.Warnw("Failed to persist the resource versions", zap.Error(err))
+		}
+		cancel()
+	}
 	srv.Shutdown(ctx)
 	return nil
 }
 
+// resumableWatch returns the resumableWatch of the resources gvr in namespace,
+// or nil when the watches are not resumed.
+func (a *apiServerAdapter) resumableWatch(rd *resourceDelegate, gvr schema.GroupVersionResource, namespace string) *resumableWatch {
+	if a.checkpoints == nil {
+		return nil
+	}
+	return &resumableWatch{
+		key:         checkpointKey(gvr, namespace),
+		checkpoints: a.checkpoints,
+		relisted: func(resourceVersion string) {
+			rd.sync(gvr, namespace, resourceVersion)
+		},
+		reseed: rd.diff != nil || rd.filter != nil,
+	}
+}
+
+// runReflector syncs delegate with the resources res selected by rw, until stop is closed.
+// The watches are resumed by resume, unless it is nil.
+func runReflector(ctx context.Context, res dynamic.ResourceInterface, rw ResourceWatch, delegate cache.Store, resync time.Duration, stop <-chan struct{}, resume *resumableWatch) {
+	var lw cache.ListerWatcher = &cache.ListWatch{
+		ListFunc:  asUnstructuredLister(ctx, res.List, rw.LabelSelector, rw.FieldSelector),
+		WatchFunc: asUnstructuredWatcher(ctx, res.Watch, rw.LabelSelector, rw.FieldSelector),
+	}
+	if resume != nil {
+		lw = resume.listWatch(lw)
+		delegate = resume.store(delegate)
+	}
+
+	reflector := cache.NewReflector(lw, &unstructured.Unstructured{}, delegate, resync)
+	reflector.Run(stop)
+}
+
 type unstructuredLister func(context.Context, metav1.ListOptions) (*unstructured.UnstructuredList, error)
 
-func asUnstructuredLister(ctx context.Context, ulist unstructuredLister, selector string) cache.ListFunc {
+func asUnstructuredLister(ctx context.Context, ulist unstructuredLister, selector, fieldSelector string) cache.ListFunc {
 	return func(opts metav1.ListOptions) (runtime.Object, error) {
 		if selector != "" && opts.LabelSelector == "" {
 			opts.LabelSelector = selector
 		}
+		if fieldSelector != "" && opts.FieldSelector == "" {
+			opts.FieldSelector = fieldSelector
+		}
 		ul, err := ulist(ctx, opts)
 		if err != nil {
 			return nil, err
@@ -150,11 +236,14 @@ func asUnstructuredLister(ctx context.Context, ulist unstructuredLister, selecto
 
 type structuredWatcher func(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
 
-func asUnstructuredWatcher(ctx context.Context, wf structuredWatcher, selector string) cache.WatchFunc {
+func asUnstructuredWatcher(ctx context.Context, wf structuredWatcher, selector, fieldSelector string) cache.WatchFunc {
 	return func(lo metav1.ListOptions) (watch.Interface, error) {
 		if selector != "" && lo.LabelSelector == "" {
 			lo.LabelSelector = selector
 		}
+		if fieldSelector != "" && lo.FieldSelector == "" {
+			lo.FieldSelector = fieldSelector
+		}
 		return wf(ctx, lo)
 	}
 }
@@ -66,7 +66,7 @@ func NewAdapter(ctx context.Context, processed adapter.EnvConfigAccessor, ceClie
 		panic("failed to create config from json")
 	}
 
-	return &apiServerAdapter{
+	a := &apiServerAdapter{
 		discover: kubeclient.Get(ctx).Discovery(),
 		k8s:      dynamicclient.Get(ctx),
 		ce:       ceClient,
@@ -76,4 +76,8 @@ func NewAdapter(ctx context.Context, processed adapter.EnvConfigAccessor, ceClie
 
 		logger: logger,
 	}
+	if config.CheckpointConfigMap != "" {
+		a.checkpoints = newCheckpoints(kubeclient.Get(ctx).CoreV1().ConfigMaps(config.Namespace), config.CheckpointConfigMap, logger)
+	}
+	return a
 }
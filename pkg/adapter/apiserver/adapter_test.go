@@ -18,9 +18,11 @@ package apiserver
 
 import (
 	"context"
+	"sort"
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
@@ -28,11 +30,13 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
 	discoveryfake "k8s.io/client-go/discovery/fake"
 	"k8s.io/client-go/dynamic"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
 	kubetesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
 	adaptertest "knative.dev/eventing/pkg/adapter/v2/test"
 	rectesting "knative.dev/eventing/pkg/reconciler/testing"
 	"knative.dev/pkg/logging"
@@ -183,6 +187,158 @@ func TestAdapter_StartNonNamespacedResource(t *testing.T) {
 	}
 }
 
+func TestAdapter_StartNamespaceSelector(t *testing.T) {
+	testCases := map[string]struct {
+		selector string
+		want     []string
+	}{
+		"labels": {
+			selector: "team=payments",
+			want:     []string{"/apis/v1/namespaces/payments/pods/foo"},
+		},
+		"all namespaces": {
+			selector: "",
+			want:     []string{"/apis/v1/namespaces/default/pods/bar", "/apis/v1/namespaces/payments/pods/foo"},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			ce := adaptertest.NewTestClient()
+
+			selector := tc.selector
+			config := Config{
+				Namespace:         "default",
+				NamespaceSelector: &selector,
+				Resources: []ResourceWatch{{
+					GVR: schema.GroupVersionResource{
+						Version:  "v1",
+						Resource: "pods",
+					},
+				}},
+				EventMode: "Resource",
+			}
+			ctx, _ := pkgtesting.SetupFakeContext(t)
+
+			payments := simpleNamespace("payments")
+			payments.SetLabels(map[string]string{"team": "payments"})
+
+			k8s := makeDynamicClient(payments, simpleNamespace("default"))
+
+			a := &apiServerAdapter{
+				ce:     ce,
+				logger: logging.FromContext(ctx),
+				config: config,
+
+				discover: makeDiscoveryClient(),
+				k8s:      k8s,
+				source:   "unit-test",
+				name:     "unittest",
+			}
+
+			err := errors.New("test never ran")
+			ctx, cancel := context.WithCancel(ctx)
+			done := make(chan struct{})
+			go func() {
+				err = a.Start(ctx)
+				close(done)
+			}()
+
+			// Wait for the reflectors to be fully initialized.
+			time.Sleep(1 * time.Second)
+
+			pods := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+			for _, pod := range []*unstructured.Unstructured{simplePod("foo", "payments"), simplePod("bar", "default")} {
+				if _, err := k8s.Resource(pods).Namespace(pod.GetNamespace()).Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+					t.Fatal("Failed to create the pod:", err)
+				}
+			}
+			time.Sleep(500 * time.Millisecond)
+
+			cancel()
+			<-done
+
+			if err != nil {
+				t.Error("Did not expect an error, but got:", err)
+			}
+			got := make([]string, 0, len(ce.Sent()))
+			for _, event := range ce.Sent() {
+				got = append(got, event.Subject())
+			}
+			sort.Strings(got)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Error("Unexpected events (-want, +got) =", diff)
+			}
+		})
+	}
+}
+
+func TestNamespaceWatcher(t *testing.T) {
+	ctx, _ := pkgtesting.SetupFakeContext(t)
+
+	w := &namespaceWatcher{
+		k8s:    makeDynamicClient(),
+		logger: logging.FromContext(ctx),
+		resources: []ResourceWatch{{
+			GVR: schema.GroupVersionResource{
+				Version:  "v1",
+				Resource: "pods",
+			},
+		}},
+		delegate: cache.NewStore(cache.MetaNamespaceKeyFunc),
+		resync:   time.Hour,
+		stops:    make(map[string]chan struct{}),
+	}
+
+	w.add(ctx, "a")
+	w.add(ctx, "b")
+	w.add(ctx, "a")
+	if got := w.namespaces(); len(got) != 2 {
+		t.Error("Expected 2 watched namespaces, got:", got)
+	}
+
+	w.remove("a")
+	w.remove("c")
+	if got := w.namespaces(); len(got) != 1 || got[0] != "b" {
+		t.Error("Expected namespace b to be watched, got:", got)
+	}
+
+	w.removeAll()
+	if got := w.namespaces(); len(got) != 0 {
+		t.Error("Expected no watched namespaces, got:", got)
+	}
+}
+
+func TestSelectors(t *testing.T) {
+	var got []metav1.ListOptions
+	list := asUnstructuredLister(context.Background(), func(_ context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+		got = append(got, opts)
+		return &unstructured.UnstructuredList{}, nil
+	}, "team=payments", "status.phase=Failed")
+	watcher := asUnstructuredWatcher(context.Background(), func(_ context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+		got = append(got, opts)
+		return watch.NewEmptyWatch(), nil
+	}, "team=payments", "status.phase=Failed")
+
+	if _, err := list(metav1.ListOptions{}); err != nil {
+		t.Fatal("Failed to list:", err)
+	}
+	if _, err := watcher(metav1.ListOptions{ResourceVersion: "1"}); err != nil {
+		t.Fatal("Failed to watch:", err)
+	}
+
+	want := []metav1.ListOptions{{
+		LabelSelector: "team=payments",
+		FieldSelector: "status.phase=Failed",
+	}, {
+		LabelSelector:   "team=payments",
+		FieldSelector:   "status.phase=Failed",
+		ResourceVersion: "1",
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error("Unexpected list options (-want, +got):", diff)
+	}
+}
+
 // Common methods:
 
 // GetDynamicClient returns the mockDynamicClient to use for this test case.
@@ -0,0 +1,349 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// checkpointPeriod is how often the resource versions are persisted.
+const checkpointPeriod = 10 * time.Second
+
+// checkpoints persists the last resource versions seen by the watches in a
+// ConfigMap, so that they resume where they stopped when the adapter restarts.
+type checkpoints struct {
+	configMaps corev1client.ConfigMapInterface
+	name       string
+	logger     *zap.SugaredLogger
+
+	mu sync.Mutex
+	// versions are the resource versions, per checkpointKey.
+	versions map[string]string
+	dirty    bool
+}
+
+func newCheckpoints(configMaps corev1client.ConfigMapInterface, name string, logger *zap.SugaredLogger) *checkpoints {
+	return &checkpoints{
+		configMaps: configMaps,
+		name:       name,
+		logger:     logger,
+		versions:   make(map[string]string),
+	}
+}
+
+// checkpointKey returns the ConfigMap key of the resources gvr in namespace.
+func checkpointKey(gvr schema.GroupVersionResource, namespace string) string {
+	key := gvr.Resource + "." + gvr.Version
+	if gvr.Group != "" {
+		key += "." + gvr.Group
+	}
+	if namespace != "" {
+		key = namespace + "_" + key
+	}
+	return key
+}
+
+// load reads the persisted resource versions.
+func (c *checkpoints) load(ctx context.Context) error {
+	cm, err := c.configMaps.Get(ctx, c.name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get the checkpoint ConfigMap %q: %w", c.name, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, resourceVersion := range cm.Data {
+		c.versions[key] = resourceVersion
+	}
+	return nil
+}
+
+// get returns the resource version of key, or an empty string when it is unknown.
+func (c *checkpoints) get(key string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.versions[key]
+}
+
+// set remembers the resource version of key, to persist it on the next flush.
+func (c *checkpoints) set(key, resourceVersion string) {
+	if resourceVersion == "" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.versions[key] != resourceVersion {
+		c.versions[key] = resourceVersion
+		c.dirty = true
+	}
+}
+
+// run persists the resource versions every period, until stop is closed.
+func (c *checkpoints) run(ctx context.Context, period time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.flush(ctx); err != nil {
+				c.logger.Warnw("Failed to persist the resource versions", zap.Error(err))
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// flush persists the resource versions changed since the last flush.
+func (c *checkpoints) flush(ctx context.Context) error {
+	c.mu.Lock()
+	if !c.dirty {
+		c.mu.Unlock()
+		return nil
+	}
+	versions := make(map[string]string, len(c.versions))
+	for key, resourceVersion := range c.versions {
+		versions[key] = resourceVersion
+	}
+	c.dirty = false
+	c.mu.Unlock()
+
+	err := c.update(ctx, versions)
+	if err != nil {
+		c.mu.Lock()
+		c.dirty = true
+		c.mu.Unlock()
+	}
+	return err
+}
+
+func (c *checkpoints) update(ctx context.Context, versions map[string]string) error {
+	cm, err := c.configMaps.Get(ctx, c.name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	cm = cm.DeepCopy()
+	if cm.Data == nil {
+		cm.Data = make(map[string]string, len(versions))
+	}
+	for key, resourceVersion := range versions {
+		cm.Data[key] = resourceVersion
+	}
+	_, err = c.configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+	return err
+}
+
+// resumableWatch lists and watches resources from the last resource version
+// delivered by their previous watches, when it is known, instead of listing
+// them again. The resources are listed again only when that resource version
+// expired.
+type resumableWatch struct {
+	key         string
+	checkpoints *checkpoints
+	// relisted is called with the resource version of the list of the
+	// resources, when their watch could not resume.
+	relisted func(resourceVersion string)
+	// reseed lists the resources at the resumed resource version, instead of
+	// skipping the list, to remember their versions for the Diff mode and the
+	// filters.
+	reseed bool
+
+	mu      sync.Mutex
+	expired bool
+	// undelivered is set when an event could not be delivered, to not advance
+	// the resource version past it until the resources are listed again.
+	undelivered bool
+}
+
+// store wraps delegate to advance the resource version once the events about
+// the objects are delivered.
+func (w *resumableWatch) store(delegate cache.Store) cache.Store {
+	return &checkpointStore{Store: delegate, watch: w}
+}
+
+// listWatch wraps lw to resume its watches.
+func (w *resumableWatch) listWatch(lw cache.ListerWatcher) *cache.ListWatch {
+	return &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			if opts.Continue != "" {
+				// The next page of a list.
+				return lw.List(opts)
+			}
+
+			resourceVersion := w.checkpoints.get(w.key)
+			if resourceVersion != "" && !w.isExpired() {
+				if !w.reseed {
+					// Nothing to list, the reflector watches from resourceVersion.
+					list := &unstructured.UnstructuredList{}
+					list.SetResourceVersion(resourceVersion)
+					return list, nil
+				}
+
+				// The objects as they were at resourceVersion, which the
+				// reflector remembers without sending events about them.
+				exactOpts := opts
+				exactOpts.ResourceVersion = resourceVersion
+				exactOpts.ResourceVersionMatch = metav1.ResourceVersionMatchExact
+				obj, err := lw.List(exactOpts)
+				if err == nil {
+					return obj, nil
+				}
+				if !isExpiredError(err) {
+					return nil, err
+				}
+				w.setExpired(true)
+			}
+
+			obj, err := lw.List(opts)
+			if err != nil {
+				return nil, err
+			}
+			list, err := meta.ListAccessor(obj)
+			if err != nil {
+				return nil, err
+			}
+			w.checkpoints.set(w.key, list.GetResourceVersion())
+			w.setExpired(false)
+			w.setUndelivered(false)
+			if resourceVersion != "" {
+				w.relisted(list.GetResourceVersion())
+			}
+			return obj, nil
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			opts.AllowWatchBookmarks = true
+			wi, err := lw.Watch(opts)
+			if err != nil {
+				if isExpiredError(err) {
+					w.setExpired(true)
+				}
+				return nil, err
+			}
+			return watch.Filter(wi, w.recorder()), nil
+		},
+	}
+}
+
+// recorder returns the filter of a watch remembering the resource versions of
+// its bookmarks, and whether its resource version expired. The resource
+// versions of the other events are remembered by the store once they are
+// delivered.
+func (w *resumableWatch) recorder() watch.FilterFunc {
+	// The reflector handles the events one at a time, so it handled the events
+	// before a bookmark when the next event is filtered.
+	bookmark := ""
+	return func(event watch.Event) (watch.Event, bool) {
+		if bookmark != "" {
+			w.delivered(bookmark)
+			bookmark = ""
+		}
+		switch event.Type {
+		case watch.Error:
+			if isExpiredError(apierrors.FromObject(event.Object)) {
+				w.setExpired(true)
+			}
+		case watch.Bookmark:
+			if obj, err := meta.Accessor(event.Object); err == nil {
+				bookmark = obj.GetResourceVersion()
+			}
+		}
+		return event, true
+	}
+}
+
+// delivered advances the resource version to resourceVersion, unless an
+// event before it could not be delivered.
+func (w *resumableWatch) delivered(resourceVersion string) {
+	if !w.isUndelivered() {
+		w.checkpoints.set(w.key, resourceVersion)
+	}
+}
+
+func (w *resumableWatch) isUndelivered() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.undelivered
+}
+
+func (w *resumableWatch) setUndelivered(undelivered bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.undelivered = undelivered
+}
+
+func (w *resumableWatch) isExpired() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.expired
+}
+
+func (w *resumableWatch) setExpired(expired bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.expired = expired
+}
+
+// isExpiredError returns whether err means that a resource version cannot be watched from anymore.
+func isExpiredError(err error) bool {
+	return apierrors.IsResourceExpired(err) || apierrors.IsGone(err) ||
+		apierrors.HasStatusCause(err, metav1.CauseTypeResourceVersionTooLarge)
+}
+
+// checkpointStore advances the resource version of its resumableWatch once
+// the events about the objects are delivered by the wrapped store.
+type checkpointStore struct {
+	cache.Store
+	watch *resumableWatch
+}
+
+func (s *checkpointStore) Add(obj interface{}) error {
+	return s.record(obj, s.Store.Add(obj))
+}
+
+func (s *checkpointStore) Update(obj interface{}) error {
+	return s.record(obj, s.Store.Update(obj))
+}
+
+func (s *checkpointStore) Delete(obj interface{}) error {
+	return s.record(obj, s.Store.Delete(obj))
+}
+
+func (s *checkpointStore) record(obj interface{}, err error) error {
+	if err != nil {
+		s.watch.setUndelivered(true)
+		return err
+	}
+	if o, err := meta.Accessor(obj); err == nil {
+		s.watch.delivered(o.GetResourceVersion())
+	}
+	return nil
+}
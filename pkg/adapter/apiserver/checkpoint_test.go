@@ -0,0 +1,316 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/logging"
+	pkgtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestCheckpointKey(t *testing.T) {
+	testCases := map[string]struct {
+		gvr       schema.GroupVersionResource
+		namespace string
+		want      string
+	}{
+		"core namespaced resource": {
+			gvr:       schema.GroupVersionResource{Version: "v1", Resource: "pods"},
+			namespace: "default",
+			want:      "default_pods.v1",
+		},
+		"core cluster resource": {
+			gvr:  schema.GroupVersionResource{Version: "v1", Resource: "namespaces"},
+			want: "namespaces.v1",
+		},
+		"grouped resource": {
+			gvr:       schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+			namespace: "default",
+			want:      "default_deployments.v1.apps",
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if got := checkpointKey(tc.gvr, tc.namespace); got != tc.want {
+				t.Errorf("checkpointKey() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCheckpoints(t *testing.T) {
+	ctx, _ := pkgtesting.SetupFakeContext(t)
+	kube := kubefake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "checkpoint"},
+		Data:       map[string]string{"default_pods.v1": "3"},
+	})
+	configMaps := kube.CoreV1().ConfigMaps("default")
+
+	c := newCheckpoints(configMaps, "checkpoint", logging.FromContext(ctx))
+	if err := c.load(ctx); err != nil {
+		t.Fatal("Failed to load the checkpoints:", err)
+	}
+	if got := c.get("default_pods.v1"); got != "3" {
+		t.Errorf("get() = %q, want 3", got)
+	}
+
+	c.set("default_pods.v1", "5")
+	c.set("namespaces.v1", "7")
+	c.set("namespaces.v1", "")
+	if err := c.flush(ctx); err != nil {
+		t.Fatal("Failed to flush the checkpoints:", err)
+	}
+
+	cm, err := configMaps.Get(ctx, "checkpoint", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal("Failed to get the ConfigMap:", err)
+	}
+	want := map[string]string{"default_pods.v1": "5", "namespaces.v1": "7"}
+	if diff := cmp.Diff(want, cm.Data); diff != "" {
+		t.Error("Unexpected checkpoints (-want, +got):", diff)
+	}
+
+	// Nothing changed since the last flush.
+	kube.ClearActions()
+	if err := c.flush(ctx); err != nil {
+		t.Fatal("Failed to flush the checkpoints:", err)
+	}
+	if got := len(kube.Actions()); got != 0 {
+		t.Error("Expected no update of the ConfigMap, got actions:", got)
+	}
+
+	missing := newCheckpoints(configMaps, "missing", logging.FromContext(ctx))
+	if err := missing.load(ctx); err == nil {
+		t.Error("Expected an error loading a missing ConfigMap")
+	}
+}
+
+func TestResumableWatch(t *testing.T) {
+	ctx, _ := pkgtesting.SetupFakeContext(t)
+	c := newCheckpoints(kubefake.NewSimpleClientset().CoreV1().ConfigMaps("default"), "checkpoint", logging.FromContext(ctx))
+
+	lists := 0
+	var watchErr error
+	watcher := watch.NewFake()
+	lw := &cache.ListWatch{
+		ListFunc: func(metav1.ListOptions) (runtime.Object, error) {
+			lists++
+			list := &unstructured.UnstructuredList{Items: []unstructured.Unstructured{*simplePod("foo", "default")}}
+			list.SetResourceVersion("10")
+			return list, nil
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			if !opts.AllowWatchBookmarks {
+				t.Error("Expected the watch to allow bookmarks")
+			}
+			return watcher, watchErr
+		},
+	}
+
+	var relisted []string
+	w := &resumableWatch{
+		key:         "default_pods.v1",
+		checkpoints: c,
+		relisted: func(resourceVersion string) {
+			relisted = append(relisted, resourceVersion)
+		},
+	}
+	rlw := w.listWatch(lw)
+
+	// The first list cannot be avoided.
+	assertList(t, rlw, 1, "10")
+	if lists != 1 || len(relisted) != 0 {
+		t.Errorf("Expected a list and no relist, got %d lists and relists %v", lists, relisted)
+	}
+
+	// The watch records the resource versions of the bookmarks, once the
+	// events before them are handled.
+	wi, err := rlw.Watch(metav1.ListOptions{ResourceVersion: "10"})
+	if err != nil {
+		t.Fatal("Failed to watch:", err)
+	}
+	bookmark := &unstructured.Unstructured{}
+	bookmark.SetResourceVersion("11")
+	go watcher.Action(watch.Bookmark, bookmark)
+	<-wi.ResultChan()
+	if got := c.get(w.key); got != "10" {
+		t.Errorf("Expected resource version 10 to be recorded, got %q", got)
+	}
+	go watcher.Modify(podAt("12"))
+	<-wi.ResultChan()
+	if got := c.get(w.key); got != "11" {
+		t.Errorf("Expected resource version 11 to be recorded, got %q", got)
+	}
+
+	// The store records the resource versions of the delivered events, and
+	// not past an undelivered one.
+	delegate := &failingStore{Store: cache.NewStore(cache.MetaNamespaceKeyFunc)}
+	store := w.store(delegate)
+	if err := store.Update(podAt("12")); err != nil {
+		t.Fatal("Failed to update:", err)
+	}
+	if got := c.get(w.key); got != "12" {
+		t.Errorf("Expected resource version 12 to be recorded, got %q", got)
+	}
+	delegate.err = errors.New("not delivered")
+	if err := store.Update(podAt("13")); err == nil {
+		t.Error("Expected the update to fail")
+	}
+	delegate.err = nil
+	if err := store.Update(podAt("14")); err != nil {
+		t.Fatal("Failed to update:", err)
+	}
+	if got := c.get(w.key); got != "12" {
+		t.Errorf("Expected resource version 12 to be recorded, got %q", got)
+	}
+
+	// The next lists resume from the recorded resource version.
+	assertList(t, rlw, 0, "12")
+	if lists != 1 {
+		t.Error("Expected the list to be skipped, got lists:", lists)
+	}
+
+	// The resource version expired.
+	go watcher.Error(&apierrors.NewResourceExpired("too old resource version").ErrStatus)
+	<-wi.ResultChan()
+	assertList(t, rlw, 1, "10")
+	if lists != 2 || len(relisted) != 1 || relisted[0] != "10" {
+		t.Errorf("Expected a relist at 10, got %d lists and relists %v", lists, relisted)
+	}
+	assertList(t, rlw, 0, "10")
+
+	// The delivered events are recorded again after the relist.
+	if err := store.Delete(podAt("15")); err != nil {
+		t.Fatal("Failed to delete:", err)
+	}
+	if got := c.get(w.key); got != "15" {
+		t.Errorf("Expected resource version 15 to be recorded, got %q", got)
+	}
+	assertList(t, rlw, 0, "15")
+
+	// The resource version is gone when watching.
+	watchErr = apierrors.NewGone("gone")
+	if _, err := rlw.Watch(metav1.ListOptions{ResourceVersion: "10"}); err == nil {
+		t.Fatal("Expected the watch to fail")
+	}
+	assertList(t, rlw, 1, "10")
+	if lists != 3 || len(relisted) != 2 {
+		t.Errorf("Expected a relist, got %d lists and relists %v", lists, relisted)
+	}
+}
+
+func TestResumableWatchReseed(t *testing.T) {
+	ctx, _ := pkgtesting.SetupFakeContext(t)
+	c := newCheckpoints(kubefake.NewSimpleClientset().CoreV1().ConfigMaps("default"), "checkpoint", logging.FromContext(ctx))
+	c.set("default_pods.v1", "12")
+
+	var listErr error
+	var listed []metav1.ListOptions
+	lw := &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			listed = append(listed, opts)
+			if listErr != nil && opts.ResourceVersionMatch == metav1.ResourceVersionMatchExact {
+				return nil, listErr
+			}
+			list := &unstructured.UnstructuredList{Items: []unstructured.Unstructured{*simplePod("foo", "default")}}
+			list.SetResourceVersion("20")
+			if opts.ResourceVersion == "12" {
+				list.SetResourceVersion("12")
+			}
+			return list, nil
+		},
+	}
+
+	var relisted []string
+	w := &resumableWatch{
+		key:         "default_pods.v1",
+		checkpoints: c,
+		relisted: func(resourceVersion string) {
+			relisted = append(relisted, resourceVersion)
+		},
+		reseed: true,
+	}
+	rlw := w.listWatch(lw)
+
+	// The objects are listed as they were at the recorded resource version.
+	assertList(t, rlw, 1, "12")
+	if len(listed) != 1 || listed[0].ResourceVersion != "12" || listed[0].ResourceVersionMatch != metav1.ResourceVersionMatchExact {
+		t.Errorf("Expected an exact list at resource version 12, got %+v", listed)
+	}
+	if len(relisted) != 0 {
+		t.Error("Expected no relist, got:", relisted)
+	}
+
+	// The recorded resource version expired.
+	listErr = apierrors.NewResourceExpired("too old resource version")
+	assertList(t, rlw, 1, "20")
+	if len(relisted) != 1 || relisted[0] != "20" {
+		t.Error("Expected a relist at 20, got:", relisted)
+	}
+}
+
+// failingStore fails to update the objects with err, when it is set.
+type failingStore struct {
+	cache.Store
+	err error
+}
+
+func (s *failingStore) Update(obj interface{}) error {
+	if s.err != nil {
+		return s.err
+	}
+	return s.Store.Update(obj)
+}
+
+func podAt(resourceVersion string) *unstructured.Unstructured {
+	pod := simplePod("foo", "default")
+	pod.SetResourceVersion(resourceVersion)
+	return pod
+}
+
+func assertList(t *testing.T, lw cache.ListerWatcher, wantItems int, wantResourceVersion string) {
+	t.Helper()
+	obj, err := lw.List(metav1.ListOptions{ResourceVersion: "0"})
+	if err != nil {
+		t.Fatal("Failed to list:", err)
+	}
+	items, err := meta.ExtractList(obj)
+	if err != nil {
+		t.Fatal("Failed to extract the list:", err)
+	}
+	list, err := meta.ListAccessor(obj)
+	if err != nil {
+		t.Fatal("Failed to access the list:", err)
+	}
+	if len(items) != wantItems || list.GetResourceVersion() != wantResourceVersion {
+		t.Errorf("Expected %d items at resource version %q, got %d items at %q",
+			wantItems, wantResourceVersion, len(items), list.GetResourceVersion())
+	}
+}
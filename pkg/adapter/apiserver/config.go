@@ -28,6 +28,11 @@ type ResourceWatch struct {
 	// label selector.
 	// +optional
 	LabelSelector string `json:"selector,omitempty"`
+
+	// FieldSelector filters this source to objects to those resources pass the
+	// field selector.
+	// +optional
+	FieldSelector string `json:"fieldSelector,omitempty"`
 }
 
 type Config struct {
@@ -35,6 +40,12 @@ type Config struct {
 	// +required
 	Namespace string `json:"namespace"`
 
+	// NamespaceSelector is the label selector of the namespaces that namespaced
+	// Resources[] exist. It supersedes Namespace when set, and an empty
+	// selector selects all the namespaces.
+	// +optional
+	NamespaceSelector *string `json:"namespaceSelector,omitempty"`
+
 	// Resource is the resource this source will track and send related
 	// lifecycle events from the Kubernetes ApiServer.
 	// +required
@@ -50,6 +61,28 @@ type Config struct {
 	// `Reference` sends a dataref event type for the resource under watch.
 	// `Resource` send the full resource lifecycle event.
 	// Defaults to `Reference`
+	// `Diff` sends the full resource on add and delete, and the changes on update.
 	// +optional
 	EventMode string `json:"mode,omitempty"`
+
+	// IgnoredFields are the fields ignored by the `Diff` mode, in addition to
+	// the resource version and managed fields.
+	// +optional
+	IgnoredFields []string `json:"ignoredFields,omitempty"`
+
+	// Filters are the CEL expressions selecting the changes of the resources
+	// to send events about.
+	// +optional
+	Filters []string `json:"filters,omitempty"`
+
+	// KubernetesEvents types the events about Kubernetes Events after the
+	// objects they are about.
+	// +optional
+	KubernetesEvents bool `json:"kubernetesEvents,omitempty"`
+
+	// CheckpointConfigMap is the name of the ConfigMap in Namespace where the
+	// resource versions of the watches are persisted, to resume them when the
+	// adapter restarts. The watches are not resumed when it is empty.
+	// +optional
+	CheckpointConfigMap string `json:"checkpoint,omitempty"`
 }
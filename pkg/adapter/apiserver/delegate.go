@@ -22,6 +22,8 @@ import (
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/tools/cache"
 	"knative.dev/eventing/pkg/adapter/apiserver/events"
 )
@@ -31,6 +33,12 @@ type resourceDelegate struct {
 	source              string
 	ref                 bool
 	apiServerSourceName string
+	// diff is the state of the Diff mode, when enabled.
+	diff *diffState
+	// filter selects the changes to send events about, when set.
+	filter *objectFilter
+	// kubernetesEvents types the events about Kubernetes Events, when set.
+	kubernetesEvents bool
 
 	logger *zap.SugaredLogger
 }
@@ -38,37 +46,105 @@ type resourceDelegate struct {
 var _ cache.Store = (*resourceDelegate)(nil)
 
 func (a *resourceDelegate) Add(obj interface{}) error {
+	if a.diff != nil && obj != nil {
+		a.diff.swap(obj.(*unstructured.Unstructured))
+	}
+	if a.filtered(addOperation, obj) {
+		return nil
+	}
 	ctx, event, err := events.MakeAddEvent(a.source, a.apiServerSourceName, obj, a.ref)
 	if err != nil {
 		a.logger.Infow("event creation failed", zap.Error(err))
 		return err
 	}
-	a.sendCloudEvent(ctx, event)
-	return nil
+	a.setKubernetesEventAttributes(&event, obj)
+	return a.sendCloudEvent(ctx, event)
 }
 
 func (a *resourceDelegate) Update(obj interface{}) error {
+	if a.diff != nil {
+		return a.updateDiff(obj)
+	}
+	if a.filtered(updateOperation, obj) {
+		return nil
+	}
 	ctx, event, err := events.MakeUpdateEvent(a.source, a.apiServerSourceName, obj, a.ref)
 	if err != nil {
 		a.logger.Info("event creation failed", zap.Error(err))
 		return err
 	}
-	a.sendCloudEvent(ctx, event)
-	return nil
+	a.setKubernetesEventAttributes(&event, obj)
+	return a.sendCloudEvent(ctx, event)
+}
+
+// updateDiff sends the changes of obj since its previous version, unless only ignored fields changed.
+func (a *resourceDelegate) updateDiff(obj interface{}) error {
+	var old interface{}
+	if obj != nil {
+		old = a.diff.swap(obj.(*unstructured.Unstructured))
+	}
+	if a.filtered(updateOperation, obj) {
+		return nil
+	}
+	ctx, event, err := events.MakeUpdateDiffEvent(a.source, a.apiServerSourceName, old, obj, a.diff.ignoredFields)
+	if err != nil {
+		a.logger.Info("event creation failed", zap.Error(err))
+		return err
+	}
+	a.setKubernetesEventAttributes(&event, obj)
+	if ctx == nil {
+		// Only ignored fields changed.
+		return nil
+	}
+	return a.sendCloudEvent(ctx, event)
 }
 
 func (a *resourceDelegate) Delete(obj interface{}) error {
+	if a.diff != nil && obj != nil {
+		a.diff.forget(obj.(*unstructured.Unstructured))
+	}
+	if a.filtered(deleteOperation, obj) {
+		return nil
+	}
 	ctx, event, err := events.MakeDeleteEvent(a.source, a.apiServerSourceName, obj, a.ref)
 	if err != nil {
 		a.logger.Info("event creation failed", zap.Error(err))
 		return err
 	}
-	a.sendCloudEvent(ctx, event)
-	return nil
+	a.setKubernetesEventAttributes(&event, obj)
+	return a.sendCloudEvent(ctx, event)
+}
+
+// setKubernetesEventAttributes types event when it is about a Kubernetes Event and they are enabled.
+func (a *resourceDelegate) setKubernetesEventAttributes(event *cloudevents.Event, obj interface{}) {
+	if !a.kubernetesEvents || obj == nil {
+		return
+	}
+	if object := obj.(*unstructured.Unstructured); events.IsKubernetesEvent(object) {
+		events.SetKubernetesEventAttributes(event, object)
+	}
+}
+
+// filtered returns whether the filter leaves operation on obj out.
+func (a *resourceDelegate) filtered(operation string, obj interface{}) bool {
+	return a.filter != nil && obj != nil && !a.filter.matches(operation, obj.(*unstructured.Unstructured))
+}
+
+// sync sends a cloudevent when the resources gvr in namespace are listed again
+// at resourceVersion because their watch could not resume.
+func (a *resourceDelegate) sync(gvr schema.GroupVersionResource, namespace, resourceVersion string) {
+	ctx, event, err := events.MakeSyncEvent(a.source, a.apiServerSourceName, gvr, namespace, resourceVersion)
+	if err != nil {
+		a.logger.Info("event creation failed", zap.Error(err))
+		return
+	}
+	// A sync event which could not be delivered is not sent again.
+	_ = a.sendCloudEvent(ctx, event)
 }
 
 // sendCloudEvent sends a cloudevent everytime k8s api event is created, updated or deleted.
-func (a *resourceDelegate) sendCloudEvent(ctx context.Context, event cloudevents.Event) {
+// It returns an error when the event is not acknowledged.
+func (a *resourceDelegate) sendCloudEvent(ctx context.Context, event cloudevents.Event) error {
 	event.SetID(uuid.New().String()) // provide an ID here so we can track it with logging
 	defer a.logger.Debug("Finished sending cloudevent id: ", event.ID())
 	source := event.Context.GetSource()
@@ -78,9 +154,10 @@ func (a *resourceDelegate) sendCloudEvent(ctx context.Context, event cloudevents
 	if result := a.ce.Send(ctx, event); !cloudevents.IsACK(result) {
 		a.logger.Errorw("failed to send cloudevent", zap.Error(result), zap.String("source", source),
 			zap.String("subject", subject), zap.String("id", event.ID()))
-	} else {
-		a.logger.Debugf("cloudevent sent id: %s, source: %s, subject: %s", event.ID(), source, subject)
+		return result
 	}
+	a.logger.Debugf("cloudevent sent id: %s, source: %s, subject: %s", event.ID(), source, subject)
+	return nil
 }
 
 // Stub cache.Store impl
@@ -106,7 +183,19 @@ func (a *resourceDelegate) GetByKey(key string) (item interface{}, exists bool,
 }
 
 // Implements cache.Store
-func (a *resourceDelegate) Replace([]interface{}, string) error {
+func (a *resourceDelegate) Replace(objs []interface{}, _ string) error {
+	// Remember the listed objects to compute the changes of their next updates,
+	// and to filter them.
+	if a.diff != nil {
+		for _, obj := range objs {
+			a.diff.swap(obj.(*unstructured.Unstructured))
+		}
+	}
+	if a.filter != nil {
+		for _, obj := range objs {
+			a.filter.remember(obj.(*unstructured.Unstructured))
+		}
+	}
 	return nil
 }
 
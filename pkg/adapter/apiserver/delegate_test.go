@@ -16,8 +16,13 @@ limitations under the License.
 package apiserver
 
 import (
+	"reflect"
 	"testing"
 
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"knative.dev/eventing/pkg/adapter/apiserver/events"
+	adaptertest "knative.dev/eventing/pkg/adapter/v2/test"
 	"knative.dev/eventing/pkg/apis/sources"
 )
 
@@ -57,6 +62,90 @@ func TestResourceDeleteEventNil(t *testing.T) {
 	validateNotSent(t, ce, sources.ApiServerSourceDeleteEventType)
 }
 
+func TestResourceDiffEvents(t *testing.T) {
+	d, ce := makeResourceAndTestingClient()
+	d.diff = newDiffState([]string{"/status"})
+
+	pod := simplePod("unit", "test")
+	d.Add(pod)
+	validateSent(t, ce, sources.ApiServerSourceAddEventType)
+
+	// Only ignored fields changed.
+	ce.Reset()
+	pod = pod.DeepCopy()
+	pod.SetResourceVersion("2")
+	unstructured.SetNestedField(pod.Object, "Running", "status", "phase")
+	d.Update(pod)
+	validateNotSent(t, ce, sources.ApiServerSourceUpdateDiffEventType)
+
+	ce.Reset()
+	pod = pod.DeepCopy()
+	pod.SetLabels(map[string]string{"app": "unit"})
+	d.Update(pod)
+	validateSent(t, ce, sources.ApiServerSourceUpdateDiffEventType)
+	validateChangedPaths(t, ce, "/metadata/labels")
+
+	// The previous version of an object is remembered when it is listed.
+	ce.Reset()
+	d.Replace([]interface{}{simplePod("listed", "test")}, "")
+	listed := simplePod("listed", "test")
+	listed.SetLabels(map[string]string{"app": "listed"})
+	d.Update(listed)
+	validateChangedPaths(t, ce, "/metadata/labels")
+
+	// The changes of an unknown object are its fields.
+	d.Delete(pod)
+	ce.Reset()
+	d.Update(pod)
+	validateChangedPaths(t, ce, "/apiVersion", "/kind", "/metadata")
+}
+
+func TestResourceKubernetesEvents(t *testing.T) {
+	event := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Event",
+			"metadata": map[string]interface{}{
+				"namespace": "test",
+				"name":      "unit.1",
+			},
+		},
+	}
+
+	d, ce := makeResourceAndTestingClient()
+	d.Add(event)
+	validateSent(t, ce, sources.ApiServerSourceAddEventType)
+
+	ce.Reset()
+	d.kubernetesEvents = true
+	d.Add(event)
+	validateSent(t, ce, sources.ApiServerSourceKubernetesEventAddEventType)
+
+	ce.Reset()
+	d.Add(simplePod("unit", "test"))
+	validateSent(t, ce, sources.ApiServerSourceAddEventType)
+
+	d, ce = makeRefAndTestingClient()
+	d.kubernetesEvents = true
+	d.Delete(event)
+	validateSent(t, ce, sources.ApiServerSourceKubernetesEventDeleteRefEventType)
+}
+
+func validateChangedPaths(t *testing.T, ce *adaptertest.TestCloudEventsClient, want ...string) {
+	t.Helper()
+
+	if got := len(ce.Sent()); got != 1 {
+		t.Fatal("Expected 1 event to be sent, got:", got)
+	}
+	var diff events.ResourceDiff
+	if err := ce.Sent()[0].DataAs(&diff); err != nil {
+		t.Fatal("Failed to read the diff:", err)
+	}
+	if !reflect.DeepEqual(want, diff.ChangedPaths) {
+		t.Errorf("Expected the changed paths %v, got %v", want, diff.ChangedPaths)
+	}
+}
+
 // HACKHACKHACK For test coverage.
 func TestResourceStub(t *testing.T) {
 	d, _ := makeResourceAndTestingClient()
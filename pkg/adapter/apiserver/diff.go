@@ -0,0 +1,71 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"knative.dev/eventing/pkg/adapter/apiserver/events"
+)
+
+// alwaysIgnoredFields are the fields changing on every update.
+var alwaysIgnoredFields = []string{"/metadata/resourceVersion", "/metadata/managedFields"}
+
+// diffState remembers the last version of the objects, without their ignored
+// fields, to compute the changes of their updates.
+type diffState struct {
+	ignoredFields []string
+
+	mu      sync.Mutex
+	objects map[string]*unstructured.Unstructured
+}
+
+func newDiffState(ignoredFields []string) *diffState {
+	return &diffState{
+		ignoredFields: append(append([]string{}, alwaysIgnoredFields...), ignoredFields...),
+		objects:       make(map[string]*unstructured.Unstructured),
+	}
+}
+
+// swap remembers obj and returns its previous version, or an empty object
+// when it is unknown.
+func (s *diffState) swap(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := objectKey(obj)
+	old, ok := s.objects[key]
+	if !ok {
+		old = &unstructured.Unstructured{Object: map[string]interface{}{}}
+	}
+	s.objects[key] = events.IgnoreFields(obj, s.ignoredFields)
+	return old
+}
+
+// forget forgets obj.
+func (s *diffState) forget(obj *unstructured.Unstructured) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.objects, objectKey(obj))
+}
+
+func objectKey(obj *unstructured.Unstructured) string {
+	return obj.GetAPIVersion() + "/" + obj.GetKind() + "/" + obj.GetNamespace() + "/" + obj.GetName()
+}
@@ -19,6 +19,7 @@ package events
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -28,13 +29,26 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	sources "knative.dev/eventing/pkg/apis/sources"
+	v1 "knative.dev/eventing/pkg/apis/sources/v1"
+	"knative.dev/pkg/apis/duck"
 )
 
 const (
 	resourceGroup = "apiserversources.sources.knative.dev"
 )
 
+// kubernetesEventTypes are the types of the cloudevents about Kubernetes Events, per generic type.
+var kubernetesEventTypes = map[string]string{
+	sources.ApiServerSourceAddEventType:       sources.ApiServerSourceKubernetesEventAddEventType,
+	sources.ApiServerSourceAddRefEventType:    sources.ApiServerSourceKubernetesEventAddRefEventType,
+	sources.ApiServerSourceUpdateEventType:    sources.ApiServerSourceKubernetesEventUpdateEventType,
+	sources.ApiServerSourceUpdateRefEventType: sources.ApiServerSourceKubernetesEventUpdateRefEventType,
+	sources.ApiServerSourceDeleteEventType:    sources.ApiServerSourceKubernetesEventDeleteEventType,
+	sources.ApiServerSourceDeleteRefEventType: sources.ApiServerSourceKubernetesEventDeleteRefEventType,
+}
+
 // MakeAddEvent returns a cloudevent when a k8s api event is created.
 func MakeAddEvent(source string, apiServerSourceName string, obj interface{}, ref bool) (context.Context, cloudevents.Event, error) {
 	if obj == nil {
@@ -95,6 +109,106 @@ func MakeDeleteEvent(source string, apiServerSourceName string, obj interface{},
 	return makeEvent(source, apiServerSourceName, eventType, object, data)
 }
 
+// ResourceDiff is the data of the update events of the Diff mode.
+type ResourceDiff struct {
+	// Object is the updated resource.
+	Object corev1.ObjectReference `json:"object"`
+	// Patch is the JSON Patch from the old to the new resource.
+	Patch duck.JSONPatch `json:"patch"`
+	// ChangedPaths are the sorted JSON Pointers of the fields changed by Patch.
+	ChangedPaths []string `json:"changedPaths"`
+}
+
+// MakeUpdateDiffEvent returns a cloudevent with the changes of a k8s api object when it is updated,
+// ignoring the changes of ignoredFields. It returns a nil context when there are no other changes.
+func MakeUpdateDiffEvent(source string, apiServerSourceName string, oldObj, obj interface{}, ignoredFields []string) (context.Context, cloudevents.Event, error) {
+	if oldObj == nil || obj == nil {
+		return nil, cloudevents.Event{}, fmt.Errorf("resource can not be nil")
+	}
+	object := obj.(*unstructured.Unstructured)
+
+	before := IgnoreFields(oldObj.(*unstructured.Unstructured), ignoredFields)
+	after := IgnoreFields(object, ignoredFields)
+	patch, err := duck.CreatePatch(before.Object, after.Object)
+	if err != nil {
+		return nil, cloudevents.Event{}, err
+	}
+	if len(patch) == 0 {
+		return nil, cloudevents.Event{}, nil
+	}
+
+	ref := getRef(object)
+	ref.UID = object.GetUID()
+	ref.ResourceVersion = object.GetResourceVersion()
+
+	diff := ResourceDiff{
+		Object:       ref,
+		Patch:        patch,
+		ChangedPaths: make([]string, 0, len(patch)),
+	}
+	seen := make(map[string]bool, len(patch))
+	for _, op := range patch {
+		if !seen[op.Path] {
+			seen[op.Path] = true
+			diff.ChangedPaths = append(diff.ChangedPaths, op.Path)
+		}
+	}
+	sort.Strings(diff.ChangedPaths)
+
+	return makeEvent(source, apiServerSourceName, sources.ApiServerSourceUpdateDiffEventType, object, diff)
+}
+
+// ResourceSync is the data of the sync events.
+type ResourceSync struct {
+	// APIVersion is the API version of the listed resources.
+	APIVersion string `json:"apiVersion"`
+	// Resource is the plural name of the listed resources.
+	Resource string `json:"resource"`
+	// Namespace is the namespace of the listed resources, if they are namespaced.
+	Namespace string `json:"namespace,omitempty"`
+	// ResourceVersion is the resource version the resources were listed at.
+	ResourceVersion string `json:"resourceVersion"`
+}
+
+// MakeSyncEvent returns a cloudevent when the resources gvr in namespace are listed again
+// because their watch could not resume, so their changes since the last event may have been missed.
+func MakeSyncEvent(source string, apiServerSourceName string, gvr schema.GroupVersionResource, namespace, resourceVersion string) (context.Context, cloudevents.Event, error) {
+	apiVersion := gvr.GroupVersion().String()
+
+	event := cloudevents.NewEvent(cloudevents.VersionV1)
+	event.SetType(sources.ApiServerSourceSyncEventType)
+	event.SetSource(source)
+	if namespace != "" {
+		event.SetSubject(fmt.Sprintf("/apis/%s/namespaces/%s/%s", apiVersion, namespace, gvr.Resource))
+		event.SetExtension("namespace", namespace)
+	} else {
+		event.SetSubject(fmt.Sprintf("/apis/%s/%s", apiVersion, gvr.Resource))
+	}
+	data := ResourceSync{
+		APIVersion:      apiVersion,
+		Resource:        gvr.Resource,
+		Namespace:       namespace,
+		ResourceVersion: resourceVersion,
+	}
+	if err := event.SetData(cloudevents.ApplicationJSON, data); err != nil {
+		return nil, event, err
+	}
+
+	return withMetricTag(apiServerSourceName, namespace), event, nil
+}
+
+// IgnoreFields returns a copy of obj without fields, which are JSON Pointers.
+// Invalid fields are skipped.
+func IgnoreFields(obj *unstructured.Unstructured, fields []string) *unstructured.Unstructured {
+	obj = obj.DeepCopy()
+	for _, field := range fields {
+		if names, err := v1.ParseIgnoredField(field); err == nil {
+			unstructured.RemoveNestedField(obj.Object, names...)
+		}
+	}
+	return obj
+}
+
 func getRef(object *unstructured.Unstructured) corev1.ObjectReference {
 	return corev1.ObjectReference{
 		APIVersion: object.GetAPIVersion(),
@@ -127,6 +241,10 @@ func makeEvent(source, apiServerSourceName, eventType string, obj *unstructured.
 		return nil, event, err
 	}
 
+	return withMetricTag(apiServerSourceName, namespace), event, nil
+}
+
+func withMetricTag(apiServerSourceName, namespace string) context.Context {
 	ctx := context.Background()
 	metricTag := &kncloudevents.MetricTag{
 		Namespace:     namespace,
@@ -134,9 +252,42 @@ func makeEvent(source, apiServerSourceName, eventType string, obj *unstructured.
 		ResourceGroup: resourceGroup,
 	}
 	ctx = kncloudevents.ContextWithMetricTag(ctx, metricTag)
-	ctx = cloudevents.ContextWithRetriesExponentialBackoff(ctx, 50*time.Millisecond, 5)
+	return cloudevents.ContextWithRetriesExponentialBackoff(ctx, 50*time.Millisecond, 5)
+}
+
+// IsKubernetesEvent returns whether obj is a Kubernetes Event, core/v1 or events.k8s.io/v1.
+func IsKubernetesEvent(obj *unstructured.Unstructured) bool {
+	apiVersion := obj.GetAPIVersion()
+	return obj.GetKind() == "Event" && (apiVersion == "v1" || apiVersion == "events.k8s.io/v1")
+}
+
+// SetKubernetesEventAttributes sets the type of event about the Kubernetes Event obj, keeping its
+// payload shape, its subject to the object obj is about, and the reason and type of obj as extensions.
+func SetKubernetesEventAttributes(event *cloudevents.Event, obj *unstructured.Unstructured) {
+	if eventType, ok := kubernetesEventTypes[event.Type()]; ok {
+		event.SetType(eventType)
+	}
 
-	return ctx, event, nil
+	// The object is the involvedObject of core/v1 Events and regarding of events.k8s.io/v1 Events.
+	involved, ok, _ := unstructured.NestedMap(obj.Object, "involvedObject")
+	if !ok {
+		involved, ok, _ = unstructured.NestedMap(obj.Object, "regarding")
+	}
+	if ok {
+		ref := corev1.ObjectReference{}
+		ref.APIVersion, _, _ = unstructured.NestedString(involved, "apiVersion")
+		ref.Kind, _, _ = unstructured.NestedString(involved, "kind")
+		ref.Name, _, _ = unstructured.NestedString(involved, "name")
+		ref.Namespace, _, _ = unstructured.NestedString(involved, "namespace")
+		event.SetSubject(createSelfLink(ref))
+	}
+
+	if reason, _, _ := unstructured.NestedString(obj.Object, "reason"); reason != "" {
+		event.SetExtension("reason", reason)
+	}
+	if eventType, _, _ := unstructured.NestedString(obj.Object, "type"); eventType != "" {
+		event.SetExtension("eventtype", eventType)
+	}
 }
 
 // Creates a URI of the form found in object metadata selfLinks
@@ -18,6 +18,7 @@ package events_test
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -25,6 +26,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"knative.dev/eventing/pkg/adapter/apiserver/events"
 )
@@ -133,6 +135,239 @@ func TestMakeUpdateEvent(t *testing.T) {
 	}
 }
 
+func TestMakeUpdateDiffEvent(t *testing.T) {
+	labelledPod := simplePod("unit", "test")
+	labelledPod.SetLabels(map[string]string{"app": "unit"})
+
+	testCases := map[string]struct {
+		oldObj interface{}
+		obj    interface{}
+		source string
+
+		want     *cloudevents.Event
+		wantData string
+		wantErr  string
+	}{
+		"nil object": {
+			source:  "unit-test",
+			oldObj:  simplePod("unit", "test"),
+			want:    nil,
+			wantErr: "resource can not be nil",
+		},
+		"nil old object": {
+			source:  "unit-test",
+			obj:     simplePod("unit", "test"),
+			want:    nil,
+			wantErr: "resource can not be nil",
+		},
+		"labelled pod": {
+			source: "unit-test",
+			oldObj: simplePod("unit", "test"),
+			obj:    labelledPod,
+			want: &cloudevents.Event{
+				Context: cloudevents.EventContextV1{
+					Type:            "dev.knative.apiserver.diff.update",
+					Source:          *cloudevents.ParseURIRef("unit-test"),
+					Subject:         simpleSubject("unit", "test"),
+					DataContentType: &contentType,
+					Extensions: map[string]interface{}{
+						"kind":      "Pod",
+						"name":      "unit",
+						"namespace": "test",
+					},
+				}.AsV1(),
+			},
+			wantData: `{"object":{"kind":"Pod","namespace":"test","name":"unit","apiVersion":"v1"},"patch":[{"op":"add","path":"/metadata/labels","value":{"app":"unit"}}],"changedPaths":["/metadata/labels"]}`,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			_, got, err := events.MakeUpdateDiffEvent(tc.source, apiServerSourceNameTest, tc.oldObj, tc.obj, nil)
+			validate(t, got, err, tc.want, tc.wantData, tc.wantErr)
+		})
+	}
+}
+
+func TestMakeUpdateDiffEventIgnoredFields(t *testing.T) {
+	updatedPod := simplePod("unit", "test")
+	updatedPod.SetResourceVersion("2")
+	if err := unstructured.SetNestedField(updatedPod.Object, "Running", "status", "phase"); err != nil {
+		t.Fatal("Failed to set the status:", err)
+	}
+
+	ctx, _, err := events.MakeUpdateDiffEvent("unit-test", apiServerSourceNameTest, simplePod("unit", "test"), updatedPod,
+		[]string{"/metadata/resourceVersion", "/status"})
+	if err != nil {
+		t.Fatal("MakeUpdateDiffEvent() =", err)
+	}
+	if ctx != nil {
+		t.Error("Expected no event for an object with only ignored changes")
+	}
+	if updatedPod.GetResourceVersion() != "2" {
+		t.Error("Expected the object not to be modified")
+	}
+}
+
+func TestMakeSyncEvent(t *testing.T) {
+	podsSubject := "/apis/v1/namespaces/test/pods"
+	namespacesSubject := "/apis/v1/namespaces"
+
+	testCases := map[string]struct {
+		gvr       schema.GroupVersionResource
+		namespace string
+
+		want     *cloudevents.Event
+		wantData string
+	}{
+		"namespaced resource": {
+			gvr:       schema.GroupVersionResource{Version: "v1", Resource: "pods"},
+			namespace: "test",
+			want: &cloudevents.Event{
+				Context: cloudevents.EventContextV1{
+					Type:            "dev.knative.apiserver.sync",
+					Source:          *cloudevents.ParseURIRef("unit-test"),
+					Subject:         &podsSubject,
+					DataContentType: &contentType,
+					Extensions: map[string]interface{}{
+						"namespace": "test",
+					},
+				}.AsV1(),
+			},
+			wantData: `{"apiVersion":"v1","resource":"pods","namespace":"test","resourceVersion":"42"}`,
+		},
+		"cluster resource": {
+			gvr: schema.GroupVersionResource{Version: "v1", Resource: "namespaces"},
+			want: &cloudevents.Event{
+				Context: cloudevents.EventContextV1{
+					Type:            "dev.knative.apiserver.sync",
+					Source:          *cloudevents.ParseURIRef("unit-test"),
+					Subject:         &namespacesSubject,
+					DataContentType: &contentType,
+				}.AsV1(),
+			},
+			wantData: `{"apiVersion":"v1","resource":"namespaces","resourceVersion":"42"}`,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			_, got, err := events.MakeSyncEvent("unit-test", apiServerSourceNameTest, tc.gvr, tc.namespace, "42")
+			validate(t, got, err, tc.want, tc.wantData, "")
+		})
+	}
+}
+
+func TestMakeKubernetesEvents(t *testing.T) {
+	coreEvent := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Event",
+			"metadata": map[string]interface{}{
+				"namespace": "test",
+				"name":      "unit.1",
+			},
+			"involvedObject": map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Pod",
+				"namespace":  "test",
+				"name":       "unit",
+			},
+			"reason": "BackOff",
+			"type":   "Warning",
+		},
+	}
+	event := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "events.k8s.io/v1",
+			"kind":       "Event",
+			"metadata": map[string]interface{}{
+				"namespace": "test",
+				"name":      "unit.2",
+			},
+			"regarding": map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Pod",
+				"namespace":  "test",
+				"name":       "unit",
+			},
+			"reason": "Pulled",
+			"type":   "Normal",
+		},
+	}
+
+	testCases := map[string]struct {
+		make func() (cloudevents.Event, error)
+		obj  *unstructured.Unstructured
+
+		wantType      string
+		wantReason    string
+		wantEventType string
+	}{
+		"core event add": {
+			make: func() (cloudevents.Event, error) {
+				_, got, err := events.MakeAddEvent("unit-test", apiServerSourceNameTest, coreEvent, false)
+				return got, err
+			},
+			obj:           coreEvent,
+			wantType:      "dev.knative.apiserver.event.resource.add",
+			wantReason:    "BackOff",
+			wantEventType: "Warning",
+		},
+		"core event update ref": {
+			make: func() (cloudevents.Event, error) {
+				_, got, err := events.MakeUpdateEvent("unit-test", apiServerSourceNameTest, coreEvent, true)
+				return got, err
+			},
+			obj:           coreEvent,
+			wantType:      "dev.knative.apiserver.event.ref.update",
+			wantReason:    "BackOff",
+			wantEventType: "Warning",
+		},
+		"event delete": {
+			make: func() (cloudevents.Event, error) {
+				_, got, err := events.MakeDeleteEvent("unit-test", apiServerSourceNameTest, event, false)
+				return got, err
+			},
+			obj:           event,
+			wantType:      "dev.knative.apiserver.event.resource.delete",
+			wantReason:    "Pulled",
+			wantEventType: "Normal",
+		},
+		"event diff update": {
+			make: func() (cloudevents.Event, error) {
+				updated := event.DeepCopy()
+				updated.Object["note"] = "Pulled again"
+				_, got, err := events.MakeUpdateDiffEvent("unit-test", apiServerSourceNameTest, event, updated, nil)
+				return got, err
+			},
+			obj:           event,
+			wantType:      "dev.knative.apiserver.diff.update",
+			wantReason:    "Pulled",
+			wantEventType: "Normal",
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got, err := tc.make()
+			if err != nil {
+				t.Fatal("Failed to make the event:", err)
+			}
+			events.SetKubernetesEventAttributes(&got, tc.obj)
+			if got.Type() != tc.wantType {
+				t.Errorf("Type() = %q, want %q", got.Type(), tc.wantType)
+			}
+			if want := *simpleSubject("unit", "test"); got.Subject() != want {
+				t.Errorf("Subject() = %q, want %q", got.Subject(), want)
+			}
+			if reason := got.Extensions()["reason"]; reason != tc.wantReason {
+				t.Errorf("reason = %v, want %q", reason, tc.wantReason)
+			}
+			if eventType := got.Extensions()["eventtype"]; eventType != tc.wantEventType {
+				t.Errorf("eventtype = %v, want %q", eventType, tc.wantEventType)
+			}
+		})
+	}
+}
+
 func TestMakeDeleteEvent(t *testing.T) {
 	testCases := map[string]struct {
 		obj    interface{}
@@ -319,3 +554,19 @@ func validate(t *testing.T, got cloudevents.Event, err error, want *cloudevents.
 		t.Error("unexpected data diff (-want, +got) =", diff)
 	}
 }
+
+func TestIgnoreFields(t *testing.T) {
+	pod := simplePod("unit", "test")
+	pod.SetAnnotations(map[string]string{"example.com/revision": "1", "other": "2"})
+
+	got := events.IgnoreFields(pod, []string{"/metadata/annotations/example.com~1revision", "/metadata/namespace", "invalid"})
+	if want := map[string]string{"other": "2"}; !reflect.DeepEqual(got.GetAnnotations(), want) {
+		t.Errorf("Expected the annotations %v, got %v", want, got.GetAnnotations())
+	}
+	if got.GetNamespace() != "" {
+		t.Error("Expected the namespace to be ignored, got:", got.GetNamespace())
+	}
+	if pod.GetNamespace() != "test" {
+		t.Error("Expected the object not to be modified")
+	}
+}
@@ -17,9 +17,16 @@ limitations under the License.
 package apiserver
 
 import (
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"go.uber.org/zap"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/tools/cache"
+
+	v1 "knative.dev/eventing/pkg/apis/sources/v1"
 )
 
 // controllerFilter by apiVersion and/or kind
@@ -87,11 +94,88 @@ func (c *controllerFilter) GetByKey(key string) (item interface{}, exists bool,
 }
 
 // Implements cache.Store
-func (c *controllerFilter) Replace([]interface{}, string) error {
-	return nil
+func (c *controllerFilter) Replace(objs []interface{}, resourceVersion string) error {
+	filtered := make([]interface{}, 0, len(objs))
+	for _, obj := range objs {
+		if !c.filtered(obj) {
+			filtered = append(filtered, obj)
+		}
+	}
+	return c.delegate.Replace(filtered, resourceVersion)
 }
 
 // Implements cache.Store
 func (c *controllerFilter) Resync() error {
 	return nil
 }
+
+// The operations on the objects, in the filter expressions.
+const (
+	addOperation    = "add"
+	updateOperation = "update"
+	deleteOperation = "delete"
+)
+
+// objectFilter selects the changes of the objects with CEL expressions.
+type objectFilter struct {
+	expressions []string
+	programs    []cel.Program
+	// objects are the last versions of the objects, to evaluate the
+	// expressions on their updates.
+	objects *diffState
+
+	logger *zap.SugaredLogger
+}
+
+func newObjectFilter(expressions []string, logger *zap.SugaredLogger) (*objectFilter, error) {
+	f := &objectFilter{
+		expressions: expressions,
+		programs:    make([]cel.Program, 0, len(expressions)),
+		objects:     newDiffState(nil),
+		logger:      logger,
+	}
+	for _, expression := range expressions {
+		program, err := v1.CompileApiServerSourceFilter(expression)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter %q: %w", expression, err)
+		}
+		f.programs = append(f.programs, program)
+	}
+	return f, nil
+}
+
+// matches returns whether all the expressions select operation on obj, and
+// remembers obj for its next update.
+func (f *objectFilter) matches(operation string, obj *unstructured.Unstructured) bool {
+	old := map[string]interface{}{}
+	switch operation {
+	case addOperation:
+		f.objects.swap(obj)
+	case updateOperation:
+		old = f.objects.swap(obj).Object
+	case deleteOperation:
+		f.objects.forget(obj)
+	}
+
+	vars := map[string]interface{}{
+		v1.FilterObjectVariable:    obj.Object,
+		v1.FilterOldObjectVariable: old,
+		v1.FilterOperationVariable: operation,
+	}
+	for i, program := range f.programs {
+		out, _, err := program.Eval(vars)
+		if err != nil {
+			f.logger.Debugw("Failed to evaluate the filter", zap.String("filter", f.expressions[i]), zap.Error(err))
+			return false
+		}
+		if out != types.True {
+			return false
+		}
+	}
+	return true
+}
+
+// remember remembers obj for its next update.
+func (f *objectFilter) remember(obj *unstructured.Unstructured) {
+	f.objects.swap(obj)
+}
@@ -17,8 +17,12 @@ limitations under the License.
 package apiserver
 
 import (
+	"reflect"
 	"testing"
 
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+
 	adaptertest "knative.dev/eventing/pkg/adapter/v2/test"
 	sources "knative.dev/eventing/pkg/apis/sources"
 )
@@ -95,6 +99,90 @@ func TestControllerDeleteEventWithGoodControllerNoAPIVersion(t *testing.T) {
 	validateSent(t, tc, sources.ApiServerSourceDeleteRefEventType)
 }
 
+func TestControllerReplace(t *testing.T) {
+	store := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	c := &controllerFilter{
+		kind:     "ReplicaSet",
+		delegate: store,
+	}
+	if err := c.Replace([]interface{}{simplePod("unit", "test"), simpleOwnedPod("owned", "test")}, "42"); err != nil {
+		t.Fatal("Replace() =", err)
+	}
+	if got, want := store.ListKeys(), []string{"test/owned"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected the delegate to be replaced with %v, got %v", want, got)
+	}
+}
+
+func TestObjectFilter(t *testing.T) {
+	d, ce := makeResourceAndTestingClient()
+	filter, err := newObjectFilter([]string{
+		`object.metadata.namespace == "test"`,
+		`operation != "update" || object.spec.replicas != oldObject.spec.replicas`,
+	}, d.logger)
+	if err != nil {
+		t.Fatal("Failed to create the filter:", err)
+	}
+	d.filter = filter
+
+	deployment := simpleDeployment("unit", "test", 1)
+	d.Add(deployment)
+	validateSent(t, ce, sources.ApiServerSourceAddEventType)
+
+	ce.Reset()
+	d.Add(simpleDeployment("unit", "other", 1))
+	validateNotSent(t, ce, sources.ApiServerSourceAddEventType)
+
+	// The replicas did not change.
+	ce.Reset()
+	deployment = deployment.DeepCopy()
+	deployment.SetLabels(map[string]string{"app": "unit"})
+	d.Update(deployment)
+	validateNotSent(t, ce, sources.ApiServerSourceUpdateEventType)
+
+	ce.Reset()
+	d.Update(simpleDeployment("unit", "test", 2))
+	validateSent(t, ce, sources.ApiServerSourceUpdateEventType)
+
+	// The previous version of an object is remembered when it is listed.
+	ce.Reset()
+	d.Replace([]interface{}{simpleDeployment("listed", "test", 3)}, "")
+	d.Update(simpleDeployment("listed", "test", 3))
+	validateNotSent(t, ce, sources.ApiServerSourceUpdateEventType)
+
+	// The filter fails to evaluate for an unknown object.
+	ce.Reset()
+	d.Update(simpleDeployment("unknown", "test", 1))
+	validateNotSent(t, ce, sources.ApiServerSourceUpdateEventType)
+
+	ce.Reset()
+	d.Delete(deployment)
+	validateSent(t, ce, sources.ApiServerSourceDeleteEventType)
+}
+
+func TestObjectFilterInvalid(t *testing.T) {
+	for _, expression := range []string{"object.", `operation + "s"`, "unknown == 1"} {
+		if _, err := newObjectFilter([]string{expression}, nil); err == nil {
+			t.Errorf("Expected filter %q to be invalid", expression)
+		}
+	}
+}
+
+func simpleDeployment(name, namespace string, replicas int64) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"namespace": namespace,
+				"name":      name,
+			},
+			"spec": map[string]interface{}{
+				"replicas": replicas,
+			},
+		},
+	}
+}
+
 func makeController(apiVersion, kind string) (*controllerFilter, *adaptertest.TestCloudEventsClient) {
 	delegate, tc := makeRefAndTestingClient()
 	return &controllerFilter{
@@ -0,0 +1,131 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+)
+
+var namespacesGVR = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+
+// namespaceWatcher watches namespaced resources in the namespaces matching a
+// label selector, starting and stopping their reflectors as the namespaces
+// start and stop matching.
+type namespaceWatcher struct {
+	k8s    dynamic.Interface
+	logger *zap.SugaredLogger
+
+	resources []ResourceWatch
+	delegate  cache.Store
+	resync    time.Duration
+	// resume returns the resumableWatch of the resources, or nil when the
+	// watches are not resumed.
+	resume func(gvr schema.GroupVersionResource, namespace string) *resumableWatch
+
+	mu sync.Mutex
+	// stops are the stop channels of the reflectors, per namespace.
+	stops map[string]chan struct{}
+}
+
+// run watches the namespaces matching selector until stop is closed.
+func (w *namespaceWatcher) run(ctx context.Context, selector string, stop <-chan struct{}) {
+	res := w.k8s.Resource(namespacesGVR)
+	lw := &cache.ListWatch{
+		ListFunc:  asUnstructuredLister(ctx, res.List, selector, ""),
+		WatchFunc: asUnstructuredWatcher(ctx, res.Watch, selector, ""),
+	}
+
+	// Namespaces which stop matching the selector are deleted from the watch.
+	_, controller := cache.NewInformer(lw, &unstructured.Unstructured{}, w.resync, cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if ns, ok := obj.(*unstructured.Unstructured); ok {
+				w.add(ctx, ns.GetName())
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if name, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj); err == nil {
+				w.remove(name)
+			}
+		},
+	})
+	controller.Run(stop)
+	w.removeAll()
+}
+
+// add starts the reflectors of the resources in namespace.
+func (w *namespaceWatcher) add(ctx context.Context, namespace string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.stops[namespace]; ok {
+		return
+	}
+	w.logger.Infow("Watching namespace", zap.String("namespace", namespace))
+
+	stop := make(chan struct{})
+	w.stops[namespace] = stop
+	for _, rw := range w.resources {
+		var resume *resumableWatch
+		if w.resume != nil {
+			resume = w.resume(rw.GVR, namespace)
+		}
+		go runReflector(ctx, w.k8s.Resource(rw.GVR).Namespace(namespace), rw, w.delegate, w.resync, stop, resume)
+	}
+}
+
+// remove stops the reflectors of the resources in namespace.
+func (w *namespaceWatcher) remove(namespace string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if stop, ok := w.stops[namespace]; ok {
+		w.logger.Infow("Not watching namespace anymore", zap.String("namespace", namespace))
+		close(stop)
+		delete(w.stops, namespace)
+	}
+}
+
+// removeAll stops all the reflectors.
+func (w *namespaceWatcher) removeAll() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for namespace, stop := range w.stops {
+		close(stop)
+		delete(w.stops, namespace)
+	}
+}
+
+// namespaces returns the watched namespaces.
+func (w *namespaceWatcher) namespaces() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	namespaces := make([]string, 0, len(w.stops))
+	for namespace := range w.stops {
+		namespaces = append(namespaces, namespace)
+	}
+	return namespaces
+}
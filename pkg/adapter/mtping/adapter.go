@@ -33,6 +33,7 @@ import (
 
 	"knative.dev/eventing/pkg/adapter/v2"
 	sourcesv1 "knative.dev/eventing/pkg/apis/sources/v1"
+	eventingclient "knative.dev/eventing/pkg/client/injection/client"
 )
 
 const (
@@ -58,7 +59,8 @@ func NewEnvConfig() adapter.EnvConfigAccessor {
 
 func NewAdapter(ctx context.Context, _ adapter.EnvConfigAccessor, ceClient cloudevents.Client) adapter.Adapter {
 	logger := logging.FromContext(ctx)
-	runner := NewCronJobsRunner(ceClient, kubeclient.Get(ctx), logging.FromContext(ctx))
+	runner := NewCronJobsRunner(ceClient, kubeclient.Get(ctx), eventingclient.Get(ctx), logging.FromContext(ctx))
+	runner.jitter.policy, runner.jitter.window = GetJitterValue()
 
 	return &mtpingAdapter{
 		logger:    logger,
@@ -127,6 +129,7 @@ func (a *mtpingAdapter) Remove(source *sourcesv1.PingSource) {
 		delete(a.entryids, key)
 		a.entryidMu.Unlock()
 	}
+	a.runner.RemoveSource(source)
 }
 
 func (a *mtpingAdapter) RemoveAll(ctx context.Context) {
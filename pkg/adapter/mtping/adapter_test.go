@@ -18,6 +18,7 @@ package mtping
 
 import (
 	"context"
+	"reflect"
 	"sync"
 	"testing"
 	"time"
@@ -63,9 +64,10 @@ func TestStartStopAdapter(t *testing.T) {
 
 func TestUpdateRemoveAdapter(t *testing.T) {
 	ctx, _ := rectesting.SetupFakeContext(t)
+	runner := &testRunner{}
 	adapter := mtpingAdapter{
 		logger:    logging.FromContext(ctx),
-		runner:    &testRunner{},
+		runner:    runner,
 		entryidMu: sync.RWMutex{},
 		entryids:  make(map[string]cron.EntryID),
 	}
@@ -90,13 +92,20 @@ func TestUpdateRemoveAdapter(t *testing.T) {
 	if _, ok := adapter.entryids["test-ns/test-name"]; ok {
 		t.Error(`Expected cron entries to not contain "test-ns/test-name"`)
 	}
+	if want := []string{"test-ns/test-name"}; !reflect.DeepEqual(runner.removed, want) {
+		t.Errorf("Expected the schedule state of %v to be removed, got %v", want, runner.removed)
+	}
 }
 
 type testRunner struct {
 	CronJobRunner
+	removed []string
 }
 
 func (*testRunner) AddSchedule(*sourcesv1.PingSource) cron.EntryID {
 	return cron.EntryID(1)
 }
 func (*testRunner) RemoveSchedule(cron.EntryID) {}
+func (r *testRunner) RemoveSource(source *sourcesv1.PingSource) {
+	r.removed = append(r.removed, source.Namespace+"/"+source.Name)
+}
@@ -19,6 +19,7 @@ package mtping
 import (
 	"context"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/client-go/tools/cache"
 
 	"knative.dev/eventing/pkg/adapter/v2"
@@ -65,9 +66,37 @@ func NewController(ctx context.Context, adapter adapter.Adapter) *controller.Imp
 
 	pingsourceinformer.Get(ctx).Informer().AddEventHandler(
 		cache.ResourceEventHandlerFuncs{
-			AddFunc:    impl.Enqueue,
-			UpdateFunc: controller.PassNew(impl.Enqueue),
+			AddFunc: impl.Enqueue,
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				// The adapter records the schedule times itself.
+				if !scheduleTimesUpdate(oldObj, newObj) {
+					impl.Enqueue(newObj)
+				}
+			},
 			DeleteFunc: r.deleteFunc,
 		})
 	return impl
 }
+
+// scheduleTimesUpdate returns true when the only change made by an update of a PingSource is
+// to the schedule times in its status.
+func scheduleTimesUpdate(oldObj, newObj interface{}) bool {
+	old, ok := oldObj.(*sourcesv1.PingSource)
+	if !ok {
+		return false
+	}
+	new, ok := newObj.(*sourcesv1.PingSource)
+	if !ok {
+		return false
+	}
+	if old.ResourceVersion == new.ResourceVersion || old.Generation != new.Generation ||
+		!equality.Semantic.DeepEqual(old.Labels, new.Labels) ||
+		!equality.Semantic.DeepEqual(old.Annotations, new.Annotations) ||
+		!equality.Semantic.DeepEqual(old.DeletionTimestamp, new.DeletionTimestamp) {
+		return false
+	}
+	oldStatus, newStatus := old.Status.DeepCopy(), new.Status.DeepCopy()
+	oldStatus.LastScheduleTime, oldStatus.NextScheduleTime = nil, nil
+	newStatus.LastScheduleTime, newStatus.NextScheduleTime = nil, nil
+	return equality.Semantic.DeepEqual(oldStatus, newStatus)
+}
@@ -19,9 +19,13 @@ package mtping
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"testing"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
 	. "knative.dev/pkg/reconciler/testing"
 
 	"knative.dev/eventing/pkg/adapter/v2"
@@ -57,3 +61,63 @@ func TestNew(t *testing.T) {
 		t.Fatal("Expected NewController to return a non-nil value")
 	}
 }
+
+func TestScheduleTimesUpdate(t *testing.T) {
+	old := &sourcesv1.PingSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-ns", Generation: 1, ResourceVersion: "1"},
+		Spec:       sourcesv1.PingSourceSpec{Schedule: "* * * * *"},
+		Status: sourcesv1.PingSourceStatus{
+			SourceStatus: duckv1.SourceStatus{SinkURI: apis.HTTP("sink")},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		update func(*sourcesv1.PingSource)
+		want   bool
+	}{{
+		name: "resync",
+		want: false,
+	}, {
+		name: "schedule times",
+		update: func(ps *sourcesv1.PingSource) {
+			ps.ResourceVersion = "2"
+			ps.Status.LastScheduleTime = &metav1.Time{Time: time.Now()}
+			ps.Status.NextScheduleTime = &metav1.Time{Time: time.Now().Add(time.Minute)}
+		},
+		want: true,
+	}, {
+		name: "sink",
+		update: func(ps *sourcesv1.PingSource) {
+			ps.ResourceVersion = "2"
+			ps.Status.SinkURI = apis.HTTP("other")
+		},
+		want: false,
+	}, {
+		name: "spec",
+		update: func(ps *sourcesv1.PingSource) {
+			ps.ResourceVersion = "2"
+			ps.Generation = 2
+		},
+		want: false,
+	}, {
+		name: "labels",
+		update: func(ps *sourcesv1.PingSource) {
+			ps.ResourceVersion = "2"
+			ps.Labels = map[string]string{"team": "a"}
+		},
+		want: false,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			new := old.DeepCopy()
+			if test.update != nil {
+				test.update(new)
+			}
+			if got := scheduleTimesUpdate(old, new); got != test.want {
+				t.Errorf("scheduleTimesUpdate() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mtping
+
+import (
+	"log"
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/rickb777/date/period"
+
+	sourcesv1 "knative.dev/eventing/pkg/apis/sources/v1"
+)
+
+const (
+	EnvJitterPolicy = "K_JITTER_POLICY"
+	EnvJitterWindow = "K_JITTER_WINDOW"
+
+	defaultJitterPolicy = sourcesv1.PingJitterFixed
+	defaultJitterWindow = 500 * time.Millisecond
+)
+
+// jitter delays the events of a PingSource.
+type jitter struct {
+	policy sourcesv1.PingJitterPolicy
+	window time.Duration
+}
+
+// GetJitterValue returns the jitter policy and window applied to the PingSources without jitter.
+func GetJitterValue() (sourcesv1.PingJitterPolicy, time.Duration) {
+	policy, window := defaultJitterPolicy, defaultJitterWindow
+
+	switch str := sourcesv1.PingJitterPolicy(os.Getenv(EnvJitterPolicy)); str {
+	case "":
+	case sourcesv1.PingJitterNone, sourcesv1.PingJitterFixed, sourcesv1.PingJitterSpread:
+		policy = str
+	default:
+		log.Printf("%s environment value is invalid. It must be one of None, Fixed or Spread. (got %s)", EnvJitterPolicy, str)
+	}
+
+	if str := os.Getenv(EnvJitterWindow); str != "" {
+		d, err := time.ParseDuration(str)
+		if err != nil || d < 0 || d > sourcesv1.MaxPingJitterDelay {
+			log.Printf("%s environment value is invalid. It must be a duration between 0s and %v. (got %s)", EnvJitterWindow, sourcesv1.MaxPingJitterDelay, str)
+		} else {
+			window = d
+		}
+	}
+	return policy, window
+}
+
+// jitterOf returns the jitter of source, defaulting to def.
+func jitterOf(source *sourcesv1.PingSource, def jitter) jitter {
+	spec := source.Spec.Jitter
+	if spec == nil {
+		return def
+	}
+
+	j := jitter{policy: spec.Policy, window: def.window}
+	if spec.Window != nil {
+		if p, err := period.Parse(*spec.Window); err == nil {
+			j.window, _ = p.Duration()
+		}
+	}
+	return j
+}
+
+// delay returns a random delay for an event of a PingSource sharing its schedule with
+// sources PingSources, itself included.
+func (j jitter) delay(sources int) time.Duration {
+	window := j.window
+	switch j.policy {
+	case sourcesv1.PingJitterFixed:
+	case sourcesv1.PingJitterSpread:
+		if sources > 1 {
+			window *= time.Duration(sources)
+		}
+	default:
+		return 0
+	}
+	if window > sourcesv1.MaxPingJitterDelay {
+		window = sourcesv1.MaxPingJitterDelay
+	}
+	if window <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(window))) //nolint:gosec // Cryptographic randomness not necessary here.
+}
+
+// scheduleKey identifies the PingSources firing at the same times.
+func scheduleKey(source *sourcesv1.PingSource) string {
+	if source.Spec.At != nil {
+		return source.Spec.At.UTC().Format(time.RFC3339)
+	}
+	return source.Spec.Timezone + " " + source.Spec.Schedule
+}
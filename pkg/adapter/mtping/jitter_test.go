@@ -0,0 +1,141 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mtping
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"knative.dev/pkg/ptr"
+
+	sourcesv1 "knative.dev/eventing/pkg/apis/sources/v1"
+)
+
+func TestGetJitterValue(t *testing.T) {
+	tests := map[string]struct {
+		policy     string
+		window     string
+		wantPolicy sourcesv1.PingJitterPolicy
+		wantWindow time.Duration
+	}{
+		"defaults": {
+			wantPolicy: sourcesv1.PingJitterFixed,
+			wantWindow: 500 * time.Millisecond,
+		},
+		"set": {
+			policy:     "Spread",
+			window:     "50ms",
+			wantPolicy: sourcesv1.PingJitterSpread,
+			wantWindow: 50 * time.Millisecond,
+		},
+		"invalid": {
+			policy:     "Random",
+			window:     "1h",
+			wantPolicy: sourcesv1.PingJitterFixed,
+			wantWindow: 500 * time.Millisecond,
+		},
+	}
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			os.Setenv(EnvJitterPolicy, tc.policy)
+			os.Setenv(EnvJitterWindow, tc.window)
+			defer os.Unsetenv(EnvJitterPolicy)
+			defer os.Unsetenv(EnvJitterWindow)
+
+			policy, window := GetJitterValue()
+			if policy != tc.wantPolicy || window != tc.wantWindow {
+				t.Errorf("GetJitterValue() = %s, %v, want %s, %v", policy, window, tc.wantPolicy, tc.wantWindow)
+			}
+		})
+	}
+}
+
+func TestJitterDelay(t *testing.T) {
+	def := jitter{policy: sourcesv1.PingJitterFixed, window: 100 * time.Millisecond}
+
+	tests := map[string]struct {
+		jitter  *sourcesv1.PingJitter
+		sources int
+		max     time.Duration
+	}{
+		"default": {
+			sources: 10,
+			max:     100 * time.Millisecond,
+		},
+		"none": {
+			jitter:  &sourcesv1.PingJitter{Policy: sourcesv1.PingJitterNone},
+			sources: 10,
+		},
+		"fixed": {
+			jitter:  &sourcesv1.PingJitter{Policy: sourcesv1.PingJitterFixed, Window: ptr.String("PT0.5S")},
+			sources: 10,
+			max:     500 * time.Millisecond,
+		},
+		"spread": {
+			jitter:  &sourcesv1.PingJitter{Policy: sourcesv1.PingJitterSpread},
+			sources: 10,
+			max:     time.Second,
+		},
+		"spread capped": {
+			jitter:  &sourcesv1.PingJitter{Policy: sourcesv1.PingJitterSpread, Window: ptr.String("PT10S")},
+			sources: 10,
+			max:     sourcesv1.MaxPingJitterDelay,
+		},
+	}
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			j := jitterOf(&sourcesv1.PingSource{Spec: sourcesv1.PingSourceSpec{Jitter: tc.jitter}}, def)
+			var longest time.Duration
+			for i := 0; i < 1000; i++ {
+				d := j.delay(tc.sources)
+				if d < 0 || (d > 0 && d >= tc.max) {
+					t.Fatalf("delay(%d) = %v, want in [0, %v)", tc.sources, d, tc.max)
+				}
+				if d > longest {
+					longest = d
+				}
+			}
+			// The delays should use most of the window.
+			if longest < tc.max/2 {
+				t.Errorf("Expected delays up to %v, got up to %v", tc.max, longest)
+			}
+		})
+	}
+}
+
+func TestScheduleGroups(t *testing.T) {
+	runner := NewCronJobsRunner(nil, nil, nil, nil)
+	runner.joinGroup(1, "a")
+	runner.joinGroup(2, "a")
+	runner.joinGroup(3, "b")
+
+	if got := runner.groupSize("a"); got != 2 {
+		t.Error("Expected 2 entries in group a, got", got)
+	}
+
+	runner.leaveGroup(1)
+	runner.leaveGroup(3)
+	runner.leaveGroup(4)
+
+	if got := runner.groupSize("a"); got != 1 {
+		t.Error("Expected 1 entry in group a, got", got)
+	}
+	if got := len(runner.groupSizes); got != 1 {
+		t.Error("Expected 1 group, got", got)
+	}
+}
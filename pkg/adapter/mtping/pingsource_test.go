@@ -83,6 +83,7 @@ func TestAllCases(t *testing.T) {
 					}),
 					rtv1.WithInitPingSourceConditions,
 					rtv1.WithPingSourceDeployed,
+					rtv1.WithPingSourceDeadLetterSinkNotConfigured,
 					rtv1.WithPingSourceSink(sinkURI),
 					rtv1.WithPingSourceCloudEventAttributes,
 				),
@@ -106,6 +107,7 @@ func TestAllCases(t *testing.T) {
 					}),
 					rtv1.WithInitPingSourceConditions,
 					rtv1.WithPingSourceDeployed,
+					rtv1.WithPingSourceDeadLetterSinkNotConfigured,
 					rtv1.WithPingSourceSink(sinkURI),
 					rtv1.WithPingSourceCloudEventAttributes,
 				),
@@ -131,6 +133,7 @@ func TestAllCases(t *testing.T) {
 					}),
 					rtv1.WithInitPingSourceConditions,
 					rtv1.WithPingSourceDeployed,
+					rtv1.WithPingSourceDeadLetterSinkNotConfigured,
 					rtv1.WithPingSourceSink(sinkURI),
 					rtv1.WithPingSourceCloudEventAttributes,
 				),
@@ -156,6 +159,7 @@ func TestAllCases(t *testing.T) {
 					}),
 					rtv1.WithInitPingSourceConditions,
 					rtv1.WithPingSourceDeployed,
+					rtv1.WithPingSourceDeadLetterSinkNotConfigured,
 					rtv1.WithPingSourceSink(sinkURI),
 					rtv1.WithPingSourceCloudEventAttributes,
 				),
@@ -181,6 +185,7 @@ func TestAllCases(t *testing.T) {
 					}),
 					rtv1.WithInitPingSourceConditions,
 					rtv1.WithPingSourceDeployed,
+					rtv1.WithPingSourceDeadLetterSinkNotConfigured,
 					rtv1.WithPingSourceSink(sinkURI),
 					rtv1.WithPingSourceCloudEventAttributes,
 					rtv1.WithPingSourceDeleted,
@@ -203,6 +208,7 @@ func TestAllCases(t *testing.T) {
 					}),
 					rtv1.WithInitPingSourceConditions,
 					rtv1.WithPingSourceDeployed,
+					rtv1.WithPingSourceDeadLetterSinkNotConfigured,
 					rtv1.WithPingSourceSink(sinkURI),
 					rtv1.WithPingSourceCloudEventAttributes,
 					rtv1.WithPingSourceDeleted,
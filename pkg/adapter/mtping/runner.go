@@ -19,15 +19,21 @@ package mtping
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
-	"math/rand"
+	"sync"
+	"text/template"
 	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
 	"github.com/google/uuid"
 	"github.com/robfig/cron/v3"
 	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/record"
@@ -35,6 +41,9 @@ import (
 	kncloudevents "knative.dev/eventing/pkg/adapter/v2"
 	"knative.dev/eventing/pkg/adapter/v2/util/crstatusevent"
 	sourcesv1 "knative.dev/eventing/pkg/apis/sources/v1"
+	"knative.dev/eventing/pkg/channel/attributes"
+	"knative.dev/eventing/pkg/client/clientset/versioned"
+	knevents "knative.dev/eventing/pkg/kncloudevents"
 )
 
 type CronJobRunner interface {
@@ -42,6 +51,8 @@ type CronJobRunner interface {
 	Stop()
 	AddSchedule(source *sourcesv1.PingSource) cron.EntryID
 	RemoveSchedule(id cron.EntryID)
+	// RemoveSource forgets the schedule state of a deleted PingSource.
+	RemoveSource(source *sourcesv1.PingSource)
 }
 
 type cronJobsRunner struct {
@@ -56,22 +67,90 @@ type cronJobsRunner struct {
 
 	// kubeClient for sending k8s events
 	kubeClient kubernetes.Interface
+
+	// eventingClient for recording the schedule times of PingSources
+	eventingClient versioned.Interface
+
+	// now returns the current time
+	now func() time.Time
+
+	// jitter is the jitter of the PingSources without jitter
+	jitter jitter
+
+	schedulesMu sync.Mutex
+	schedules   map[types.NamespacedName]*scheduleState
+	// groupSizes is the number of cron entries per schedule key
+	groupSizes map[string]int
+	// groups is the schedule key of the cron entries
+	groups map[cron.EntryID]string
+}
+
+// pingJob sends the events of a PingSource.
+type pingJob struct {
+	source   *sourcesv1.PingSource
+	schedule cron.Schedule
+	event    cloudevents.Event
+	// template renders the event data, when set.
+	template *template.Template
+	// retryConfig is the retry configuration of the PingSource delivery spec. When nil,
+	// the retries are configured in the sending context.
+	retryConfig *knevents.RetryConfig
+	// jitter delays the events sent on schedule.
+	jitter jitter
+	// group is the schedule key of the PingSource.
+	group string
+}
+
+// scheduleState tracks the schedules of a PingSource with catch-up or templated data enabled.
+type scheduleState struct {
+	// sequence is the number of events sent by this runner. It is not persisted, so it
+	// starts again from 0 when the adapter restarts.
+	sequence int64
+
+	// last is the time of the last schedule sent, or being sent, by this runner.
+	last time.Time
+	// recorded is the last schedule time recorded in the PingSource status.
+	recorded time.Time
+	// next is the next schedule time recorded in the PingSource status, when nextRecorded.
+	next         time.Time
+	nextRecorded bool
 }
 
 const (
 	resourceGroup = "pingsources.sources.knative.dev"
+
+	// maxMissedSchedules is the maximum number of missed schedules sent when catching up.
+	maxMissedSchedules = 100
 )
 
-func NewCronJobsRunner(ceClient cloudevents.Client, kubeClient kubernetes.Interface, logger *zap.SugaredLogger, opts ...cron.Option) *cronJobsRunner {
+func NewCronJobsRunner(ceClient cloudevents.Client, kubeClient kubernetes.Interface, eventingClient versioned.Interface, logger *zap.SugaredLogger, opts ...cron.Option) *cronJobsRunner {
 	return &cronJobsRunner{
-		cron:       *cron.New(opts...),
-		Client:     ceClient,
-		Logger:     logger,
-		kubeClient: kubeClient,
+		cron:           *cron.New(opts...),
+		Client:         ceClient,
+		Logger:         logger,
+		kubeClient:     kubeClient,
+		eventingClient: eventingClient,
+		now:            time.Now,
+		jitter:         jitter{policy: defaultJitterPolicy, window: defaultJitterWindow},
+		schedules:      make(map[types.NamespacedName]*scheduleState),
+		groupSizes:     make(map[string]int),
+		groups:         make(map[cron.EntryID]string),
 	}
 }
 
 func (a *cronJobsRunner) AddSchedule(source *sourcesv1.PingSource) cron.EntryID {
+	if source.Spec.Suspend {
+		a.recordNextScheduleTime(context.Background(), source, time.Time{})
+		return 0
+	}
+
+	sched, err := newSchedule(source)
+	if err != nil {
+		a.Logger.Errorw("failed to parse the schedule", zap.String("namespace", source.Namespace),
+			zap.String("name", source.Name), zap.Error(err))
+		return 0
+	}
+
 	event, err := makeEvent(source)
 	if err != nil {
 		a.Logger.Error("failed to makeEvent: ", zap.Error(err))
@@ -83,9 +162,27 @@ func (a *cronJobsRunner) AddSchedule(source *sourcesv1.PingSource) cron.EntryID
 	var kubeEventSink record.EventSink = &typedcorev1.EventSinkImpl{Interface: a.kubeClient.CoreV1().Events(source.Namespace)}
 	ctx = crstatusevent.ContextWithCRStatus(ctx, &kubeEventSink, "ping-source-mt-adapter", source, a.Logger.Infof)
 
-	// Simple retry configuration to be less than 1mn.
-	// We might want to retry more times for less-frequent schedule.
-	ctx = cloudevents.ContextWithRetriesExponentialBackoff(ctx, 50*time.Millisecond, 5)
+	job := &pingJob{
+		source:   source,
+		schedule: sched,
+		event:    event,
+		jitter:   jitterOf(source, a.jitter),
+		group:    scheduleKey(source),
+	}
+
+	if source.Spec.Delivery != nil {
+		retryConfig, err := knevents.RetryConfigFromDeliverySpec(*source.Spec.Delivery)
+		if err != nil {
+			a.Logger.Errorw("failed to parse the delivery spec, using the default retries", zap.Error(err))
+		} else {
+			job.retryConfig = &retryConfig
+		}
+	}
+	if job.retryConfig == nil {
+		// Simple retry configuration to be less than 1mn.
+		// We might want to retry more times for less-frequent schedule.
+		ctx = cloudevents.ContextWithRetriesExponentialBackoff(ctx, 50*time.Millisecond, 5)
+	}
 
 	metricTag := &kncloudevents.MetricTag{
 		Namespace:     source.Namespace,
@@ -93,18 +190,51 @@ func (a *cronJobsRunner) AddSchedule(source *sourcesv1.PingSource) cron.EntryID
 		ResourceGroup: resourceGroup,
 	}
 
-	schedule := source.Spec.Schedule
-	if source.Spec.Timezone != "" {
-		schedule = "CRON_TZ=" + source.Spec.Timezone + " " + schedule
+	ctx = kncloudevents.ContextWithMetricTag(ctx, metricTag)
+
+	if source.Spec.TemplateData {
+		job.template, err = sourcesv1.ParsePingDataTemplate(source.Spec.Data)
+		if err != nil {
+			a.Logger.Errorw("failed to parse the data template, sending the data as is", zap.Error(err))
+		}
 	}
 
-	ctx = kncloudevents.ContextWithMetricTag(ctx, metricTag)
-	id, _ := a.cron.AddFunc(schedule, a.cronTick(ctx, event))
+	// Claim the missed schedules before adding the cron job, which only fires after now.
+	var missed []time.Time
+	if source.Spec.At != nil {
+		missed = a.missedOneShot(source)
+	} else if source.Spec.StartingDeadlineSeconds != nil {
+		missed = a.missedSchedules(source, sched)
+	}
+
+	id := a.cron.Schedule(sched, cron.FuncJob(a.cronTick(ctx, job)))
+	a.joinGroup(id, job.group)
+	a.recordNextScheduleTime(ctx, source, sched.Next(a.now()))
+
+	if len(missed) > 0 {
+		a.Logger.Infow("Catching up missed schedules", zap.String("namespace", source.Namespace),
+			zap.String("name", source.Name), zap.Int("count", len(missed)))
+		go func() {
+			for _, t := range missed {
+				event := event.Clone()
+				event.SetTime(t)
+				a.send(ctx, job, event, t)
+			}
+		}()
+	}
 	return id
 }
 
 func (a *cronJobsRunner) RemoveSchedule(id cron.EntryID) {
 	a.cron.Remove(id)
+	a.leaveGroup(id)
+}
+
+func (a *cronJobsRunner) RemoveSource(source *sourcesv1.PingSource) {
+	a.schedulesMu.Lock()
+	defer a.schedulesMu.Unlock()
+
+	delete(a.schedules, types.NamespacedName{Namespace: source.Namespace, Name: source.Name})
 }
 
 func (a *cronJobsRunner) Start(stopCh <-chan struct{}) {
@@ -120,25 +250,303 @@ func (a *cronJobsRunner) Stop() {
 	}
 }
 
-func (a *cronJobsRunner) cronTick(ctx context.Context, event cloudevents.Event) func() {
+func (a *cronJobsRunner) cronTick(ctx context.Context, job *pingJob) func() {
 	return func() {
-		event := event.Clone()
-		event.SetID(uuid.New().String()) // provide an ID here so we can track it with logging
-		defer a.Logger.Debug("Finished sending cloudevent id: ", event.ID())
-		target := cecontext.TargetFrom(ctx).String()
-		source := event.Context.GetSource()
+		// Cron jobs are fired on the second of their schedule.
+		scheduled := a.now().Truncate(time.Second)
+		// Mark the schedule as sent before the jitter delay, so that re-adding the schedule
+		// while the event is being sent does not send it again as missed.
+		if job.source.Spec.StartingDeadlineSeconds != nil || job.source.Spec.At != nil {
+			a.markSent(job.source, scheduled)
+		}
 
 		// Provide a delay so not all ping fired instantaneously distribute load on resources.
-		time.Sleep(time.Duration(rand.Intn(500)) * time.Millisecond) //nolint:gosec // Cryptographic randomness not necessary here.
+		time.Sleep(job.jitter.delay(a.groupSize(job.group)))
+
+		a.send(ctx, job, job.event.Clone(), scheduled)
+	}
+}
+
+// send sends the event of the schedule at scheduled, or to the dead letter sink when it can't be
+// delivered, and records it in the PingSource status.
+func (a *cronJobsRunner) send(ctx context.Context, job *pingJob, event cloudevents.Event, scheduled time.Time) {
+	ps := job.source
+	event.SetID(uuid.New().String()) // provide an ID here so we can track it with logging
+	event.SetExtension(sourcesv1.PingSourceScheduledTimeExtension, scheduled)
+	defer a.Logger.Debug("Finished sending cloudevent id: ", event.ID())
+	target := cecontext.TargetFrom(ctx).String()
+	source := event.Context.GetSource()
+
+	if job.template != nil {
+		data, err := sourcesv1.RenderPingDataTemplate(job.template, sourcesv1.PingDataTemplateContext{
+			ScheduledTime: scheduled,
+			FireTime:      a.now(),
+			Sequence:      a.nextSequence(ps),
+			Namespace:     ps.Namespace,
+			Name:          ps.Name,
+			Labels:        ps.Labels,
+			Annotations:   ps.Annotations,
+		}, sourcesv1.PingDataTemplateMaxSize)
+		if err != nil {
+			a.Logger.Error("failed to render the data template: ", zap.Error(err),
+				zap.String("source", source), zap.String("id", event.ID()))
+			return
+		}
+		if err := event.SetData(ps.Spec.ContentType, data); err != nil {
+			a.Logger.Error("failed to set the rendered data: ", zap.Error(err),
+				zap.String("source", source), zap.String("id", event.ID()))
+			return
+		}
+	}
+
+	a.Logger.Debugf("sending cloudevent id: %s, source: %s, target: %s", event.ID(), source, target)
 
-		a.Logger.Debugf("sending cloudevent id: %s, source: %s, target: %s", event.ID(), source, target)
+	if result := a.sendWithRetries(ctx, job, event); !cloudevents.IsACK(result) {
+		// Exhausted number of retries.
+		a.Logger.Error("failed to send cloudevent result: ", zap.Any("result", result),
+			zap.String("source", source), zap.String("target", target), zap.String("id", event.ID()))
 
-		if result := a.Client.Send(ctx, event); !cloudevents.IsACK(result) {
-			// Exhausted number of retries. Event is lost.
-			a.Logger.Error("failed to send cloudevent result: ", zap.Any("result", result),
-				zap.String("source", source), zap.String("target", target), zap.String("id", event.ID()))
+		dls := ps.Status.DeadLetterSinkURI
+		if dls == nil {
+			// Event is lost.
+			return
 		}
+		event.SetExtension(attributes.KnativeErrorDestExtensionKey, target)
+		var httpResult *cehttp.Result
+		if cloudevents.ResultAs(result, &httpResult) {
+			event.SetExtension(attributes.KnativeErrorCodeExtensionKey, httpResult.StatusCode)
+		}
+		if result := a.sendWithRetries(cloudevents.ContextWithTarget(ctx, dls.String()), job, event); !cloudevents.IsACK(result) {
+			// Event is lost.
+			a.Logger.Error("failed to send cloudevent to the dead letter sink result: ", zap.Any("result", result),
+				zap.String("source", source), zap.String("target", dls.String()), zap.String("id", event.ID()))
+		}
+		return
+	}
+
+	a.recordLastScheduleTime(ctx, job, scheduled)
+}
+
+// sendWithRetries sends event, retrying as configured by the delivery spec of the job.
+func (a *cronJobsRunner) sendWithRetries(ctx context.Context, job *pingJob, event cloudevents.Event) protocol.Result {
+	if job.retryConfig == nil {
+		return a.Client.Send(ctx, event)
+	}
+
+	for attempt := 0; ; attempt++ {
+		result := a.sendOnce(ctx, job.retryConfig.RequestTimeout, event)
+		if cloudevents.IsACK(result) || attempt >= job.retryConfig.RetryMax {
+			return result
+		}
+		select {
+		case <-time.After(job.retryConfig.Backoff(attempt, nil)):
+		case <-ctx.Done():
+			return result
+		}
+	}
+}
+
+// sendOnce sends event, within timeout when set.
+func (a *cronJobsRunner) sendOnce(ctx context.Context, timeout time.Duration, event cloudevents.Event) protocol.Result {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return a.Client.Send(ctx, event)
+}
+
+// missedSchedules returns the schedules of ps missed since the last one sent, within its
+// starting deadline, and marks them as sent.
+func (a *cronJobsRunner) missedSchedules(ps *sourcesv1.PingSource, sched cron.Schedule) []time.Time {
+	a.schedulesMu.Lock()
+	defer a.schedulesMu.Unlock()
+
+	state := a.scheduleState(ps)
+	now := a.now()
+
+	last := state.last
+	if ps.Status.LastScheduleTime != nil && ps.Status.LastScheduleTime.After(last) {
+		last = ps.Status.LastScheduleTime.Time
+	}
+	if ps.CreationTimestamp.After(last) {
+		last = ps.CreationTimestamp.Time
+	}
+	if earliest := now.Add(-time.Duration(*ps.Spec.StartingDeadlineSeconds) * time.Second); earliest.After(last) {
+		last = earliest
+	}
+
+	var missed []time.Time
+	for t := sched.Next(last); !t.IsZero() && !t.After(now); t = sched.Next(t) {
+		missed = append(missed, t)
+	}
+	if len(missed) > maxMissedSchedules {
+		a.Logger.Warnw("too many missed schedules, only sending the most recent ones",
+			zap.String("namespace", ps.Namespace), zap.String("name", ps.Name), zap.Int("missed", len(missed)))
+		missed = missed[len(missed)-maxMissedSchedules:]
+	}
+	if len(missed) > 0 {
+		state.last = missed[len(missed)-1]
+	}
+	return missed
+}
+
+// missedOneShot returns the time of the one-shot ps when it passed before its event was sent,
+// for instance when the adapter took over ps after its time, and marks it as sent.
+func (a *cronJobsRunner) missedOneShot(ps *sourcesv1.PingSource) []time.Time {
+	a.schedulesMu.Lock()
+	defer a.schedulesMu.Unlock()
+
+	at := ps.Spec.At.Time
+	state := a.scheduleState(ps)
+	if at.After(a.now()) || !state.last.Before(at) ||
+		(ps.Status.LastScheduleTime != nil && !ps.Status.LastScheduleTime.Time.Before(at)) {
+		return nil
+	}
+	state.last = at
+	return []time.Time{at}
+}
+
+// nextSequence returns the sequence number of the next event sent for ps.
+func (a *cronJobsRunner) nextSequence(ps *sourcesv1.PingSource) int64 {
+	a.schedulesMu.Lock()
+	defer a.schedulesMu.Unlock()
+
+	state := a.scheduleState(ps)
+	state.sequence++
+	return state.sequence
+}
+
+// markSent marks the schedule at scheduled as sent.
+func (a *cronJobsRunner) markSent(ps *sourcesv1.PingSource, scheduled time.Time) {
+	a.schedulesMu.Lock()
+	defer a.schedulesMu.Unlock()
+
+	if state := a.scheduleState(ps); scheduled.After(state.last) {
+		state.last = scheduled
+	}
+}
+
+// recordLastScheduleTime records scheduled and the next schedule time in the status of the
+// PingSource, unless a later schedule has already been recorded. Only the times which changed
+// are patched.
+func (a *cronJobsRunner) recordLastScheduleTime(ctx context.Context, job *pingJob, scheduled time.Time) {
+	ps := job.source
+	status := make(map[string]interface{}, 2)
+
+	a.schedulesMu.Lock()
+	state := a.scheduleState(ps)
+	if last := ps.Status.LastScheduleTime; scheduled.After(state.recorded) && (last == nil || scheduled.After(last.Time)) {
+		state.recorded = scheduled
+		status["lastScheduleTime"] = metav1.NewTime(scheduled)
+	}
+	if next := job.schedule.Next(a.now()); a.nextChanged(ps, state, next) {
+		status["nextScheduleTime"] = scheduleTime(next)
+	}
+	a.schedulesMu.Unlock()
+
+	if len(status) > 0 {
+		a.patchStatus(ctx, ps, status)
+	}
+}
+
+// recordNextScheduleTime records next in the status of ps, when it changed. A zero next
+// clears it.
+func (a *cronJobsRunner) recordNextScheduleTime(ctx context.Context, ps *sourcesv1.PingSource, next time.Time) {
+	a.schedulesMu.Lock()
+	state := a.scheduleState(ps)
+	// The status of ps is more recent than the last time recorded by this runner.
+	state.nextRecorded = false
+	changed := a.nextChanged(ps, state, next)
+	a.schedulesMu.Unlock()
+
+	if changed {
+		a.patchStatus(ctx, ps, map[string]interface{}{
+			"nextScheduleTime": scheduleTime(next),
+		})
+	}
+}
+
+// nextChanged returns true when next differs from the next schedule time recorded in the
+// status of ps, and marks it as recorded. schedulesMu must be held.
+func (a *cronJobsRunner) nextChanged(ps *sourcesv1.PingSource, state *scheduleState, next time.Time) bool {
+	var current time.Time
+	if state.nextRecorded {
+		current = state.next
+	} else if ps.Status.NextScheduleTime != nil {
+		current = ps.Status.NextScheduleTime.Time
+	}
+	if current.Equal(next) {
+		return false
+	}
+	state.next, state.nextRecorded = next, true
+	return true
+}
+
+// patchStatus merges status into the status of ps.
+func (a *cronJobsRunner) patchStatus(ctx context.Context, ps *sourcesv1.PingSource, status map[string]interface{}) {
+	patch, err := json.Marshal(map[string]interface{}{
+		"status": status,
+	})
+	if err != nil {
+		a.Logger.Errorw("failed to marshal the status patch", zap.Error(err))
+		return
+	}
+	if _, err := a.eventingClient.SourcesV1().PingSources(ps.Namespace).Patch(ctx, ps.Name, types.MergePatchType, patch, metav1.PatchOptions{}, "status"); err != nil {
+		a.Logger.Errorw("failed to record the schedule times", zap.String("namespace", ps.Namespace),
+			zap.String("name", ps.Name), zap.Error(err))
+	}
+}
+
+// scheduleTime returns t as a status field value, which is null when t is zero.
+func scheduleTime(t time.Time) *metav1.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &metav1.Time{Time: t}
+}
+
+// joinGroup adds the cron entry id to the group of the entries with the same schedule.
+func (a *cronJobsRunner) joinGroup(id cron.EntryID, group string) {
+	a.schedulesMu.Lock()
+	defer a.schedulesMu.Unlock()
+
+	a.groups[id] = group
+	a.groupSizes[group]++
+}
+
+// leaveGroup removes the cron entry id from its group.
+func (a *cronJobsRunner) leaveGroup(id cron.EntryID) {
+	a.schedulesMu.Lock()
+	defer a.schedulesMu.Unlock()
+
+	group, ok := a.groups[id]
+	if !ok {
+		return
+	}
+	delete(a.groups, id)
+	if a.groupSizes[group]--; a.groupSizes[group] <= 0 {
+		delete(a.groupSizes, group)
+	}
+}
+
+// groupSize returns the number of cron entries in group.
+func (a *cronJobsRunner) groupSize(group string) int {
+	a.schedulesMu.Lock()
+	defer a.schedulesMu.Unlock()
+
+	return a.groupSizes[group]
+}
+
+// scheduleState returns the schedule state of ps. schedulesMu must be held.
+func (a *cronJobsRunner) scheduleState(ps *sourcesv1.PingSource) *scheduleState {
+	key := types.NamespacedName{Namespace: ps.Namespace, Name: ps.Name}
+	state, ok := a.schedules[key]
+	if !ok {
+		state = &scheduleState{}
+		a.schedules[key] = state
 	}
+	return state
 }
 
 func makeEvent(source *sourcesv1.PingSource) (cloudevents.Event, error) {
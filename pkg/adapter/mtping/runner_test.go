@@ -21,22 +21,34 @@ import (
 	"context"
 	"encoding/base64"
 	"reflect"
+	"sync"
 	"testing"
 	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/google/go-cmp/cmp"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientgotesting "k8s.io/client-go/testing"
 
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	kubeclient "knative.dev/pkg/client/injection/kube/client"
 	_ "knative.dev/pkg/client/injection/kube/client/fake"
 	"knative.dev/pkg/logging"
+	"knative.dev/pkg/ptr"
 	rectesting "knative.dev/pkg/reconciler/testing"
 
 	adaptertesting "knative.dev/eventing/pkg/adapter/v2/test"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	sourcesv1 "knative.dev/eventing/pkg/apis/sources/v1"
+	"knative.dev/eventing/pkg/channel/attributes"
+	eventingclient "knative.dev/eventing/pkg/client/injection/client"
+	_ "knative.dev/eventing/pkg/client/injection/client/fake"
 )
 
 const (
@@ -201,7 +213,7 @@ func TestAddRunRemoveSchedules(t *testing.T) {
 			logger := logging.FromContext(ctx)
 			ce := adaptertesting.NewTestClient()
 
-			runner := NewCronJobsRunner(ce, kubeclient.Get(ctx), logger)
+			runner := NewCronJobsRunner(ce, kubeclient.Get(ctx), eventingclient.Get(ctx), logger)
 			entryId := runner.AddSchedule(tc.src)
 
 			entry := runner.cron.Entry(entryId)
@@ -228,7 +240,7 @@ func TestStartStopCron(t *testing.T) {
 	logger := logging.FromContext(ctx)
 	ce := adaptertesting.NewTestClient()
 
-	runner := NewCronJobsRunner(ce, kubeclient.Get(ctx), logger)
+	runner := NewCronJobsRunner(ce, kubeclient.Get(ctx), eventingclient.Get(ctx), logger)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	wctx, wcancel := context.WithCancel(context.Background())
@@ -257,7 +269,7 @@ func TestStartStopCronDelayWait(t *testing.T) {
 	logger := logging.FromContext(ctx)
 	ce := adaptertesting.NewTestClientWithDelay(time.Second * 5)
 
-	runner := NewCronJobsRunner(ce, kubeclient.Get(ctx), logger)
+	runner := NewCronJobsRunner(ce, kubeclient.Get(ctx), eventingclient.Get(ctx), logger)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -296,6 +308,461 @@ func TestStartStopCronDelayWait(t *testing.T) {
 	validateSent(t, ce, []byte("some delayed data"), cloudevents.TextPlain, nil)
 }
 
+func TestCatchUpMissedSchedules(t *testing.T) {
+	ctx, _ := rectesting.SetupFakeContext(t)
+	logger := logging.FromContext(ctx)
+	ce := adaptertesting.NewTestClient()
+
+	now := time.Date(2021, 6, 1, 10, 30, 0, 0, time.UTC)
+	src := &sourcesv1.PingSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-name",
+			Namespace: "test-ns",
+		},
+		Spec: sourcesv1.PingSourceSpec{
+			SourceSpec: duckv1.SourceSpec{
+				CloudEventOverrides: &duckv1.CloudEventOverrides{},
+			},
+			Schedule:                "0 * * * *",
+			Timezone:                "UTC",
+			ContentType:             cloudevents.TextPlain,
+			Data:                    sampleData,
+			StartingDeadlineSeconds: ptr.Int64(3 * 60 * 60),
+		},
+		Status: sourcesv1.PingSourceStatus{
+			SourceStatus: duckv1.SourceStatus{
+				SinkURI: &apis.URL{Path: "a sink"},
+			},
+			LastScheduleTime: &metav1.Time{Time: now.Add(-210 * time.Minute)},
+		},
+	}
+	if _, err := eventingclient.Get(ctx).SourcesV1().PingSources(src.Namespace).Create(ctx, src, metav1.CreateOptions{}); err != nil {
+		t.Fatal("Failed to create the PingSource:", err)
+	}
+
+	runner := NewCronJobsRunner(ce, kubeclient.Get(ctx), eventingclient.Get(ctx), logger)
+	runner.now = func() time.Time { return now }
+	runner.AddSchedule(src)
+
+	// The schedule at 7:00 is outside of the starting deadline.
+	want := []time.Time{
+		time.Date(2021, 6, 1, 8, 0, 0, 0, time.UTC),
+		time.Date(2021, 6, 1, 9, 0, 0, 0, time.UTC),
+		time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC),
+	}
+	if err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		ps, err := eventingclient.Get(ctx).SourcesV1().PingSources(src.Namespace).Get(ctx, src.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return ps.Status.LastScheduleTime != nil && ps.Status.LastScheduleTime.Time.Equal(want[2]), nil
+	}); err != nil {
+		t.Fatal("The last schedule time was not recorded:", err)
+	}
+
+	sent := ce.Sent()
+	if len(sent) != len(want) {
+		t.Fatalf("Expected %d events to be sent, got %d", len(want), len(sent))
+	}
+	for i, event := range sent {
+		if !event.Time().Equal(want[i]) {
+			t.Errorf("Expected event %d with time %v, got %v", i, want[i], event.Time())
+		}
+	}
+
+	// Re-adding the schedule does not send the missed schedules again.
+	sched, err := newSchedule(src)
+	if err != nil {
+		t.Fatal("newSchedule() =", err)
+	}
+	if missed := runner.missedSchedules(src, sched); len(missed) != 0 {
+		t.Error("Expected no missed schedules, got", missed)
+	}
+}
+
+func TestNoCatchUpWithoutStartingDeadline(t *testing.T) {
+	ctx, _ := rectesting.SetupFakeContext(t)
+	logger := logging.FromContext(ctx)
+	ce := adaptertesting.NewTestClient()
+
+	runner := NewCronJobsRunner(ce, kubeclient.Get(ctx), eventingclient.Get(ctx), logger)
+	entryID := runner.AddSchedule(&sourcesv1.PingSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-name",
+			Namespace: "test-ns",
+		},
+		Spec: sourcesv1.PingSourceSpec{
+			Schedule: "* * * * *",
+		},
+		Status: sourcesv1.PingSourceStatus{
+			SourceStatus: duckv1.SourceStatus{
+				SinkURI: &apis.URL{Path: "a sink"},
+			},
+			LastScheduleTime: &metav1.Time{Time: time.Now().Add(-time.Hour)},
+		},
+	})
+	runner.cron.Entry(entryID).Job.Run()
+
+	if got := len(ce.Sent()); got != 1 {
+		t.Error("Expected 1 event to be sent, got", got)
+	}
+}
+
+func TestTemplatedData(t *testing.T) {
+	ctx, _ := rectesting.SetupFakeContext(t)
+	logger := logging.FromContext(ctx)
+	ce := adaptertesting.NewTestClient()
+
+	now := time.Date(2021, 6, 1, 10, 0, 0, 300*int(time.Millisecond), time.UTC)
+	runner := NewCronJobsRunner(ce, kubeclient.Get(ctx), eventingclient.Get(ctx), logger)
+	runner.now = func() time.Time { return now }
+
+	entryID := runner.AddSchedule(&sourcesv1.PingSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-name",
+			Namespace: "test-ns",
+		},
+		Spec: sourcesv1.PingSourceSpec{
+			Schedule:     "0 * * * *",
+			ContentType:  cloudevents.ApplicationJSON,
+			TemplateData: true,
+			Data:         `{"scheduled":"{{rfc3339 .ScheduledTime}}","sequence":{{.Sequence}},"source":"{{.Namespace}}/{{.Name}}"}`,
+		},
+		Status: sourcesv1.PingSourceStatus{
+			SourceStatus: duckv1.SourceStatus{
+				SinkURI: &apis.URL{Path: "a sink"},
+			},
+		},
+	})
+	entry := runner.cron.Entry(entryID)
+	entry.Job.Run()
+	now = now.Add(time.Hour)
+	entry.Job.Run()
+
+	want := []string{
+		`{"scheduled":"2021-06-01T10:00:00Z","sequence":1,"source":"test-ns/test-name"}`,
+		`{"scheduled":"2021-06-01T11:00:00Z","sequence":2,"source":"test-ns/test-name"}`,
+	}
+	sent := ce.Sent()
+	if len(sent) != len(want) {
+		t.Fatalf("Expected %d events to be sent, got %d", len(want), len(sent))
+	}
+	for i, event := range sent {
+		if got := string(event.Data()); got != want[i] {
+			t.Errorf("Expected event %d with data %s, got %s", i, want[i], got)
+		}
+		if got := event.DataContentType(); got != cloudevents.ApplicationJSON {
+			t.Errorf("Expected event %d with content type %s, got %s", i, cloudevents.ApplicationJSON, got)
+		}
+	}
+}
+
+func TestDeliveryRetriesAndDeadLetterSink(t *testing.T) {
+	ctx, _ := rectesting.SetupFakeContext(t)
+	logger := logging.FromContext(ctx)
+	ce := &deliveryClient{failures: map[string]int{"http://sink": 503}}
+
+	runner := NewCronJobsRunner(ce, kubeclient.Get(ctx), eventingclient.Get(ctx), logger)
+	linear := eventingduckv1.BackoffPolicyLinear
+	entryID := runner.AddSchedule(&sourcesv1.PingSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-name",
+			Namespace: "test-ns",
+		},
+		Spec: sourcesv1.PingSourceSpec{
+			Schedule: "* * * * *",
+			Delivery: &eventingduckv1.DeliverySpec{
+				Retry:         ptr.Int32(2),
+				BackoffPolicy: &linear,
+				BackoffDelay:  ptr.String("PT0.01S"),
+			},
+		},
+		Status: sourcesv1.PingSourceStatus{
+			SourceStatus: duckv1.SourceStatus{
+				SinkURI: apis.HTTP("sink"),
+			},
+			DeliveryStatus: eventingduckv1.DeliveryStatus{
+				DeadLetterSinkURI: apis.HTTP("dls"),
+			},
+		},
+	})
+	runner.cron.Entry(entryID).Job.Run()
+
+	want := []string{"http://sink", "http://sink", "http://sink", "http://dls"}
+	if diff := cmp.Diff(want, ce.targets); diff != "" {
+		t.Fatal("Unexpected targets (-want, +got):", diff)
+	}
+	dlsEvent := ce.events[3]
+	if got := dlsEvent.Extensions()[attributes.KnativeErrorDestExtensionKey]; got != "http://sink" {
+		t.Errorf("Expected %s extension http://sink, got %v", attributes.KnativeErrorDestExtensionKey, got)
+	}
+	if got := dlsEvent.Extensions()[attributes.KnativeErrorCodeExtensionKey]; got != int32(503) {
+		t.Errorf("Expected %s extension 503, got %v", attributes.KnativeErrorCodeExtensionKey, got)
+	}
+}
+
+// deliveryClient records the targets of the events it sends, and fails sending to some targets.
+type deliveryClient struct {
+	cloudevents.Client
+
+	mu       sync.Mutex
+	failures map[string]int
+	targets  []string
+	events   []cloudevents.Event
+}
+
+func (c *deliveryClient) Send(ctx context.Context, event cloudevents.Event) protocol.Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	target := cecontext.TargetFrom(ctx).String()
+	c.targets = append(c.targets, target)
+	c.events = append(c.events, event.Clone())
+	if code, ok := c.failures[target]; ok {
+		return cehttp.NewResult(code, "%w", protocol.ResultNACK)
+	}
+	return cehttp.NewResult(202, "%w", protocol.ResultACK)
+}
+
+func TestScheduleTimesInStatus(t *testing.T) {
+	ctx, _ := rectesting.SetupFakeContext(t)
+	logger := logging.FromContext(ctx)
+	ce := adaptertesting.NewTestClient()
+
+	now := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	src := &sourcesv1.PingSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-name",
+			Namespace: "test-ns",
+		},
+		Spec: sourcesv1.PingSourceSpec{
+			At: &metav1.Time{Time: now.Add(time.Hour)},
+		},
+		Status: sourcesv1.PingSourceStatus{
+			SourceStatus: duckv1.SourceStatus{
+				SinkURI: &apis.URL{Path: "a sink"},
+			},
+		},
+	}
+	pingSources := eventingclient.Get(ctx).SourcesV1().PingSources(src.Namespace)
+	if _, err := pingSources.Create(ctx, src, metav1.CreateOptions{}); err != nil {
+		t.Fatal("Failed to create the PingSource:", err)
+	}
+	getStatus := func() sourcesv1.PingSourceStatus {
+		ps, err := pingSources.Get(ctx, src.Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal("Failed to get the PingSource:", err)
+		}
+		return ps.Status
+	}
+
+	runner := NewCronJobsRunner(ce, kubeclient.Get(ctx), eventingclient.Get(ctx), logger)
+	runner.now = func() time.Time { return now }
+
+	entryID := runner.AddSchedule(src)
+	if status := getStatus(); status.NextScheduleTime == nil || !status.NextScheduleTime.Time.Equal(now.Add(time.Hour)) {
+		t.Errorf("Expected the next schedule time %v, got %v", now.Add(time.Hour), status.NextScheduleTime)
+	}
+
+	now = now.Add(time.Hour)
+	runner.cron.Entry(entryID).Job.Run()
+	status := getStatus()
+	if status.LastScheduleTime == nil || !status.LastScheduleTime.Time.Equal(now) {
+		t.Errorf("Expected the last schedule time %v, got %v", now, status.LastScheduleTime)
+	}
+	if status.NextScheduleTime != nil {
+		t.Error("Expected no next schedule time, got", status.NextScheduleTime)
+	}
+}
+
+func TestMissedOneShot(t *testing.T) {
+	ctx, _ := rectesting.SetupFakeContext(t)
+	logger := logging.FromContext(ctx)
+	ce := adaptertesting.NewTestClient()
+
+	now := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	src := &sourcesv1.PingSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-name",
+			Namespace: "test-ns",
+		},
+		Spec: sourcesv1.PingSourceSpec{
+			At: &metav1.Time{Time: now.Add(-time.Minute)},
+		},
+		Status: sourcesv1.PingSourceStatus{
+			SourceStatus: duckv1.SourceStatus{
+				SinkURI: &apis.URL{Path: "a sink"},
+			},
+		},
+	}
+	pingSources := eventingclient.Get(ctx).SourcesV1().PingSources(src.Namespace)
+	if _, err := pingSources.Create(ctx, src, metav1.CreateOptions{}); err != nil {
+		t.Fatal("Failed to create the PingSource:", err)
+	}
+
+	runner := NewCronJobsRunner(ce, kubeclient.Get(ctx), eventingclient.Get(ctx), logger)
+	runner.now = func() time.Time { return now }
+	runner.AddSchedule(src)
+
+	if err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		ps, err := pingSources.Get(ctx, src.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return ps.Status.LastScheduleTime != nil && ps.Status.LastScheduleTime.Time.Equal(src.Spec.At.Time), nil
+	}); err != nil {
+		t.Fatal("The last schedule time was not recorded:", err)
+	}
+	if got := len(ce.Sent()); got != 1 {
+		t.Error("Expected 1 event to be sent, got", got)
+	}
+
+	// Re-adding the schedule does not send the event again.
+	if missed := runner.missedOneShot(src); len(missed) != 0 {
+		t.Error("Expected no missed schedule, got", missed)
+	}
+}
+
+func TestOneShotNotResentWhenReadded(t *testing.T) {
+	ctx, _ := rectesting.SetupFakeContext(t)
+	logger := logging.FromContext(ctx)
+	ce := adaptertesting.NewTestClient()
+
+	now := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	src := &sourcesv1.PingSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-name",
+			Namespace: "test-ns",
+		},
+		Spec: sourcesv1.PingSourceSpec{
+			At: &metav1.Time{Time: now.Add(time.Hour)},
+		},
+		Status: sourcesv1.PingSourceStatus{
+			SourceStatus: duckv1.SourceStatus{
+				SinkURI: &apis.URL{Path: "a sink"},
+			},
+		},
+	}
+	if _, err := eventingclient.Get(ctx).SourcesV1().PingSources(src.Namespace).Create(ctx, src, metav1.CreateOptions{}); err != nil {
+		t.Fatal("Failed to create the PingSource:", err)
+	}
+
+	runner := NewCronJobsRunner(ce, kubeclient.Get(ctx), eventingclient.Get(ctx), logger)
+	runner.now = func() time.Time { return now }
+
+	entryID := runner.AddSchedule(src)
+	now = now.Add(time.Hour)
+	runner.cron.Entry(entryID).Job.Run()
+
+	// The schedule is re-added from a PingSource which does not have the last schedule time yet.
+	runner.RemoveSchedule(entryID)
+	runner.AddSchedule(src)
+
+	if err := wait.PollImmediate(10*time.Millisecond, 200*time.Millisecond, func() (bool, error) {
+		return len(ce.Sent()) > 1, nil
+	}); err == nil {
+		t.Error("Expected 1 event to be sent, got", len(ce.Sent()))
+	}
+}
+
+func TestScheduleTimesPatchedOnChange(t *testing.T) {
+	ctx, _ := rectesting.SetupFakeContext(t)
+	logger := logging.FromContext(ctx)
+	ce := adaptertesting.NewTestClient()
+
+	now := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	src := &sourcesv1.PingSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-name",
+			Namespace: "test-ns",
+		},
+		Spec: sourcesv1.PingSourceSpec{
+			Schedule: "0 * * * *",
+			Timezone: "UTC",
+		},
+		Status: sourcesv1.PingSourceStatus{
+			SourceStatus: duckv1.SourceStatus{
+				SinkURI: &apis.URL{Path: "a sink"},
+			},
+			NextScheduleTime: &metav1.Time{Time: now.Add(time.Hour)},
+		},
+	}
+	client := eventingclient.Get(ctx)
+	if _, err := client.SourcesV1().PingSources(src.Namespace).Create(ctx, src, metav1.CreateOptions{}); err != nil {
+		t.Fatal("Failed to create the PingSource:", err)
+	}
+	patches := func() int {
+		n := 0
+		for _, action := range client.(interface {
+			Actions() []clientgotesting.Action
+		}).Actions() {
+			if action.GetVerb() == "patch" {
+				n++
+			}
+		}
+		return n
+	}
+
+	runner := NewCronJobsRunner(ce, kubeclient.Get(ctx), client, logger)
+	runner.now = func() time.Time { return now }
+
+	entryID := runner.AddSchedule(src)
+	if got := patches(); got != 0 {
+		t.Error("Expected the unchanged next schedule time not to be patched, got patches:", got)
+	}
+
+	job := runner.cron.Entry(entryID).Job
+	now = now.Add(time.Hour)
+	job.Run()
+	// The same schedule is not recorded twice.
+	job.Run()
+	if got := patches(); got != 1 {
+		t.Error("Expected 1 patch, got", got)
+	}
+}
+
+func TestSuspend(t *testing.T) {
+	ctx, _ := rectesting.SetupFakeContext(t)
+	logger := logging.FromContext(ctx)
+	ce := adaptertesting.NewTestClient()
+
+	src := &sourcesv1.PingSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-name",
+			Namespace: "test-ns",
+		},
+		Spec: sourcesv1.PingSourceSpec{
+			Schedule: "* * * * *",
+			Suspend:  true,
+		},
+		Status: sourcesv1.PingSourceStatus{
+			SourceStatus: duckv1.SourceStatus{
+				SinkURI: &apis.URL{Path: "a sink"},
+			},
+			NextScheduleTime: &metav1.Time{Time: time.Now().Add(time.Minute)},
+		},
+	}
+	pingSources := eventingclient.Get(ctx).SourcesV1().PingSources(src.Namespace)
+	if _, err := pingSources.Create(ctx, src, metav1.CreateOptions{}); err != nil {
+		t.Fatal("Failed to create the PingSource:", err)
+	}
+
+	runner := NewCronJobsRunner(ce, kubeclient.Get(ctx), eventingclient.Get(ctx), logger)
+	if id := runner.AddSchedule(src); id != 0 {
+		t.Error("Expected no entry for a suspended PingSource, got", id)
+	}
+	if entries := runner.cron.Entries(); len(entries) != 0 {
+		t.Error("Expected no entries, got", entries)
+	}
+	ps, err := pingSources.Get(ctx, src.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal("Failed to get the PingSource:", err)
+	}
+	if ps.Status.NextScheduleTime != nil {
+		t.Error("Expected no next schedule time, got", ps.Status.NextScheduleTime)
+	}
+}
+
 func validateSent(t *testing.T, ce *adaptertesting.TestCloudEventsClient, wantData []byte, wantContentType string, extensions map[string]string) {
 	if got := len(ce.Sent()); got != 1 {
 		t.Error("Expected 1 event to be sent, got", got)
@@ -311,7 +778,20 @@ func validateSent(t *testing.T, ce *adaptertesting.TestCloudEventsClient, wantDa
 		t.Errorf("Expected %q event to be sent, got %q", wantData, got)
 	}
 
-	gotExtensions := event.Context.GetExtensions()
+	if _, err := event.Context.GetExtension(sourcesv1.PingSourceScheduledTimeExtension); err != nil {
+		t.Error("Expected event with the scheduled time extension, got:", err)
+	}
+
+	var gotExtensions map[string]interface{}
+	for k, v := range event.Context.GetExtensions() {
+		if k == sourcesv1.PingSourceScheduledTimeExtension {
+			continue
+		}
+		if gotExtensions == nil {
+			gotExtensions = make(map[string]interface{})
+		}
+		gotExtensions[k] = v
+	}
 
 	if extensions == nil && gotExtensions != nil {
 		t.Error("Expected event with no extension overrides, got:", gotExtensions)
@@ -0,0 +1,84 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mtping
+
+import (
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	sourcesv1 "knative.dev/eventing/pkg/apis/sources/v1"
+)
+
+// newSchedule returns the schedule of source: a one-shot schedule, or its cron schedule
+// restricted to its time window.
+func newSchedule(source *sourcesv1.PingSource) (cron.Schedule, error) {
+	if source.Spec.At != nil {
+		return oneShotSchedule(source.Spec.At.Time), nil
+	}
+
+	schedule := source.Spec.Schedule
+	if source.Spec.Timezone != "" {
+		schedule = "CRON_TZ=" + source.Spec.Timezone + " " + schedule
+	}
+	sched, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	if source.Spec.StartTime == nil && source.Spec.EndTime == nil {
+		return sched, nil
+	}
+	w := &windowSchedule{schedule: sched}
+	if source.Spec.StartTime != nil {
+		w.start = source.Spec.StartTime.Time
+	}
+	if source.Spec.EndTime != nil {
+		w.end = source.Spec.EndTime.Time
+	}
+	return w, nil
+}
+
+// oneShotSchedule fires once, at the given time.
+type oneShotSchedule time.Time
+
+// Next implements cron.Schedule
+func (s oneShotSchedule) Next(t time.Time) time.Time {
+	if at := time.Time(s); at.After(t) {
+		return at
+	}
+	return time.Time{}
+}
+
+// windowSchedule restricts a schedule to the times between start and end, when set.
+type windowSchedule struct {
+	schedule   cron.Schedule
+	start, end time.Time
+}
+
+// Next implements cron.Schedule
+func (s *windowSchedule) Next(t time.Time) time.Time {
+	if !s.start.IsZero() && t.Before(s.start) {
+		// Next returns times after t, and the schedule may fire at start.
+		t = s.start.Add(-time.Second)
+	}
+	next := s.schedule.Next(t)
+	if !s.end.IsZero() && next.After(s.end) {
+		return time.Time{}
+	}
+	return next
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mtping
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	sourcesv1 "knative.dev/eventing/pkg/apis/sources/v1"
+)
+
+func TestNewSchedule(t *testing.T) {
+	day := func(d, h int) time.Time {
+		return time.Date(2021, 6, d, h, 0, 0, 0, time.UTC)
+	}
+	metaTime := func(t time.Time) *metav1.Time {
+		return &metav1.Time{Time: t}
+	}
+
+	tests := map[string]struct {
+		spec sourcesv1.PingSourceSpec
+		from time.Time
+		want time.Time
+	}{
+		"cron": {
+			spec: sourcesv1.PingSourceSpec{Schedule: "0 12 * * *", Timezone: "UTC"},
+			from: day(1, 13),
+			want: day(2, 12),
+		},
+		"before start": {
+			spec: sourcesv1.PingSourceSpec{Schedule: "0 * * * *", Timezone: "UTC", StartTime: metaTime(day(3, 5))},
+			from: day(1, 0),
+			want: day(3, 5),
+		},
+		"within window": {
+			spec: sourcesv1.PingSourceSpec{Schedule: "0 * * * *", Timezone: "UTC", StartTime: metaTime(day(1, 0)), EndTime: metaTime(day(2, 0))},
+			from: day(1, 10),
+			want: day(1, 11),
+		},
+		"last in window": {
+			spec: sourcesv1.PingSourceSpec{Schedule: "0 * * * *", Timezone: "UTC", EndTime: metaTime(day(2, 0))},
+			from: day(1, 23),
+			want: day(2, 0),
+		},
+		"after end": {
+			spec: sourcesv1.PingSourceSpec{Schedule: "0 * * * *", Timezone: "UTC", EndTime: metaTime(day(2, 0))},
+			from: day(2, 0),
+		},
+		"one-shot": {
+			spec: sourcesv1.PingSourceSpec{At: metaTime(day(3, 5))},
+			from: day(1, 0),
+			want: day(3, 5),
+		},
+		"one-shot fired": {
+			spec: sourcesv1.PingSourceSpec{At: metaTime(day(3, 5))},
+			from: day(3, 5),
+		},
+	}
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			sched, err := newSchedule(&sourcesv1.PingSource{Spec: tc.spec})
+			if err != nil {
+				t.Fatal("newSchedule() =", err)
+			}
+			if got := sched.Next(tc.from); !got.Equal(tc.want) {
+				t.Errorf("Next(%v) = %v, want %v", tc.from, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	nethttp "net/http"
+	"strings"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/protocol/http"
+)
+
+const (
+	// batchContentType is the media type of the CloudEvents JSON batch format.
+	batchContentType = "application/cloudevents-batch+json"
+
+	// batchProbeInterval is how long a sink found not to support batches is
+	// sent single events before it is probed again.
+	batchProbeInterval = 5 * time.Minute
+)
+
+// batcher groups the events sent to the same target into batches, sent in
+// the CloudEvents batch format to sinks advertising support for it in the
+// Accept or Accept-Post headers of their OPTIONS response. Events sent to
+// other sinks are sent on their own.
+//
+// A batch outlives the Send calls it groups, so it is sent with the retries
+// and the request timeout of the delivery spec only: the SDK retries and the
+// deadlines set on the contexts of the calls don't apply to it.
+type batcher struct {
+	client *client
+	http   *nethttp.Client
+	config BatchConfig
+
+	mu      sync.Mutex
+	batches map[string]*batch
+	support map[string]batchSupport
+}
+
+type batchSupport struct {
+	supported bool
+	checked   time.Time
+}
+
+// batch is the pending events to a target.
+type batch struct {
+	target  string
+	entries []batchEntry
+	bytes   int
+	timer   *time.Timer
+}
+
+type batchEntry struct {
+	ctx    context.Context
+	event  event.Event
+	raw    []byte
+	result chan protocol.Result
+}
+
+func newBatcher(c *client, httpClient *nethttp.Client, config BatchConfig) *batcher {
+	return &batcher{
+		client:  c,
+		http:    httpClient,
+		config:  config,
+		batches: make(map[string]*batch),
+		support: make(map[string]batchSupport),
+	}
+}
+
+// send adds event to the batch of its target and returns the result of the
+// batch once it is sent, or the error of ctx once it is done. The event is
+// removed from its batch when ctx is done before the batch is sent.
+func (b *batcher) send(ctx context.Context, out event.Event) protocol.Result {
+	target := b.client.target
+	if t := cloudevents.TargetFromContext(ctx); t != nil {
+		target = t.String()
+	}
+	raw, err := json.Marshal(out)
+	if target == "" || err != nil || (b.config.MaxBytes > 0 && len(raw)+2 > b.config.MaxBytes) {
+		return b.client.sendSingle(ctx, out)
+	}
+
+	entry := batchEntry{ctx: ctx, event: out, raw: raw, result: make(chan protocol.Result, 1)}
+	b.mu.Lock()
+	bt := b.batches[target]
+	if bt != nil && b.config.MaxBytes > 0 && bt.bytes+len(raw)+1 > b.config.MaxBytes {
+		b.flushLocked(bt)
+		bt = nil
+	}
+	if bt == nil {
+		bt = &batch{target: target, bytes: 2}
+		b.batches[target] = bt
+		bt.timer = time.AfterFunc(b.config.Linger, func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			if b.batches[target] == bt {
+				b.flushLocked(bt)
+			}
+		})
+	}
+	bt.entries = append(bt.entries, entry)
+	bt.bytes += len(raw) + 1
+	if len(bt.entries) >= b.config.MaxEvents {
+		b.flushLocked(bt)
+	}
+	b.mu.Unlock()
+
+	select {
+	case res := <-entry.result:
+		return res
+	case <-ctx.Done():
+		b.cancel(bt, entry)
+		return ctx.Err()
+	}
+}
+
+// cancel removes entry from bt, unless bt is being sent.
+func (b *batcher) cancel(bt *batch, entry batchEntry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.batches[bt.target] != bt {
+		return
+	}
+	for i, e := range bt.entries {
+		if e.result == entry.result {
+			bt.entries = append(bt.entries[:i], bt.entries[i+1:]...)
+			bt.bytes -= len(e.raw) + 1
+			break
+		}
+	}
+	if len(bt.entries) == 0 {
+		delete(b.batches, bt.target)
+		bt.timer.Stop()
+	}
+}
+
+// flushLocked sends bt in the background. b.mu must be held.
+func (b *batcher) flushLocked(bt *batch) {
+	delete(b.batches, bt.target)
+	bt.timer.Stop()
+	go b.deliver(bt)
+}
+
+// deliver sends the events of bt and hands each of them its result.
+func (b *batcher) deliver(bt *batch) {
+	// The batch outlives the Send calls it groups, only keep their metric tag.
+	ctx := ContextWithMetricTag(context.Background(), MetricTagFromContext(bt.entries[0].ctx))
+	if len(bt.entries) == 1 || !b.supported(ctx, bt.target) {
+		b.deliverSingles(bt)
+		return
+	}
+
+	body := make([]byte, 0, bt.bytes)
+	body = append(body, '[')
+	for i, e := range bt.entries {
+		if i > 0 {
+			body = append(body, ',')
+		}
+		body = append(body, e.raw...)
+	}
+	body = append(body, ']')
+
+	res := b.client.withRetries(ctx, func(ctx context.Context) protocol.Result {
+		return b.post(ctx, bt.target, body)
+	}, func(res protocol.Result) bool {
+		return !isUnsupportedMediaType(res)
+	})
+	if isUnsupportedMediaType(res) {
+		b.mu.Lock()
+		b.support[bt.target] = batchSupport{supported: false, checked: time.Now()}
+		b.mu.Unlock()
+		b.deliverSingles(bt)
+		return
+	}
+
+	for _, e := range bt.entries {
+		b.client.reportMetrics(e.ctx, e.event, res)
+		if cloudevents.IsACK(res) || b.client.deadLetterSink == "" {
+			e.result <- res
+			continue
+		}
+		e.result <- b.client.sendToDeadLetterSink(e.ctx, e.event, res)
+	}
+}
+
+// deliverSingles sends the events of bt on their own.
+func (b *batcher) deliverSingles(bt *batch) {
+	var wg sync.WaitGroup
+	for _, e := range bt.entries {
+		e := e
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e.result <- b.client.sendSingle(cloudevents.ContextWithTarget(e.ctx, bt.target), e.event)
+		}()
+	}
+	wg.Wait()
+}
+
+// post sends body to target in the batch format.
+func (b *batcher) post(ctx context.Context, target string, body []byte) protocol.Result {
+	req, err := nethttp.NewRequestWithContext(ctx, nethttp.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", batchContentType)
+	resp, err := b.http.Do(req)
+	if err != nil {
+		return err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		return http.NewResult(resp.StatusCode, "%w", protocol.ResultACK)
+	}
+	return http.NewResult(resp.StatusCode, "%w", protocol.ResultNACK)
+}
+
+// supported reports whether target advertises support for batches, probing
+// it when it was not yet or not recently.
+func (b *batcher) supported(ctx context.Context, target string) bool {
+	b.mu.Lock()
+	s, ok := b.support[target]
+	b.mu.Unlock()
+	if ok && (s.supported || time.Since(s.checked) < batchProbeInterval) {
+		return s.supported
+	}
+
+	s = batchSupport{supported: b.probe(ctx, target), checked: time.Now()}
+	b.mu.Lock()
+	b.support[target] = s
+	b.mu.Unlock()
+	return s.supported
+}
+
+func (b *batcher) probe(ctx context.Context, target string) bool {
+	req, err := nethttp.NewRequestWithContext(ctx, nethttp.MethodOptions, target, nil)
+	if err != nil {
+		return false
+	}
+	resp, err := b.http.Do(req)
+	if err != nil {
+		return false
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	for _, header := range []string{"Accept", "Accept-Post"} {
+		for _, v := range resp.Header.Values(header) {
+			for _, mediaType := range strings.Split(v, ",") {
+				if strings.EqualFold(strings.TrimSpace(strings.Split(mediaType, ";")[0]), batchContentType) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func isUnsupportedMediaType(res protocol.Result) bool {
+	var hres *http.Result
+	return cloudevents.ResultAs(res, &hres) && hres.StatusCode == nethttp.StatusUnsupportedMediaType
+}
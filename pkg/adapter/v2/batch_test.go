@@ -0,0 +1,200 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	cloudeventsobsclient "github.com/cloudevents/sdk-go/observability/opencensus/v2/client"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+// batchSink records the size of the batches and single events it receives.
+type batchSink struct {
+	advertise   bool
+	rejectBatch bool
+
+	mu      sync.Mutex
+	batches []int
+	singles int
+}
+
+func (s *batchSink) ServeHTTP(w nethttp.ResponseWriter, r *nethttp.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r.Method == nethttp.MethodOptions {
+		if s.advertise {
+			w.Header().Set("Accept", "application/cloudevents+json, application/cloudevents-batch+json")
+		}
+		w.WriteHeader(nethttp.StatusOK)
+		return
+	}
+	if r.Header.Get("Content-Type") == batchContentType {
+		if s.rejectBatch {
+			w.WriteHeader(nethttp.StatusUnsupportedMediaType)
+			return
+		}
+		var events []json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&events); err != nil {
+			w.WriteHeader(nethttp.StatusBadRequest)
+			return
+		}
+		s.batches = append(s.batches, len(events))
+	} else {
+		s.singles++
+	}
+	w.WriteHeader(nethttp.StatusAccepted)
+}
+
+func TestNewCloudEventsClient_batching(t *testing.T) {
+	tests := []struct {
+		name        string
+		advertise   bool
+		rejectBatch bool
+		wantBatches []int
+		wantSingles int
+	}{{
+		name:        "batches to a supporting sink",
+		advertise:   true,
+		wantBatches: []int{5},
+	}, {
+		name:        "singles to other sinks",
+		wantSingles: 5,
+	}, {
+		name:        "singles when the sink rejects batches",
+		advertise:   true,
+		rejectBatch: true,
+		wantSingles: 5,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sink := &batchSink{advertise: test.advertise, rejectBatch: test.rejectBatch}
+			server := httptest.NewServer(sink)
+			defer server.Close()
+
+			newClientHTTPObserved = cloudeventsobsclient.NewClientHTTP
+			reporter := &mockReporter{}
+			c, err := NewCloudEventsClientCRStatus(&EnvConfig{
+				Sink:           server.URL,
+				BatchMaxEvents: 5,
+				BatchMaxBytes:  1 << 20,
+				BatchLinger:    time.Minute,
+			}, reporter, nil)
+			if err != nil {
+				t.Fatal("NewCloudEventsClientCRStatus() =", err)
+			}
+
+			var wg sync.WaitGroup
+			for i := 0; i < 5; i++ {
+				i := i
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					event := cloudevents.NewEvent()
+					event.SetID(strconv.Itoa(i))
+					event.SetType("unit.test")
+					event.SetSource("unit/test")
+					if res := c.Send(context.Background(), event); !cloudevents.IsACK(res) {
+						t.Error("Send() =", res)
+					}
+				}()
+			}
+			wg.Wait()
+
+			sink.mu.Lock()
+			defer sink.mu.Unlock()
+			if len(sink.batches) != len(test.wantBatches) || (len(sink.batches) > 0 && sink.batches[0] != test.wantBatches[0]) {
+				t.Errorf("batches = %v, want %v", sink.batches, test.wantBatches)
+			}
+			if sink.singles != test.wantSingles {
+				t.Errorf("singles = %d, want %d", sink.singles, test.wantSingles)
+			}
+		})
+	}
+}
+
+func TestBatchLinger(t *testing.T) {
+	sink := &batchSink{advertise: true}
+	server := httptest.NewServer(sink)
+	defer server.Close()
+
+	newClientHTTPObserved = cloudeventsobsclient.NewClientHTTP
+	c, err := NewCloudEventsClientCRStatus(&EnvConfig{
+		Sink:           server.URL,
+		BatchMaxEvents: 100,
+		BatchLinger:    10 * time.Millisecond,
+	}, &mockReporter{}, nil)
+	if err != nil {
+		t.Fatal("NewCloudEventsClientCRStatus() =", err)
+	}
+
+	// A lone event is sent once the batch lingered.
+	event := cloudevents.NewEvent()
+	event.SetID("1")
+	event.SetType("unit.test")
+	event.SetSource("unit/test")
+	if res := c.Send(context.Background(), event); !cloudevents.IsACK(res) {
+		t.Error("Send() =", res)
+	}
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	if sink.singles != 1 {
+		t.Errorf("singles = %d, want 1", sink.singles)
+	}
+}
+
+func TestBatchSendCanceled(t *testing.T) {
+	sink := &batchSink{advertise: true}
+	server := httptest.NewServer(sink)
+	defer server.Close()
+
+	newClientHTTPObserved = cloudeventsobsclient.NewClientHTTP
+	c, err := NewCloudEventsClientCRStatus(&EnvConfig{
+		Sink:           server.URL,
+		BatchMaxEvents: 100,
+		BatchLinger:    time.Hour,
+	}, &mockReporter{}, nil)
+	if err != nil {
+		t.Fatal("NewCloudEventsClientCRStatus() =", err)
+	}
+
+	// The event of a Send call whose context is done is not sent.
+	event := cloudevents.NewEvent()
+	event.SetID("1")
+	event.SetType("unit.test")
+	event.SetSource("unit/test")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if res := c.Send(ctx, event); !errors.Is(res, context.DeadlineExceeded) {
+		t.Errorf("Send() = %v, want %v", res, context.DeadlineExceeded)
+	}
+
+	b := c.(*client).batcher
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.batches) != 0 {
+		t.Errorf("pending batches = %d, want 0", len(b.batches))
+	}
+}
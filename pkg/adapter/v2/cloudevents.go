@@ -21,6 +21,7 @@ import (
 	"fmt"
 	nethttp "net/http"
 	"net/url"
+	"sync"
 	"time"
 
 	cloudeventsobsclient "github.com/cloudevents/sdk-go/observability/opencensus/v2/client"
@@ -30,6 +31,8 @@ import (
 	"github.com/cloudevents/sdk-go/v2/protocol/http"
 	"go.opencensus.io/plugin/ochttp"
 	"knative.dev/eventing/pkg/adapter/v2/util/crstatusevent"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/eventing/pkg/kncloudevents"
 	"knative.dev/eventing/pkg/metrics/source"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/tracing/propagation/tracecontextb3"
@@ -59,19 +62,39 @@ func NewCloudEventsClientCRStatus(env EnvConfigAccessor, reporter source.StatsRe
 func newCloudEventsClientCRStatus(env EnvConfigAccessor, ceOverrides *duckv1.CloudEventOverrides, reporter source.StatsReporter,
 	crStatusEventClient *crstatusevent.CRStatusEventClient, opts ...http.Option) (cloudevents.Client, error) {
 
-	pOpts := make([]http.Option, 0)
-	pOpts = append(pOpts, cloudevents.WithRoundTripper(&ochttp.Transport{
+	var files SinkFiles
+	if fa, ok := env.(SinkFilesAccessor); ok {
+		files = fa.GetSinkFiles()
+	}
+	transport := &ochttp.Transport{
 		Propagation: tracecontextb3.TraceContextEgress,
-	}))
+	}
+	if files.CACerts != "" || files.Token != "" {
+		base, err := newSinkTransport(files)
+		if err != nil {
+			return nil, err
+		}
+		transport.Base = base
+	}
+
+	pOpts := make([]http.Option, 0)
+	pOpts = append(pOpts, cloudevents.WithRoundTripper(transport))
 
+	var target string
+	var delivery *eventingduckv1.DeliverySpec
 	if env != nil {
-		if target := env.GetSink(); len(target) > 0 {
+		if target = env.GetSink(); len(target) > 0 {
 			pOpts = append(pOpts, cloudevents.WithTarget(target))
 		}
+		var err error
+		if da, ok := env.(DeliveryAccessor); ok {
+			if delivery, err = da.GetDelivery(); err != nil {
+				return nil, fmt.Errorf("failed to parse the delivery spec: %w", err)
+			}
+		}
 		if sinkWait := env.GetSinktimeout(); sinkWait > 0 {
 			pOpts = append(pOpts, setTimeOut(time.Duration(sinkWait)*time.Second))
 		}
-		var err error
 		if ceOverrides == nil {
 			ceOverrides, err = env.GetCloudEventOverrides()
 			if err != nil {
@@ -91,12 +114,39 @@ func newCloudEventsClientCRStatus(env EnvConfigAccessor, ceOverrides *duckv1.Clo
 	if err != nil {
 		return nil, err
 	}
-	return &client{
+	c := &client{
 		ceClient:            ceClient,
 		ceOverrides:         ceOverrides,
 		reporter:            reporter,
 		crStatusEventClient: *crStatusEventClient,
-	}, nil
+		target:              target,
+	}
+	if delivery != nil {
+		retryConfig, err := kncloudevents.RetryConfigFromDeliverySpec(*delivery)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the delivery spec: %w", err)
+		}
+		c.retryConfig = &retryConfig
+		if delivery.DeadLetterSink != nil {
+			c.deadLetterSink = delivery.DeadLetterSink.URI.String()
+		}
+	}
+	if files.Sink != "" {
+		c.sinkFile = newFileValue(files.Sink)
+	}
+	if ba, ok := env.(BatchConfigAccessor); ok {
+		if config := ba.GetBatchConfig(); config.MaxEvents > 1 {
+			httpClient := &nethttp.Client{Transport: transport}
+			if sinkWait := env.GetSinktimeout(); sinkWait > 0 {
+				httpClient.Timeout = time.Duration(sinkWait) * time.Second
+			}
+			c.batcher = newBatcher(c, httpClient, config)
+		}
+	}
+	if files.CEOverrides != "" {
+		c.ceOverridesFile = newFileValue(files.CEOverrides)
+	}
+	return c, nil
 }
 
 func setTimeOut(duration time.Duration) http.Option {
@@ -114,23 +164,52 @@ func setTimeOut(duration time.Duration) http.Option {
 
 type client struct {
 	ceClient            cloudevents.Client
-	ceOverrides         *duckv1.CloudEventOverrides
 	reporter            source.StatsReporter
 	crStatusEventClient crstatusevent.CRStatusEventClient
+
+	// sinkFile and ceOverridesFile are set when a SinkBinding projects the
+	// sink as files, they are re-read as the files change.
+	sinkFile        *fileValue
+	ceOverridesFile *fileValue
+
+	// retryConfig and deadLetterSink are set from the delivery spec of the
+	// adapter, target is the sink it was created with.
+	retryConfig    *kncloudevents.RetryConfig
+	batcher        *batcher
+	deadLetterSink string
+	target         string
+
+	mu             sync.RWMutex
+	ceOverrides    *duckv1.CloudEventOverrides
+	ceOverridesRaw string
 }
 
 var _ cloudevents.Client = (*client)(nil)
 
 // Send implements client.Send
 func (c *client) Send(ctx context.Context, out event.Event) protocol.Result {
+	ctx = c.refreshSinkFiles(ctx)
 	c.applyOverrides(&out)
-	res := c.ceClient.Send(ctx, out)
+	if c.batcher != nil {
+		return c.batcher.send(ctx, out)
+	}
+	return c.sendSingle(ctx, out)
+}
+
+// sendSingle sends event on its own, with retries and dead lettering.
+func (c *client) sendSingle(ctx context.Context, out event.Event) protocol.Result {
+	res := c.sendWithRetries(ctx, out)
 	c.reportMetrics(ctx, out, res)
-	return res
+	if cloudevents.IsACK(res) || c.deadLetterSink == "" {
+		return res
+	}
+	// The result of a dead lettered event is the one of the dead letter sink.
+	return c.sendToDeadLetterSink(ctx, out, res)
 }
 
 // Request implements client.Request
 func (c *client) Request(ctx context.Context, out event.Event) (*event.Event, protocol.Result) {
+	ctx = c.refreshSinkFiles(ctx)
 	c.applyOverrides(&out)
 	resp, res := c.ceClient.Request(ctx, out)
 	c.reportMetrics(ctx, out, res)
@@ -142,7 +221,33 @@ func (c *client) StartReceiver(ctx context.Context, fn interface{}) error {
 	return c.ceClient.StartReceiver(ctx, fn)
 }
 
+// refreshSinkFiles picks up changes of the projected sink files. The sink is
+// set as the target of ctx unless the caller already set one.
+func (c *client) refreshSinkFiles(ctx context.Context) context.Context {
+	if c.sinkFile != nil && cloudevents.TargetFromContext(ctx) == nil {
+		if sink, _ := c.sinkFile.Get(); sink != "" {
+			ctx = cloudevents.ContextWithTarget(ctx, sink)
+		}
+	}
+	if c.ceOverridesFile != nil {
+		raw, _ := c.ceOverridesFile.Get()
+		c.mu.RLock()
+		changed := raw != c.ceOverridesRaw
+		c.mu.RUnlock()
+		if changed {
+			if ceOverrides, err := parseCloudEventOverrides(raw); err == nil {
+				c.mu.Lock()
+				c.ceOverrides, c.ceOverridesRaw = ceOverrides, raw
+				c.mu.Unlock()
+			}
+		}
+	}
+	return ctx
+}
+
 func (c *client) applyOverrides(event *cloudevents.Event) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	if c.ceOverrides != nil && c.ceOverrides.Extensions != nil {
 		for n, v := range c.ceOverrides.Extensions {
 			event.SetExtension(n, v)
@@ -18,22 +18,29 @@ package adapter
 
 import (
 	"context"
+	"net/http/httptest"
 	"os"
 	"strconv"
+	"sync"
 	"testing"
 	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	v2client "github.com/cloudevents/sdk-go/v2/client"
 	"github.com/cloudevents/sdk-go/v2/protocol/http"
+	"go.uber.org/zap"
 	"knative.dev/eventing/pkg/adapter/v2/test"
 	"knative.dev/eventing/pkg/metrics/source"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
+	kle "knative.dev/pkg/leaderelection"
+	"knative.dev/pkg/metrics"
 )
 
 type mockReporter struct {
-	eventCount      int
-	retryEventCount int
+	mu                sync.Mutex
+	eventCount        int
+	retryEventCount   int
+	droppedEventCount int
 }
 
 var (
@@ -41,15 +48,26 @@ var (
 )
 
 func (r *mockReporter) ReportEventCount(args *source.ReportArgs, responseCode int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.eventCount++
 	return nil
 }
 
 func (r *mockReporter) ReportRetryEventCount(args *source.ReportArgs, responseCode int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.retryEventCount++
 	return nil
 }
 
+func (r *mockReporter) ReportDroppedEventCount(args *source.ReportArgs) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.droppedEventCount++
+	return nil
+}
+
 func TestNewCloudEventsClient_send(t *testing.T) {
 	demoEvent := func() *cloudevents.Event {
 		event := cloudevents.NewEvent()
@@ -318,3 +336,49 @@ func validateMetric(t *testing.T, reporter source.StatsReporter, want int, wantR
 		t.Errorf("Expected %d for metric, got %d", want, mockReporter.retryEventCount)
 	}
 }
+
+// minimalEnv is an EnvConfigAccessor implementing none of the optional
+// accessors, like the ones of adapters that don't embed EnvConfig.
+type minimalEnv struct {
+	sink string
+}
+
+func (e *minimalEnv) SetComponent(string)                                 {}
+func (e *minimalEnv) GetSink() string                                     { return e.sink }
+func (e *minimalEnv) GetNamespace() string                                { return "ns" }
+func (e *minimalEnv) GetName() string                                     { return "name" }
+func (e *minimalEnv) GetMetricsConfig() (*metrics.ExporterOptions, error) { return nil, nil }
+func (e *minimalEnv) GetLogger() *zap.SugaredLogger                       { return zap.NewNop().Sugar() }
+func (e *minimalEnv) SetupTracing(*zap.SugaredLogger) error               { return nil }
+func (e *minimalEnv) GetCloudEventOverrides() (*duckv1.CloudEventOverrides, error) {
+	return nil, nil
+}
+func (e *minimalEnv) GetLeaderElectionConfig() (*kle.ComponentConfig, error) { return nil, nil }
+func (e *minimalEnv) GetSinktimeout() int                                    { return -1 }
+
+func TestNewCloudEventsClient_minimalEnv(t *testing.T) {
+	sink := &recordingSink{}
+	server := httptest.NewServer(sink)
+	defer server.Close()
+	env := &minimalEnv{sink: server.URL}
+	reporter := &mockReporter{}
+
+	c, err := NewCloudEventsClientCRStatus(env, reporter, nil)
+	if err != nil {
+		t.Fatal("NewCloudEventsClientCRStatus() =", err)
+	}
+	if c, err = WrapClient(context.Background(), env, c, reporter); err != nil {
+		t.Fatal("WrapClient() =", err)
+	}
+
+	event := cloudevents.NewEvent()
+	event.SetID("abc-123")
+	event.SetSource("unit/test")
+	event.SetType("unit.type")
+	if result := c.Send(context.Background(), event); !cloudevents.IsACK(result) {
+		t.Fatal("Send() =", result)
+	}
+	if got := len(sink.requests()); got != 1 {
+		t.Errorf("sink got %d requests, want 1", got)
+	}
+}
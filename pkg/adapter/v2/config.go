@@ -17,12 +17,14 @@ package adapter
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"strconv"
 	"time"
 
 	"go.uber.org/zap"
 
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	kle "knative.dev/pkg/leaderelection"
 	"knative.dev/pkg/logging"
@@ -45,6 +47,16 @@ const (
 	EnvConfigTracingConfig        = "K_TRACING_CONFIG"
 	EnvConfigLeaderElectionConfig = "K_LEADER_ELECTION_CONFIG"
 	EnvSinkTimeout                = "K_SINK_TIMEOUT"
+	EnvConfigSinkFile             = "K_SINK_FILE"
+	EnvConfigCEOverridesFile      = "K_CE_OVERRIDES_FILE"
+	EnvConfigCACertsFile          = "K_CA_CERTS_FILE"
+	EnvConfigSinkTokenFile        = "K_SINK_TOKEN_FILE"
+	EnvConfigDelivery             = "K_DELIVERY"
+	EnvConfigOutboxDir            = "K_OUTBOX_DIR"
+	EnvConfigOutboxMaxEvents      = "K_OUTBOX_MAX_EVENTS"
+	EnvConfigBatchMaxEvents       = "K_BATCH_MAX_EVENTS"
+	EnvConfigBatchMaxBytes        = "K_BATCH_MAX_BYTES"
+	EnvConfigBatchLinger          = "K_BATCH_LINGER"
 )
 
 // EnvConfig is the minimal set of configuration parameters
@@ -68,6 +80,21 @@ type EnvConfig struct {
 	// CEOverrides are the CloudEvents overrides to be applied to the outbound event.
 	CEOverrides string `envconfig:"K_CE_OVERRIDES"`
 
+	// SinkFile is the path of a file holding the URI messages will be sent
+	// to. It is read when Sink is unset and re-read when it changes.
+	SinkFile string `envconfig:"K_SINK_FILE"`
+
+	// CEOverridesFile is the path of a file holding the CloudEvents overrides.
+	// It is read when CEOverrides is unset and re-read when it changes.
+	CEOverridesFile string `envconfig:"K_CE_OVERRIDES_FILE"`
+
+	// CACertsFile is the path of a PEM encoded CA bundle to trust when
+	// sending to the sink.
+	CACertsFile string `envconfig:"K_CA_CERTS_FILE"`
+
+	// SinkTokenFile is the path of a token sent as a bearer token to the sink.
+	SinkTokenFile string `envconfig:"K_SINK_TOKEN_FILE"`
+
 	// MetricsConfigJson is a json string of metrics.ExporterOptions.
 	// This is used to configure the metrics exporter options,
 	// the config is stored in a config map inside the controllers
@@ -91,6 +118,50 @@ type EnvConfig struct {
 	// Time in seconds to wait for sink to respond
 	EnvSinkTimeout string `envconfig:"K_SINK_TIMEOUT"`
 
+	// DeliveryJson is a json string of an eventing duck/v1 DeliverySpec
+	// configuring the retries and the dead letter sink of outbound events.
+	// The dead letter sink must be resolved to an absolute URI. Its retries
+	// replace the ones set on the contexts of the sent events.
+	DeliveryJson string `envconfig:"K_DELIVERY"`
+
+	// HealthPort is the port serving the adapter health endpoints. The
+	// endpoints are disabled when it is unset.
+	HealthPort int `envconfig:"K_HEALTH_PORT"`
+
+	// OutboxDir is the directory of a local durable outbox. When set, sent
+	// events are stored in the outbox and delivered to the sink in the
+	// background, surviving sink outages and adapter restarts.
+	OutboxDir string `envconfig:"K_OUTBOX_DIR"`
+
+	// OutboxMaxEvents is the number of events the outbox holds before Send
+	// fails, 0 for no limit.
+	OutboxMaxEvents int `envconfig:"K_OUTBOX_MAX_EVENTS" default:"10000"`
+
+	// OutboxMaxAttempts is the number of times the outbox tries to send an
+	// event before it drops it, 0 for no limit.
+	OutboxMaxAttempts int `envconfig:"K_OUTBOX_MAX_ATTEMPTS"`
+
+	// OutboxMaxAge is how long the outbox tries to send an event before it
+	// drops it, 0 for no limit.
+	OutboxMaxAge time.Duration `envconfig:"K_OUTBOX_MAX_AGE" default:"24h"`
+
+	// OutboxConcurrency is the number of events the outbox sends at once.
+	// Events are only delivered in order when it is 1.
+	OutboxConcurrency int `envconfig:"K_OUTBOX_CONCURRENCY" default:"1"`
+
+	// BatchMaxEvents is the number of events sent together in a batch to
+	// sinks supporting the CloudEvents batch format. Batching is disabled
+	// unless it is greater than 1. The batches are retried as configured by
+	// DeliveryJson, regardless of the retries and the deadlines set on the
+	// contexts of the sent events.
+	BatchMaxEvents int `envconfig:"K_BATCH_MAX_EVENTS"`
+
+	// BatchMaxBytes is the size of the largest batch, 0 for no limit.
+	BatchMaxBytes int `envconfig:"K_BATCH_MAX_BYTES" default:"1048576"`
+
+	// BatchLinger is how long a batch waits for more events before it is sent.
+	BatchLinger time.Duration `envconfig:"K_BATCH_LINGER" default:"10ms"`
+
 	// cached zap logger
 	logger *zap.SugaredLogger
 }
@@ -127,7 +198,92 @@ type EnvConfigAccessor interface {
 	GetSinktimeout() int
 }
 
-var _ EnvConfigAccessor = (*EnvConfig)(nil)
+// The accessors below are optional: EnvConfig implements all of them, and
+// the features they configure are disabled for an EnvConfigAccessor that
+// doesn't implement them.
+
+// HealthPortAccessor is implemented by the EnvConfigAccessors of the adapters
+// serving health endpoints.
+type HealthPortAccessor interface {
+	// Get the port serving the health endpoints, 0 if they are disabled.
+	GetHealthPort() int
+}
+
+// SinkFilesAccessor is implemented by the EnvConfigAccessors of the adapters
+// reading their sink from the files projected by a SinkBinding.
+type SinkFilesAccessor interface {
+	// Get the files projected by a SinkBinding.
+	GetSinkFiles() SinkFiles
+}
+
+// DeliveryAccessor is implemented by the EnvConfigAccessors of the adapters
+// retrying outbound events.
+type DeliveryAccessor interface {
+	// Get the delivery spec of outbound events, nil if it is not configured.
+	GetDelivery() (*eventingduckv1.DeliverySpec, error)
+}
+
+// OutboxConfigAccessor is implemented by the EnvConfigAccessors of the
+// adapters sending events through an outbox.
+type OutboxConfigAccessor interface {
+	// Get the configuration of the outbox.
+	GetOutboxConfig() OutboxConfig
+}
+
+// BatchConfigAccessor is implemented by the EnvConfigAccessors of the
+// adapters batching outbound events.
+type BatchConfigAccessor interface {
+	// Get the batching configuration of outbound events.
+	GetBatchConfig() BatchConfig
+}
+
+// OutboxConfig configures the outbox of outbound events.
+type OutboxConfig struct {
+	// Dir is the directory of the outbox, empty if it is disabled.
+	Dir string
+	// MaxEvents is the number of events the outbox holds, 0 for no limit.
+	MaxEvents int
+	// MaxAttempts is the number of times an event is sent before it is
+	// dropped, 0 for no limit.
+	MaxAttempts int
+	// MaxAge is how long an event is sent before it is dropped, 0 for no limit.
+	MaxAge time.Duration
+	// Concurrency is the number of events sent at once.
+	Concurrency int
+}
+
+// BatchConfig configures the batching of outbound events.
+type BatchConfig struct {
+	// MaxEvents is the number of events in the largest batch. Batching is
+	// disabled unless it is greater than 1.
+	MaxEvents int
+	// MaxBytes is the size of the largest batch, 0 for no limit.
+	MaxBytes int
+	// Linger is how long a batch waits for more events before it is sent.
+	Linger time.Duration
+}
+
+// SinkFiles are the paths of the files a SinkBinding projects into the
+// adapter. Empty paths are not projected.
+type SinkFiles struct {
+	// Sink holds the URI messages will be sent to.
+	Sink string
+	// CEOverrides holds the JSON encoded CloudEvents overrides.
+	CEOverrides string
+	// CACerts holds a PEM encoded CA bundle to trust when sending to the sink.
+	CACerts string
+	// Token holds a bearer token to authenticate against the sink.
+	Token string
+}
+
+var (
+	_ EnvConfigAccessor    = (*EnvConfig)(nil)
+	_ HealthPortAccessor   = (*EnvConfig)(nil)
+	_ SinkFilesAccessor    = (*EnvConfig)(nil)
+	_ DeliveryAccessor     = (*EnvConfig)(nil)
+	_ OutboxConfigAccessor = (*EnvConfig)(nil)
+	_ BatchConfigAccessor  = (*EnvConfig)(nil)
+)
 
 func (e *EnvConfig) SetComponent(component string) {
 	e.Component = component
@@ -160,9 +316,31 @@ func (e *EnvConfig) GetLogger() *zap.SugaredLogger {
 }
 
 func (e *EnvConfig) GetSink() string {
+	if e.Sink == "" && e.SinkFile != "" {
+		sink, err := readFileValue(e.SinkFile)
+		if err != nil {
+			e.GetLogger().Warnw("Failed to read the sink file", zap.String("path", e.SinkFile), zap.Error(err))
+		}
+		return sink
+	}
 	return e.Sink
 }
 
+func (e *EnvConfig) GetSinkFiles() SinkFiles {
+	files := SinkFiles{
+		CACerts: e.CACertsFile,
+		Token:   e.SinkTokenFile,
+	}
+	// Explicit values take precedence over the files.
+	if e.Sink == "" {
+		files.Sink = e.SinkFile
+	}
+	if e.CEOverrides == "" {
+		files.CEOverrides = e.CEOverridesFile
+	}
+	return files
+}
+
 func (e *EnvConfig) GetNamespace() string {
 	return e.Namespace
 }
@@ -179,6 +357,28 @@ func (e *EnvConfig) GetSinktimeout() int {
 	return -1
 }
 
+func (e *EnvConfig) GetHealthPort() int {
+	return e.HealthPort
+}
+
+func (e *EnvConfig) GetOutboxConfig() OutboxConfig {
+	return OutboxConfig{
+		Dir:         e.OutboxDir,
+		MaxEvents:   e.OutboxMaxEvents,
+		MaxAttempts: e.OutboxMaxAttempts,
+		MaxAge:      e.OutboxMaxAge,
+		Concurrency: e.OutboxConcurrency,
+	}
+}
+
+func (e *EnvConfig) GetBatchConfig() BatchConfig {
+	return BatchConfig{
+		MaxEvents: e.BatchMaxEvents,
+		MaxBytes:  e.BatchMaxBytes,
+		Linger:    e.BatchLinger,
+	}
+}
+
 func (e *EnvConfig) SetupTracing(logger *zap.SugaredLogger) error {
 	config, err := tracingconfig.JSONToTracingConfig(e.TracingConfigJson)
 	if err != nil {
@@ -188,9 +388,20 @@ func (e *EnvConfig) SetupTracing(logger *zap.SugaredLogger) error {
 }
 
 func (e *EnvConfig) GetCloudEventOverrides() (*duckv1.CloudEventOverrides, error) {
+	if e.CEOverrides == "" && e.CEOverridesFile != "" {
+		co, err := readFileValue(e.CEOverridesFile)
+		if err != nil {
+			return nil, err
+		}
+		return parseCloudEventOverrides(co)
+	}
+	return parseCloudEventOverrides(e.CEOverrides)
+}
+
+func parseCloudEventOverrides(co string) (*duckv1.CloudEventOverrides, error) {
 	var ceOverrides duckv1.CloudEventOverrides
-	if len(e.CEOverrides) > 0 {
-		err := json.Unmarshal([]byte(e.CEOverrides), &ceOverrides)
+	if len(co) > 0 {
+		err := json.Unmarshal([]byte(co), &ceOverrides)
 		if err != nil {
 			return nil, err
 		}
@@ -198,6 +409,20 @@ func (e *EnvConfig) GetCloudEventOverrides() (*duckv1.CloudEventOverrides, error
 	return &ceOverrides, nil
 }
 
+func (e *EnvConfig) GetDelivery() (*eventingduckv1.DeliverySpec, error) {
+	if e.DeliveryJson == "" {
+		return nil, nil
+	}
+	var delivery eventingduckv1.DeliverySpec
+	if err := json.Unmarshal([]byte(e.DeliveryJson), &delivery); err != nil {
+		return nil, err
+	}
+	if dls := delivery.DeadLetterSink; dls != nil && (dls.URI == nil || !dls.URI.URL().IsAbs()) {
+		return nil, errors.New("the dead letter sink must be resolved to an absolute URI")
+	}
+	return &delivery, nil
+}
+
 func (e *EnvConfig) GetLeaderElectionConfig() (*kle.ComponentConfig, error) {
 	if e.LeaderElectionConfigJson == "" {
 		return e.defaultLeaderElectionConfig(), nil
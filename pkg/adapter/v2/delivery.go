@@ -0,0 +1,120 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	nethttp "net/http"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/protocol/http"
+
+	"knative.dev/eventing/pkg/channel/attributes"
+)
+
+// sendWithRetries sends event, retrying as configured by the delivery spec of
+// the client. Failed attempts are kept in the result so that they are
+// reported as retries.
+func (c *client) sendWithRetries(ctx context.Context, out event.Event) protocol.Result {
+	return c.withRetries(ctx, func(ctx context.Context) protocol.Result {
+		return c.ceClient.Send(ctx, out)
+	}, nil)
+}
+
+// withRetries calls send, retrying as configured by the delivery spec of the
+// client. Results that retryable rejects are not retried, on top of the
+// ones the delivery spec does not retry. The retries of the delivery spec
+// replace the ones of the SDK set on ctx, so that they don't multiply.
+func (c *client) withRetries(ctx context.Context, send func(context.Context) protocol.Result, retryable func(protocol.Result) bool) protocol.Result {
+	if c.retryConfig == nil {
+		return send(ctx)
+	}
+	ctx = cecontext.WithRetryParams(ctx, &cecontext.DefaultRetryParams)
+
+	start := time.Now()
+	var attempts []protocol.Result
+	for attempt := 0; ; attempt++ {
+		result := c.sendOnce(ctx, send)
+		if cloudevents.IsACK(result) || attempt >= c.retryConfig.RetryMax || !c.shouldRetry(ctx, result) ||
+			(retryable != nil && !retryable(result)) {
+			if attempt == 0 {
+				return result
+			}
+			return http.NewRetriesResult(result, attempt, start, attempts)
+		}
+		select {
+		case <-time.After(c.retryConfig.Backoff(attempt, nil)):
+		case <-ctx.Done():
+			if attempt == 0 {
+				return result
+			}
+			return http.NewRetriesResult(result, attempt, start, attempts)
+		}
+		attempts = append(attempts, result)
+	}
+}
+
+// sendOnce calls send, within the request timeout of the delivery spec when set.
+func (c *client) sendOnce(ctx context.Context, send func(context.Context) protocol.Result) protocol.Result {
+	if timeout := c.retryConfig.RequestTimeout; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return send(ctx)
+}
+
+// shouldRetry reports whether the delivery spec retries the failed result.
+func (c *client) shouldRetry(ctx context.Context, result protocol.Result) bool {
+	var res *http.Result
+	if cloudevents.ResultAs(result, &res) {
+		retry, _ := c.retryConfig.CheckRetry(ctx, &nethttp.Response{StatusCode: res.StatusCode}, nil)
+		return retry
+	}
+	retry, _ := c.retryConfig.CheckRetry(ctx, nil, result)
+	return retry
+}
+
+// sendToDeadLetterSink sends event, which could not be delivered with result,
+// to the dead letter sink of the delivery spec. The event carries the
+// Knative error extensions describing the failed delivery.
+func (c *client) sendToDeadLetterSink(ctx context.Context, out event.Event, result protocol.Result) protocol.Result {
+	target := c.target
+	if t := cloudevents.TargetFromContext(ctx); t != nil {
+		target = t.String()
+	}
+	if target != "" {
+		out.SetExtension(attributes.KnativeErrorDestExtensionKey, target)
+	}
+	var rres *http.RetriesResult
+	if cloudevents.ResultAs(result, &rres) {
+		result = rres.Result
+	}
+	var res *http.Result
+	if cloudevents.ResultAs(result, &res) {
+		out.SetExtension(attributes.KnativeErrorCodeExtensionKey, res.StatusCode)
+	}
+
+	ctx = cloudevents.ContextWithTarget(ctx, c.deadLetterSink)
+	dlsResult := c.sendWithRetries(ctx, out)
+	c.reportMetrics(ctx, out, dlsResult)
+	return dlsResult
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"fmt"
+	nethttp "net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	cloudeventsobsclient "github.com/cloudevents/sdk-go/observability/opencensus/v2/client"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+// recordingSink responds with the given status codes in order, then with
+// 202, and records the headers of the requests it receives.
+type recordingSink struct {
+	mu       sync.Mutex
+	statuses []int
+	headers  []nethttp.Header
+}
+
+func (s *recordingSink) ServeHTTP(w nethttp.ResponseWriter, r *nethttp.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.headers = append(s.headers, r.Header.Clone())
+	status := nethttp.StatusAccepted
+	if len(s.statuses) > 0 {
+		status, s.statuses = s.statuses[0], s.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func (s *recordingSink) requests() []nethttp.Header {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.headers
+}
+
+func TestGetDelivery(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		wantNil bool
+		wantErr bool
+	}{{
+		name:    "unset",
+		wantNil: true,
+	}, {
+		name: "retries",
+		json: `{"retry":3,"backoffPolicy":"linear","backoffDelay":"PT0.1S"}`,
+	}, {
+		name: "resolved dead letter sink",
+		json: `{"deadLetterSink":{"uri":"http://dls.ns.svc.cluster.local"}}`,
+	}, {
+		name:    "unresolved dead letter sink",
+		json:    `{"deadLetterSink":{"ref":{"apiVersion":"v1","kind":"Service","name":"dls"}}}`,
+		wantErr: true,
+	}, {
+		name:    "invalid json",
+		json:    `{`,
+		wantErr: true,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			env := &EnvConfig{DeliveryJson: test.json}
+			got, err := env.GetDelivery()
+			if (err != nil) != test.wantErr {
+				t.Fatalf("GetDelivery() = %v, want error %v", err, test.wantErr)
+			}
+			if !test.wantErr && (got == nil) != test.wantNil {
+				t.Errorf("GetDelivery() = %v, want nil %v", got, test.wantNil)
+			}
+		})
+	}
+}
+
+func TestNewCloudEventsClient_delivery(t *testing.T) {
+	tests := []struct {
+		name           string
+		sinkStatuses   []int
+		retry          int
+		ctxRetries     int
+		withDLS        bool
+		wantACK        bool
+		wantSinkCalls  int
+		wantDLSCalls   int
+		wantRetryCount int
+	}{{
+		name:           "succeeds after retries",
+		sinkStatuses:   []int{503, 500},
+		retry:          3,
+		wantACK:        true,
+		wantSinkCalls:  3,
+		wantRetryCount: 2,
+	}, {
+		name:           "retries exhausted",
+		sinkStatuses:   []int{503, 503, 503},
+		retry:          2,
+		wantSinkCalls:  3,
+		wantRetryCount: 2,
+	}, {
+		name:           "context retries replaced",
+		sinkStatuses:   []int{503, 503, 503, 503, 503, 503},
+		retry:          1,
+		ctxRetries:     2,
+		wantSinkCalls:  2,
+		wantRetryCount: 1,
+	}, {
+		name:           "dead lettered",
+		sinkStatuses:   []int{500, 500},
+		retry:          1,
+		withDLS:        true,
+		wantACK:        true,
+		wantSinkCalls:  2,
+		wantDLSCalls:   1,
+		wantRetryCount: 1,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sink := &recordingSink{statuses: test.sinkStatuses}
+			sinkServer := httptest.NewServer(sink)
+			defer sinkServer.Close()
+			dls := &recordingSink{}
+			dlsServer := httptest.NewServer(dls)
+			defer dlsServer.Close()
+
+			delivery := fmt.Sprintf(`{"retry":%d,"backoffPolicy":"linear","backoffDelay":"PT0.001S"`, test.retry)
+			if test.withDLS {
+				delivery += fmt.Sprintf(`,"deadLetterSink":{"uri":%q}`, dlsServer.URL)
+			}
+			delivery += "}"
+
+			newClientHTTPObserved = cloudeventsobsclient.NewClientHTTP
+			reporter := &mockReporter{}
+			c, err := NewCloudEventsClientCRStatus(&EnvConfig{
+				Sink:         sinkServer.URL,
+				DeliveryJson: delivery,
+			}, reporter, nil)
+			if err != nil {
+				t.Fatal("NewCloudEventsClientCRStatus() =", err)
+			}
+
+			event := cloudevents.NewEvent()
+			event.SetID("abc")
+			event.SetType("unit.test")
+			event.SetSource("unit/test")
+			ctx := context.Background()
+			if test.ctxRetries > 0 {
+				ctx = cloudevents.ContextWithRetriesLinearBackoff(ctx, time.Millisecond, test.ctxRetries)
+			}
+			result := c.Send(ctx, event)
+			if got := cloudevents.IsACK(result); got != test.wantACK {
+				t.Errorf("IsACK(%v) = %v, want %v", result, got, test.wantACK)
+			}
+
+			if got := len(sink.requests()); got != test.wantSinkCalls {
+				t.Errorf("sink received %d requests, want %d", got, test.wantSinkCalls)
+			}
+			if got := reporter.retryEventCount; got != test.wantRetryCount {
+				t.Errorf("reported %d retries, want %d", got, test.wantRetryCount)
+			}
+			dlsRequests := dls.requests()
+			if got := len(dlsRequests); got != test.wantDLSCalls {
+				t.Fatalf("dead letter sink received %d requests, want %d", got, test.wantDLSCalls)
+			}
+			if test.wantDLSCalls > 0 {
+				if got := dlsRequests[0].Get("ce-knativeerrordest"); got != sinkServer.URL {
+					t.Errorf("knativeerrordest = %q, want %q", got, sinkServer.URL)
+				}
+				if got := dlsRequests[0].Get("ce-knativeerrorcode"); got != "500" {
+					t.Errorf("knativeerrorcode = %q, want 500", got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+
+	"knative.dev/eventing/pkg/adapter/v2/util/health"
+)
+
+// healthClient records the result of every event sent through the wrapped
+// client in a health.Tracker.
+type healthClient struct {
+	cloudevents.Client
+	tracker *health.Tracker
+}
+
+var _ cloudevents.Client = (*healthClient)(nil)
+
+func withHealthTracker(client cloudevents.Client, tracker *health.Tracker) cloudevents.Client {
+	return &healthClient{Client: client, tracker: tracker}
+}
+
+// Send implements client.Send
+func (c *healthClient) Send(ctx context.Context, out event.Event) protocol.Result {
+	res := c.Client.Send(ctx, out)
+	c.tracker.RecordResult(res)
+	return res
+}
+
+// Request implements client.Request
+func (c *healthClient) Request(ctx context.Context, out event.Event) (*event.Event, protocol.Result) {
+	resp, res := c.Client.Request(ctx, out)
+	c.tracker.RecordResult(res)
+	return resp, res
+}
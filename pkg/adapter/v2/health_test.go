@@ -0,0 +1,61 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	adaptertest "knative.dev/eventing/pkg/adapter/v2/test"
+	"knative.dev/eventing/pkg/adapter/v2/util/health"
+)
+
+func TestHealthClient(t *testing.T) {
+	inner := adaptertest.NewTestClient()
+	tracker := health.NewTracker()
+	c := withHealthTracker(inner, tracker)
+
+	event := cloudevents.NewEvent()
+	event.SetID("abc-123")
+	event.SetSource("unit/test")
+	event.SetType("unit.type")
+
+	if res := c.Send(context.Background(), event); !cloudevents.IsACK(res) {
+		t.Fatal("Expected send to succeed, got", res)
+	}
+	// The test client fails events of this type without an HTTP response.
+	event.SetType("unit.wantErr")
+	if _, res := c.Request(context.Background(), event); cloudevents.IsACK(res) {
+		t.Fatal("Expected request to fail")
+	}
+
+	if got := len(inner.Sent()); got != 2 {
+		t.Errorf("Expected 2 events to reach the wrapped client, got %d", got)
+	}
+	status := tracker.Status()
+	if status.SentEvents != 1 || status.FailedEvents != 1 {
+		t.Errorf("Expected 1 sent and 1 failed event, got %d and %d", status.SentEvents, status.FailedEvents)
+	}
+	if status.SinkReachable {
+		t.Error("Expected the sink to be unreachable after a connection error")
+	}
+	if status.LastSuccessfulSend == nil {
+		t.Error("Expected the last successful send to be recorded")
+	}
+}
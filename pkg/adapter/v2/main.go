@@ -43,6 +43,8 @@ import (
 	"knative.dev/pkg/signals"
 
 	"knative.dev/eventing/pkg/adapter/v2/util/crstatusevent"
+	"knative.dev/eventing/pkg/adapter/v2/util/health"
+	"knative.dev/eventing/pkg/adapter/v2/util/outbox"
 )
 
 // Adapter is the interface receive adapters are expected to implement
@@ -164,6 +166,11 @@ func MainWithInformers(ctx context.Context, component string, env EnvConfigAcces
 		logger.Fatalw("Error building cloud event client", zap.Error(err))
 	}
 
+	eventsClient, err = WrapClient(ctx, env, eventsClient, reporter)
+	if err != nil {
+		logger.Fatalw("Error wrapping the cloud event client", zap.Error(err))
+	}
+
 	// Configuring the adapter
 	adapter := ctor(ctx, env, eventsClient)
 
@@ -208,6 +215,49 @@ func MainWithInformers(ctx context.Context, component string, env EnvConfigAcces
 	wg.Wait()
 }
 
+// WrapClient wraps the client of an adapter with the health tracker and the
+// outbox configured by env. The health server shuts down when ctx is done.
+func WrapClient(ctx context.Context, env EnvConfigAccessor, client cloudevents.Client, reporter source.StatsReporter) (cloudevents.Client, error) {
+	logger := logging.FromContext(ctx)
+
+	var port int
+	if ha, ok := env.(HealthPortAccessor); ok {
+		port = ha.GetHealthPort()
+	}
+	if port > 0 {
+		tracker := health.NewTracker()
+		client = withHealthTracker(client, tracker)
+		server := health.NewServer(port, tracker)
+		go func() {
+			// Don't forward ErrServerClosed as that indicates we're already shutting down.
+			if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Errorw("Health server failed", zap.Error(err))
+			}
+		}()
+		go func() {
+			<-ctx.Done()
+			_ = server.Shutdown(context.Background())
+		}()
+	}
+
+	var cfg OutboxConfig
+	if oa, ok := env.(OutboxConfigAccessor); ok {
+		cfg = oa.GetOutboxConfig()
+	}
+	if cfg.Dir != "" {
+		ob, err := outbox.New(cfg.Dir, cfg.MaxEvents)
+		if err != nil {
+			return nil, fmt.Errorf("opening the outbox: %w", err)
+		}
+		ob.MaxAttempts = cfg.MaxAttempts
+		ob.MaxAge = cfg.MaxAge
+		ob.Concurrency = cfg.Concurrency
+		logger.Infow("Sending events through the outbox", zap.String("dir", cfg.Dir), zap.Int("pending", ob.Len()))
+		client = withOutbox(ctx, client, ob, reporter)
+	}
+	return client, nil
+}
+
 func ConstructEnvOrDie(ector EnvConfigConstructor) EnvConfigAccessor {
 	env := ector()
 	if err := envconfig.Process("", env); err != nil {
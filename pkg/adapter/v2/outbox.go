@@ -0,0 +1,133 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	nethttp "net/http"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/protocol/http"
+
+	"knative.dev/eventing/pkg/adapter/v2/util/outbox"
+	"knative.dev/eventing/pkg/kncloudevents"
+	"knative.dev/eventing/pkg/metrics/source"
+)
+
+// outboxRecord is an event waiting in the outbox, along with the parts of
+// the context it was sent with that affect its delivery.
+type outboxRecord struct {
+	Event     event.Event `json:"event"`
+	Target    string      `json:"target,omitempty"`
+	MetricTag *MetricTag  `json:"metricTag,omitempty"`
+}
+
+// outboxClient stores the events sent through it in an outbox, which a
+// background sender drains to the wrapped client. Send returns once the
+// event is durably stored, delivery to the sink is at least once.
+type outboxClient struct {
+	cloudevents.Client
+	outbox *outbox.Outbox
+}
+
+var _ cloudevents.Client = (*outboxClient)(nil)
+
+// withOutbox returns a client sending events through ob, which is drained to
+// client until ctx is done. The events ob drops are reported to reporter.
+func withOutbox(ctx context.Context, client cloudevents.Client, ob *outbox.Outbox, reporter source.StatsReporter) cloudevents.Client {
+	if dropped, ok := reporter.(source.DroppedEventReporter); ok {
+		ob.OnDrop = func(raw []byte, err error) {
+			// The records which cannot be decoded are counted too, without
+			// their attributes.
+			args := &source.ReportArgs{Error: err.Error()}
+			var record outboxRecord
+			if raw == nil || json.Unmarshal(raw, &record) != nil {
+				_ = dropped.ReportDroppedEventCount(args)
+				return
+			}
+			args.EventSource = record.Event.Source()
+			args.EventType = record.Event.Type()
+			if record.MetricTag != nil {
+				args.Namespace = record.MetricTag.Namespace
+				args.Name = record.MetricTag.Name
+				args.ResourceGroup = record.MetricTag.ResourceGroup
+			}
+			_ = dropped.ReportDroppedEventCount(args)
+		}
+	}
+	go ob.Run(ctx, func(ctx context.Context, raw []byte) error {
+		var record outboxRecord
+		if err := json.Unmarshal(raw, &record); err != nil {
+			return outbox.Permanent(err)
+		}
+		if record.Target != "" {
+			ctx = cloudevents.ContextWithTarget(ctx, record.Target)
+		}
+		if record.MetricTag != nil {
+			ctx = ContextWithMetricTag(ctx, record.MetricTag)
+		}
+		if res := client.Send(ctx, record.Event); !cloudevents.IsACK(res) {
+			if !retryable(ctx, res) {
+				return outbox.Permanent(res)
+			}
+			return res
+		}
+		return nil
+	})
+	return &outboxClient{Client: client, outbox: ob}
+}
+
+// retryable reports whether sending an event again could fix the failed result.
+func retryable(ctx context.Context, result protocol.Result) bool {
+	var rres *http.RetriesResult
+	if cloudevents.ResultAs(result, &rres) {
+		result = rres.Result
+	}
+	var res *http.Result
+	if !cloudevents.ResultAs(result, &res) {
+		return true
+	}
+	retry, _ := kncloudevents.SelectiveRetry(ctx, &nethttp.Response{StatusCode: res.StatusCode}, nil)
+	return retry
+}
+
+// Send implements client.Send
+func (c *outboxClient) Send(ctx context.Context, out event.Event) protocol.Result {
+	// Invalid events would never be delivered, reject them right away.
+	if err := out.Validate(); err != nil {
+		return protocol.NewReceipt(false, "invalid event: %v", err)
+	}
+	record := outboxRecord{Event: out}
+	if target := cloudevents.TargetFromContext(ctx); target != nil {
+		record.Target = target.String()
+	}
+	if tag, ok := ctx.Value(metricKey{}).(*MetricTag); ok {
+		record.MetricTag = tag
+	}
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return protocol.NewReceipt(false, "failed to encode event: %v", err)
+	}
+	if err := c.outbox.Append(raw); err != nil {
+		return fmt.Errorf("failed to store event in the outbox: %w", err)
+	}
+	return protocol.ResultACK
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/protocol/http"
+
+	"knative.dev/eventing/pkg/adapter/v2/util/outbox"
+)
+
+// flakyClient fails the first failures sends and records the target and
+// metric tag of the ones that succeed.
+type flakyClient struct {
+	cloudevents.Client
+
+	mu       sync.Mutex
+	failures int
+	// rejected are the IDs of the events rejected with a 400.
+	rejected map[string]bool
+	sent     []string
+	targets  []string
+	tags     []*MetricTag
+}
+
+func (c *flakyClient) Send(ctx context.Context, out event.Event) protocol.Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.failures > 0 {
+		c.failures--
+		return protocol.NewReceipt(false, "sink unavailable")
+	}
+	if c.rejected[out.ID()] {
+		return http.NewResult(400, "bad request")
+	}
+	c.sent = append(c.sent, out.ID())
+	if target := cloudevents.TargetFromContext(ctx); target != nil {
+		c.targets = append(c.targets, target.String())
+	}
+	c.tags = append(c.tags, MetricTagFromContext(ctx))
+	return protocol.ResultACK
+}
+
+func (c *flakyClient) sentIDs() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.sent...)
+}
+
+func TestOutboxClient(t *testing.T) {
+	ob, err := outbox.New(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal("New() =", err)
+	}
+	ob.RetryInterval = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	inner := &flakyClient{failures: 2}
+	c := withOutbox(ctx, inner, ob, nil)
+
+	tag := &MetricTag{Name: "src", Namespace: "ns", ResourceGroup: "sources"}
+	sendCtx := ContextWithMetricTag(cloudevents.ContextWithTarget(context.Background(), "http://sink"), tag)
+	for _, id := range []string{"1", "2"} {
+		e := cloudevents.NewEvent()
+		e.SetID(id)
+		e.SetType("unit.test")
+		e.SetSource("unit/test")
+		if res := c.Send(sendCtx, e); !cloudevents.IsACK(res) {
+			t.Fatal("Send() =", res)
+		}
+	}
+
+	if res := c.Send(sendCtx, cloudevents.NewEvent()); cloudevents.IsACK(res) {
+		t.Error("Send() of an invalid event = ACK, want NACK")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(inner.sentIDs()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := inner.sentIDs(); len(got) != 2 || got[0] != "1" || got[1] != "2" {
+		t.Fatalf("sent = %v, want [1 2]", got)
+	}
+	inner.mu.Lock()
+	defer inner.mu.Unlock()
+	for i := range inner.sent {
+		if inner.targets[i] != "http://sink" {
+			t.Errorf("target = %q, want http://sink", inner.targets[i])
+		}
+		if *inner.tags[i] != *tag {
+			t.Errorf("metric tag = %+v, want %+v", inner.tags[i], tag)
+		}
+	}
+}
+
+func TestOutboxClientDropsRejectedEvents(t *testing.T) {
+	ob, err := outbox.New(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal("New() =", err)
+	}
+	ob.RetryInterval = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	inner := &flakyClient{rejected: map[string]bool{"1": true}}
+	reporter := &mockReporter{}
+	c := withOutbox(ctx, inner, ob, reporter)
+
+	for _, id := range []string{"1", "2"} {
+		e := cloudevents.NewEvent()
+		e.SetID(id)
+		e.SetType("unit.test")
+		e.SetSource("unit/test")
+		if res := c.Send(context.Background(), e); !cloudevents.IsACK(res) {
+			t.Fatal("Send() =", res)
+		}
+	}
+
+	// The rejected event is dropped instead of blocking the next one.
+	deadline := time.Now().Add(5 * time.Second)
+	for len(inner.sentIDs()) < 1 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := inner.sentIDs(); len(got) != 1 || got[0] != "2" {
+		t.Fatalf("sent = %v, want [2]", got)
+	}
+	reporter.mu.Lock()
+	defer reporter.mu.Unlock()
+	if reporter.droppedEventCount != 1 {
+		t.Errorf("dropped event count = %d, want 1", reporter.droppedEventCount)
+	}
+}
+
+func TestOutboxClientReportsUndecodableDrops(t *testing.T) {
+	ob, err := outbox.New(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal("New() =", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reporter := &mockReporter{}
+	withOutbox(ctx, &flakyClient{}, ob, reporter)
+
+	// An undecodable record, and the unreadable part of a segment.
+	ob.OnDrop([]byte("not json"), errors.New("rejected"))
+	ob.OnDrop(nil, errors.New("truncated record"))
+
+	reporter.mu.Lock()
+	defer reporter.mu.Unlock()
+	if reporter.droppedEventCount != 2 {
+		t.Errorf("dropped event count = %d, want 2", reporter.droppedEventCount)
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	nethttp "net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// readFileValue returns the content of the file at path without surrounding
+// whitespace.
+func readFileValue(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// fileValue caches the content of a file and re-reads it once its
+// modification time changes. Projected volumes are updated by the kubelet
+// behind a symlink swap, which os.Stat follows.
+type fileValue struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	value   string
+}
+
+func newFileValue(path string) *fileValue {
+	return &fileValue{path: path}
+}
+
+// Get returns the current content of the file. On errors it keeps returning
+// the last content read successfully.
+func (f *fileValue) Get() (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return f.value, err
+	}
+	if !f.modTime.IsZero() && info.ModTime().Equal(f.modTime) {
+		return f.value, nil
+	}
+	value, err := readFileValue(f.path)
+	if err != nil {
+		return f.value, err
+	}
+	f.value, f.modTime = value, info.ModTime()
+	return f.value, nil
+}
+
+// newSinkTransport returns the transport trusting the CA bundle of files and
+// authenticating with the bearer token of files.
+func newSinkTransport(files SinkFiles) (nethttp.RoundTripper, error) {
+	var transport nethttp.RoundTripper = nethttp.DefaultTransport.(*nethttp.Transport).Clone()
+	if files.CACerts != "" {
+		ca := &caTransport{
+			base: transport.(*nethttp.Transport),
+			ca:   newFileValue(files.CACerts),
+		}
+		// Fail early on a CA bundle that cannot be used at all.
+		if _, err := ca.transport(); err != nil {
+			return nil, err
+		}
+		transport = ca
+	}
+	if files.Token == "" {
+		return transport, nil
+	}
+	return &tokenTransport{
+		base:  transport,
+		token: newFileValue(files.Token),
+	}, nil
+}
+
+// caTransport trusts the CA bundle of a file, which is rotated like the token,
+// and switches to a transport trusting the new bundle once it changes.
+type caTransport struct {
+	base *nethttp.Transport
+	ca   *fileValue
+
+	mu      sync.Mutex
+	pem     string
+	current *nethttp.Transport
+}
+
+func (t *caTransport) RoundTrip(req *nethttp.Request) (*nethttp.Response, error) {
+	transport, err := t.transport()
+	if err != nil {
+		return nil, err
+	}
+	return transport.RoundTrip(req)
+}
+
+// transport returns the transport trusting the current CA bundle. A bundle
+// that cannot be read or holds no certificate keeps the previous transport.
+func (t *caTransport) transport() (*nethttp.Transport, error) {
+	pem, err := t.ca.Get()
+	if err != nil && pem == "" {
+		return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.current != nil && pem == t.pem {
+		return t.current, nil
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM([]byte(pem)) {
+		if t.current != nil {
+			return t.current, nil
+		}
+		return nil, fmt.Errorf("no certificates found in CA bundle %q", t.ca.path)
+	}
+	transport := t.base.Clone()
+	transport.TLSClientConfig = &tls.Config{
+		RootCAs:    pool,
+		MinVersion: tls.VersionTLS12,
+	}
+	if t.current != nil {
+		t.current.CloseIdleConnections()
+	}
+	t.pem, t.current = pem, transport
+	return transport, nil
+}
+
+// tokenTransport sets the token of a file, which the kubelet rotates, as the
+// bearer token of every request.
+type tokenTransport struct {
+	base  nethttp.RoundTripper
+	token *fileValue
+}
+
+func (t *tokenTransport) RoundTrip(req *nethttp.Request) (*nethttp.Response, error) {
+	token, err := t.token.Get()
+	if err != nil && token == "" {
+		return nil, fmt.Errorf("failed to read sink token: %w", err)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return t.base.RoundTrip(req)
+}
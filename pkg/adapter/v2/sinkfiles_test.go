@@ -0,0 +1,246 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	nethttp "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	cloudeventsobsclient "github.com/cloudevents/sdk-go/observability/opencensus/v2/client"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+func writeFile(t *testing.T, path, content string, modTime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Make sure the modification time changes even on coarse filesystems.
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileValue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sink")
+	now := time.Now()
+	writeFile(t, path, "http://first\n", now)
+
+	f := newFileValue(path)
+	if got, err := f.Get(); err != nil || got != "http://first" {
+		t.Fatalf("Get() = %q, %v, want http://first", got, err)
+	}
+
+	writeFile(t, path, "http://second", now.Add(time.Second))
+	if got, err := f.Get(); err != nil || got != "http://second" {
+		t.Fatalf("Get() = %q, %v, want http://second", got, err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := f.Get(); err == nil || got != "http://second" {
+		t.Fatalf("Get() = %q, %v, want the last value and an error", got, err)
+	}
+}
+
+func TestEnvConfigSinkFiles(t *testing.T) {
+	dir := t.TempDir()
+	sinkPath := filepath.Join(dir, "sink")
+	coPath := filepath.Join(dir, "ce-overrides")
+	writeFile(t, sinkPath, "http://sink", time.Now())
+	writeFile(t, coPath, `{"extensions":{"foo":"bar"}}`, time.Now())
+
+	env := &EnvConfig{
+		SinkFile:        sinkPath,
+		CEOverridesFile: coPath,
+		SinkTokenFile:   "/token",
+	}
+	if got, want := env.GetSink(), "http://sink"; got != want {
+		t.Errorf("GetSink() = %q, want %q", got, want)
+	}
+	co, err := env.GetCloudEventOverrides()
+	if err != nil {
+		t.Fatal("GetCloudEventOverrides() =", err)
+	}
+	if got, want := co.Extensions["foo"], "bar"; got != want {
+		t.Errorf("extension foo = %q, want %q", got, want)
+	}
+	want := SinkFiles{Sink: sinkPath, CEOverrides: coPath, Token: "/token"}
+	if got := env.GetSinkFiles(); got != want {
+		t.Errorf("GetSinkFiles() = %+v, want %+v", got, want)
+	}
+
+	// Explicit values take precedence over the files.
+	env.Sink = "http://explicit"
+	env.CEOverrides = `{"extensions":{"foo":"baz"}}`
+	if got, want := env.GetSink(), "http://explicit"; got != want {
+		t.Errorf("GetSink() = %q, want %q", got, want)
+	}
+	if got := env.GetSinkFiles(); got.Sink != "" || got.CEOverrides != "" {
+		t.Errorf("GetSinkFiles() = %+v, want no sink files", got)
+	}
+}
+
+func TestNewCloudEventsClient_sinkFiles(t *testing.T) {
+	type received struct {
+		auth string
+		foo  string
+	}
+	var (
+		mu     sync.Mutex
+		events = map[string][]received{}
+	)
+	handler := func(name string) nethttp.Handler {
+		return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			events[name] = append(events[name], received{
+				auth: r.Header.Get("Authorization"),
+				foo:  r.Header.Get("ce-foo"),
+			})
+			w.WriteHeader(nethttp.StatusAccepted)
+		})
+	}
+	first := httptest.NewServer(handler("first"))
+	defer first.Close()
+	second := httptest.NewServer(handler("second"))
+	defer second.Close()
+
+	dir := t.TempDir()
+	now := time.Now()
+	sinkPath := filepath.Join(dir, "sink")
+	coPath := filepath.Join(dir, "ce-overrides")
+	tokenPath := filepath.Join(dir, "token")
+	writeFile(t, sinkPath, first.URL, now)
+	writeFile(t, coPath, `{"extensions":{"foo":"one"}}`, now)
+	writeFile(t, tokenPath, "token-1", now)
+
+	newClientHTTPObserved = cloudeventsobsclient.NewClientHTTP
+	c, err := NewCloudEventsClientCRStatus(&EnvConfig{
+		SinkFile:        sinkPath,
+		CEOverridesFile: coPath,
+		SinkTokenFile:   tokenPath,
+	}, &mockReporter{}, nil)
+	if err != nil {
+		t.Fatal("NewCloudEventsClientCRStatus() =", err)
+	}
+
+	send := func() {
+		t.Helper()
+		event := cloudevents.NewEvent()
+		event.SetID("abc")
+		event.SetType("unit.test")
+		event.SetSource("unit/test")
+		if result := c.Send(context.Background(), event); !cloudevents.IsACK(result) {
+			t.Fatal("Send() =", result)
+		}
+	}
+
+	send()
+	writeFile(t, sinkPath, second.URL, now.Add(time.Second))
+	writeFile(t, coPath, `{"extensions":{"foo":"two"}}`, now.Add(time.Second))
+	writeFile(t, tokenPath, "token-2", now.Add(time.Second))
+	send()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if got, want := events["first"], []received{{auth: "Bearer token-1", foo: "one"}}; len(got) != 1 || got[0] != want[0] {
+		t.Errorf("first sink received %+v, want %+v", got, want)
+	}
+	if got, want := events["second"], []received{{auth: "Bearer token-2", foo: "two"}}; len(got) != 1 || got[0] != want[0] {
+		t.Errorf("second sink received %+v, want %+v", got, want)
+	}
+}
+
+func TestSinkTransportReloadsCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		w.WriteHeader(nethttp.StatusAccepted)
+	}))
+	defer server.Close()
+
+	// A self-signed certificate that did not sign the certificate of server.
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "other"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}, &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "other"},
+	}, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pemOf := func(der []byte) string {
+		return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	}
+
+	caPath := filepath.Join(t.TempDir(), "ca-certs")
+	now := time.Now()
+	writeFile(t, caPath, pemOf(other), now)
+
+	transport, err := newSinkTransport(SinkFiles{CACerts: caPath})
+	if err != nil {
+		t.Fatal("newSinkTransport() =", err)
+	}
+	get := func() error {
+		req, err := nethttp.NewRequest(nethttp.MethodGet, server.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := transport.RoundTrip(req)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	if err := get(); err == nil {
+		t.Fatal("RoundTrip() trusting another CA succeeded")
+	}
+
+	writeFile(t, caPath, pemOf(server.Certificate().Raw), now.Add(time.Second))
+	if err := get(); err != nil {
+		t.Fatal("RoundTrip() after rotating the CA bundle =", err)
+	}
+
+	// A bundle without certificates keeps trusting the previous one.
+	writeFile(t, caPath, "garbage", now.Add(2*time.Second))
+	if err := get(); err != nil {
+		t.Fatal("RoundTrip() after an invalid CA bundle =", err)
+	}
+}
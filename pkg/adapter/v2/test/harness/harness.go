@@ -0,0 +1,192 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package harness runs receive adapters end-to-end in unit tests, against a
+// local recording sink.
+package harness
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cetest "github.com/cloudevents/sdk-go/v2/test"
+	"github.com/kelseyhightower/envconfig"
+
+	logtesting "knative.dev/pkg/logging/testing"
+
+	"knative.dev/eventing/pkg/adapter/v2"
+)
+
+const (
+	// Component is the component name adapters run with.
+	Component = "harness"
+	// Namespace is the namespace adapters run in.
+	Namespace = "harness-namespace"
+	// Name is the name adapters run with.
+	Name = "harness-adapter"
+)
+
+type config struct {
+	ector adapter.EnvConfigConstructor
+	sink  *Sink
+	env   map[string]string
+}
+
+// Option customizes how Run starts the adapter.
+type Option func(*config)
+
+// WithEnvConfig sets the constructor of the adapter's environment. Defaults
+// to adapter.EnvConfig.
+func WithEnvConfig(ector adapter.EnvConfigConstructor) Option {
+	return func(c *config) {
+		c.ector = ector
+	}
+}
+
+// WithEnv sets the environment variable key while the adapter runs.
+func WithEnv(key, value string) Option {
+	return func(c *config) {
+		c.env[key] = value
+	}
+}
+
+// WithSink sends the events to s instead of a new Sink, which lets tests
+// script the sink before the adapter starts.
+func WithSink(s *Sink) Option {
+	return func(c *config) {
+		c.sink = s
+	}
+}
+
+// Harness is an adapter running against a Sink.
+type Harness struct {
+	// Sink receives the events sent by the adapter.
+	Sink *Sink
+	// Reporter records the metrics reported by the adapter's client.
+	Reporter *Reporter
+	// Env is the environment the adapter was built with.
+	Env adapter.EnvConfigAccessor
+	// Client is the CloudEvents client handed to the adapter.
+	Client cloudevents.Client
+
+	cancel context.CancelFunc
+	done   chan error
+	once   sync.Once
+	err    error
+}
+
+// Run builds the adapter returned by ctor the way adapter.Main does and
+// starts it. The adapter is stopped when t completes. Its client is wrapped
+// like in adapter.Main, so K_HEALTH_PORT and K_OUTBOX_DIR are honored.
+// Metrics, tracing, profiling, leader election and the controllers of
+// adapter.Main are not set up.
+func Run(t testing.TB, ctor adapter.AdapterConstructor, opts ...Option) *Harness {
+	t.Helper()
+
+	c := &config{
+		ector: func() adapter.EnvConfigAccessor { return &adapter.EnvConfig{} },
+		env:   make(map[string]string),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.sink == nil {
+		c.sink = NewSink(t)
+	}
+
+	env := map[string]string{
+		adapter.EnvConfigComponent: Component,
+		adapter.EnvConfigNamespace: Namespace,
+		adapter.EnvConfigName:      Name,
+		adapter.EnvConfigSink:      c.sink.URL(),
+	}
+	for k, v := range c.env {
+		env[k] = v
+	}
+	setEnv(t, env)
+
+	h := &Harness{
+		Sink:     c.sink,
+		Reporter: &Reporter{},
+		Env:      c.ector(),
+		done:     make(chan error, 1),
+	}
+	if err := envconfig.Process("", h.Env); err != nil {
+		t.Fatal("Error processing the environment:", err)
+	}
+	h.Env.SetComponent(Component)
+
+	ctx, cancel := context.WithCancel(logtesting.TestContextWithLogger(t))
+	h.cancel = cancel
+
+	client, err := adapter.NewCloudEventsClientCRStatus(h.Env, h.Reporter, nil)
+	if err != nil {
+		cancel()
+		t.Fatal("Error building the CloudEvents client:", err)
+	}
+	h.Client, err = adapter.WrapClient(ctx, h.Env, client, h.Reporter)
+	if err != nil {
+		cancel()
+		t.Fatal("Error wrapping the CloudEvents client:", err)
+	}
+
+	a := ctor(ctx, h.Env, h.Client)
+	go func() {
+		h.done <- a.Start(ctx)
+	}()
+
+	t.Cleanup(func() {
+		if err := h.Stop(); err != nil {
+			t.Error("Adapter stopped with an error:", err)
+		}
+	})
+	return h
+}
+
+// Stop cancels the adapter's context and returns the error returned by its
+// Start method. It is safe to call Stop more than once.
+func (h *Harness) Stop() error {
+	h.once.Do(func() {
+		h.cancel()
+		h.err = <-h.done
+	})
+	return h.err
+}
+
+// AssertEvents fails t unless every event matches all matchers.
+func AssertEvents(t testing.TB, events []cloudevents.Event, matchers ...cetest.EventMatcher) {
+	t.Helper()
+	for _, event := range events {
+		cetest.AssertEvent(t, event, matchers...)
+	}
+}
+
+// setEnv sets the environment variables in env, restoring their previous
+// values when t completes.
+func setEnv(t testing.TB, env map[string]string) {
+	for k, v := range env {
+		k := k
+		if prev, ok := os.LookupEnv(k); ok {
+			t.Cleanup(func() { os.Setenv(k, prev) })
+		} else {
+			t.Cleanup(func() { os.Unsetenv(k) })
+		}
+		os.Setenv(k, v)
+	}
+}
@@ -0,0 +1,222 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package harness
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cetest "github.com/cloudevents/sdk-go/v2/test"
+
+	"knative.dev/eventing/pkg/adapter/v2"
+	"knative.dev/eventing/pkg/adapter/v2/util/health"
+)
+
+type countEnv struct {
+	adapter.EnvConfig
+
+	Count int `envconfig:"COUNT" default:"1"`
+}
+
+type countAdapter struct {
+	count  int
+	client cloudevents.Client
+}
+
+func newCountAdapter(ctx context.Context, env adapter.EnvConfigAccessor, client cloudevents.Client) adapter.Adapter {
+	return &countAdapter{
+		count:  env.(*countEnv).Count,
+		client: client,
+	}
+}
+
+func (a *countAdapter) Start(ctx context.Context) error {
+	for i := 0; i < a.count; i++ {
+		event := cloudevents.NewEvent()
+		event.SetID(fmt.Sprint(i))
+		event.SetType("dev.knative.harness.count")
+		event.SetSource("harness")
+		if err := event.SetData(cloudevents.ApplicationJSON, map[string]int{"count": i}); err != nil {
+			return err
+		}
+		a.client.Send(ctx, event)
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func countEnvConfig() adapter.EnvConfigAccessor {
+	return &countEnv{}
+}
+
+func TestRun(t *testing.T) {
+	h := Run(t, newCountAdapter,
+		WithEnvConfig(countEnvConfig),
+		WithEnv("COUNT", "3"))
+
+	events := h.Sink.WaitForEvents(t, 3, 5*time.Second)
+	AssertEvents(t, events,
+		cetest.IsValid(),
+		cetest.HasType("dev.knative.harness.count"),
+		cetest.HasSource("harness"))
+	h.Reporter.WaitForEventCount(t, http.StatusAccepted, 3, 5*time.Second)
+
+	if err := h.Stop(); err != nil {
+		t.Error("Stop() =", err)
+	}
+	if got := h.Env.GetNamespace(); got != Namespace {
+		t.Errorf("GetNamespace() = %q, want %q", got, Namespace)
+	}
+}
+
+func TestRunScriptedFailures(t *testing.T) {
+	sink := NewSink(t)
+	sink.Enqueue(
+		Response{StatusCode: http.StatusServiceUnavailable},
+		Response{StatusCode: http.StatusServiceUnavailable, Delay: 10 * time.Millisecond},
+	)
+
+	h := Run(t, newCountAdapter,
+		WithEnvConfig(countEnvConfig),
+		WithSink(sink),
+		WithEnv(adapter.EnvConfigDelivery, `{"retry":2,"backoffPolicy":"linear","backoffDelay":"PT0.001S"}`))
+
+	events := sink.WaitForEvents(t, 1, 5*time.Second)
+	AssertEvents(t, events, cetest.HasId("0"))
+	h.Reporter.WaitForEventCount(t, http.StatusAccepted, 1, 5*time.Second)
+
+	if got, want := sink.Requests(), 3; got != want {
+		t.Errorf("Requests() = %d, want %d", got, want)
+	}
+	if got, want := h.Reporter.RetryCount(http.StatusServiceUnavailable), 2; got != want {
+		t.Errorf("RetryCount(503) = %d, want %d", got, want)
+	}
+}
+
+func TestRunOutbox(t *testing.T) {
+	dir := t.TempDir()
+	sink := NewSink(t)
+	sink.Enqueue(Response{StatusCode: http.StatusServiceUnavailable})
+
+	h := Run(t, newCountAdapter,
+		WithEnvConfig(countEnvConfig),
+		WithSink(sink),
+		WithEnv("COUNT", "2"),
+		WithEnv(adapter.EnvConfigOutboxDir, dir))
+
+	// Without the outbox, the event rejected by the sink would be lost.
+	events := sink.WaitForEvents(t, 2, 5*time.Second)
+	AssertEvents(t, events, cetest.HasType("dev.knative.harness.count"))
+
+	if got := h.Env.(adapter.OutboxConfigAccessor).GetOutboxConfig().Dir; got != dir {
+		t.Errorf("GetOutboxConfig().Dir = %q, want %q", got, dir)
+	}
+	if got, want := sink.Requests(), 3; got != want {
+		t.Errorf("Requests() = %d, want %d", got, want)
+	}
+}
+
+func TestRunHealth(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal("Listen() =", err)
+	}
+	port := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	h := Run(t, newCountAdapter,
+		WithEnvConfig(countEnvConfig),
+		WithEnv("K_HEALTH_PORT", fmt.Sprint(port)))
+	h.Sink.WaitForEvents(t, 1, 5*time.Second)
+
+	var status health.Status
+	if !poll(5*time.Second, func() bool {
+		resp, err := http.Get(fmt.Sprintf("http://localhost:%d%s", port, health.StatusPath))
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+		if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+			return false
+		}
+		return status.SentEvents == 1
+	}) {
+		t.Fatalf("Health status = %+v, want 1 sent event", status)
+	}
+
+	// The health server stops with the adapter.
+	if err := h.Stop(); err != nil {
+		t.Error("Stop() =", err)
+	}
+	if !poll(5*time.Second, func() bool {
+		resp, err := http.Get(fmt.Sprintf("http://localhost:%d%s", port, health.LivenessPath))
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err != nil
+	}) {
+		t.Error("Health server still serving after Stop()")
+	}
+}
+
+func TestRunBatches(t *testing.T) {
+	sink := NewSink(t)
+	sink.AcceptBatches(true)
+
+	h := Run(t, newBatchAdapter(4),
+		WithSink(sink),
+		WithEnv(adapter.EnvConfigBatchMaxEvents, "4"),
+		WithEnv(adapter.EnvConfigBatchLinger, "1s"))
+
+	events := sink.WaitForEvents(t, 4, 5*time.Second)
+	AssertEvents(t, events, cetest.IsValid(), cetest.HasType("dev.knative.harness.batch"))
+	h.Reporter.WaitForEventCount(t, http.StatusAccepted, 4, 5*time.Second)
+
+	if got, want := sink.Requests(), 1; got != want {
+		t.Errorf("Requests() = %d, want %d", got, want)
+	}
+}
+
+// newBatchAdapter returns a constructor of adapters sending n events
+// concurrently, so that they can share a batch.
+func newBatchAdapter(n int) adapter.AdapterConstructor {
+	return func(ctx context.Context, env adapter.EnvConfigAccessor, client cloudevents.Client) adapter.Adapter {
+		return adapterFunc(func(ctx context.Context) error {
+			for i := 0; i < n; i++ {
+				event := cloudevents.NewEvent()
+				event.SetID(fmt.Sprint(i))
+				event.SetType("dev.knative.harness.batch")
+				event.SetSource("harness")
+				go client.Send(ctx, event)
+			}
+			<-ctx.Done()
+			return nil
+		})
+	}
+}
+
+type adapterFunc func(ctx context.Context) error
+
+func (f adapterFunc) Start(ctx context.Context) error {
+	return f(ctx)
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package harness
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"knative.dev/eventing/pkg/metrics/source"
+)
+
+// Report is a single metric recorded by the Reporter.
+type Report struct {
+	Args         source.ReportArgs
+	ResponseCode int
+	// Retry is true for reports made through ReportRetryEventCount.
+	Retry bool
+}
+
+// Reporter is a source.StatsReporter keeping the reports in memory.
+type Reporter struct {
+	mu      sync.Mutex
+	reports []Report
+}
+
+var _ source.StatsReporter = (*Reporter)(nil)
+
+// ReportEventCount implements source.StatsReporter.
+func (r *Reporter) ReportEventCount(args *source.ReportArgs, responseCode int) error {
+	r.record(Report{Args: *args, ResponseCode: responseCode})
+	return nil
+}
+
+// ReportRetryEventCount implements source.StatsReporter.
+func (r *Reporter) ReportRetryEventCount(args *source.ReportArgs, responseCode int) error {
+	r.record(Report{Args: *args, ResponseCode: responseCode, Retry: true})
+	return nil
+}
+
+func (r *Reporter) record(report Report) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reports = append(r.reports, report)
+}
+
+// Reports returns the recorded reports, in order.
+func (r *Reporter) Reports() []Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	reports := make([]Report, len(r.reports))
+	copy(reports, r.reports)
+	return reports
+}
+
+// EventCount returns the number of event count reports with responseCode,
+// retries excluded.
+func (r *Reporter) EventCount(responseCode int) int {
+	count := 0
+	for _, report := range r.Reports() {
+		if !report.Retry && report.ResponseCode == responseCode {
+			count++
+		}
+	}
+	return count
+}
+
+// RetryCount returns the number of retry reports with responseCode.
+func (r *Reporter) RetryCount(responseCode int) int {
+	count := 0
+	for _, report := range r.Reports() {
+		if report.Retry && report.ResponseCode == responseCode {
+			count++
+		}
+	}
+	return count
+}
+
+// WaitForEventCount waits until n event count reports with responseCode
+// were recorded. It fails t if that does not happen within timeout.
+func (r *Reporter) WaitForEventCount(t testing.TB, responseCode, n int, timeout time.Duration) {
+	t.Helper()
+	if !poll(timeout, func() bool { return r.EventCount(responseCode) >= n }) {
+		t.Fatalf("Timed out waiting for %d reports with code %d, got %d", n, responseCode, r.EventCount(responseCode))
+	}
+}
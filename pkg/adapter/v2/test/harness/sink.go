@@ -0,0 +1,215 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package harness
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+)
+
+const batchContentType = "application/cloudevents-batch+json"
+
+// Response scripts how the Sink answers a single delivery.
+type Response struct {
+	// StatusCode is the HTTP status returned to the adapter. Zero means
+	// http.StatusAccepted.
+	StatusCode int
+	// Delay is how long the Sink waits before answering.
+	Delay time.Duration
+}
+
+func (r Response) statusCode() int {
+	if r.StatusCode == 0 {
+		return http.StatusAccepted
+	}
+	return r.StatusCode
+}
+
+// Sink is a local HTTP sink recording the CloudEvents it receives. It
+// understands binary, structured and batched deliveries.
+type Sink struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	script   []Response
+	def      Response
+	batch    bool
+	requests int
+	events   []cloudevents.Event
+}
+
+// NewSink starts a Sink which is closed when t completes.
+func NewSink(t testing.TB) *Sink {
+	s := &Sink{}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+// URL returns the address the adapter should send events to.
+func (s *Sink) URL() string {
+	return s.server.URL
+}
+
+// Enqueue scripts the answers to the next deliveries, in order. Once the
+// script is exhausted the default response is used.
+func (s *Sink) Enqueue(responses ...Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.script = append(s.script, responses...)
+}
+
+// SetDefault sets the answer used when no scripted response is pending.
+func (s *Sink) SetDefault(r Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.def = r
+}
+
+// AcceptBatches makes the Sink advertise the batch content mode on OPTIONS
+// requests.
+func (s *Sink) AcceptBatches(accept bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batch = accept
+}
+
+// Requests returns the number of delivery attempts received, including the
+// rejected ones.
+func (s *Sink) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// Events returns the events the Sink accepted, in arrival order.
+func (s *Sink) Events() []cloudevents.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	events := make([]cloudevents.Event, len(s.events))
+	copy(events, s.events)
+	return events
+}
+
+// WaitForEvents waits until the Sink accepted at least n events and returns
+// them. It fails t if that does not happen within timeout.
+func (s *Sink) WaitForEvents(t testing.TB, n int, timeout time.Duration) []cloudevents.Event {
+	t.Helper()
+	if !poll(timeout, func() bool { return len(s.Events()) >= n }) {
+		t.Fatalf("Timed out waiting for %d events, got %d", n, len(s.Events()))
+	}
+	return s.Events()
+}
+
+// Reset forgets the recorded events and the request count.
+func (s *Sink) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = 0
+	s.events = nil
+}
+
+func (s *Sink) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		s.mu.Lock()
+		batch := s.batch
+		s.mu.Unlock()
+		if batch {
+			w.Header().Set("Accept-Post", batchContentType+", application/cloudevents+json")
+		}
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	s.mu.Lock()
+	s.requests++
+	res := s.def
+	if len(s.script) > 0 {
+		res = s.script[0]
+		s.script = s.script[1:]
+	}
+	s.mu.Unlock()
+
+	events, err := decode(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if res.Delay > 0 {
+		select {
+		case <-time.After(res.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	code := res.statusCode()
+	if code >= 200 && code < 300 {
+		s.mu.Lock()
+		s.events = append(s.events, events...)
+		s.mu.Unlock()
+	}
+	w.WriteHeader(code)
+}
+
+// decode reads the events carried by r.
+func decode(r *http.Request) ([]cloudevents.Event, error) {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), batchContentType) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		var events []cloudevents.Event
+		if err := json.Unmarshal(body, &events); err != nil {
+			return nil, err
+		}
+		return events, nil
+	}
+
+	message := cehttp.NewMessageFromHttpRequest(r)
+	defer message.Finish(nil)
+	event, err := binding.ToEvent(r.Context(), message)
+	if err != nil {
+		return nil, err
+	}
+	return []cloudevents.Event{*event}, nil
+}
+
+// poll evaluates cond until it holds or timeout elapses.
+func poll(timeout time.Duration, cond func() bool) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		if cond() {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
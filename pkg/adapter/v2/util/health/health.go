@@ -0,0 +1,146 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package health tracks the outcome of the events an adapter sends and serves
+// it over HTTP, so that kubelet probes and controllers can tell whether the
+// adapter is still able to reach its sink.
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	nethttp "net/http"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/protocol/http"
+)
+
+const (
+	// LivenessPath answers OK for as long as the adapter is serving.
+	LivenessPath = "/healthz"
+
+	// StatusPath serves the Status of event delivery to the sink. It answers
+	// 503 Service Unavailable while the sink is unreachable.
+	StatusPath = "/health"
+)
+
+// Status is the state of event delivery reported by an adapter.
+type Status struct {
+	// SinkReachable is false when the last send got no response from the
+	// sink, e.g. because the connection was refused or timed out.
+	SinkReachable bool `json:"sinkReachable"`
+
+	// LastSuccessfulSend is when the sink last acknowledged an event.
+	LastSuccessfulSend *time.Time `json:"lastSuccessfulSend,omitempty"`
+
+	// LastError is the error of the last failed send.
+	LastError string `json:"lastError,omitempty"`
+
+	// LastErrorTime is when the last send failed.
+	LastErrorTime *time.Time `json:"lastErrorTime,omitempty"`
+
+	// ConsecutiveErrors is the number of sends that failed since the last
+	// successful one.
+	ConsecutiveErrors int64 `json:"consecutiveErrors"`
+
+	// SentEvents is the number of events the sink acknowledged.
+	SentEvents int64 `json:"sentEvents"`
+
+	// FailedEvents is the number of events that could not be delivered.
+	FailedEvents int64 `json:"failedEvents"`
+}
+
+// Tracker records send results and serves them as an http.Handler.
+type Tracker struct {
+	mu     sync.RWMutex
+	status Status
+
+	// now is overridden in tests.
+	now func() time.Time
+}
+
+var _ nethttp.Handler = (*Tracker)(nil)
+
+// NewTracker creates a Tracker that considers the sink reachable until a send
+// proves otherwise.
+func NewTracker() *Tracker {
+	return &Tracker{
+		status: Status{SinkReachable: true},
+		now:    time.Now,
+	}
+}
+
+// RecordResult records the result of sending one event.
+func (t *Tracker) RecordResult(result protocol.Result) {
+	var rres *http.RetriesResult
+	if cloudevents.ResultAs(result, &rres) {
+		result = rres.Result
+	}
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if cloudevents.IsACK(result) {
+		t.status.SinkReachable = true
+		t.status.LastSuccessfulSend = &now
+		t.status.ConsecutiveErrors = 0
+		t.status.SentEvents++
+		return
+	}
+
+	var res *http.Result
+	t.status.SinkReachable = cloudevents.ResultAs(result, &res)
+	t.status.LastError = result.Error()
+	t.status.LastErrorTime = &now
+	t.status.ConsecutiveErrors++
+	t.status.FailedEvents++
+}
+
+// Status returns a snapshot of the recorded results.
+func (t *Tracker) Status() Status {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.status
+}
+
+// ServeHTTP implements http.Handler for LivenessPath and StatusPath.
+func (t *Tracker) ServeHTTP(w nethttp.ResponseWriter, r *nethttp.Request) {
+	switch r.URL.Path {
+	case LivenessPath:
+		w.WriteHeader(nethttp.StatusOK)
+	case StatusPath:
+		status := t.Status()
+		w.Header().Set("Content-Type", "application/json")
+		if !status.SinkReachable {
+			w.WriteHeader(nethttp.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(status)
+	default:
+		w.WriteHeader(nethttp.StatusNotFound)
+	}
+}
+
+// NewServer returns a server for the Tracker listening on the given port.
+func NewServer(port int, t *Tracker) *nethttp.Server {
+	return &nethttp.Server{
+		Addr:    fmt.Sprint(":", port),
+		Handler: t,
+	}
+}
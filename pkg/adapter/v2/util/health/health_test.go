@@ -0,0 +1,141 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTracker(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	tracker := NewTracker()
+	tracker.now = func() time.Time { return now }
+
+	if got := tracker.Status(); !got.SinkReachable {
+		t.Error("Expected a new tracker to report the sink reachable")
+	}
+
+	tracker.RecordResult(http.NewResult(nethttp.StatusAccepted, "%w", protocol.ResultACK))
+	sent := now
+	want := Status{
+		SinkReachable:      true,
+		LastSuccessfulSend: &sent,
+		SentEvents:         1,
+	}
+	if diff := cmp.Diff(want, tracker.Status()); diff != "" {
+		t.Error("Unexpected status after an ack (-want, +got) =", diff)
+	}
+
+	now = now.Add(time.Minute)
+	nack := protocol.NewReceipt(false, "%w", http.NewResult(nethttp.StatusInternalServerError, "%w", protocol.ResultNACK))
+	tracker.RecordResult(nack)
+	failed := now
+	want = Status{
+		SinkReachable:      true,
+		LastSuccessfulSend: &sent,
+		LastError:          nack.Error(),
+		LastErrorTime:      &failed,
+		ConsecutiveErrors:  1,
+		SentEvents:         1,
+		FailedEvents:       1,
+	}
+	if diff := cmp.Diff(want, tracker.Status()); diff != "" {
+		t.Error("Unexpected status after a nack (-want, +got) =", diff)
+	}
+
+	now = now.Add(time.Minute)
+	refused := errors.New("connection refused")
+	tracker.RecordResult(&http.RetriesResult{Result: refused})
+	failed = now
+	want = Status{
+		SinkReachable:      false,
+		LastSuccessfulSend: &sent,
+		LastError:          refused.Error(),
+		LastErrorTime:      &failed,
+		ConsecutiveErrors:  2,
+		SentEvents:         1,
+		FailedEvents:       2,
+	}
+	if diff := cmp.Diff(want, tracker.Status()); diff != "" {
+		t.Error("Unexpected status after a connection error (-want, +got) =", diff)
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	tracker := NewTracker()
+
+	tests := []struct {
+		name       string
+		path       string
+		result     protocol.Result
+		wantStatus int
+	}{{
+		name:       "liveness",
+		path:       LivenessPath,
+		wantStatus: nethttp.StatusOK,
+	}, {
+		name:       "sink reachable",
+		path:       StatusPath,
+		result:     http.NewResult(nethttp.StatusAccepted, "%w", protocol.ResultACK),
+		wantStatus: nethttp.StatusOK,
+	}, {
+		name:       "sink unreachable",
+		path:       StatusPath,
+		result:     errors.New("connection refused"),
+		wantStatus: nethttp.StatusServiceUnavailable,
+	}, {
+		name:       "liveness while sink unreachable",
+		path:       LivenessPath,
+		wantStatus: nethttp.StatusOK,
+	}, {
+		name:       "unknown path",
+		path:       "/metrics",
+		wantStatus: nethttp.StatusNotFound,
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.result != nil {
+				tracker.RecordResult(tc.result)
+			}
+			w := httptest.NewRecorder()
+			tracker.ServeHTTP(w, httptest.NewRequest(nethttp.MethodGet, tc.path, nil))
+			if w.Code != tc.wantStatus {
+				t.Errorf("Expected status %d, got %d", tc.wantStatus, w.Code)
+			}
+			if tc.path != StatusPath {
+				return
+			}
+			var got Status
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatal("Failed to decode status:", err)
+			}
+			if diff := cmp.Diff(tracker.Status(), got); diff != "" {
+				t.Error("Unexpected status body (-want, +got) =", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,513 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package outbox implements a durable FIFO queue of records kept in a local
+// directory, so that an adapter can accept events while its sink is
+// unavailable and deliver them once it is back, including across restarts.
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"knative.dev/pkg/logging"
+)
+
+const (
+	segmentSuffix = ".segment"
+	tempSuffix    = ".tmp"
+	// quarantineSuffix is appended to the segments which cannot be read, to
+	// keep them aside for inspection.
+	quarantineSuffix = ".corrupt"
+
+	// DefaultRetryInterval is the initial interval between two attempts to
+	// handle the oldest records.
+	DefaultRetryInterval = time.Second
+
+	// DefaultMaxRetryInterval caps the exponentially growing interval between
+	// two attempts to handle the oldest records.
+	DefaultMaxRetryInterval = time.Minute
+
+	// DefaultMaxAge is how long a record failing to be handled is retried
+	// before it is dropped.
+	DefaultMaxAge = 24 * time.Hour
+)
+
+// ErrFull is returned by Append when the outbox holds its maximum number of
+// records.
+var ErrFull = errors.New("outbox is full")
+
+// permanentError is an error handling a record which would fail again.
+type permanentError struct {
+	error
+}
+
+func (e permanentError) Unwrap() error {
+	return e.error
+}
+
+// Permanent marks err as an error that handling the record again would not
+// fix, so that the record is dropped right away instead of retried.
+func Permanent(err error) error {
+	return permanentError{err}
+}
+
+// Outbox is a durable FIFO queue of records. Records are appended by any
+// number of goroutines and handed in order by Run.
+//
+// Concurrent appends are group-committed: the records appended while a
+// commit is in progress are stored together in a single segment file, with
+// a single flush to stable storage.
+type Outbox struct {
+	dir        string
+	maxRecords int
+
+	// RetryInterval and MaxRetryInterval bound the exponential backoff
+	// between two attempts to handle a record.
+	RetryInterval    time.Duration
+	MaxRetryInterval time.Duration
+
+	// MaxAttempts is the number of times a record is handed before it is
+	// dropped, 0 for no limit.
+	MaxAttempts int
+	// MaxAge is how long after it was appended a failing record is dropped,
+	// 0 for no limit.
+	MaxAge time.Duration
+	// OnDrop, when set, is called with the records dropped and the error
+	// handling them last. It is called with a nil record for the rest of a
+	// segment which cannot be read, which is quarantined.
+	OnDrop func(record []byte, err error)
+
+	// Concurrency is the number of records handled at once. Records are
+	// handed in order, but only handled in order when it is 1.
+	Concurrency int
+
+	mu       sync.Mutex
+	next     uint64
+	segments []segment
+	// count is the number of committed records waiting to be handled.
+	count int
+	// pending are the records waiting for the next commit, that batch tracks.
+	pending [][]byte
+	batch   *commit
+	// committing is set while a commit is in progress, committed is
+	// broadcast when it completes.
+	committing bool
+	committed  *sync.Cond
+
+	notify chan struct{}
+}
+
+// segment is a file of records committed together.
+type segment struct {
+	seq     uint64
+	records int
+}
+
+// commit is the outcome of the commit of a group of records.
+type commit struct {
+	done bool
+	err  error
+}
+
+// New returns the Outbox stored in dir, creating the directory if needed.
+// Records left in dir by a previous process are handled first. The segments
+// which cannot be read are quarantined by Run, after handling the records
+// before the unreadable part. The outbox is unbounded when maxRecords is not
+// positive.
+func New(dir string, maxRecords int) (*Outbox, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create the outbox directory: %w", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the outbox directory: %w", err)
+	}
+
+	o := &Outbox{
+		dir:              dir,
+		maxRecords:       maxRecords,
+		RetryInterval:    DefaultRetryInterval,
+		MaxRetryInterval: DefaultMaxRetryInterval,
+		MaxAge:           DefaultMaxAge,
+		Concurrency:      1,
+		notify:           make(chan struct{}, 1),
+	}
+	o.committed = sync.NewCond(&o.mu)
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasSuffix(name, tempSuffix) {
+			// A commit interrupted before it completed.
+			os.Remove(filepath.Join(dir, name))
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, segmentSuffix), 10, 64)
+		if err != nil || !strings.HasSuffix(name, segmentSuffix) {
+			continue
+		}
+		// The readable records of a segment which cannot be read are handled
+		// by Run, which quarantines it.
+		records, _, _ := o.read(seq)
+		o.segments = append(o.segments, segment{seq: seq, records: len(records)})
+		o.count += len(records)
+		if seq >= o.next {
+			o.next = seq + 1
+		}
+	}
+	sort.Slice(o.segments, func(i, j int) bool { return o.segments[i].seq < o.segments[j].seq })
+	return o, nil
+}
+
+// Len returns the number of records waiting to be handled.
+func (o *Outbox) Len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.count
+}
+
+// Append durably stores record at the end of the outbox.
+func (o *Outbox) Append(record []byte) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.maxRecords > 0 && o.count+len(o.pending) >= o.maxRecords {
+		return ErrFull
+	}
+	if o.batch == nil {
+		o.batch = &commit{}
+	}
+	c := o.batch
+	o.pending = append(o.pending, record)
+
+	for o.committing && !c.done {
+		o.committed.Wait()
+	}
+	if c.done {
+		return c.err
+	}
+
+	// No commit is in progress, this append commits the pending records.
+	records := o.pending
+	seq := o.next
+	o.pending, o.batch = nil, nil
+	o.committing = true
+	o.mu.Unlock()
+	err := o.write(seq, records)
+	o.mu.Lock()
+	o.committing = false
+	c.done, c.err = true, err
+	o.committed.Broadcast()
+	if err != nil {
+		return err
+	}
+
+	o.next++
+	o.segments = append(o.segments, segment{seq: seq, records: len(records)})
+	o.count += len(records)
+	select {
+	case o.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Run hands the records to handle in order until ctx is done. A record is
+// removed once handle returns nil, otherwise it is handed again after a
+// backoff, until it was handed MaxAttempts times, MaxAge elapsed since it
+// was appended, or handle returned a Permanent error. It is then dropped.
+// The records following a failing record are handed again after it. A
+// segment which cannot be read is quarantined once its readable records are
+// handled, and its unreadable part is dropped.
+func (o *Outbox) Run(ctx context.Context, handle func(context.Context, []byte) error) {
+	logger := logging.FromContext(ctx)
+	for {
+		segments := o.oldest()
+		if len(segments) == 0 {
+			select {
+			case <-o.notify:
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		var (
+			records  [][]byte
+			appended []time.Time
+			corrupt  = make(map[uint64]bool)
+		)
+		for _, seg := range segments {
+			recs, at, err := o.read(seg.seq)
+			if errors.Is(err, os.ErrNotExist) {
+				// The segment was removed behind our back, there is nothing to handle.
+				continue
+			} else if err != nil {
+				logger.Errorw("Failed to read outbox segment, quarantining it", zap.Uint64("segment", seg.seq),
+					zap.Int("records", len(recs)), zap.Error(err))
+				corrupt[seg.seq] = true
+				if o.OnDrop != nil {
+					o.OnDrop(nil, fmt.Errorf("failed to read outbox segment %d: %w", seg.seq, err))
+				}
+			}
+			records = append(records, recs...)
+			for range recs {
+				appended = append(appended, at)
+			}
+		}
+		if !o.handleGroup(ctx, logger, handle, records, appended) {
+			return
+		}
+		if err := o.remove(len(segments), corrupt); err != nil {
+			// The records are handed again after a restart, delivery is at least once.
+			logger.Warnw("Failed to remove outbox segment", zap.Error(err))
+		}
+	}
+}
+
+// handleGroup hands records, appended at the given times, until they are
+// all handled or dropped. It returns false when ctx is done first.
+func (o *Outbox) handleGroup(ctx context.Context, logger *zap.SugaredLogger, handle func(context.Context, []byte) error,
+	records [][]byte, appended []time.Time) bool {
+	done := make([]bool, len(records))
+	attempts := make([]int, len(records))
+	interval := o.RetryInterval
+	for ctx.Err() == nil {
+		errs := o.handleRecords(ctx, handle, records, done)
+		retry := false
+		for i, err := range errs {
+			if err == nil {
+				continue
+			}
+			attempts[i]++
+			var permanent permanentError
+			if errors.As(err, &permanent) || (o.MaxAttempts > 0 && attempts[i] >= o.MaxAttempts) ||
+				(o.MaxAge > 0 && time.Since(appended[i]) > o.MaxAge) {
+				logger.Errorw("Failed to handle outbox record, dropping it", zap.Int("attempts", attempts[i]), zap.Error(err))
+				if o.OnDrop != nil {
+					o.OnDrop(records[i], err)
+				}
+				done[i] = true
+				continue
+			}
+			retry = true
+			logger.Warnw("Failed to handle outbox record, retrying", zap.Int("attempts", attempts[i]),
+				zap.Duration("backoff", interval), zap.Error(err))
+		}
+		if !retry {
+			// The records following a dropped one are handed right away.
+			if allDone(done) {
+				return true
+			}
+			continue
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return false
+		}
+		if interval *= 2; interval > o.MaxRetryInterval {
+			interval = o.MaxRetryInterval
+		}
+	}
+	return false
+}
+
+func allDone(done []bool) bool {
+	for _, d := range done {
+		if !d {
+			return false
+		}
+	}
+	return true
+}
+
+// handleRecords hands the records not done in order, Concurrency at a time,
+// until one of them fails. It marks the handled records done and returns the
+// errors of the failing ones.
+func (o *Outbox) handleRecords(ctx context.Context, handle func(context.Context, []byte) error, records [][]byte, done []bool) []error {
+	concurrency := o.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
+	errs := make([]error, len(records))
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed bool
+	)
+	for i := range records {
+		if done[i] {
+			continue
+		}
+		sem <- struct{}{}
+		mu.Lock()
+		stop := failed || ctx.Err() != nil
+		mu.Unlock()
+		if stop {
+			<-sem
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := handle(ctx, records[i])
+			mu.Lock()
+			if err != nil {
+				errs[i], failed = err, true
+			} else {
+				done[i] = true
+			}
+			mu.Unlock()
+			<-sem
+		}(i)
+	}
+	wg.Wait()
+	return errs
+}
+
+// oldest returns the oldest segments holding at least Concurrency records,
+// or all of them.
+func (o *Outbox) oldest() []segment {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var n, records int
+	for n < len(o.segments) && (n == 0 || records < o.Concurrency) {
+		records += o.segments[n].records
+		n++
+	}
+	return append([]segment(nil), o.segments[:n]...)
+}
+
+// remove removes the n oldest segments, quarantining the corrupt ones.
+func (o *Outbox) remove(n int, corrupt map[uint64]bool) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var errs []string
+	for _, seg := range o.segments[:n] {
+		o.count -= seg.records
+		var err error
+		if corrupt[seg.seq] {
+			err = os.Rename(o.path(seg.seq), o.path(seg.seq)+quarantineSuffix)
+		} else {
+			err = os.Remove(o.path(seg.seq))
+		}
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err.Error())
+		}
+	}
+	o.segments = o.segments[n:]
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (o *Outbox) path(seq uint64) string {
+	return filepath.Join(o.dir, fmt.Sprintf("%020d%s", seq, segmentSuffix))
+}
+
+// write durably stores records in the segment seq. Each record is prefixed
+// with its length as a uvarint.
+func (o *Outbox) write(seq uint64, records [][]byte) error {
+	var buf bytes.Buffer
+	var size [binary.MaxVarintLen64]byte
+	for _, record := range records {
+		buf.Write(size[:binary.PutUvarint(size[:], uint64(len(record)))])
+		buf.Write(record)
+	}
+
+	path := o.path(seq)
+	if err := writeFileSync(path+tempSuffix, buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write records: %w", err)
+	}
+	if err := os.Rename(path+tempSuffix, path); err != nil {
+		os.Remove(path + tempSuffix)
+		return fmt.Errorf("failed to commit records: %w", err)
+	}
+	syncDir(o.dir)
+	return nil
+}
+
+// read returns the records of the segment seq and when they were appended.
+// When the segment is truncated, it returns the records before the truncation
+// along with the error.
+func (o *Outbox) read(seq uint64) ([][]byte, time.Time, error) {
+	f, err := os.Open(o.path(seq))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	var records [][]byte
+	for len(data) > 0 {
+		size, n := binary.Uvarint(data)
+		if n <= 0 || uint64(len(data)-n) < size {
+			return records, info.ModTime(), errors.New("truncated record")
+		}
+		records = append(records, data[n:n+int(size)])
+		data = data[n+int(size):]
+	}
+	return records, info.ModTime(), nil
+}
+
+// writeFileSync writes data to path and flushes it to stable storage.
+func writeFileSync(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// syncDir flushes the entries of dir, making a rename durable. It is best
+// effort as not every platform supports it.
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+}
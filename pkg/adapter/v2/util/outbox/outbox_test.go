@@ -0,0 +1,351 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package outbox
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// collector records the records it handles and fails the first failures ones.
+type collector struct {
+	mu       sync.Mutex
+	failures int
+	attempts int
+	handled  []string
+	done     chan struct{}
+	want     int
+}
+
+func newCollector(want, failures int) *collector {
+	return &collector{want: want, failures: failures, done: make(chan struct{})}
+}
+
+func (c *collector) handle(_ context.Context, record []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.attempts++
+	if c.failures > 0 {
+		c.failures--
+		return errors.New("sink unavailable")
+	}
+	c.handled = append(c.handled, string(record))
+	if len(c.handled) == c.want {
+		close(c.done)
+	}
+	return nil
+}
+
+func (c *collector) wait(t *testing.T) []string {
+	t.Helper()
+	select {
+	case <-c.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for records")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.handled
+}
+
+func newTestOutbox(t *testing.T, dir string, max int) *Outbox {
+	t.Helper()
+	o, err := New(dir, max)
+	if err != nil {
+		t.Fatal("New() =", err)
+	}
+	o.RetryInterval = time.Millisecond
+	o.MaxRetryInterval = 4 * time.Millisecond
+	return o
+}
+
+func TestOutboxDeliversInOrderWithRetries(t *testing.T) {
+	o := newTestOutbox(t, t.TempDir(), 0)
+	for _, r := range []string{"a", "b", "c"} {
+		if err := o.Append([]byte(r)); err != nil {
+			t.Fatal("Append() =", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := newCollector(4, 3)
+	go o.Run(ctx, c.handle)
+
+	// Records appended while running are delivered too.
+	if err := o.Append([]byte("d")); err != nil {
+		t.Fatal("Append() =", err)
+	}
+	if got, want := c.wait(t), []string{"a", "b", "c", "d"}; !cmp.Equal(got, want) {
+		t.Error("handled (-want, +got):", cmp.Diff(want, got))
+	}
+	if c.attempts != 7 {
+		t.Errorf("attempts = %d, want 7", c.attempts)
+	}
+	if err := waitFor(func() bool { return o.Len() == 0 }); err != nil {
+		t.Error("Len() =", o.Len(), "want 0")
+	}
+}
+
+func TestOutboxSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	o := newTestOutbox(t, dir, 0)
+	for _, r := range []string{"a", "b"} {
+		if err := o.Append([]byte(r)); err != nil {
+			t.Fatal("Append() =", err)
+		}
+	}
+	// An append interrupted by the restart.
+	if err := os.WriteFile(filepath.Join(dir, "00000000000000000002.segment.tmp"), []byte("partial"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	restarted := newTestOutbox(t, dir, 0)
+	if got := restarted.Len(); got != 2 {
+		t.Fatalf("Len() after restart = %d, want 2", got)
+	}
+	if err := restarted.Append([]byte("c")); err != nil {
+		t.Fatal("Append() =", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := newCollector(3, 0)
+	go restarted.Run(ctx, c.handle)
+	if got, want := c.wait(t), []string{"a", "b", "c"}; !cmp.Equal(got, want) {
+		t.Error("handled (-want, +got):", cmp.Diff(want, got))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "00000000000000000002.segment.tmp")); !os.IsNotExist(err) {
+		t.Error("partial record was not removed:", err)
+	}
+}
+
+func TestOutboxQuarantinesCorruptSegments(t *testing.T) {
+	dir := t.TempDir()
+	o := newTestOutbox(t, dir, 0)
+	if err := o.Append([]byte("a")); err != nil {
+		t.Fatal("Append() =", err)
+	}
+	// A segment with a record, followed by a truncated one.
+	if err := os.WriteFile(filepath.Join(dir, "00000000000000000001.segment"), []byte("\x01b\x05c"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	restarted := newTestOutbox(t, dir, 0)
+	if got := restarted.Len(); got != 2 {
+		t.Fatalf("Len() after restart = %d, want 2", got)
+	}
+	var (
+		mu      sync.Mutex
+		dropped []error
+	)
+	restarted.OnDrop = func(record []byte, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if record != nil {
+			t.Errorf("dropped record %q, want nil", record)
+		}
+		dropped = append(dropped, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := newCollector(2, 0)
+	go restarted.Run(ctx, c.handle)
+	if got, want := c.wait(t), []string{"a", "b"}; !cmp.Equal(got, want) {
+		t.Error("handled (-want, +got):", cmp.Diff(want, got))
+	}
+	if err := waitFor(func() bool { return restarted.Len() == 0 }); err != nil {
+		t.Error("Len() =", restarted.Len(), "want 0")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "00000000000000000001.segment.corrupt")); err != nil {
+		t.Error("corrupt segment was not quarantined:", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "00000000000000000001.segment")); !os.IsNotExist(err) {
+		t.Error("corrupt segment was not removed:", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(dropped) != 1 {
+		t.Errorf("dropped %d times, want 1", len(dropped))
+	}
+}
+
+func TestOutboxFull(t *testing.T) {
+	o := newTestOutbox(t, t.TempDir(), 1)
+	if err := o.Append([]byte("a")); err != nil {
+		t.Fatal("Append() =", err)
+	}
+	if err := o.Append([]byte("b")); !errors.Is(err, ErrFull) {
+		t.Errorf("Append() = %v, want %v", err, ErrFull)
+	}
+}
+
+func TestOutboxGroupCommit(t *testing.T) {
+	dir := t.TempDir()
+	o := newTestOutbox(t, dir, 0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := o.Append([]byte("r")); err != nil {
+				t.Error("Append() =", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := o.Len(); got != 50 {
+		t.Errorf("Len() = %d, want 50", got)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) == 0 || len(entries) > 50 {
+		t.Errorf("%d segments were committed, want between 1 and 50", len(entries))
+	}
+	if restarted := newTestOutbox(t, dir, 0); restarted.Len() != 50 {
+		t.Errorf("Len() after restart = %d, want 50", restarted.Len())
+	}
+}
+
+func TestOutboxDropsFailingRecords(t *testing.T) {
+	o := newTestOutbox(t, t.TempDir(), 0)
+	o.MaxAttempts = 3
+	for _, r := range []string{"poison", "permanent", "a"} {
+		if err := o.Append([]byte(r)); err != nil {
+			t.Fatal("Append() =", err)
+		}
+	}
+
+	var (
+		mu      sync.Mutex
+		dropped []string
+	)
+	o.OnDrop = func(record []byte, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		dropped = append(dropped, string(record))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := newCollector(1, 0)
+	go o.Run(ctx, func(ctx context.Context, record []byte) error {
+		switch string(record) {
+		case "poison":
+			return errors.New("rejected")
+		case "permanent":
+			return Permanent(errors.New("bad request"))
+		}
+		return c.handle(ctx, record)
+	})
+
+	if got, want := c.wait(t), []string{"a"}; !cmp.Equal(got, want) {
+		t.Error("handled (-want, +got):", cmp.Diff(want, got))
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if want := []string{"poison", "permanent"}; !cmp.Equal(dropped, want) {
+		t.Error("dropped (-want, +got):", cmp.Diff(want, dropped))
+	}
+}
+
+func TestOutboxDropsExpiredRecords(t *testing.T) {
+	o := newTestOutbox(t, t.TempDir(), 0)
+	o.MaxAge = time.Nanosecond
+	if err := o.Append([]byte("a")); err != nil {
+		t.Fatal("Append() =", err)
+	}
+
+	dropped := make(chan string, 1)
+	o.OnDrop = func(record []byte, err error) {
+		dropped <- string(record)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := newCollector(1, 1)
+	go o.Run(ctx, c.handle)
+
+	select {
+	case got := <-dropped:
+		if got != "a" {
+			t.Errorf("dropped %q, want a", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the record to be dropped")
+	}
+	if err := waitFor(func() bool { return o.Len() == 0 }); err != nil {
+		t.Error("Len() =", o.Len(), "want 0")
+	}
+}
+
+func TestOutboxConcurrency(t *testing.T) {
+	o := newTestOutbox(t, t.TempDir(), 0)
+	o.Concurrency = 3
+	for _, r := range []string{"a", "b", "c"} {
+		if err := o.Append([]byte(r)); err != nil {
+			t.Fatal("Append() =", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var (
+		mu       sync.Mutex
+		inFlight int
+	)
+	release := make(chan struct{})
+	c := newCollector(3, 0)
+	go o.Run(ctx, func(ctx context.Context, record []byte) error {
+		mu.Lock()
+		inFlight++
+		mu.Unlock()
+		<-release
+		return c.handle(ctx, record)
+	})
+
+	// The records of different appends are handled at once.
+	if err := waitFor(func() bool { mu.Lock(); defer mu.Unlock(); return inFlight == 3 }); err != nil {
+		t.Error("the records were not handled at once")
+	}
+	close(release)
+	if got := c.wait(t); len(got) != 3 {
+		t.Errorf("handled %v, want 3 records", got)
+	}
+}
+
+func waitFor(cond func() bool) error {
+	for i := 0; i < 500; i++ {
+		if cond() {
+			return nil
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return errors.New("timed out")
+}
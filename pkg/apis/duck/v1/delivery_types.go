@@ -20,6 +20,7 @@ import (
 	"context"
 
 	"github.com/rickb777/date/period"
+	corev1 "k8s.io/api/core/v1"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 
@@ -81,6 +82,32 @@ type DeliverySpec struct {
 	//
 	// +optional
 	RetryAfterMax *string `json:"retryAfterMax,omitempty"`
+
+	// Auth configures the credentials attached to the events delivered to the subscriber of a
+	// Trigger or a Subscription. It is not used when sending replies or to the dead letter sink.
+	//
+	// Note: This API is EXPERIMENTAL and might be changed at anytime.
+	// +optional
+	Auth *DeliveryAuth `json:"auth,omitempty"`
+}
+
+// DeliveryAuth configures the credentials attached to delivered events. Exactly one of Audience
+// and SecretRef must be set.
+type DeliveryAuth struct {
+	// Audience is the audience of the service account token minted by the sender and attached
+	// as bearer token. It must identify the subscriber, either as its resolved URI or as its
+	// origin (scheme://host), tokens are not minted for other audiences. As the sender is shared
+	// by all the namespaces, tokens are only minted for subscribers resolving to a Service in the
+	// namespace of the Trigger or Subscription.
+	// +optional
+	Audience *string `json:"audience,omitempty"`
+
+	// SecretRef references a Secret, in the namespace of the Trigger or Subscription, holding
+	// either a "token" key attached as bearer token, or "username" and "password" keys
+	// attached using basic authentication. The sender must be granted access to the Secret
+	// through a Role in that namespace.
+	// +optional
+	SecretRef *corev1.LocalObjectReference `json:"secretRef,omitempty"`
 }
 
 func (ds *DeliverySpec) Validate(ctx context.Context) *apis.FieldError {
@@ -134,9 +161,35 @@ func (ds *DeliverySpec) Validate(ctx context.Context) *apis.FieldError {
 		}
 	}
 
+	if ds.Auth != nil {
+		if feature.FromContext(ctx).IsEnabled(feature.DeliveryAuth) {
+			errs = errs.Also(ds.Auth.Validate(ctx).ViaField("auth"))
+		} else {
+			errs = errs.Also(apis.ErrDisallowedFields("auth"))
+		}
+	}
+
 	return errs
 }
 
+func (da *DeliveryAuth) Validate(ctx context.Context) *apis.FieldError {
+	switch {
+	case da.Audience != nil && da.SecretRef != nil:
+		return apis.ErrMultipleOneOf("audience", "secretRef")
+	case da.Audience != nil:
+		if *da.Audience == "" {
+			return apis.ErrInvalidValue(*da.Audience, "audience")
+		}
+	case da.SecretRef != nil:
+		if da.SecretRef.Name == "" {
+			return apis.ErrMissingField("secretRef.name")
+		}
+	default:
+		return apis.ErrMissingOneOf("audience", "secretRef")
+	}
+	return nil
+}
+
 // BackoffPolicyType is the type for backoff policies
 type BackoffPolicyType string
 
@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/utils/pointer"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
@@ -34,6 +35,9 @@ func TestDeliverySpecValidation(t *testing.T) {
 	deliveryRetryAfterEnabledCtx := feature.ToContext(context.TODO(), feature.Flags{
 		feature.DeliveryRetryAfter: feature.Enabled,
 	})
+	deliveryAuthEnabledCtx := feature.ToContext(context.TODO(), feature.Flags{
+		feature.DeliveryAuth: feature.Enabled,
+	})
 
 	invalidString := "invalid time"
 	bop := BackoffPolicyExponential
@@ -141,6 +145,38 @@ func TestDeliverySpecValidation(t *testing.T) {
 		want: func() *apis.FieldError {
 			return apis.ErrDisallowedFields("retryAfterMax")
 		}(),
+	}, {
+		name: "valid auth audience",
+		ctx:  deliveryAuthEnabledCtx,
+		spec: &DeliverySpec{Auth: &DeliveryAuth{Audience: pointer.StringPtr("subscriber")}},
+		want: nil,
+	}, {
+		name: "valid auth secretRef",
+		ctx:  deliveryAuthEnabledCtx,
+		spec: &DeliverySpec{Auth: &DeliveryAuth{SecretRef: &corev1.LocalObjectReference{Name: "credentials"}}},
+		want: nil,
+	}, {
+		name: "empty auth",
+		ctx:  deliveryAuthEnabledCtx,
+		spec: &DeliverySpec{Auth: &DeliveryAuth{}},
+		want: apis.ErrMissingOneOf("audience", "secretRef").ViaField("auth"),
+	}, {
+		name: "auth with audience and secretRef",
+		ctx:  deliveryAuthEnabledCtx,
+		spec: &DeliverySpec{Auth: &DeliveryAuth{
+			Audience:  pointer.StringPtr("subscriber"),
+			SecretRef: &corev1.LocalObjectReference{Name: "credentials"},
+		}},
+		want: apis.ErrMultipleOneOf("audience", "secretRef").ViaField("auth"),
+	}, {
+		name: "auth with empty secret name",
+		ctx:  deliveryAuthEnabledCtx,
+		spec: &DeliverySpec{Auth: &DeliveryAuth{SecretRef: &corev1.LocalObjectReference{}}},
+		want: apis.ErrMissingField("auth.secretRef.name"),
+	}, {
+		name: "disabled feature with auth",
+		spec: &DeliverySpec{Auth: &DeliveryAuth{Audience: pointer.StringPtr("subscriber")}},
+		want: apis.ErrDisallowedFields("auth"),
 	}}
 
 	for _, test := range tests {
@@ -74,6 +74,23 @@ const (
 	// annotation key used to specify the name of the channel for
 	// the triggers to subscribe to.
 	BrokerChannelNameStatusAnnotationKey = "knative.dev/channelName"
+
+	// BrokerAllowedSubjectsAnnotationKey is the annotation key on Brokers
+	// listing, comma separated, the authenticated subjects allowed to send
+	// events to the Broker. A trailing * matches any suffix. Only the service
+	// accounts of the Broker's namespace are allowed when it is missing.
+	BrokerAllowedSubjectsAnnotationKey = GroupName + "/allowed-subjects"
+
+	// BrokerEventsPerSecondAnnotationKey is the annotation key on Brokers
+	// lowering the maximum number of events per second accepted by the
+	// ingress for the Broker. It can't raise or disable the configured limit.
+	BrokerEventsPerSecondAnnotationKey = GroupName + "/ingress-events-per-second"
+
+	// BrokerBytesPerSecondAnnotationKey is the annotation key on Brokers
+	// lowering the maximum number of event data bytes per second accepted
+	// by the ingress for the Broker. It can't raise or disable the
+	// configured limit.
+	BrokerBytesPerSecondAnnotationKey = GroupName + "/ingress-bytes-per-second"
 )
 
 var (
@@ -18,11 +18,14 @@ package v1
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/google/go-cmp/cmp/cmpopts"
 
 	"knative.dev/pkg/apis"
 	"knative.dev/pkg/kmp"
+
+	"knative.dev/eventing/pkg/apis/eventing"
 )
 
 const (
@@ -37,6 +40,13 @@ func (b *Broker) Validate(ctx context.Context) *apis.FieldError {
 	if bc, ok := b.GetAnnotations()[BrokerClassAnnotationKey]; !ok || bc == "" {
 		errs = errs.Also(apis.ErrMissingField(BrokerClassAnnotationKey))
 	}
+	for _, key := range []string{eventing.BrokerEventsPerSecondAnnotationKey, eventing.BrokerBytesPerSecondAnnotationKey} {
+		if v, ok := b.GetAnnotations()[key]; ok {
+			if f, err := strconv.ParseFloat(v, 64); err != nil || f < 0 {
+				errs = errs.Also(apis.ErrInvalidValue(v, key))
+			}
+		}
+	}
 
 	errs = errs.Also(b.Spec.Validate(withNS).ViaField("spec"))
 	if apis.IsInUpdate(ctx) {
@@ -100,6 +100,30 @@ func TestValidate(t *testing.T) {
 				Annotations: map[string]string{"eventing.knative.dev/broker.class": "MTChannelBasedBroker"},
 			},
 		},
+	}, {
+		name: "valid ingress limits",
+		b: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					"eventing.knative.dev/broker.class":              "MTChannelBasedBroker",
+					"eventing.knative.dev/ingress-events-per-second": "100",
+					"eventing.knative.dev/ingress-bytes-per-second":  "0",
+				},
+			},
+		},
+	}, {
+		name: "invalid ingress limits",
+		b: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					"eventing.knative.dev/broker.class":              "MTChannelBasedBroker",
+					"eventing.knative.dev/ingress-events-per-second": "-1",
+					"eventing.knative.dev/ingress-bytes-per-second":  "lots",
+				},
+			},
+		},
+		want: apis.ErrInvalidValue("-1", "eventing.knative.dev/ingress-events-per-second").Also(
+			apis.ErrInvalidValue("lots", "eventing.knative.dev/ingress-bytes-per-second")),
 	}, {
 		name: "valid config",
 		b: Broker{
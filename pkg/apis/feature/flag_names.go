@@ -17,10 +17,12 @@ limitations under the License.
 package feature
 
 const (
-	KReferenceGroup    = "kreference-group"
-	DeliveryRetryAfter = "delivery-retryafter"
-	DeliveryTimeout    = "delivery-timeout"
-	KReferenceMapping  = "kreference-mapping"
-	StrictSubscriber   = "strict-subscriber"
-	NewTriggerFilters  = "new-trigger-filters"
+	KReferenceGroup     = "kreference-group"
+	DeliveryRetryAfter  = "delivery-retryafter"
+	DeliveryTimeout     = "delivery-timeout"
+	DeliveryAuth        = "delivery-auth"
+	KReferenceMapping   = "kreference-mapping"
+	StrictSubscriber    = "strict-subscriber"
+	NewTriggerFilters   = "new-trigger-filters"
+	TransportEncryption = "transport-encryption"
 )
@@ -30,6 +30,27 @@ const (
 	ApiServerSourceUpdateRefEventType = "dev.knative.apiserver.ref.update"
 	// ApiServerSourceDeleteRefEventType is the ApiServerSource CloudEvent type for ref deletions.
 	ApiServerSourceDeleteRefEventType = "dev.knative.apiserver.ref.delete"
+
+	// ApiServerSourceUpdateDiffEventType is the ApiServerSource CloudEvent type for diff updates.
+	ApiServerSourceUpdateDiffEventType = "dev.knative.apiserver.diff.update"
+
+	// ApiServerSourceKubernetesEventAddEventType is the ApiServerSource CloudEvent type for adds of Kubernetes Events.
+	ApiServerSourceKubernetesEventAddEventType = "dev.knative.apiserver.event.resource.add"
+	// ApiServerSourceKubernetesEventUpdateEventType is the ApiServerSource CloudEvent type for updates of Kubernetes Events.
+	ApiServerSourceKubernetesEventUpdateEventType = "dev.knative.apiserver.event.resource.update"
+	// ApiServerSourceKubernetesEventDeleteEventType is the ApiServerSource CloudEvent type for deletions of Kubernetes Events.
+	ApiServerSourceKubernetesEventDeleteEventType = "dev.knative.apiserver.event.resource.delete"
+
+	// ApiServerSourceKubernetesEventAddRefEventType is the ApiServerSource CloudEvent type for ref adds of Kubernetes Events.
+	ApiServerSourceKubernetesEventAddRefEventType = "dev.knative.apiserver.event.ref.add"
+	// ApiServerSourceKubernetesEventUpdateRefEventType is the ApiServerSource CloudEvent type for ref updates of Kubernetes Events.
+	ApiServerSourceKubernetesEventUpdateRefEventType = "dev.knative.apiserver.event.ref.update"
+	// ApiServerSourceKubernetesEventDeleteRefEventType is the ApiServerSource CloudEvent type for ref deletions of Kubernetes Events.
+	ApiServerSourceKubernetesEventDeleteRefEventType = "dev.knative.apiserver.event.ref.delete"
+
+	// ApiServerSourceSyncEventType is the ApiServerSource CloudEvent type sent when a resumed watch
+	// has to list the resources again.
+	ApiServerSourceSyncEventType = "dev.knative.apiserver.sync"
 )
 
 // ApiServerSourceEventReferenceModeTypes is the list of CloudEvent types the ApiServerSource with EventMode of ReferenceMode emits.
@@ -45,3 +66,34 @@ var ApiServerSourceEventResourceModeTypes = []string{
 	ApiServerSourceDeleteEventType,
 	ApiServerSourceUpdateEventType,
 }
+
+// ApiServerSourceEventDiffModeTypes is the list of CloudEvent types the ApiServerSource with EventMode of DiffMode emits.
+var ApiServerSourceEventDiffModeTypes = []string{
+	ApiServerSourceAddEventType,
+	ApiServerSourceDeleteEventType,
+	ApiServerSourceUpdateDiffEventType,
+}
+
+// ApiServerSourceKubernetesEventReferenceModeTypes is the list of CloudEvent types the ApiServerSource with EventMode
+// of ReferenceMode emits for Kubernetes Events, core/v1 or events.k8s.io/v1, when KubernetesEvents is set.
+var ApiServerSourceKubernetesEventReferenceModeTypes = []string{
+	ApiServerSourceKubernetesEventAddRefEventType,
+	ApiServerSourceKubernetesEventDeleteRefEventType,
+	ApiServerSourceKubernetesEventUpdateRefEventType,
+}
+
+// ApiServerSourceKubernetesEventResourceModeTypes is the list of CloudEvent types the ApiServerSource with EventMode
+// of ResourceMode emits for Kubernetes Events, core/v1 or events.k8s.io/v1, when KubernetesEvents is set.
+var ApiServerSourceKubernetesEventResourceModeTypes = []string{
+	ApiServerSourceKubernetesEventAddEventType,
+	ApiServerSourceKubernetesEventDeleteEventType,
+	ApiServerSourceKubernetesEventUpdateEventType,
+}
+
+// ApiServerSourceKubernetesEventDiffModeTypes is the list of CloudEvent types the ApiServerSource with EventMode
+// of DiffMode emits for Kubernetes Events, core/v1 or events.k8s.io/v1, when KubernetesEvents is set. Their
+// updates keep the ApiServerSourceUpdateDiffEventType type.
+var ApiServerSourceKubernetesEventDiffModeTypes = []string{
+	ApiServerSourceKubernetesEventAddEventType,
+	ApiServerSourceKubernetesEventDeleteEventType,
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"errors"
+	"strings"
+)
+
+// ParseIgnoredField parses field, an ignored field of ApiServerSourceSpec, and
+// returns the names of its path. field is a JSON Pointer (RFC 6901), so that
+// names containing `/` or `.`, like the keys of annotations, can be ignored.
+func ParseIgnoredField(field string) ([]string, error) {
+	if !strings.HasPrefix(field, "/") {
+		return nil, errors.New("the field must start with /")
+	}
+	names := strings.Split(field[1:], "/")
+	for i, name := range names {
+		if name == "" {
+			return nil, errors.New("the field must not have empty names")
+		}
+		// ~1 has to be unescaped before ~0, so that ~01 is ~1.
+		names[i] = strings.ReplaceAll(strings.ReplaceAll(name, "~1", "/"), "~0", "~")
+	}
+	return names, nil
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseIgnoredField(t *testing.T) {
+	testCases := map[string]struct {
+		field   string
+		want    []string
+		wantErr bool
+	}{
+		"field": {
+			field: "/status",
+			want:  []string{"status"},
+		},
+		"escaped names": {
+			field: "/metadata/annotations/example.com~1revision~0~01",
+			want:  []string{"metadata", "annotations", "example.com/revision~~1"},
+		},
+		"dot-separated path": {
+			field:   "metadata.annotations",
+			wantErr: true,
+		},
+		"empty name": {
+			field:   "/metadata//annotations",
+			wantErr: true,
+		},
+		"root": {
+			field:   "/",
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got, err := ParseIgnoredField(tc.field)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseIgnoredField() = %v, wanted error %v", err, tc.wantErr)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ParseIgnoredField() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
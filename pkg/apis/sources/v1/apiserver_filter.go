@@ -0,0 +1,69 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker/decls"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// FilterObjectVariable is the variable of the resource, in the filters of
+	// ApiServerSourceSpec.
+	FilterObjectVariable = "object"
+	// FilterOldObjectVariable is the variable of the previous version of the
+	// updated resource, or an empty object, in the filters of ApiServerSourceSpec.
+	FilterOldObjectVariable = "oldObject"
+	// FilterOperationVariable is the variable of the operation on the resource,
+	// `add`, `update` or `delete`, in the filters of ApiServerSourceSpec.
+	FilterOperationVariable = "operation"
+)
+
+var (
+	filterEnvOnce sync.Once
+	filterEnv     *cel.Env
+	filterEnvErr  error
+)
+
+// CompileApiServerSourceFilter compiles expression, a filter of ApiServerSourceSpec,
+// which has to evaluate to a bool.
+func CompileApiServerSourceFilter(expression string) (cel.Program, error) {
+	filterEnvOnce.Do(func() {
+		object := decls.NewMapType(decls.String, decls.Dyn)
+		filterEnv, filterEnvErr = cel.NewEnv(cel.Declarations(
+			decls.NewVar(FilterObjectVariable, object),
+			decls.NewVar(FilterOldObjectVariable, object),
+			decls.NewVar(FilterOperationVariable, decls.String),
+		))
+	})
+	if filterEnvErr != nil {
+		return nil, filterEnvErr
+	}
+
+	ast, iss := filterEnv.Compile(expression)
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+	if t := ast.ResultType(); !proto.Equal(t, decls.Bool) && !proto.Equal(t, decls.Dyn) {
+		return nil, fmt.Errorf("expression evaluates to %s instead of bool", cel.FormatType(t))
+	}
+	return filterEnv.Program(ast)
+}
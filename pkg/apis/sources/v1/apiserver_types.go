@@ -63,6 +63,13 @@ type ApiServerSourceSpec struct {
 	// +required
 	Resources []APIVersionKindSelector `json:"resources,omitempty"`
 
+	// NamespaceSelector watches the namespaced resources in all the namespaces
+	// matching the label selector, instead of the namespace of the source.
+	// The namespaces are watched dynamically.
+	// More info: http://kubernetes.io/docs/concepts/overview/working-with-objects/labels/#label-selectors
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
 	// ResourceOwner is an additional filter to only track resources that are
 	// owned by a specific resource type. If ResourceOwner matches Resources[n]
 	// then Resources[n] is allowed to pass the ResourceOwner filter.
@@ -72,10 +79,54 @@ type ApiServerSourceSpec struct {
 	// EventMode controls the format of the event.
 	// `Reference` sends a dataref event type for the resource under watch.
 	// `Resource` send the full resource lifecycle event.
+	// `Diff` sends the full resource on add and delete, and the JSON Patch
+	// between the old and new resource on update.
 	// Defaults to `Reference`
 	// +optional
 	EventMode string `json:"mode,omitempty"`
 
+	// IgnoredFields are the fields ignored when computing the changes of the
+	// updated resources in the `Diff` mode, as JSON Pointers such as `/status`
+	// or `/metadata/annotations/example.com~1revision`. Updates changing only
+	// ignored fields don't send events. `/metadata/resourceVersion` and
+	// `/metadata/managedFields` are always ignored.
+	// +optional
+	IgnoredFields []string `json:"ignoredFields,omitempty"`
+
+	// Filters are CEL expressions selecting the resource changes to send events
+	// about: an event is sent only when all the expressions evaluate to true.
+	// The expressions can use `object`, the resource, `oldObject`, the previous
+	// version of the updated resource without its resource version and managed
+	// fields, or an empty object, and `operation`, which is `add`, `update` or
+	// `delete`. For instance `operation != "update" ||
+	// object.spec.replicas != oldObject.spec.replicas`. Expressions failing to
+	// evaluate filter the change out.
+	// More info: https://github.com/google/cel-spec
+	// +optional
+	Filters []string `json:"filters,omitempty"`
+
+	// ResumeWatches persists the resource version of the watched resources in
+	// a ConfigMap owned by the source, so that the watches resume where they
+	// stopped when the receive adapter restarts, instead of missing the changes
+	// made in the meantime. The resource version only advances past the
+	// changes whose events were delivered, and the resources are listed at that
+	// resource version when resuming in the `Diff` mode or with filters, to
+	// know their previous versions. When a watch cannot resume because its
+	// resource version expired, the resources are listed again and a
+	// `dev.knative.apiserver.sync` event is sent, as changes may have been
+	// missed. The ServiceAccount needs to get and update ConfigMaps.
+	// +optional
+	ResumeWatches bool `json:"resumeWatches,omitempty"`
+
+	// KubernetesEvents sends the events about the watched Kubernetes Events,
+	// core/v1 or events.k8s.io/v1, with the `dev.knative.apiserver.event.ref.*`
+	// types in the `Reference` mode and the `dev.knative.apiserver.event.resource.*`
+	// types otherwise, the object they are about as subject, and their reason
+	// and type as the `reason` and `eventtype` extensions. Updates keep the
+	// `dev.knative.apiserver.diff.update` type in the `Diff` mode.
+	// +optional
+	KubernetesEvents bool `json:"kubernetesEvents,omitempty"`
+
 	// ServiceAccountName is the name of the ServiceAccount to use to run this
 	// source. Defaults to default if not set.
 	// +optional
@@ -118,6 +169,13 @@ type APIVersionKindSelector struct {
 	// More info: http://kubernetes.io/docs/concepts/overview/working-with-objects/labels/#label-selectors
 	// +optional
 	LabelSelector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// FieldSelector filters this source to objects to those resources pass the
+	// field selector, evaluated by the Kubernetes ApiServer. For instance
+	// `status.phase=Failed`.
+	// More info: https://kubernetes.io/docs/concepts/overview/working-with-objects/field-selectors/
+	// +optional
+	FieldSelector string `json:"fieldSelector,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -20,6 +20,8 @@ import (
 	"context"
 	"strings"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"knative.dev/pkg/apis"
@@ -30,6 +32,9 @@ const (
 	ReferenceMode = "Reference"
 	// ResourceMode produces payloads of ResourceEvent
 	ResourceMode = "Resource"
+	// DiffMode produces payloads of ResourceEvent on add and delete, and of
+	// ResourceDiff on update
+	DiffMode = "Diff"
 )
 
 func (c *ApiServerSource) Validate(ctx context.Context) *apis.FieldError {
@@ -41,12 +46,27 @@ func (cs *ApiServerSourceSpec) Validate(ctx context.Context) *apis.FieldError {
 
 	// Validate mode, if can be empty or set as certain value
 	switch cs.EventMode {
-	case ReferenceMode, ResourceMode:
+	case ReferenceMode, ResourceMode, DiffMode:
 	// EventMode is valid.
 	default:
 		errs = errs.Also(apis.ErrInvalidValue(cs.EventMode, "mode"))
 	}
 
+	if len(cs.IgnoredFields) > 0 && cs.EventMode != DiffMode {
+		errs = errs.Also(apis.ErrDisallowedFields("ignoredFields"))
+	}
+	for i, field := range cs.IgnoredFields {
+		if _, err := ParseIgnoredField(field); err != nil {
+			errs = errs.Also(apis.ErrInvalidArrayValue(field, "ignoredFields", i))
+		}
+	}
+
+	for i, filter := range cs.Filters {
+		if _, err := CompileApiServerSourceFilter(filter); err != nil {
+			errs = errs.Also(apis.ErrInvalidArrayValue(err.Error(), "filters", i))
+		}
+	}
+
 	// Validate sink
 	errs = errs.Also(cs.Sink.Validate(ctx).ViaField("sink"))
 
@@ -61,6 +81,22 @@ func (cs *ApiServerSourceSpec) Validate(ctx context.Context) *apis.FieldError {
 		if strings.TrimSpace(res.Kind) == "" {
 			errs = errs.Also(apis.ErrMissingField("kind").ViaFieldIndex("resources", i))
 		}
+		if res.LabelSelector != nil {
+			if _, err := metav1.LabelSelectorAsSelector(res.LabelSelector); err != nil {
+				errs = errs.Also(apis.ErrInvalidValue(err.Error(), "selector").ViaFieldIndex("resources", i))
+			}
+		}
+		if res.FieldSelector != "" {
+			if _, err := fields.ParseSelector(res.FieldSelector); err != nil {
+				errs = errs.Also(apis.ErrInvalidValue(res.FieldSelector, "fieldSelector").ViaFieldIndex("resources", i))
+			}
+		}
+	}
+
+	if cs.NamespaceSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(cs.NamespaceSelector); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(err.Error(), "namespaceSelector"))
+		}
 	}
 
 	if cs.ResourceOwner != nil {
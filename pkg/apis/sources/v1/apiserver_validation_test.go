@@ -23,6 +23,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 
 	"github.com/google/go-cmp/cmp"
@@ -204,6 +205,174 @@ func TestAPIServerValidation(t *testing.T) {
 			},
 		},
 		want: errors.New("missing field(s): resources"),
+	}, {
+		name: "valid field and namespace selectors",
+		spec: ApiServerSourceSpec{
+			EventMode: "Resource",
+			Resources: []APIVersionKindSelector{{
+				APIVersion:    "v1",
+				Kind:          "Pod",
+				FieldSelector: "status.phase=Failed",
+			}},
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"team": "payments"},
+			},
+			SourceSpec: duckv1.SourceSpec{
+				Sink: duckv1.Destination{
+					Ref: &duckv1.KReference{
+						APIVersion: "v1",
+						Kind:       "broker",
+						Name:       "default",
+					},
+				},
+			},
+		},
+		want: nil,
+	}, {
+		name: "invalid field selector",
+		spec: ApiServerSourceSpec{
+			EventMode: "Resource",
+			Resources: []APIVersionKindSelector{{
+				APIVersion:    "v1",
+				Kind:          "Pod",
+				FieldSelector: "status.phase",
+			}},
+			SourceSpec: duckv1.SourceSpec{
+				Sink: duckv1.Destination{
+					Ref: &duckv1.KReference{
+						APIVersion: "v1",
+						Kind:       "broker",
+						Name:       "default",
+					},
+				},
+			},
+		},
+		want: errors.New("invalid value: status.phase: resources[0].fieldSelector"),
+	}, {
+		name: "invalid namespace selector",
+		spec: ApiServerSourceSpec{
+			EventMode: "Resource",
+			Resources: []APIVersionKindSelector{{
+				APIVersion: "v1",
+				Kind:       "Pod",
+			}},
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{
+					Key:      "team",
+					Operator: "Between",
+				}},
+			},
+			SourceSpec: duckv1.SourceSpec{
+				Sink: duckv1.Destination{
+					Ref: &duckv1.KReference{
+						APIVersion: "v1",
+						Kind:       "broker",
+						Name:       "default",
+					},
+				},
+			},
+		},
+		want: errors.New(`invalid value: "Between" is not a valid pod selector operator: namespaceSelector`),
+	}, {
+		name: "valid diff mode with ignored fields",
+		spec: ApiServerSourceSpec{
+			EventMode: "Diff",
+			Resources: []APIVersionKindSelector{{
+				APIVersion: "v1",
+				Kind:       "Pod",
+			}},
+			IgnoredFields: []string{"/status", "/metadata/annotations/example.com~1revision"},
+			SourceSpec: duckv1.SourceSpec{
+				Sink: duckv1.Destination{
+					Ref: &duckv1.KReference{
+						APIVersion: "v1",
+						Kind:       "broker",
+						Name:       "default",
+					},
+				},
+			},
+		},
+		want: nil,
+	}, {
+		name: "ignored fields without diff mode",
+		spec: ApiServerSourceSpec{
+			EventMode: "Resource",
+			Resources: []APIVersionKindSelector{{
+				APIVersion: "v1",
+				Kind:       "Pod",
+			}},
+			IgnoredFields: []string{"/status"},
+			SourceSpec: duckv1.SourceSpec{
+				Sink: duckv1.Destination{
+					Ref: &duckv1.KReference{
+						APIVersion: "v1",
+						Kind:       "broker",
+						Name:       "default",
+					},
+				},
+			},
+		},
+		want: errors.New("must not set the field(s): ignoredFields"),
+	}, {
+		name: "invalid ignored field",
+		spec: ApiServerSourceSpec{
+			EventMode: "Diff",
+			Resources: []APIVersionKindSelector{{
+				APIVersion: "v1",
+				Kind:       "Pod",
+			}},
+			IgnoredFields: []string{"/metadata//annotations", "status"},
+			SourceSpec: duckv1.SourceSpec{
+				Sink: duckv1.Destination{
+					Ref: &duckv1.KReference{
+						APIVersion: "v1",
+						Kind:       "broker",
+						Name:       "default",
+					},
+				},
+			},
+		},
+		want: errors.New("invalid value: /metadata//annotations: ignoredFields[0]\ninvalid value: status: ignoredFields[1]"),
+	}, {
+		name: "valid filters",
+		spec: ApiServerSourceSpec{
+			EventMode: "Resource",
+			Resources: []APIVersionKindSelector{{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+			}},
+			Filters: []string{`operation != "update" || object.spec.replicas != oldObject.spec.replicas`},
+			SourceSpec: duckv1.SourceSpec{
+				Sink: duckv1.Destination{
+					Ref: &duckv1.KReference{
+						APIVersion: "v1",
+						Kind:       "broker",
+						Name:       "default",
+					},
+				},
+			},
+		},
+		want: nil,
+	}, {
+		name: "filter not evaluating to a bool",
+		spec: ApiServerSourceSpec{
+			EventMode: "Resource",
+			Resources: []APIVersionKindSelector{{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+			}},
+			Filters: []string{`object.metadata.name == "foo"`, `operation + "s"`},
+			SourceSpec: duckv1.SourceSpec{
+				Sink: duckv1.Destination{
+					Ref: &duckv1.KReference{
+						APIVersion: "v1",
+						Kind:       "broker",
+						Name:       "default",
+					},
+				},
+			},
+		},
+		want: errors.New("invalid value: expression evaluates to string instead of bool: filters[1]"),
 	}, {
 		name: "invalid spec ceOverrides validation",
 		spec: ApiServerSourceSpec{
@@ -22,6 +22,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"knative.dev/pkg/apis"
+	"knative.dev/pkg/ptr"
 )
 
 func (s *ContainerSource) SetDefaults(ctx context.Context) {
@@ -39,4 +40,29 @@ func (ss *ContainerSourceSpec) SetDefaults(ctx context.Context) {
 		containers = append(containers, c)
 	}
 	ss.Template.Spec.Containers = containers
+
+	if ss.Scaling != nil {
+		ss.Scaling.SetDefaults(ctx)
+	}
+}
+
+const (
+	// DefaultContainerSourceCPUTarget is the average CPU utilization, in
+	// percent, used when a ContainerSource autoscales without a metric.
+	DefaultContainerSourceCPUTarget = 80
+)
+
+func (s *ContainerSourceScaling) SetDefaults(ctx context.Context) {
+	if s.MaxReplicas == nil {
+		return
+	}
+	if s.MinReplicas == nil {
+		s.MinReplicas = ptr.Int32(1)
+	}
+	if s.Metric == nil {
+		s.Metric = &ContainerSourceScalingMetric{
+			Type:   ContainerSourceScalingMetricCPU,
+			Target: DefaultContainerSourceCPUTarget,
+		}
+	}
 }
@@ -23,6 +23,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/ptr"
 )
 
 func TestContainerSourceDefaults(t *testing.T) {
@@ -102,6 +103,40 @@ func TestContainerSourceDefaults(t *testing.T) {
 				},
 			},
 		},
+		"scaling defaults": {
+			initial: ContainerSource{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-name",
+					Namespace: "test-namespace",
+				},
+				Spec: ContainerSourceSpec{
+					Scaling: &ContainerSourceScaling{
+						MaxReplicas: ptr.Int32(3),
+					},
+				},
+			},
+			expected: ContainerSource{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-name",
+					Namespace: "test-namespace",
+				},
+				Spec: ContainerSourceSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{},
+						},
+					},
+					Scaling: &ContainerSourceScaling{
+						MinReplicas: ptr.Int32(1),
+						MaxReplicas: ptr.Int32(3),
+						Metric: &ContainerSourceScalingMetric{
+							Type:   ContainerSourceScalingMetricCPU,
+							Target: DefaultContainerSourceCPUTarget,
+						},
+					},
+				},
+			},
+		},
 	}
 	for n, tc := range testCases {
 		t.Run(n, func(t *testing.T) {
@@ -17,8 +17,11 @@ limitations under the License.
 package v1
 
 import (
+	"fmt"
+
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"knative.dev/pkg/apis"
 )
 
@@ -31,8 +34,28 @@ const (
 
 	// ContainerSourceConditionReceiveAdapterReady has status True when the ContainerSource's ReceiveAdapter is ready.
 	ContainerSourceConditionReceiveAdapterReady apis.ConditionType = "ReceiveAdapterReady"
+
+	// ContainerSourceConditionImagesPulled has status False when a container image of the
+	// ContainerSource's pods can not be pulled.
+	ContainerSourceConditionImagesPulled apis.ConditionType = "ImagesPulled"
+
+	// ContainerSourceConditionContainersRunning has status False when a container of the
+	// ContainerSource's pods is crash looping or can not be started.
+	ContainerSourceConditionContainersRunning apis.ConditionType = "ContainersRunning"
+
+	// ContainerSourceConditionEventsSent reports whether the ContainerSource's adapter can
+	// deliver events, as observed from the readiness of the container serving its health
+	// endpoints. It has status False while the sink is unreachable.
+	ContainerSourceConditionEventsSent apis.ConditionType = "EventsSent"
 )
 
+// imagePullReasons are the container waiting reasons reported when an image can not be pulled.
+var imagePullReasons = sets.NewString("ErrImagePull", "ImagePullBackOff", "InvalidImageName", "ErrImageNeverPull")
+
+// containerStartReasons are the container waiting reasons reported when a container keeps
+// failing to start or run.
+var containerStartReasons = sets.NewString("CrashLoopBackOff", "CreateContainerConfigError", "CreateContainerError", "RunContainerError")
+
 var containerCondSet = apis.NewLivingConditionSet(
 	ContainerSourceConditionSinkBindingReady,
 	ContainerSourceConditionReceiveAdapterReady,
@@ -108,3 +131,74 @@ func (s *ContainerSourceStatus) PropagateReceiveAdapterStatus(d *appsv1.Deployme
 		containerCondSet.Manage(s).MarkUnknown(ContainerSourceConditionReceiveAdapterReady, "DeploymentUnavailable", "The Deployment '%s' is unavailable.", d.Name)
 	}
 }
+
+// PropagatePodStatuses uses the container statuses of the ContainerSource's pods to determine
+// if ContainerSourceConditionImagesPulled and ContainerSourceConditionContainersRunning should
+// be marked as true, false or unknown. These conditions do not affect readiness, they explain it.
+func (s *ContainerSourceStatus) PropagatePodStatuses(pods []*corev1.Pod) {
+	if len(pods) == 0 {
+		containerCondSet.Manage(s).MarkUnknown(ContainerSourceConditionImagesPulled, "NoPods", "The ContainerSource has no pods.")
+		containerCondSet.Manage(s).MarkUnknown(ContainerSourceConditionContainersRunning, "NoPods", "The ContainerSource has no pods.")
+		return
+	}
+
+	pulled, running := true, true
+	for _, pod := range pods {
+		statuses := make([]corev1.ContainerStatus, 0, len(pod.Status.InitContainerStatuses)+len(pod.Status.ContainerStatuses))
+		statuses = append(statuses, pod.Status.InitContainerStatuses...)
+		statuses = append(statuses, pod.Status.ContainerStatuses...)
+		for _, cs := range statuses {
+			waiting := cs.State.Waiting
+			if waiting == nil {
+				continue
+			}
+			switch {
+			case pulled && imagePullReasons.Has(waiting.Reason):
+				pulled = false
+				containerCondSet.Manage(s).MarkFalse(ContainerSourceConditionImagesPulled, waiting.Reason,
+					"Container %q in pod %q: %s", cs.Name, pod.Name, waiting.Message)
+			case running && containerStartReasons.Has(waiting.Reason):
+				running = false
+				containerCondSet.Manage(s).MarkFalse(ContainerSourceConditionContainersRunning, waiting.Reason,
+					"Container %q in pod %q: %s", cs.Name, pod.Name, containerFailureMessage(cs))
+			}
+		}
+	}
+	if pulled {
+		containerCondSet.Manage(s).MarkTrue(ContainerSourceConditionImagesPulled)
+	}
+	if running {
+		containerCondSet.Manage(s).MarkTrue(ContainerSourceConditionContainersRunning)
+	}
+}
+
+// containerFailureMessage describes why a container keeps failing, including how its last run ended.
+func containerFailureMessage(cs corev1.ContainerStatus) string {
+	msg := cs.State.Waiting.Message
+	if t := cs.LastTerminationState.Terminated; t != nil {
+		msg = fmt.Sprintf("%s (restarted %d times, last exit code %d: %s)", msg, cs.RestartCount, t.ExitCode, t.Reason)
+	}
+	return msg
+}
+
+// MarkEventsSent sets the condition that the ContainerSource's adapter can reach its sink.
+func (s *ContainerSourceStatus) MarkEventsSent() {
+	containerCondSet.Manage(s).MarkTrueWithReason(ContainerSourceConditionEventsSent, "SinkReachable",
+		"The adapter can reach its sink.")
+}
+
+// MarkEventsNotSent sets the condition that the ContainerSource's adapter can not reach its sink.
+func (s *ContainerSourceStatus) MarkEventsNotSent(reason, messageFormat string, messageA ...interface{}) {
+	containerCondSet.Manage(s).MarkFalse(ContainerSourceConditionEventsSent, reason, messageFormat, messageA...)
+}
+
+// MarkEventsSentUnknown sets the condition that the ContainerSource's adapter health is not known.
+func (s *ContainerSourceStatus) MarkEventsSentUnknown(reason, messageFormat string, messageA ...interface{}) {
+	containerCondSet.Manage(s).MarkUnknown(ContainerSourceConditionEventsSent, reason, messageFormat, messageA...)
+}
+
+// ClearEventsSent removes the ContainerSourceConditionEventsSent condition once the
+// ContainerSource no longer exposes a health endpoint.
+func (s *ContainerSourceStatus) ClearEventsSent() {
+	_ = containerCondSet.Manage(s).ClearCondition(ContainerSourceConditionEventsSent)
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 )
@@ -336,3 +337,132 @@ func TestContainerSourceStatusGetCondition(t *testing.T) {
 		})
 	}
 }
+
+func TestContainerSourceStatusPropagatePodStatuses(t *testing.T) {
+	tests := []struct {
+		name        string
+		pods        []*corev1.Pod
+		wantPulled  corev1.ConditionStatus
+		wantRunning corev1.ConditionStatus
+		wantReason  string
+	}{{
+		name:        "no pods",
+		wantPulled:  corev1.ConditionUnknown,
+		wantRunning: corev1.ConditionUnknown,
+	}, {
+		name: "running",
+		pods: []*corev1.Pod{
+			podWithStatuses("pod", corev1.ContainerStatus{
+				Name:  "source",
+				State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			}),
+		},
+		wantPulled:  corev1.ConditionTrue,
+		wantRunning: corev1.ConditionTrue,
+	}, {
+		name: "image pull backoff",
+		pods: []*corev1.Pod{
+			podWithStatuses("pod", corev1.ContainerStatus{
+				Name:  "source",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "Back-off pulling image"}},
+			}),
+		},
+		wantPulled:  corev1.ConditionFalse,
+		wantRunning: corev1.ConditionTrue,
+		wantReason:  "ImagePullBackOff",
+	}, {
+		name: "crash loop in second pod",
+		pods: []*corev1.Pod{
+			podWithStatuses("pod-1", corev1.ContainerStatus{
+				Name:  "source",
+				State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			}),
+			podWithStatuses("pod-2", corev1.ContainerStatus{
+				Name:         "source",
+				RestartCount: 4,
+				State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff", Message: "back-off 1m20s"}},
+				LastTerminationState: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"},
+				},
+			}),
+		},
+		wantPulled:  corev1.ConditionTrue,
+		wantRunning: corev1.ConditionFalse,
+		wantReason:  "CrashLoopBackOff",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := &ContainerSourceStatus{}
+			s.InitializeConditions()
+			s.PropagatePodStatuses(test.pods)
+
+			pulled := s.GetCondition(ContainerSourceConditionImagesPulled)
+			if pulled.Status != test.wantPulled {
+				t.Errorf("Unexpected ImagesPulled status, want %s, got %s", test.wantPulled, pulled.Status)
+			}
+			running := s.GetCondition(ContainerSourceConditionContainersRunning)
+			if running.Status != test.wantRunning {
+				t.Errorf("Unexpected ContainersRunning status, want %s, got %s", test.wantRunning, running.Status)
+			}
+			if test.wantReason != "" && pulled.Reason != test.wantReason && running.Reason != test.wantReason {
+				t.Errorf("Expected a condition with reason %q, got %q and %q", test.wantReason, pulled.Reason, running.Reason)
+			}
+			if s.GetCondition(apis.ConditionReady).Status != corev1.ConditionUnknown {
+				t.Error("Pod statuses must not change the Ready condition")
+			}
+		})
+	}
+}
+
+func TestContainerSourceStatusCrashLoopMessage(t *testing.T) {
+	s := &ContainerSourceStatus{}
+	s.PropagatePodStatuses([]*corev1.Pod{
+		podWithStatuses("pod", corev1.ContainerStatus{
+			Name:         "source",
+			RestartCount: 4,
+			State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff", Message: "back-off 1m20s"}},
+			LastTerminationState: corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"},
+			},
+		}),
+	})
+	want := `Container "source" in pod "pod": back-off 1m20s (restarted 4 times, last exit code 1: Error)`
+	if got := s.GetCondition(ContainerSourceConditionContainersRunning).Message; got != want {
+		t.Errorf("Unexpected message, want %q, got %q", want, got)
+	}
+}
+
+func TestContainerSourceStatusEventsSent(t *testing.T) {
+	s := &ContainerSourceStatus{}
+	s.InitializeConditions()
+
+	s.MarkEventsSent()
+	got := s.GetCondition(ContainerSourceConditionEventsSent)
+	if got.Status != corev1.ConditionTrue || got.Reason != "SinkReachable" {
+		t.Errorf("Unexpected condition while the sink is reachable: %+v", got)
+	}
+
+	s.MarkEventsNotSent("SinkUnreachable", "connection refused")
+	got = s.GetCondition(ContainerSourceConditionEventsSent)
+	if got.Status != corev1.ConditionFalse || got.Severity != apis.ConditionSeverityInfo {
+		t.Errorf("Unexpected condition while the sink is unreachable: %+v", got)
+	}
+	if s.GetCondition(apis.ConditionReady).Status != corev1.ConditionUnknown {
+		t.Error("EventsSent must not change the Ready condition")
+	}
+
+	s.ClearEventsSent()
+	if got := s.GetCondition(ContainerSourceConditionEventsSent); got != nil {
+		t.Error("Expected EventsSent to be cleared, got", got)
+	}
+}
+
+func podWithStatuses(name string, statuses ...corev1.ContainerStatus) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.PodStatus{
+			ContainerStatuses: statuses,
+		},
+	}
+}
@@ -21,6 +21,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/kmeta"
@@ -59,6 +60,63 @@ type ContainerSourceSpec struct {
 
 	// Template describes the pods that will be created
 	Template corev1.PodTemplateSpec `json:"template"`
+
+	// Scaling configures horizontal autoscaling and a disruption budget for
+	// the pods running the ContainerSource.
+	// +optional
+	Scaling *ContainerSourceScaling `json:"scaling,omitempty"`
+}
+
+// ContainerSourceScaling describes how the ContainerSource's Deployment is
+// scaled and protected from voluntary disruptions such as node drains.
+type ContainerSourceScaling struct {
+	// MinReplicas is the lower bound on the number of pods. Defaults to 1.
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas is the upper bound on the number of pods. When set, a
+	// HorizontalPodAutoscaler is created for the ContainerSource.
+	// +optional
+	MaxReplicas *int32 `json:"maxReplicas,omitempty"`
+
+	// Metric is the metric the HorizontalPodAutoscaler scales on. Defaults to
+	// 80% average CPU utilization.
+	// +optional
+	Metric *ContainerSourceScalingMetric `json:"metric,omitempty"`
+
+	// MinAvailable is the number or percentage of pods that must remain
+	// available during voluntary disruptions. When set, a PodDisruptionBudget
+	// is created for the ContainerSource.
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+}
+
+// ContainerSourceScalingMetricType is the kind of metric a ContainerSource
+// scales on.
+type ContainerSourceScalingMetricType string
+
+const (
+	// ContainerSourceScalingMetricCPU scales on the average CPU utilization of
+	// the pods, as a percentage of their CPU requests.
+	ContainerSourceScalingMetricCPU ContainerSourceScalingMetricType = "cpu"
+
+	// ContainerSourceScalingMetricSendRate scales on the average number of
+	// events per second sent by each pod, as recorded by the source metrics
+	// reporter. It requires a custom metrics adapter serving the rate of the
+	// event_count counter as event_send_rate, such as the Prometheus adapter
+	// configured by config/monitoring/metrics/prometheus-adapter.
+	ContainerSourceScalingMetricSendRate ContainerSourceScalingMetricType = "sendRate"
+)
+
+// ContainerSourceScalingMetric is the metric and per-pod target used to scale
+// a ContainerSource.
+type ContainerSourceScalingMetric struct {
+	// Type is the metric to scale on, either cpu or sendRate.
+	Type ContainerSourceScalingMetricType `json:"type"`
+
+	// Target is the average value per pod the autoscaler aims for: a
+	// utilization percentage for cpu and events per second for sendRate.
+	Target int32 `json:"target"`
 }
 
 // GetGroupVersionKind returns the GroupVersionKind.
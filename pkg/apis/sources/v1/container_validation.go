@@ -18,8 +18,10 @@ package v1
 
 import (
 	"context"
+	"math"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"knative.dev/pkg/apis"
 )
 
@@ -44,10 +46,47 @@ func (cs *ContainerSourceSpec) Validate(ctx context.Context) *apis.FieldError {
 			}
 		}
 	}
+	if cs.Scaling != nil {
+		errs = errs.Also(cs.Scaling.Validate(ctx).ViaField("scaling"))
+	}
 	errs = errs.Also(cs.SourceSpec.Validate(ctx))
 	return errs
 }
 
+func (s *ContainerSourceScaling) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	if s.MinReplicas != nil && *s.MinReplicas < 1 {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(*s.MinReplicas, 1, math.MaxInt32, "minReplicas"))
+	}
+	if s.MaxReplicas != nil {
+		min := int32(1)
+		if s.MinReplicas != nil && *s.MinReplicas > min {
+			min = *s.MinReplicas
+		}
+		if *s.MaxReplicas < min {
+			errs = errs.Also(apis.ErrOutOfBoundsValue(*s.MaxReplicas, min, math.MaxInt32, "maxReplicas"))
+		}
+	} else if s.MinReplicas != nil || s.Metric != nil {
+		errs = errs.Also(apis.ErrMissingField("maxReplicas"))
+	}
+	if s.Metric != nil {
+		switch s.Metric.Type {
+		case ContainerSourceScalingMetricCPU, ContainerSourceScalingMetricSendRate:
+		default:
+			errs = errs.Also(apis.ErrInvalidValue(s.Metric.Type, "metric.type"))
+		}
+		if s.Metric.Target < 1 {
+			errs = errs.Also(apis.ErrOutOfBoundsValue(s.Metric.Target, 1, math.MaxInt32, "metric.target"))
+		}
+	}
+	if s.MinAvailable != nil {
+		if v, err := intstr.GetScaledValueFromIntOrPercent(s.MinAvailable, 100, true); err != nil || v < 0 {
+			errs = errs.Also(apis.ErrInvalidValue(s.MinAvailable.String(), "minAvailable"))
+		}
+	}
+	return errs
+}
+
 func isValidContainer(c *corev1.Container) *apis.FieldError {
 	var errs *apis.FieldError
 	if c.Name == "" {
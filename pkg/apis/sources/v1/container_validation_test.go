@@ -18,12 +18,20 @@ package v1
 
 import (
 	"context"
+	"math"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/ptr"
+)
+
+var (
+	minAvailable    = intstr.FromString("50%")
+	badMinAvailable = intstr.FromInt(-1)
 )
 
 func TestContainerSourceValidation(t *testing.T) {
@@ -133,6 +141,101 @@ func TestContainerSourceValidation(t *testing.T) {
 				errs = errs.Also(fe)
 				return errs
 			}(),
+		}, {
+			name: "valid scaling",
+			spec: ContainerSourceSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{
+							Name:  "name",
+							Image: "image",
+						}},
+					},
+				},
+				SourceSpec: duckv1.SourceSpec{
+					Sink: duckv1.Destination{
+						Ref: &duckv1.KReference{
+							APIVersion: "eventing.knative.dev/v1",
+							Kind:       "Broker",
+							Name:       "default",
+						},
+					},
+				},
+				Scaling: &ContainerSourceScaling{
+					MinReplicas: ptr.Int32(2),
+					MaxReplicas: ptr.Int32(5),
+					Metric: &ContainerSourceScalingMetric{
+						Type:   ContainerSourceScalingMetricSendRate,
+						Target: 100,
+					},
+					MinAvailable: &minAvailable,
+				},
+			},
+		}, {
+			name: "invalid scaling",
+			spec: ContainerSourceSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{
+							Name:  "name",
+							Image: "image",
+						}},
+					},
+				},
+				SourceSpec: duckv1.SourceSpec{
+					Sink: duckv1.Destination{
+						Ref: &duckv1.KReference{
+							APIVersion: "eventing.knative.dev/v1",
+							Kind:       "Broker",
+							Name:       "default",
+						},
+					},
+				},
+				Scaling: &ContainerSourceScaling{
+					MinReplicas: ptr.Int32(3),
+					MaxReplicas: ptr.Int32(2),
+					Metric: &ContainerSourceScalingMetric{
+						Type: "memory",
+					},
+					MinAvailable: &badMinAvailable,
+				},
+			},
+			want: func() *apis.FieldError {
+				var errs *apis.FieldError
+				errs = errs.Also(apis.ErrOutOfBoundsValue(2, 3, math.MaxInt32, "scaling.maxReplicas"))
+				errs = errs.Also(apis.ErrInvalidValue("memory", "scaling.metric.type"))
+				errs = errs.Also(apis.ErrOutOfBoundsValue(0, 1, math.MaxInt32, "scaling.metric.target"))
+				errs = errs.Also(apis.ErrInvalidValue("-1", "scaling.minAvailable"))
+				return errs
+			}(),
+		}, {
+			name: "metric without maxReplicas",
+			spec: ContainerSourceSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{
+							Name:  "name",
+							Image: "image",
+						}},
+					},
+				},
+				SourceSpec: duckv1.SourceSpec{
+					Sink: duckv1.Destination{
+						Ref: &duckv1.KReference{
+							APIVersion: "eventing.knative.dev/v1",
+							Kind:       "Broker",
+							Name:       "default",
+						},
+					},
+				},
+				Scaling: &ContainerSourceScaling{
+					Metric: &ContainerSourceScalingMetric{
+						Type:   ContainerSourceScalingMetricCPU,
+						Target: 50,
+					},
+				},
+			},
+			want: apis.ErrMissingField("scaling.maxReplicas"),
 		},
 	}
 
@@ -29,7 +29,8 @@ func (s *PingSource) SetDefaults(ctx context.Context) {
 }
 
 func (ss *PingSourceSpec) SetDefaults(ctx context.Context) {
-	if ss.Schedule == "" {
+	// One-shot PingSources don't have a schedule.
+	if ss.Schedule == "" && ss.At == nil {
 		ss.Schedule = defaultSchedule
 	}
 }
@@ -34,15 +34,24 @@ const (
 
 	// PingSourceConditionDeployed has status True when the PingSource has had it's receive adapter deployment created.
 	PingSourceConditionDeployed apis.ConditionType = "Deployed"
+
+	// PingSourceConditionDeadLetterSinkResolved has status True when the dead letter sink of the PingSource
+	// has been resolved, or when no dead letter sink is configured.
+	PingSourceConditionDeadLetterSinkResolved apis.ConditionType = "DeadLetterSinkResolved"
 )
 
 var PingSourceCondSet = apis.NewLivingConditionSet(
 	PingSourceConditionSinkProvided,
-	PingSourceConditionDeployed)
+	PingSourceConditionDeployed,
+	PingSourceConditionDeadLetterSinkResolved)
 
 const (
 	// PingSourceEventType is the default PingSource CloudEvent type.
 	PingSourceEventType = "dev.knative.sources.ping"
+
+	// PingSourceScheduledTimeExtension is the CloudEvent extension holding the time of
+	// the schedule of the event, which the event may be sent after.
+	PingSourceScheduledTimeExtension = "scheduledtime"
 )
 
 // GetConditionSet retrieves the condition set for this resource. Implements the KRShaped interface.
@@ -100,6 +109,24 @@ func (s *PingSourceStatus) MarkNoSink(reason, messageFormat string, messageA ...
 	PingSourceCondSet.Manage(s).MarkFalse(PingSourceConditionSinkProvided, reason, messageFormat, messageA...)
 }
 
+// MarkDeadLetterSinkResolvedSucceeded sets the condition that the dead letter sink has been resolved.
+func (s *PingSourceStatus) MarkDeadLetterSinkResolvedSucceeded(deadLetterSinkURI *apis.URL) {
+	s.DeadLetterSinkURI = deadLetterSinkURI
+	PingSourceCondSet.Manage(s).MarkTrue(PingSourceConditionDeadLetterSinkResolved)
+}
+
+// MarkDeadLetterSinkNotConfigured sets the condition that no dead letter sink is configured.
+func (s *PingSourceStatus) MarkDeadLetterSinkNotConfigured() {
+	s.DeadLetterSinkURI = nil
+	PingSourceCondSet.Manage(s).MarkTrueWithReason(PingSourceConditionDeadLetterSinkResolved, "DeadLetterSinkNotConfigured", "No dead letter sink is configured.")
+}
+
+// MarkDeadLetterSinkResolvedFailed sets the condition that the dead letter sink could not be resolved.
+func (s *PingSourceStatus) MarkDeadLetterSinkResolvedFailed(reason, messageFormat string, messageA ...interface{}) {
+	s.DeadLetterSinkURI = nil
+	PingSourceCondSet.Manage(s).MarkFalse(PingSourceConditionDeadLetterSinkResolved, reason, messageFormat, messageA...)
+}
+
 // PropagateDeploymentAvailability uses the availability of the provided Deployment to determine if
 // PingSourceConditionDeployed should be marked as true or false.
 func (s *PingSourceStatus) PropagateDeploymentAvailability(d *appsv1.Deployment) {
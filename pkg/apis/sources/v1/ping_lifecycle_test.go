@@ -102,8 +102,44 @@ func TestPingSourceStatusIsReady(t *testing.T) {
 			s.PropagateDeploymentAvailability(availableDeployment)
 			return s
 		}(),
+		wantConditionStatus: corev1.ConditionUnknown,
+		want:                false,
+	}, {
+		name: "mark sink, deployed and dead letter sink not configured",
+		s: func() *PingSourceStatus {
+			s := &PingSourceStatus{}
+			s.InitializeConditions()
+			s.MarkSink(exampleUri)
+			s.PropagateDeploymentAvailability(availableDeployment)
+			s.MarkDeadLetterSinkNotConfigured()
+			return s
+		}(),
+		wantConditionStatus: corev1.ConditionTrue,
+		want:                true,
+	}, {
+		name: "mark sink, deployed and dead letter sink resolved",
+		s: func() *PingSourceStatus {
+			s := &PingSourceStatus{}
+			s.InitializeConditions()
+			s.MarkSink(exampleUri)
+			s.PropagateDeploymentAvailability(availableDeployment)
+			s.MarkDeadLetterSinkResolvedSucceeded(exampleUri)
+			return s
+		}(),
 		wantConditionStatus: corev1.ConditionTrue,
 		want:                true,
+	}, {
+		name: "mark sink, deployed and dead letter sink failed",
+		s: func() *PingSourceStatus {
+			s := &PingSourceStatus{}
+			s.InitializeConditions()
+			s.MarkSink(exampleUri)
+			s.PropagateDeploymentAvailability(availableDeployment)
+			s.MarkDeadLetterSinkResolvedFailed("NotFound", "")
+			return s
+		}(),
+		wantConditionStatus: corev1.ConditionFalse,
+		want:                false,
 	}}
 
 	for _, test := range tests {
@@ -169,12 +205,13 @@ func TestPingSourceStatusGetTopLevelCondition(t *testing.T) {
 			Status: corev1.ConditionUnknown,
 		},
 	}, {
-		name: "mark sink and deployed",
+		name: "mark sink, deployed and dead letter sink not configured",
 		s: func() *PingSourceStatus {
 			s := &PingSourceStatus{}
 			s.InitializeConditions()
 			s.MarkSink(exampleUri)
 			s.PropagateDeploymentAvailability(availableDeployment)
+			s.MarkDeadLetterSinkNotConfigured()
 			return s
 		}(),
 		want: &apis.Condition{
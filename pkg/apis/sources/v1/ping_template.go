@@ -0,0 +1,152 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+)
+
+// PingDataTemplateMaxSize is the size of the largest data rendered from a PingSource data
+// template when the data size is not limited.
+const PingDataTemplateMaxSize = 1 << 20
+
+// errPingDataTooLarge is returned by the writes exceeding the size of the rendered data.
+var errPingDataTooLarge = errors.New("rendered data is too large")
+
+// PingDataTemplateContext is the data the PingSource data template is rendered with.
+// +k8s:deepcopy-gen=false
+type PingDataTemplateContext struct {
+	// ScheduledTime is the time of the schedule.
+	ScheduledTime time.Time
+	// FireTime is the time the event is sent at. It is after ScheduledTime when
+	// catching up missed schedules.
+	FireTime time.Time
+	// Sequence is the number of events sent for the PingSource since the adapter started
+	// scheduling it, starting at 1. It is kept in memory only: it starts again at 1 when the
+	// adapter restarts or another adapter replica takes over the PingSource, so it is not
+	// unique nor monotonic over the lifetime of the PingSource.
+	Sequence int64
+
+	Namespace   string
+	Name        string
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// pingDataTemplateFuncs are the functions available to the PingSource data template,
+// in addition to the text/template builtins. They don't have side effects.
+var pingDataTemplateFuncs = template.FuncMap{
+	"rfc3339": func(t time.Time) string {
+		return t.UTC().Format(time.RFC3339)
+	},
+	"unix": func(t time.Time) int64 {
+		return t.Unix()
+	},
+	"formatTime": func(layout string, t time.Time) string {
+		return t.Format(layout)
+	},
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// ParsePingDataTemplate parses the PingSource data template. The range, template, block and
+// define actions are rejected so that rendering the template always terminates quickly:
+// range loops over integers as large as the template wants, and template calls may recurse.
+func ParsePingDataTemplate(data string) (*template.Template, error) {
+	tmpl, err := template.New("data").Option("missingkey=zero").Funcs(pingDataTemplateFuncs).Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(tmpl.Templates()) > 1 {
+		return nil, errors.New("define and block actions are not supported")
+	}
+	if err := checkPingDataTemplateNode(tmpl.Tree.Root); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// checkPingDataTemplateNode rejects the range and template actions in node and its children.
+func checkPingDataTemplateNode(node parse.Node) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := checkPingDataTemplateNode(child); err != nil {
+				return err
+			}
+		}
+	case *parse.IfNode:
+		return checkPingDataTemplateBranch(&n.BranchNode)
+	case *parse.WithNode:
+		return checkPingDataTemplateBranch(&n.BranchNode)
+	case *parse.RangeNode:
+		return errors.New("range actions are not supported")
+	case *parse.TemplateNode:
+		return errors.New("template and block actions are not supported")
+	}
+	return nil
+}
+
+func checkPingDataTemplateBranch(n *parse.BranchNode) error {
+	if err := checkPingDataTemplateNode(n.List); err != nil {
+		return err
+	}
+	return checkPingDataTemplateNode(n.ElseList)
+}
+
+// RenderPingDataTemplate renders the PingSource data template, failing when the rendered data
+// exceeds maxSize bytes. PingDataTemplateMaxSize is used when maxSize is negative.
+func RenderPingDataTemplate(tmpl *template.Template, data PingDataTemplateContext, maxSize int64) ([]byte, error) {
+	if maxSize < 0 {
+		maxSize = PingDataTemplateMaxSize
+	}
+	w := &limitedWriter{max: maxSize}
+	if err := tmpl.Execute(w, data); err != nil {
+		if errors.Is(err, errPingDataTooLarge) {
+			return nil, fmt.Errorf("the rendered data exceeds the limit set at %d bytes", maxSize)
+		}
+		return nil, err
+	}
+	return w.buf.Bytes(), nil
+}
+
+// limitedWriter buffers up to max bytes, the writes beyond fail with errPingDataTooLarge.
+type limitedWriter struct {
+	buf bytes.Buffer
+	max int64
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if int64(w.buf.Len()+len(p)) > w.max {
+		return 0, errPingDataTooLarge
+	}
+	return w.buf.Write(p)
+}
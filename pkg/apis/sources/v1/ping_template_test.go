@@ -0,0 +1,101 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderPingDataTemplate(t *testing.T) {
+	scheduled := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	data := PingDataTemplateContext{
+		ScheduledTime: scheduled,
+		FireTime:      scheduled.Add(1500 * time.Millisecond),
+		Sequence:      42,
+		Namespace:     "ns",
+		Name:          "hourly",
+		Labels:        map[string]string{"team": "billing"},
+	}
+
+	tests := map[string]struct {
+		data string
+		want string
+	}{
+		"static": {
+			data: "some data",
+			want: "some data",
+		},
+		"times": {
+			data: "{{rfc3339 .ScheduledTime}} {{unix .FireTime}} {{formatTime \"2006-01-02T15\" .ScheduledTime}}",
+			want: "2021-06-01T10:00:00Z 1622541601 2021-06-01T10",
+		},
+		"metadata": {
+			data: "{{.Namespace}}/{{upper .Name}} {{.Sequence}} {{index .Labels \"team\"}} {{.Annotations.missing}}",
+			want: "ns/HOURLY 42 billing ",
+		},
+		"json": {
+			data: `{"scheduled":{{json .ScheduledTime}},"labels":{{json .Labels}}}`,
+			want: `{"scheduled":"2021-06-01T10:00:00Z","labels":{"team":"billing"}}`,
+		},
+	}
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			tmpl, err := ParsePingDataTemplate(tc.data)
+			if err != nil {
+				t.Fatal("ParsePingDataTemplate() =", err)
+			}
+			got, err := RenderPingDataTemplate(tmpl, data, -1)
+			if err != nil {
+				t.Fatal("RenderPingDataTemplate() =", err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("RenderPingDataTemplate() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParsePingDataTemplateUnboundedActions(t *testing.T) {
+	tests := map[string]string{
+		"range over an integer": "{{range 100000000000}}{{end}}",
+		"range over labels":     "{{range $k, $v := .Labels}}{{$k}}{{end}}",
+		"nested range":          "{{if .Name}}{{with .Labels}}{{else}}{{range 10}}{{end}}{{end}}{{end}}",
+		"template":              `{{define "loop"}}{{template "loop"}}{{end}}{{template "loop"}}`,
+		"block":                 `{{block "b" .}}{{end}}`,
+		"define":                `{{define "unused"}}{{end}}`,
+	}
+	for n, data := range tests {
+		t.Run(n, func(t *testing.T) {
+			if _, err := ParsePingDataTemplate(data); err == nil {
+				t.Errorf("ParsePingDataTemplate(%q) = nil, want an error", data)
+			}
+		})
+	}
+}
+
+func TestRenderPingDataTemplateTooLarge(t *testing.T) {
+	tmpl, err := ParsePingDataTemplate(strings.Repeat(`{{"some data"}}`, 1000))
+	if err != nil {
+		t.Fatal("ParsePingDataTemplate() =", err)
+	}
+	want := "the rendered data exceeds the limit set at 4096 bytes"
+	if _, err := RenderPingDataTemplate(tmpl, PingDataTemplateContext{}, 4096); err == nil || err.Error() != want {
+		t.Errorf("RenderPingDataTemplate() = %v, want %s", err, want)
+	}
+}
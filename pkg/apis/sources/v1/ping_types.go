@@ -17,12 +17,16 @@ limitations under the License.
 package v1
 
 import (
+	"time"
+
 	"knative.dev/pkg/apis"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/kmeta"
+
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 )
 
 // +genclient
@@ -58,10 +62,29 @@ type PingSourceSpec struct {
 	//   and modifications of the event sent to the sink.
 	duckv1.SourceSpec `json:",inline"`
 
-	// Schedule is the cron schedule. Defaults to `* * * * *`.
+	// Schedule is the cron schedule. Defaults to `* * * * *`, unless At is set.
 	// +optional
 	Schedule string `json:"schedule,omitempty"`
 
+	// At is the time of the single event sent by a one-shot PingSource.
+	// It must be in the future when the PingSource is created. The event is sent as
+	// soon as possible when the adapter takes over the PingSource after that time.
+	// Mutually exclusive with Schedule, StartTime and EndTime.
+	// +optional
+	At *metav1.Time `json:"at,omitempty"`
+
+	// StartTime is the time before which the schedule doesn't fire.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// EndTime is the time after which the schedule doesn't fire.
+	// +optional
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+
+	// Suspend stops sending events while true. Default is false.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
 	// Timezone modifies the actual time relative to the specified timezone.
 	// Defaults to the system time zone.
 	// More general information about time zones: https://www.iana.org/time-zones
@@ -81,6 +104,64 @@ type PingSourceSpec struct {
 	// Mutually exclusive with Data.
 	// +optional
 	DataBase64 string `json:"dataBase64,omitempty"`
+
+	// TemplateData renders Data as a Go template for each schedule, with the scheduled time,
+	// the time the event is sent, a sequence number and the PingSource metadata.
+	// The sequence number starts again at 1 when the adapter restarts, it is not
+	// monotonic over the lifetime of the PingSource.
+	// The range, template, block and define actions are not supported.
+	// Default is false.
+	// +optional
+	TemplateData bool `json:"templateData,omitempty"`
+
+	// Delivery contains the delivery spec of the events sent to the sink: the retries,
+	// backoff, timeout and dead letter sink. Default is 5 retries with an exponential backoff.
+	// +optional
+	Delivery *eventingduckv1.DeliverySpec `json:"delivery,omitempty"`
+
+	// StartingDeadlineSeconds enables the catch-up of missed schedules. When the adapter
+	// starts or takes over the PingSource, it sends the events of the schedules missed in the
+	// last StartingDeadlineSeconds seconds, for instance while the adapter was down.
+	// Default is no catch-up.
+	// +optional
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds,omitempty"`
+
+	// Jitter delays the event of each schedule, to spread the load on the sink.
+	// Defaults to the jitter of the adapter, which is a random delay up to 500ms.
+	// +optional
+	Jitter *PingJitter `json:"jitter,omitempty"`
+}
+
+// PingJitterPolicy is the policy delaying the events of a PingSource.
+type PingJitterPolicy string
+
+const (
+	// PingJitterNone sends the events on schedule.
+	PingJitterNone PingJitterPolicy = "None"
+
+	// PingJitterFixed delays each event by a random duration up to the jitter window.
+	PingJitterFixed PingJitterPolicy = "Fixed"
+
+	// PingJitterSpread delays each event by a random duration up to the jitter window
+	// times the number of PingSources on the same schedule.
+	PingJitterSpread PingJitterPolicy = "Spread"
+
+	// MaxPingJitterDelay is the maximum delay of an event.
+	MaxPingJitterDelay = 30 * time.Second
+)
+
+// PingJitter is the jitter of a PingSource.
+type PingJitter struct {
+	// Policy is the jitter policy, one of None, Fixed or Spread.
+	Policy PingJitterPolicy `json:"policy"`
+
+	// Window is the jitter window, expressed as an ISO-8601 duration.
+	// Defaults to the jitter window of the adapter. Not allowed by the None policy.
+	// More information on Duration format:
+	//  - https://www.iso.org/iso-8601-date-and-time-format.html
+	//  - https://en.wikipedia.org/wiki/ISO_8601
+	// +optional
+	Window *string `json:"window,omitempty"`
 }
 
 // PingSourceStatus defines the observed state of PingSource.
@@ -93,6 +174,19 @@ type PingSourceStatus struct {
 	// * SinkURI - the current active sink URI that has been configured for the
 	//   Source.
 	duckv1.SourceStatus `json:",inline"`
+
+	// DeliveryStatus contains a resolved URL to the dead letter sink address.
+	// +optional
+	eventingduckv1.DeliveryStatus `json:",inline"`
+
+	// LastScheduleTime is the time of the last schedule whose event was successfully sent.
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// NextScheduleTime is the time of the next schedule. It is not set when the PingSource
+	// is suspended or won't fire anymore.
+	// +optional
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -23,9 +23,11 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 
+	"github.com/rickb777/date/period"
 	"github.com/robfig/cron/v3"
 	"knative.dev/pkg/apis"
 
@@ -38,22 +40,31 @@ func (c *PingSource) Validate(ctx context.Context) *apis.FieldError {
 
 func (cs *PingSourceSpec) Validate(ctx context.Context) *apis.FieldError {
 	var errs *apis.FieldError
-	schedule := cs.Schedule
 
-	errs = validateDescriptor(schedule)
-
-	if cs.Timezone != "" {
-		schedule = "CRON_TZ=" + cs.Timezone + " " + schedule
+	if cs.At != nil {
+		// One-shot PingSources don't have a schedule.
+		for _, field := range []struct {
+			name string
+			set  bool
+		}{
+			{"schedule", cs.Schedule != ""},
+			{"startTime", cs.StartTime != nil},
+			{"endTime", cs.EndTime != nil},
+		} {
+			if field.set {
+				errs = errs.Also(apis.ErrMultipleOneOf("at", field.name))
+			}
+		}
+		// A one-shot PingSource created in the past would never fire.
+		if apis.IsInCreate(ctx) && !cs.At.After(time.Now()) {
+			errs = errs.Also(apis.ErrInvalidValue("expected a time in the future", "at"))
+		}
+	} else {
+		errs = errs.Also(validateSchedule(cs.Schedule, cs.Timezone))
 	}
 
-	if _, err := cron.ParseStandard(schedule); err != nil {
-		if strings.HasPrefix(err.Error(), "provided bad location") {
-			fe := apis.ErrInvalidValue(err, "timezone")
-			errs = errs.Also(fe)
-		} else {
-			fe := apis.ErrInvalidValue(err, "schedule")
-			errs = errs.Also(fe)
-		}
+	if cs.StartTime != nil && cs.EndTime != nil && !cs.EndTime.After(cs.StartTime.Time) {
+		errs = errs.Also(apis.ErrGeneric("expected endTime to be after startTime", "endTime"))
 	}
 
 	pingConfig := config.FromContextOrDefaults(ctx)
@@ -65,6 +76,8 @@ func (cs *PingSourceSpec) Validate(ctx context.Context) *apis.FieldError {
 
 	if cs.Data != "" && cs.DataBase64 != "" {
 		errs = errs.Also(apis.ErrMultipleOneOf("data", "dataBase64"))
+	} else if cs.TemplateData && cs.DataBase64 != "" {
+		errs = errs.Also(apis.ErrMultipleOneOf("templateData", "dataBase64"))
 	} else if cs.DataBase64 != "" {
 		if bsize := int64(len(cs.DataBase64)); pingDefaults.DataMaxSize > -1 && bsize > pingDefaults.DataMaxSize {
 			fe := apis.ErrInvalidValue(fmt.Sprintf("the dataBase64 length of %d bytes exceeds limit set at %d.", bsize, pingDefaults.DataMaxSize), "dataBase64")
@@ -87,22 +100,109 @@ func (cs *PingSourceSpec) Validate(ctx context.Context) *apis.FieldError {
 			fe := apis.ErrInvalidValue(fmt.Sprintf("the data length of %d bytes exceeds limit set at %d.", bsize, pingDefaults.DataMaxSize), "data")
 			errs = errs.Also(fe)
 		}
-		if cs.ContentType == cloudevents.ApplicationJSON {
+		if cs.TemplateData {
+			errs = errs.Also(validateDataTemplate(cs.Data, cs.ContentType, pingDefaults.DataMaxSize))
+		} else if cs.ContentType == cloudevents.ApplicationJSON {
 			// validate if data is valid JSON
 			if err := validateJSON(cs.Data); err != nil {
 				errs = errs.Also(apis.ErrInvalidValue(err, "data"))
 			}
 		}
 	}
+	if cs.Delivery != nil {
+		delivery := cs.Delivery.DeepCopy()
+		// The PingSource adapter doesn't attach delivery credentials.
+		if delivery.Auth != nil {
+			errs = errs.Also(apis.ErrDisallowedFields("auth").ViaField("delivery"))
+			delivery.Auth = nil
+		}
+		errs = errs.Also(delivery.Validate(ctx).ViaField("delivery"))
+	}
+	if cs.StartingDeadlineSeconds != nil && *cs.StartingDeadlineSeconds < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(*cs.StartingDeadlineSeconds, "startingDeadlineSeconds"))
+	}
+	if cs.Jitter != nil {
+		errs = errs.Also(cs.Jitter.Validate(ctx).ViaField("jitter"))
+	}
 	errs = errs.Also(cs.SourceSpec.Validate(ctx))
 	return errs
 }
 
+func (j *PingJitter) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+
+	switch j.Policy {
+	case PingJitterNone:
+		if j.Window != nil {
+			errs = errs.Also(apis.ErrDisallowedFields("window"))
+		}
+	case PingJitterFixed, PingJitterSpread:
+		// nothing
+	case "":
+		errs = errs.Also(apis.ErrMissingField("policy"))
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(j.Policy, "policy"))
+	}
+
+	if j.Window != nil && j.Policy != PingJitterNone {
+		p, err := period.Parse(*j.Window)
+		if err != nil || p.IsNegative() {
+			errs = errs.Also(apis.ErrInvalidValue(*j.Window, "window"))
+		} else if d, _ := p.Duration(); d > MaxPingJitterDelay {
+			errs = errs.Also(apis.ErrOutOfBoundsValue(*j.Window, "PT0S", "PT30S", "window"))
+		}
+	}
+	return errs
+}
+
+func validateSchedule(schedule, timezone string) *apis.FieldError {
+	errs := validateDescriptor(schedule)
+
+	if timezone != "" {
+		schedule = "CRON_TZ=" + timezone + " " + schedule
+	}
+
+	if _, err := cron.ParseStandard(schedule); err != nil {
+		if strings.HasPrefix(err.Error(), "provided bad location") {
+			fe := apis.ErrInvalidValue(err, "timezone")
+			errs = errs.Also(fe)
+		} else {
+			fe := apis.ErrInvalidValue(err, "schedule")
+			errs = errs.Also(fe)
+		}
+	}
+	return errs
+}
+
 func validateJSON(str string) error {
 	var objmap map[string]interface{}
 	return json.Unmarshal([]byte(str), &objmap)
 }
 
+// validateDataTemplate checks the data template parses and renders within maxSize bytes, and
+// for JSON data, renders valid JSON.
+func validateDataTemplate(data, contentType string, maxSize int64) *apis.FieldError {
+	tmpl, err := ParsePingDataTemplate(data)
+	if err != nil {
+		return apis.ErrInvalidValue(err, "data")
+	}
+	now := time.Now()
+	rendered, err := RenderPingDataTemplate(tmpl, PingDataTemplateContext{
+		ScheduledTime: now,
+		FireTime:      now,
+		Sequence:      1,
+	}, maxSize)
+	if err != nil {
+		return apis.ErrInvalidValue(err, "data")
+	}
+	if contentType == cloudevents.ApplicationJSON {
+		if err := validateJSON(string(rendered)); err != nil {
+			return apis.ErrInvalidValue(fmt.Errorf("rendered data is not valid JSON: %w", err), "data")
+		}
+	}
+	return nil
+}
+
 func validateDescriptor(spec string) *apis.FieldError {
 	if strings.Contains(spec, "@every") {
 		return apis.ErrInvalidValue(errors.New("unsupported descriptor @every"), "schedule")
@@ -21,14 +21,18 @@ import (
 	"encoding/base64"
 	"strings"
 	"testing"
+	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 
 	"github.com/google/go-cmp/cmp"
 	"knative.dev/pkg/apis"
+	"knative.dev/pkg/ptr"
 
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/eventing/pkg/apis/sources/config"
 )
 
@@ -107,6 +111,384 @@ func TestPingSourceValidation(t *testing.T) {
 			want: func() *apis.FieldError {
 				return apis.ErrGeneric("expected at least one, got none", "ref", "uri").ViaField("spec.sink")
 			}(),
+		}, {
+			name: "valid spec with startingDeadlineSeconds",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					Schedule:                "0 * * * *",
+					StartingDeadlineSeconds: ptr.Int64(3600),
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			want: nil,
+		}, {
+			name: "negative startingDeadlineSeconds",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					Schedule:                "0 * * * *",
+					StartingDeadlineSeconds: ptr.Int64(-1),
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			want: apis.ErrInvalidValue(-1, "spec.startingDeadlineSeconds"),
+		}, {
+			name: "valid spec with spread jitter",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					Schedule: "0 * * * *",
+					Jitter:   &PingJitter{Policy: PingJitterSpread, Window: ptr.String("PT0.1S")},
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			want: nil,
+		}, {
+			name: "jitter without policy",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					Schedule: "0 * * * *",
+					Jitter:   &PingJitter{Window: ptr.String("PT1S")},
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			want: apis.ErrMissingField("spec.jitter.policy"),
+		}, {
+			name: "invalid jitter policy",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					Schedule: "0 * * * *",
+					Jitter:   &PingJitter{Policy: "Random"},
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			want: apis.ErrInvalidValue("Random", "spec.jitter.policy"),
+		}, {
+			name: "jitter window with None policy",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					Schedule: "0 * * * *",
+					Jitter:   &PingJitter{Policy: PingJitterNone, Window: ptr.String("PT1S")},
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			want: apis.ErrDisallowedFields("spec.jitter.window"),
+		}, {
+			name: "invalid jitter window",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					Schedule: "0 * * * *",
+					Jitter:   &PingJitter{Policy: PingJitterFixed, Window: ptr.String("1s")},
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			want: apis.ErrInvalidValue("1s", "spec.jitter.window"),
+		}, {
+			name: "jitter window too large",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					Schedule: "0 * * * *",
+					Jitter:   &PingJitter{Policy: PingJitterFixed, Window: ptr.String("PT1M")},
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			want: apis.ErrOutOfBoundsValue("PT1M", "PT0S", "PT30S", "spec.jitter.window"),
+		}, {
+			name: "valid spec with templated JSON data",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					Schedule:     "0 * * * *",
+					ContentType:  cloudevents.ApplicationJSON,
+					TemplateData: true,
+					Data:         `{"scheduled": {{json .ScheduledTime}}, "sequence": {{.Sequence}}, "name": "{{.Name}}"}`,
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			want: nil,
+		}, {
+			name: "invalid data template",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					Schedule:     "0 * * * *",
+					TemplateData: true,
+					Data:         "{{.ScheduledTime",
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			want: apis.ErrInvalidValue("template: data:1: unclosed action", "spec.data"),
+		}, {
+			name: "data template with an unbounded range",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					Schedule:     "0 * * * *",
+					TemplateData: true,
+					Data:         "{{range 100000000000}}{{end}}",
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			want: apis.ErrInvalidValue("range actions are not supported", "spec.data"),
+		}, {
+			name: "templated data rendering invalid JSON",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					Schedule:     "0 * * * *",
+					ContentType:  cloudevents.ApplicationJSON,
+					TemplateData: true,
+					Data:         `{"scheduled": {{.ScheduledTime}}}`,
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			want: apis.ErrInvalidValue("rendered data is not valid JSON: invalid character '-' after object key:value pair", "spec.data"),
+		}, {
+			name: "templated dataBase64",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					Schedule:     "0 * * * *",
+					TemplateData: true,
+					DataBase64:   "c29tZSBkYXRh",
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			want: apis.ErrMultipleOneOf("spec.templateData", "spec.dataBase64"),
+		}, {
+			name: "valid spec with delivery",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					Schedule: "0 * * * *",
+					Delivery: &eventingduckv1.DeliverySpec{
+						Retry: ptr.Int32(10),
+						DeadLetterSink: &duckv1.Destination{
+							URI: apis.HTTP("dls.example.com"),
+						},
+					},
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			want: nil,
+		}, {
+			name: "invalid delivery",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					Schedule: "0 * * * *",
+					Delivery: &eventingduckv1.DeliverySpec{
+						Retry: ptr.Int32(-1),
+						Auth:  &eventingduckv1.DeliveryAuth{Audience: ptr.String("sink")},
+					},
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			want: apis.ErrDisallowedFields("spec.delivery.auth").Also(apis.ErrInvalidValue(-1, "spec.delivery.retry")),
+		}, {
+			name: "valid one-shot spec",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					At: &metav1.Time{Time: time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)},
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			want: nil,
+		}, {
+			name: "one-shot spec with schedule and window",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					At:        &metav1.Time{Time: time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)},
+					Schedule:  "0 * * * *",
+					StartTime: &metav1.Time{Time: time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)},
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			want: apis.ErrMultipleOneOf("spec.at", "spec.schedule").Also(apis.ErrMultipleOneOf("spec.at", "spec.startTime")),
+		}, {
+			name: "one-shot spec created in the past",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					At: &metav1.Time{Time: time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)},
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			ctx:  apis.WithinCreate,
+			want: apis.ErrInvalidValue("expected a time in the future", "spec.at"),
+		}, {
+			name: "valid spec with window and suspend",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					Schedule:  "0 * * * *",
+					StartTime: &metav1.Time{Time: time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)},
+					EndTime:   &metav1.Time{Time: time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)},
+					Suspend:   true,
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			want: nil,
+		}, {
+			name: "endTime before startTime",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					Schedule:  "0 * * * *",
+					StartTime: &metav1.Time{Time: time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)},
+					EndTime:   &metav1.Time{Time: time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)},
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			want: apis.ErrGeneric("expected endTime to be after startTime", "spec.endTime"),
 		}, {
 			name: "invalid schedule",
 			source: PingSource{
@@ -319,6 +701,29 @@ func TestPingSourceValidation(t *testing.T) {
 				errs = errs.Also(fe)
 				return errs
 			}(),
+		}, {
+			name: "templated data rendering too large data",
+			source: PingSource{
+				Spec: PingSourceSpec{
+					Schedule:     "*/2 * * * *",
+					ContentType:  cloudevents.TextPlain,
+					TemplateData: true,
+					Data:         `{{printf "%5000s" "some data"}}`,
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "v1",
+								Kind:       "broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+			ctx: func(ctx context.Context) context.Context {
+				return config.ToContext(ctx, &config.Config{PingDefaults: &config.PingDefaults{DataMaxSize: 4096}})
+			},
+			want: apis.ErrInvalidValue("the rendered data exceeds the limit set at 4096 bytes", "spec.data"),
 		}, {
 			name: "big data ok",
 			source: PingSource{
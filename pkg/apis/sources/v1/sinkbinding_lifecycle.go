@@ -20,6 +20,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"path"
+	"sort"
+	"strings"
 
 	"go.uber.org/zap"
 
@@ -29,14 +32,38 @@ import (
 	"knative.dev/pkg/apis"
 	"knative.dev/pkg/apis/duck"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/kmeta"
 	"knative.dev/pkg/logging"
 	"knative.dev/pkg/tracker"
 )
 
+const (
+	// SinkBindingFilesPath is where a SinkBinding projects its files in the subject's containers.
+	SinkBindingFilesPath = "/var/run/knative/sinkbinding"
+
+	// SinkBindingSinkKey is the key of the resolved sink URI in the SinkBinding's ConfigMap.
+	SinkBindingSinkKey = "sink"
+
+	// SinkBindingCEOverridesKey is the key of the JSON encoded CloudEvent overrides in the
+	// SinkBinding's ConfigMap.
+	SinkBindingCEOverridesKey = "ce-overrides"
+
+	sinkBindingVolumeName             = "knative-sinkbinding"
+	sinkBindingCACertsPath            = "ca-certs.pem"
+	sinkBindingTokenPath              = "token"
+	sinkBindingTokenExpirationSeconds = 3600
+)
+
 var sbCondSet = apis.NewLivingConditionSet(
 	SinkBindingConditionSinkProvided,
 )
 
+// SinkBindingConfigMapName returns the name of the ConfigMap holding the resolved sink of a
+// SinkBinding that projects it as a file.
+func SinkBindingConfigMapName(sb *SinkBinding) string {
+	return kmeta.ChildName(sb.Name, "-sink")
+}
+
 // GetConditionSet retrieves the condition set for this resource. Implements the KRShaped interface.
 func (*SinkBinding) GetConditionSet() apis.ConditionSet {
 	return sbCondSet
@@ -84,6 +111,53 @@ func (sbs *SinkBindingStatus) MarkBindingAvailable() {
 	sbCondSet.Manage(sbs).MarkTrue(SinkBindingConditionReady)
 }
 
+// PropagateSubjects records the state of the binding on each of its subjects.
+// The binding is available unless it failed on one of them, stale subjects
+// do not prevent it from being available. At most MaxSinkBindingSubjects
+// subjects are listed, failed and stale subjects first.
+func (sbs *SinkBindingStatus) PropagateSubjects(subjects []SinkBindingSubject) {
+	sortSubjects(subjects)
+	sbs.Subjects = subjects
+	if len(subjects) > MaxSinkBindingSubjects {
+		listed := make([]SinkBindingSubject, len(subjects))
+		copy(listed, subjects)
+		sort.SliceStable(listed, func(i, j int) bool {
+			return subjectStateOrder[listed[i].State] < subjectStateOrder[listed[j].State]
+		})
+		listed = listed[:MaxSinkBindingSubjects]
+		sortSubjects(listed)
+		sbs.Subjects = listed
+	}
+
+	var failed []string
+	for _, s := range subjects {
+		if s.State == SinkBindingSubjectFailed {
+			failed = append(failed, fmt.Sprintf("%s %q: %s", s.Kind, s.Name, s.Message))
+		}
+	}
+	if len(failed) > 0 {
+		sbs.MarkBindingUnavailable("BindingFailed", fmt.Sprintf("failed to bind %d of %d subjects: %s",
+			len(failed), len(subjects), strings.Join(failed, "; ")))
+		return
+	}
+	sbs.MarkBindingAvailable()
+}
+
+var subjectStateOrder = map[SinkBindingSubjectState]int{
+	SinkBindingSubjectFailed: 0,
+	SinkBindingSubjectStale:  1,
+	SinkBindingSubjectBound:  2,
+}
+
+func sortSubjects(subjects []SinkBindingSubject) {
+	sort.Slice(subjects, func(i, j int) bool {
+		if subjects[i].Kind != subjects[j].Kind {
+			return subjects[i].Kind < subjects[j].Kind
+		}
+		return subjects[i].Name < subjects[j].Name
+	})
+}
+
 // MarkSink sets the condition that the source has a sink configured.
 func (sbs *SinkBindingStatus) MarkSink(uri *apis.URL) {
 	sbs.SinkURI = uri
@@ -120,59 +194,168 @@ func (sb *SinkBinding) Do(ctx context.Context, ps *duckv1.WithPod) {
 		}
 	}
 
-	spec := ps.Spec.Template.Spec
+	env, volume := sb.projection(uri, ceOverrides)
+	spec := &ps.Spec.Template.Spec
 	for i := range spec.InitContainers {
-		spec.InitContainers[i].Env = append(spec.InitContainers[i].Env, corev1.EnvVar{
+		bindContainer(&spec.InitContainers[i], env, volume != nil)
+	}
+	for i := range spec.Containers {
+		bindContainer(&spec.Containers[i], env, volume != nil)
+	}
+	if volume != nil {
+		spec.Volumes = append(spec.Volumes, *volume)
+	}
+}
+
+func (sb *SinkBinding) Undo(ctx context.Context, ps *duckv1.WithPod) {
+	spec := &ps.Spec.Template.Spec
+	for i := range spec.InitContainers {
+		unbindContainer(&spec.InitContainers[i])
+	}
+	for i := range spec.Containers {
+		unbindContainer(&spec.Containers[i])
+	}
+	if len(spec.Volumes) == 0 {
+		return
+	}
+	volumes := make([]corev1.Volume, 0, len(spec.Volumes))
+	for _, v := range spec.Volumes {
+		if v.Name != sinkBindingVolumeName {
+			volumes = append(volumes, v)
+		}
+	}
+	spec.Volumes = volumes
+}
+
+// projection returns the environment variables and the volume, if any, the
+// SinkBinding adds to the subject's pods.
+func (sb *SinkBinding) projection(uri *apis.URL, ceOverrides string) ([]corev1.EnvVar, *corev1.Volume) {
+	files := sb.Spec.Files
+	if files == nil || !files.Sink {
+		env := []corev1.EnvVar{{
 			Name:  "K_SINK",
 			Value: uri.String(),
-		})
-		spec.InitContainers[i].Env = append(spec.InitContainers[i].Env, corev1.EnvVar{
+		}, {
 			Name:  "K_CE_OVERRIDES",
 			Value: ceOverrides,
+		}}
+		if files == nil {
+			return env, nil
+		}
+		return append(env, files.env()...), sb.filesVolume()
+	}
+
+	env := []corev1.EnvVar{{
+		Name:  "K_SINK_FILE",
+		Value: path.Join(SinkBindingFilesPath, SinkBindingSinkKey),
+	}, {
+		Name:  "K_CE_OVERRIDES_FILE",
+		Value: path.Join(SinkBindingFilesPath, SinkBindingCEOverridesKey),
+	}}
+	return append(env, files.env()...), sb.filesVolume()
+}
+
+// env returns the environment variables pointing to the projected credentials.
+func (files *SinkBindingFiles) env() []corev1.EnvVar {
+	var env []corev1.EnvVar
+	if files.CACerts != nil {
+		env = append(env, corev1.EnvVar{
+			Name:  "K_CA_CERTS_FILE",
+			Value: path.Join(SinkBindingFilesPath, sinkBindingCACertsPath),
 		})
 	}
-	for i := range spec.Containers {
-		spec.Containers[i].Env = append(spec.Containers[i].Env, corev1.EnvVar{
-			Name:  "K_SINK",
-			Value: uri.String(),
+	if files.Audience != nil {
+		env = append(env, corev1.EnvVar{
+			Name:  "K_SINK_TOKEN_FILE",
+			Value: path.Join(SinkBindingFilesPath, sinkBindingTokenPath),
 		})
-		spec.Containers[i].Env = append(spec.Containers[i].Env, corev1.EnvVar{
-			Name:  "K_CE_OVERRIDES",
-			Value: ceOverrides,
+	}
+	return env
+}
+
+// filesVolume returns the projected volume holding the SinkBinding files, or
+// nil if there is nothing to project.
+func (sb *SinkBinding) filesVolume() *corev1.Volume {
+	files := sb.Spec.Files
+	var sources []corev1.VolumeProjection
+	if files.Sink {
+		sources = append(sources, corev1.VolumeProjection{
+			ConfigMap: &corev1.ConfigMapProjection{
+				LocalObjectReference: corev1.LocalObjectReference{Name: SinkBindingConfigMapName(sb)},
+				Items: []corev1.KeyToPath{{
+					Key:  SinkBindingSinkKey,
+					Path: SinkBindingSinkKey,
+				}, {
+					Key:  SinkBindingCEOverridesKey,
+					Path: SinkBindingCEOverridesKey,
+				}},
+			},
+		})
+	}
+	if files.CACerts != nil {
+		sources = append(sources, corev1.VolumeProjection{
+			ConfigMap: &corev1.ConfigMapProjection{
+				LocalObjectReference: files.CACerts.LocalObjectReference,
+				Items: []corev1.KeyToPath{{
+					Key:  files.CACerts.Key,
+					Path: sinkBindingCACertsPath,
+				}},
+				Optional: files.CACerts.Optional,
+			},
+		})
+	}
+	if files.Audience != nil {
+		expiration := int64(sinkBindingTokenExpirationSeconds)
+		sources = append(sources, corev1.VolumeProjection{
+			ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+				Audience:          *files.Audience,
+				ExpirationSeconds: &expiration,
+				Path:              sinkBindingTokenPath,
+			},
 		})
 	}
+	if len(sources) == 0 {
+		return nil
+	}
+	return &corev1.Volume{
+		Name: sinkBindingVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{Sources: sources},
+		},
+	}
 }
 
-func (sb *SinkBinding) Undo(ctx context.Context, ps *duckv1.WithPod) {
-	spec := ps.Spec.Template.Spec
-	for i, c := range spec.InitContainers {
-		if len(c.Env) == 0 {
-			continue
-		}
-		env := make([]corev1.EnvVar, 0, len(spec.InitContainers[i].Env))
-		for j, ev := range c.Env {
+func bindContainer(c *corev1.Container, env []corev1.EnvVar, mount bool) {
+	c.Env = append(c.Env, env...)
+	if mount {
+		c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
+			Name:      sinkBindingVolumeName,
+			MountPath: SinkBindingFilesPath,
+			ReadOnly:  true,
+		})
+	}
+}
+
+func unbindContainer(c *corev1.Container) {
+	if len(c.Env) > 0 {
+		env := make([]corev1.EnvVar, 0, len(c.Env))
+		for _, ev := range c.Env {
 			switch ev.Name {
-			case "K_SINK", "K_CE_OVERRIDES":
+			case "K_SINK", "K_CE_OVERRIDES", "K_SINK_FILE", "K_CE_OVERRIDES_FILE", "K_CA_CERTS_FILE", "K_SINK_TOKEN_FILE":
 				continue
 			default:
-				env = append(env, spec.InitContainers[i].Env[j])
+				env = append(env, ev)
 			}
 		}
-		spec.InitContainers[i].Env = env
+		c.Env = env
 	}
-	for i, c := range spec.Containers {
-		if len(c.Env) == 0 {
-			continue
-		}
-		env := make([]corev1.EnvVar, 0, len(spec.Containers[i].Env))
-		for j, ev := range c.Env {
-			switch ev.Name {
-			case "K_SINK", "K_CE_OVERRIDES":
-				continue
-			default:
-				env = append(env, spec.Containers[i].Env[j])
+	if len(c.VolumeMounts) > 0 {
+		mounts := make([]corev1.VolumeMount, 0, len(c.VolumeMounts))
+		for _, vm := range c.VolumeMounts {
+			if vm.Name != sinkBindingVolumeName {
+				mounts = append(mounts, vm)
 			}
 		}
-		spec.Containers[i].Env = env
+		c.VolumeMounts = mounts
 	}
 }
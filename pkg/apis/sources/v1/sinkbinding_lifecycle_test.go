@@ -18,11 +18,13 @@ package v1
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -156,6 +158,87 @@ func TestSinkBindingStatusIsReady(t *testing.T) {
 	}
 }
 
+func TestSinkBindingPropagateSubjects(t *testing.T) {
+	tests := []struct {
+		name      string
+		subjects  []SinkBindingSubject
+		want      []SinkBindingSubject
+		wantReady corev1.ConditionStatus
+		wantMsg   string
+	}{{
+		name: "bound and stale",
+		subjects: []SinkBindingSubject{{
+			APIVersion: "batch/v1", Kind: "Job", Name: "old", State: SinkBindingSubjectStale, Message: "immutable",
+		}, {
+			APIVersion: "apps/v1", Kind: "Deployment", Name: "web", State: SinkBindingSubjectBound,
+		}},
+		want: []SinkBindingSubject{{
+			APIVersion: "apps/v1", Kind: "Deployment", Name: "web", State: SinkBindingSubjectBound,
+		}, {
+			APIVersion: "batch/v1", Kind: "Job", Name: "old", State: SinkBindingSubjectStale, Message: "immutable",
+		}},
+		wantReady: corev1.ConditionTrue,
+	}, {
+		name: "failed",
+		subjects: []SinkBindingSubject{{
+			APIVersion: "apps/v1", Kind: "Deployment", Name: "web", State: SinkBindingSubjectBound,
+		}, {
+			APIVersion: "apps/v1", Kind: "Deployment", Name: "api", State: SinkBindingSubjectFailed, Message: "forbidden",
+		}},
+		want: []SinkBindingSubject{{
+			APIVersion: "apps/v1", Kind: "Deployment", Name: "api", State: SinkBindingSubjectFailed, Message: "forbidden",
+		}, {
+			APIVersion: "apps/v1", Kind: "Deployment", Name: "web", State: SinkBindingSubjectBound,
+		}},
+		wantReady: corev1.ConditionFalse,
+		wantMsg:   `failed to bind 1 of 2 subjects: Deployment "api": forbidden`,
+	}, {
+		name: "capped",
+		subjects: func() []SinkBindingSubject {
+			subjects := []SinkBindingSubject{{
+				APIVersion: "batch/v1", Kind: "Job", Name: "zz-old", State: SinkBindingSubjectStale, Message: "immutable",
+			}}
+			for i := 0; i < MaxSinkBindingSubjects; i++ {
+				subjects = append(subjects, SinkBindingSubject{
+					APIVersion: "batch/v1", Kind: "Job", Name: fmt.Sprintf("job-%03d", i), State: SinkBindingSubjectBound,
+				})
+			}
+			return subjects
+		}(),
+		want: func() []SinkBindingSubject {
+			var subjects []SinkBindingSubject
+			for i := 0; i < MaxSinkBindingSubjects-1; i++ {
+				subjects = append(subjects, SinkBindingSubject{
+					APIVersion: "batch/v1", Kind: "Job", Name: fmt.Sprintf("job-%03d", i), State: SinkBindingSubjectBound,
+				})
+			}
+			return append(subjects, SinkBindingSubject{
+				APIVersion: "batch/v1", Kind: "Job", Name: "zz-old", State: SinkBindingSubjectStale, Message: "immutable",
+			})
+		}(),
+		wantReady: corev1.ConditionTrue,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sbs := &SinkBindingStatus{}
+			sbs.InitializeConditions()
+			sbs.MarkSink(apis.HTTP("sink"))
+			sbs.PropagateSubjects(test.subjects)
+			if !cmp.Equal(sbs.Subjects, test.want) {
+				t.Error("Subjects (-want, +got):", cmp.Diff(test.want, sbs.Subjects))
+			}
+			ready := sbs.GetCondition(SinkBindingConditionReady)
+			if got := ready.Status; got != test.wantReady {
+				t.Errorf("Ready = %v, want %v", got, test.wantReady)
+			}
+			if got := ready.Message; got != test.wantMsg {
+				t.Errorf("Ready message = %q, want %q", got, test.wantMsg)
+			}
+		})
+	}
+}
+
 func TestSinkBindingUndo(t *testing.T) {
 	tests := []struct {
 		name string
@@ -497,6 +580,149 @@ func TestSinkBindingDo(t *testing.T) {
 	}
 }
 
+func TestSinkBindingDoFiles(t *testing.T) {
+	destination := duckv1.Destination{
+		URI: &apis.URL{
+			Scheme: "http",
+			Host:   "thing.ns.svc.cluster.local",
+			Path:   "/a/path",
+		},
+	}
+	expiration := int64(3600)
+	volume := corev1.Volume{
+		Name: "knative-sinkbinding",
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{{
+					ConfigMap: &corev1.ConfigMapProjection{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "matt-sink"},
+						Items: []corev1.KeyToPath{{
+							Key:  "sink",
+							Path: "sink",
+						}, {
+							Key:  "ce-overrides",
+							Path: "ce-overrides",
+						}},
+					},
+				}, {
+					ConfigMap: &corev1.ConfigMapProjection{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "sink-ca"},
+						Items: []corev1.KeyToPath{{
+							Key:  "ca.crt",
+							Path: "ca-certs.pem",
+						}},
+					},
+				}, {
+					ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+						Audience:          "sink-audience",
+						ExpirationSeconds: &expiration,
+						Path:              "token",
+					},
+				}},
+			},
+		},
+	}
+	mount := corev1.VolumeMount{
+		Name:      "knative-sinkbinding",
+		MountPath: "/var/run/knative/sinkbinding",
+		ReadOnly:  true,
+	}
+	env := []corev1.EnvVar{{
+		Name:  "FOO",
+		Value: "BAR",
+	}, {
+		Name:  "K_SINK_FILE",
+		Value: "/var/run/knative/sinkbinding/sink",
+	}, {
+		Name:  "K_CE_OVERRIDES_FILE",
+		Value: "/var/run/knative/sinkbinding/ce-overrides",
+	}, {
+		Name:  "K_CA_CERTS_FILE",
+		Value: "/var/run/knative/sinkbinding/ca-certs.pem",
+	}, {
+		Name:  "K_SINK_TOKEN_FILE",
+		Value: "/var/run/knative/sinkbinding/token",
+	}}
+
+	got := &duckv1.WithPod{
+		Spec: duckv1.WithPodSpec{
+			Template: duckv1.PodSpecable{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:  "blah",
+						Image: "busybox",
+						Env: []corev1.EnvVar{{
+							Name:  "FOO",
+							Value: "BAR",
+						}, {
+							Name:  "K_SINK",
+							Value: "the old value",
+						}},
+					}},
+					Volumes: []corev1.Volume{{
+						Name: "data",
+					}},
+				},
+			},
+		},
+	}
+	want := &duckv1.WithPod{
+		Spec: duckv1.WithPodSpec{
+			Template: duckv1.PodSpecable{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:         "blah",
+						Image:        "busybox",
+						Env:          env,
+						VolumeMounts: []corev1.VolumeMount{mount},
+					}},
+					Volumes: []corev1.Volume{{
+						Name: "data",
+					}, volume},
+				},
+			},
+		},
+	}
+
+	ctx, _ := fakedynamicclient.With(context.Background(), scheme.Scheme, got)
+	ctx = addressable.WithDuck(ctx)
+	r := resolver.NewURIResolverFromTracker(ctx, tracker.New(func(types.NamespacedName) {}, 0))
+	ctx = WithURIResolver(context.Background(), r)
+
+	audience := "sink-audience"
+	sb := &SinkBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "matt"},
+		Spec: SinkBindingSpec{
+			SourceSpec: duckv1.SourceSpec{
+				Sink: destination,
+			},
+			Files: &SinkBindingFiles{
+				Sink: true,
+				CACerts: &corev1.ConfigMapKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "sink-ca"},
+					Key:                  "ca.crt",
+				},
+				Audience: &audience,
+			},
+		},
+	}
+
+	// Applying twice must not duplicate anything.
+	sb.Do(ctx, got)
+	sb.Do(ctx, got)
+	if !cmp.Equal(got, want) {
+		t.Error("Do (-want, +got):", cmp.Diff(want, got))
+	}
+
+	sb.Undo(ctx, got)
+	want.Spec.Template.Spec.Containers[0].Env = env[:1]
+	want.Spec.Template.Spec.Containers[0].VolumeMounts = []corev1.VolumeMount{}
+	want.Spec.Template.Spec.Volumes = want.Spec.Template.Spec.Volumes[:1]
+	if !cmp.Equal(got, want) {
+		t.Error("Undo (-want, +got):", cmp.Diff(want, got))
+	}
+}
+
 func TestSinkBindingDoNoURI(t *testing.T) {
 	want := &duckv1.WithPod{
 		Spec: duckv1.WithPodSpec{
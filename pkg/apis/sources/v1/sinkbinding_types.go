@@ -17,6 +17,7 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"knative.dev/pkg/apis"
@@ -67,6 +68,33 @@ type SinkBindingSpec struct {
 	// * Subject - Subject references the resource(s) whose "runtime contract"
 	//   should be augmented by Binding implementations.
 	duckv1.BindingSpec `json:",inline"`
+
+	// Files projects the sink configuration and credentials into the subject's
+	// containers as files under SinkBindingFilesPath.
+	// +optional
+	Files *SinkBindingFiles `json:"files,omitempty"`
+}
+
+// SinkBindingFiles selects what a SinkBinding projects as files. Projected
+// files are updated in place, so they change without restarting the subject.
+type SinkBindingFiles struct {
+	// Sink projects the sink URI and CloudEvent overrides as files instead of
+	// the K_SINK and K_CE_OVERRIDES environment variables, which are replaced
+	// by K_SINK_FILE and K_CE_OVERRIDES_FILE. Sink changes then no longer roll
+	// out the subject.
+	// +optional
+	Sink bool `json:"sink,omitempty"`
+
+	// CACerts selects the key of a ConfigMap holding the PEM encoded CA
+	// certificates of the sink. Its path is set in K_CA_CERTS_FILE.
+	// +optional
+	CACerts *corev1.ConfigMapKeySelector `json:"caCerts,omitempty"`
+
+	// Audience projects a service account token for this audience, refreshed
+	// by the kubelet, to authenticate to the sink. Its path is set in
+	// K_SINK_TOKEN_FILE.
+	// +optional
+	Audience *string `json:"audience,omitempty"`
 }
 
 const (
@@ -89,6 +117,54 @@ type SinkBindingStatus struct {
 	// * SinkURI - the current active sink URI that has been configured for the
 	//   Source.
 	duckv1.SourceStatus `json:",inline"`
+
+	// Subjects lists the resources matched by the subject of the binding and
+	// whether the binding has been applied to each of them. At most
+	// MaxSinkBindingSubjects resources are listed, failed and stale ones first.
+	// +optional
+	Subjects []SinkBindingSubject `json:"subjects,omitempty"`
+}
+
+// MaxSinkBindingSubjects is the maximum number of subjects listed in the
+// status of a SinkBinding, which keeps bindings with broad selectors well
+// under the size limit of objects.
+const MaxSinkBindingSubjects = 100
+
+// SinkBindingSubjectState is the state of a SinkBinding on one of its subjects.
+type SinkBindingSubjectState string
+
+const (
+	// SinkBindingSubjectBound means the subject carries the binding.
+	SinkBindingSubjectBound SinkBindingSubjectState = "Bound"
+
+	// SinkBindingSubjectStale means the subject predates the binding and can
+	// no longer be changed, like the pod template of a Job. Pods it creates
+	// do not carry the binding.
+	SinkBindingSubjectStale SinkBindingSubjectState = "Stale"
+
+	// SinkBindingSubjectFailed means the binding could not be applied to the
+	// subject.
+	SinkBindingSubjectFailed SinkBindingSubjectState = "Failed"
+)
+
+// SinkBindingSubject reports the state of a SinkBinding on one of the
+// resources matched by its subject.
+type SinkBindingSubject struct {
+	// APIVersion of the resource.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the resource.
+	Kind string `json:"kind"`
+
+	// Name of the resource.
+	Name string `json:"name"`
+
+	// State of the binding on the resource.
+	State SinkBindingSubjectState `json:"state"`
+
+	// Message explains why the binding is stale or failed.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -36,5 +36,25 @@ func (fbs *SinkBindingSpec) Validate(ctx context.Context) *apis.FieldError {
 	err := fbs.Subject.Validate(ctx).ViaField("subject").Also(
 		fbs.Sink.Validate(ctx).ViaField("sink"))
 	err = err.Also(fbs.SourceSpec.Validate(ctx))
+	if fbs.Files != nil {
+		err = err.Also(fbs.Files.Validate(ctx).ViaField("files"))
+	}
 	return err
 }
+
+// Validate implements apis.Validatable
+func (files *SinkBindingFiles) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	if files.CACerts != nil {
+		if files.CACerts.Name == "" {
+			errs = errs.Also(apis.ErrMissingField("caCerts.name"))
+		}
+		if files.CACerts.Key == "" {
+			errs = errs.Also(apis.ErrMissingField("caCerts.key"))
+		}
+	}
+	if files.Audience != nil && *files.Audience == "" {
+		errs = errs.Also(apis.ErrInvalidValue(*files.Audience, "audience"))
+	}
+	return errs
+}
@@ -20,7 +20,9 @@ import (
 	"context"
 	"testing"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/tracker"
@@ -146,6 +148,43 @@ func TestSinkBindingValidation(t *testing.T) {
 			"spec.ceOverrides.extensions",
 			"keys are expected to be alphanumeric",
 		),
+	}, {
+		name: "invalid files",
+		in: &SinkBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "matt",
+				Namespace: "test",
+			},
+			Spec: SinkBindingSpec{
+				BindingSpec: duckv1.BindingSpec{
+					Subject: tracker.Reference{
+						APIVersion: "apps/v1",
+						Kind:       "Deployment",
+						Name:       "jeanne",
+						Namespace:  "test",
+					},
+				},
+				SourceSpec: duckv1.SourceSpec{
+					Sink: duckv1.Destination{
+						Ref: &duckv1.KReference{
+							APIVersion: "serving.knative.dev/v1",
+							Kind:       "Service",
+							Name:       "gemma",
+							Namespace:  "test",
+						},
+					},
+				},
+				Files: &SinkBindingFiles{
+					Sink: true,
+					CACerts: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "sink-ca"},
+					},
+					Audience: pointer.StringPtr(""),
+				},
+			},
+		},
+		want: apis.ErrMissingField("spec.files.caCerts.key").Also(
+			apis.ErrInvalidValue("", "spec.files.audience")),
 	}}
 
 	for _, test := range tests {
@@ -0,0 +1,149 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package auth authenticates the senders of events using bearer tokens.
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+const (
+	// AuthorizationHeader is the header carrying the bearer token.
+	AuthorizationHeader = "Authorization"
+
+	bearerPrefix = "bearer "
+
+	// DefaultClusterIssuer is the default issuer of the Kubernetes service account tokens.
+	DefaultClusterIssuer = "https://kubernetes.default.svc.cluster.local"
+
+	// systemSubjectPrefix prefixes the Kubernetes users, e.g. system:serviceaccount:<namespace>:<name>.
+	systemSubjectPrefix = "system:"
+	// serviceAccountSubjectPrefix prefixes the Kubernetes service accounts, i.e.
+	// system:serviceaccount:<namespace>:<name>.
+	serviceAccountSubjectPrefix = "system:serviceaccount:"
+)
+
+var (
+	// ErrNoToken is returned when a request carries no bearer token.
+	ErrNoToken = errors.New("no bearer token")
+	// ErrInvalidToken is returned when a token could not be authenticated.
+	ErrInvalidToken = errors.New("invalid token")
+)
+
+// Identity is the authenticated sender of a request.
+type Identity struct {
+	// Issuer of the token. It is the cluster issuer for the Kubernetes service account tokens
+	// validated through the TokenReview API.
+	Issuer string
+	// Subject identifies the sender, e.g. system:serviceaccount:<namespace>:<name>.
+	Subject string
+}
+
+// Authenticator authenticates a bearer token.
+type Authenticator interface {
+	// Authenticate returns the Identity of the token, or an error wrapping ErrInvalidToken.
+	Authenticate(ctx context.Context, token string) (*Identity, error)
+}
+
+// AuthenticateRequest authenticates the bearer token of the request.
+func AuthenticateRequest(ctx context.Context, a Authenticator, r *http.Request) (*Identity, error) {
+	token := BearerToken(r.Header)
+	if token == "" {
+		return nil, ErrNoToken
+	}
+	return a.Authenticate(ctx, token)
+}
+
+// BearerToken extracts the bearer token from the Authorization header, if any.
+func BearerToken(h http.Header) string {
+	v := h.Get(AuthorizationHeader)
+	if len(v) < len(bearerPrefix) || !strings.EqualFold(v[:len(bearerPrefix)], bearerPrefix) {
+		return ""
+	}
+	return strings.TrimSpace(v[len(bearerPrefix):])
+}
+
+// Chain tries each Authenticator in order, returning the first Identity found.
+type Chain []Authenticator
+
+func (c Chain) Authenticate(ctx context.Context, token string) (*Identity, error) {
+	var errs []string
+	for _, a := range c {
+		id, err := a.Authenticate(ctx, token)
+		if err == nil {
+			return id, nil
+		}
+		errs = append(errs, err.Error())
+	}
+	if len(errs) == 0 {
+		return nil, ErrInvalidToken
+	}
+	return nil, &chainError{errs: errs}
+}
+
+type chainError struct {
+	errs []string
+}
+
+func (e *chainError) Error() string {
+	return strings.Join(e.errs, "; ")
+}
+
+func (e *chainError) Unwrap() error {
+	return ErrInvalidToken
+}
+
+// SubjectAllowed returns true if the subject of id matches one of the comma separated patterns.
+// A pattern ending with * matches any subject with the same prefix. Kubernetes users, e.g.
+// system:serviceaccount:<namespace>:<name>, are only allowed when issued by clusterIssuer, so
+// that tokens of other issuers can't impersonate them.
+func SubjectAllowed(patterns string, id *Identity, clusterIssuer string) bool {
+	subject := id.Subject
+	if strings.HasPrefix(subject, systemSubjectPrefix) && id.Issuer != clusterIssuer {
+		return false
+	}
+	for _, p := range strings.Split(patterns, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if strings.HasSuffix(p, "*") {
+			if strings.HasPrefix(subject, strings.TrimSuffix(p, "*")) {
+				return true
+			}
+		} else if p == subject {
+			return true
+		}
+	}
+	return false
+}
+
+// ServiceAccountNamespace returns the namespace of the Kubernetes service account id identifies,
+// when its token was issued by clusterIssuer.
+func ServiceAccountNamespace(id *Identity, clusterIssuer string) (string, bool) {
+	if id.Issuer != clusterIssuer || !strings.HasPrefix(id.Subject, serviceAccountSubjectPrefix) {
+		return "", false
+	}
+	parts := strings.Split(strings.TrimPrefix(id.Subject, serviceAccountSubjectPrefix), ":")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", false
+	}
+	return parts[0], true
+}
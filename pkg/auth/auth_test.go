@@ -0,0 +1,134 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+type staticAuthenticator map[string]string
+
+func (s staticAuthenticator) Authenticate(_ context.Context, token string) (*Identity, error) {
+	if sub, ok := s[token]; ok {
+		return &Identity{Subject: sub}, nil
+	}
+	return nil, fmt.Errorf("%w: unknown token", ErrInvalidToken)
+}
+
+func TestBearerToken(t *testing.T) {
+	tests := map[string]string{
+		"":             "",
+		"Basic abc":    "",
+		"Bearer":       "",
+		"Bearer abc":   "abc",
+		"bearer  abc ": "abc",
+		"BEARER a.b.c": "a.b.c",
+	}
+	for header, want := range tests {
+		h := http.Header{}
+		if header != "" {
+			h.Set(AuthorizationHeader, header)
+		}
+		if got := BearerToken(h); got != want {
+			t.Errorf("BearerToken(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestSubjectAllowed(t *testing.T) {
+	const external = "https://issuer.example.com"
+	tests := []struct {
+		patterns string
+		issuer   string
+		subject  string
+		want     bool
+	}{
+		{"", DefaultClusterIssuer, "system:serviceaccount:ns:sa", false},
+		{"system:serviceaccount:ns:sa", DefaultClusterIssuer, "system:serviceaccount:ns:sa", true},
+		{"system:serviceaccount:ns:other", DefaultClusterIssuer, "system:serviceaccount:ns:sa", false},
+		{"other, system:serviceaccount:ns:*", DefaultClusterIssuer, "system:serviceaccount:ns:sa", true},
+		{"system:serviceaccount:other:*", DefaultClusterIssuer, "system:serviceaccount:ns:sa", false},
+		{"*", external, "anyone", true},
+		{"other", external, "other", true},
+		{"system:serviceaccount:ns:sa", external, "system:serviceaccount:ns:sa", false},
+		{"*", external, "system:serviceaccount:ns:sa", false},
+	}
+	for _, tc := range tests {
+		id := &Identity{Issuer: tc.issuer, Subject: tc.subject}
+		if got := SubjectAllowed(tc.patterns, id, DefaultClusterIssuer); got != tc.want {
+			t.Errorf("SubjectAllowed(%q, %v) = %v, want %v", tc.patterns, id, got, tc.want)
+		}
+	}
+}
+
+func TestServiceAccountNamespace(t *testing.T) {
+	tests := []struct {
+		issuer  string
+		subject string
+		want    string
+		wantOK  bool
+	}{
+		{DefaultClusterIssuer, "system:serviceaccount:ns:sa", "ns", true},
+		{"https://issuer.example.com", "system:serviceaccount:ns:sa", "", false},
+		{DefaultClusterIssuer, "system:serviceaccount:ns", "", false},
+		{DefaultClusterIssuer, "system:node:ns:sa", "", false},
+		{DefaultClusterIssuer, "alice", "", false},
+	}
+	for _, tc := range tests {
+		id := &Identity{Issuer: tc.issuer, Subject: tc.subject}
+		if got, ok := ServiceAccountNamespace(id, DefaultClusterIssuer); got != tc.want || ok != tc.wantOK {
+			t.Errorf("ServiceAccountNamespace(%v) = %q, %v, want %q, %v", id, got, ok, tc.want, tc.wantOK)
+		}
+	}
+}
+
+func TestChain(t *testing.T) {
+	chain := Chain{staticAuthenticator{"a": "alice"}, staticAuthenticator{"b": "bob"}}
+
+	id, err := chain.Authenticate(context.Background(), "b")
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if id.Subject != "bob" {
+		t.Errorf("Subject = %q, want bob", id.Subject)
+	}
+
+	if _, err := chain.Authenticate(context.Background(), "c"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken, got %v", err)
+	}
+	if _, err := (Chain{}).Authenticate(context.Background(), "a"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken, got %v", err)
+	}
+}
+
+func TestAuthenticateRequest(t *testing.T) {
+	a := staticAuthenticator{"a": "alice"}
+	r, _ := http.NewRequest(http.MethodPost, "http://localhost", nil)
+
+	if _, err := AuthenticateRequest(context.Background(), a, r); !errors.Is(err, ErrNoToken) {
+		t.Errorf("Expected ErrNoToken, got %v", err)
+	}
+	r.Header.Set(AuthorizationHeader, "Bearer a")
+	id, err := AuthenticateRequest(context.Background(), a, r)
+	if err != nil || id.Subject != "alice" {
+		t.Errorf("AuthenticateRequest() = %v, %v, want alice", id, err)
+	}
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	authv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"knative.dev/pkg/network"
+
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/eventing/pkg/kncloudevents"
+)
+
+const (
+	// SecretTokenKey is the key of the bearer token in a credentials Secret.
+	SecretTokenKey = "token"
+	// SecretUsernameKey and SecretPasswordKey are the keys of the basic authentication
+	// credentials in a credentials Secret, as in kubernetes.io/basic-auth Secrets.
+	SecretUsernameKey = "username"
+	SecretPasswordKey = "password"
+
+	// DefaultTokenExpiration is the lifetime of the minted service account tokens.
+	DefaultTokenExpiration = time.Hour
+	// DefaultSecretCacheTTL is how long credentials read from Secrets are cached.
+	DefaultSecretCacheTTL = time.Minute
+)
+
+// CredentialsResolver resolves the credentials configured in a DeliverySpec. Service account
+// tokens are minted, through the TokenRequest API, for the service account the sender runs as,
+// and renewed once 80% of their lifetime has elapsed. As this service account is shared by all
+// the namespaces, tokens are only minted for the Services of the namespace of the Trigger or
+// Subscription.
+type CredentialsResolver struct {
+	client         kubernetes.Interface
+	serviceAccount types.NamespacedName
+	now            func() time.Time
+
+	mu sync.Mutex
+	// cache is indexed by "audience:<audience>" or "secret:<namespace>/<name>".
+	cache map[string]cachedCredentials
+}
+
+type cachedCredentials struct {
+	header  string
+	expires time.Time
+}
+
+// NewCredentialsResolver returns a CredentialsResolver minting tokens for serviceAccount.
+func NewCredentialsResolver(client kubernetes.Interface, serviceAccount types.NamespacedName) *CredentialsResolver {
+	return &CredentialsResolver{
+		client:         client,
+		serviceAccount: serviceAccount,
+		now:            time.Now,
+		cache:          make(map[string]cachedCredentials),
+	}
+}
+
+// Credentials returns the credentials configured by a, for the subscriber of a Trigger or
+// Subscription in namespace. It returns nil when a is nil.
+func (r *CredentialsResolver) Credentials(namespace string, subscriber *url.URL, a *eventingduckv1.DeliveryAuth) kncloudevents.Credentials {
+	if r == nil || a == nil {
+		return nil
+	}
+	return &deliveryCredentials{resolver: r, namespace: namespace, subscriber: subscriber, auth: a.DeepCopy()}
+}
+
+type deliveryCredentials struct {
+	resolver   *CredentialsResolver
+	namespace  string
+	subscriber *url.URL
+	auth       *eventingduckv1.DeliveryAuth
+}
+
+// Equal allows go-cmp to compare credentials, e.g. when checking whether a fanout config changed.
+func (c *deliveryCredentials) Equal(o *deliveryCredentials) bool {
+	if c == nil || o == nil {
+		return c == o
+	}
+	return c.resolver == o.resolver && c.namespace == o.namespace &&
+		equality.Semantic.DeepEqual(c.subscriber, o.subscriber) && equality.Semantic.DeepEqual(c.auth, o.auth)
+}
+
+func (c *deliveryCredentials) AuthorizationHeader(ctx context.Context) (string, error) {
+	switch {
+	case c.auth.Audience != nil:
+		// The tokens authenticate the sender on behalf of every namespace, so a namespace can
+		// only get them for its own subscribers. Otherwise it could send events to the
+		// subscribers of other namespaces trusting the sender.
+		if !IsNamespaceService(c.subscriber, c.namespace) {
+			return "", fmt.Errorf("the subscriber %s is not a Service of namespace %s, tokens are only minted for those", c.subscriber, c.namespace)
+		}
+		// Tokens are only minted for the subscriber they are sent to, so that they can't be
+		// used to impersonate the sender elsewhere.
+		if !IsSubscriberAudience(*c.auth.Audience, c.subscriber) {
+			return "", fmt.Errorf("audience %q does not identify the subscriber %s", *c.auth.Audience, c.subscriber)
+		}
+		return c.resolver.token(ctx, *c.auth.Audience)
+	case c.auth.SecretRef != nil:
+		return c.resolver.secret(ctx, types.NamespacedName{Namespace: c.namespace, Name: c.auth.SecretRef.Name})
+	default:
+		return "", fmt.Errorf("no credentials configured")
+	}
+}
+
+// IsSubscriberAudience returns whether audience identifies subscriber, either as its URI or
+// as its origin, i.e. its scheme and host.
+func IsSubscriberAudience(audience string, subscriber *url.URL) bool {
+	if subscriber == nil || subscriber.Host == "" {
+		return false
+	}
+	origin := (&url.URL{Scheme: subscriber.Scheme, Host: subscriber.Host}).String()
+	return audience == origin || audience == origin+"/" || audience == subscriber.String()
+}
+
+// IsNamespaceService returns whether subscriber is the cluster local address of a Service of
+// namespace, i.e. <name>.<namespace>.svc, optionally followed by the cluster domain.
+func IsNamespaceService(subscriber *url.URL, namespace string) bool {
+	if subscriber == nil {
+		return false
+	}
+	host := strings.TrimSuffix(subscriber.Hostname(), "."+network.GetClusterDomainName())
+	parts := strings.Split(host, ".")
+	return len(parts) == 3 && parts[0] != "" && parts[1] == namespace && parts[2] == "svc"
+}
+
+func (r *CredentialsResolver) token(ctx context.Context, audience string) (string, error) {
+	now := r.now()
+	key := "audience:" + audience
+	if h, ok := r.cached(key, now); ok {
+		return h, nil
+	}
+
+	expirationSeconds := int64(DefaultTokenExpiration / time.Second)
+	tr, err := r.client.CoreV1().ServiceAccounts(r.serviceAccount.Namespace).CreateToken(ctx, r.serviceAccount.Name, &authv1.TokenRequest{
+		Spec: authv1.TokenRequestSpec{
+			Audiences:         []string{audience},
+			ExpirationSeconds: &expirationSeconds,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to request a token for audience %q: %w", audience, err)
+	}
+
+	header := "Bearer " + tr.Status.Token
+	lifetime := tr.Status.ExpirationTimestamp.Sub(now)
+	r.store(key, cachedCredentials{header: header, expires: now.Add(lifetime * 8 / 10)}, now)
+	return header, nil
+}
+
+func (r *CredentialsResolver) secret(ctx context.Context, name types.NamespacedName) (string, error) {
+	now := r.now()
+	key := "secret:" + name.String()
+	if h, ok := r.cached(key, now); ok {
+		return h, nil
+	}
+
+	// The sender can only read the Secrets the namespace granted it access to, through a Role
+	// bound to its service account.
+	secret, err := r.client.CoreV1().Secrets(name.Namespace).Get(ctx, name.Name, metav1.GetOptions{})
+	if apierrs.IsForbidden(err) {
+		return "", fmt.Errorf("failed to get credentials Secret %s, %s is not allowed to read it in namespace %s: %w",
+			name, r.serviceAccount, name.Namespace, err)
+	} else if err != nil {
+		return "", fmt.Errorf("failed to get credentials Secret %s: %w", name, err)
+	}
+
+	var header string
+	if token, ok := secret.Data[SecretTokenKey]; ok {
+		header = "Bearer " + strings.TrimSpace(string(token))
+	} else if username, ok := secret.Data[SecretUsernameKey]; ok {
+		header = "Basic " + base64.StdEncoding.EncodeToString(append(append(username, ':'), secret.Data[SecretPasswordKey]...))
+	} else {
+		return "", fmt.Errorf("credentials Secret %s has neither a %q nor a %q key", name, SecretTokenKey, SecretUsernameKey)
+	}
+	r.store(key, cachedCredentials{header: header, expires: now.Add(DefaultSecretCacheTTL)}, now)
+	return header, nil
+}
+
+func (r *CredentialsResolver) cached(key string, now time.Time) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.cache[key]
+	if !ok || !now.Before(c.expires) {
+		return "", false
+	}
+	return c.header, true
+}
+
+func (r *CredentialsResolver) store(key string, c cachedCredentials, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// Drop expired entries, so that the cache does not grow unbounded.
+	for k, cc := range r.cache {
+		if !now.Before(cc.expires) {
+			delete(r.cache, k)
+		}
+	}
+	r.cache[key] = c
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	authv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	clientgotesting "k8s.io/client-go/testing"
+	"k8s.io/utils/pointer"
+
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+)
+
+func TestCredentialsResolverToken(t *testing.T) {
+	now := time.Now()
+	client := fake.NewSimpleClientset()
+	minted := 0
+	client.PrependReactor("create", "serviceaccounts", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		create := action.(clientgotesting.CreateAction)
+		if create.GetSubresource() != "token" || create.GetNamespace() != "knative-eventing" {
+			t.Errorf("Unexpected action %v", action)
+		}
+		tr := create.GetObject().(*authv1.TokenRequest)
+		minted++
+		tr.Status = authv1.TokenRequestStatus{
+			Token:               fmt.Sprintf("%s-%d", tr.Spec.Audiences[0], minted),
+			ExpirationTimestamp: metav1.NewTime(now.Add(time.Hour)),
+		}
+		return true, tr, nil
+	})
+
+	r := NewCredentialsResolver(client, types.NamespacedName{Namespace: "knative-eventing", Name: "filter"})
+	r.now = func() time.Time { return now }
+	subscriber := &url.URL{Scheme: "http", Host: "subscriber.ns.svc.cluster.local", Path: "/path"}
+	creds := r.Credentials("ns", subscriber, &eventingduckv1.DeliveryAuth{Audience: pointer.StringPtr("http://subscriber.ns.svc.cluster.local")})
+
+	for i := 0; i < 2; i++ {
+		h, err := creds.AuthorizationHeader(context.Background())
+		if err != nil {
+			t.Fatal("Unexpected error:", err)
+		}
+		if h != "Bearer http://subscriber.ns.svc.cluster.local-1" {
+			t.Errorf("Unexpected header %q", h)
+		}
+	}
+
+	// Renewed once 80% of the lifetime elapsed.
+	now = now.Add(50 * time.Minute)
+	h, err := creds.AuthorizationHeader(context.Background())
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if h != "Bearer http://subscriber.ns.svc.cluster.local-2" {
+		t.Errorf("Unexpected header %q", h)
+	}
+}
+
+func TestCredentialsResolverSecret(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "bearer"},
+			Data:       map[string][]byte{SecretTokenKey: []byte("token\n")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "basic"},
+			Data:       map[string][]byte{SecretUsernameKey: []byte("user"), SecretPasswordKey: []byte("pass")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "empty"},
+		},
+	)
+	r := NewCredentialsResolver(client, types.NamespacedName{})
+
+	tests := []struct {
+		secret  string
+		want    string
+		wantErr bool
+	}{
+		{secret: "bearer", want: "Bearer token"},
+		{secret: "basic", want: "Basic dXNlcjpwYXNz"},
+		{secret: "empty", wantErr: true},
+		{secret: "missing", wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.secret, func(t *testing.T) {
+			creds := r.Credentials("ns", nil, &eventingduckv1.DeliveryAuth{SecretRef: &corev1.LocalObjectReference{Name: tc.secret}})
+			h, err := creds.AuthorizationHeader(context.Background())
+			if tc.wantErr != (err != nil) {
+				t.Fatalf("Unexpected error %v", err)
+			}
+			if h != tc.want {
+				t.Errorf("Unexpected header %q, want %q", h, tc.want)
+			}
+		})
+	}
+}
+
+func TestCredentialsResolverNil(t *testing.T) {
+	var r *CredentialsResolver
+	if c := r.Credentials("ns", nil, &eventingduckv1.DeliveryAuth{Audience: pointer.StringPtr("subscriber")}); c != nil {
+		t.Errorf("Expected no credentials, got %v", c)
+	}
+	if c := NewCredentialsResolver(fake.NewSimpleClientset(), types.NamespacedName{}).Credentials("ns", nil, nil); c != nil {
+		t.Errorf("Expected no credentials, got %v", c)
+	}
+}
+
+func TestCredentialsResolverAudience(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "serviceaccounts", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		tr := action.(clientgotesting.CreateAction).GetObject().(*authv1.TokenRequest)
+		tr.Status = authv1.TokenRequestStatus{Token: "token", ExpirationTimestamp: metav1.NewTime(time.Now().Add(time.Hour))}
+		return true, tr, nil
+	})
+	r := NewCredentialsResolver(client, types.NamespacedName{Namespace: "knative-eventing", Name: "filter"})
+	subscriber := &url.URL{Scheme: "https", Host: "subscriber.ns.svc.cluster.local", Path: "/events"}
+
+	tests := []struct {
+		audience string
+		wantErr  bool
+	}{
+		{audience: "https://subscriber.ns.svc.cluster.local"},
+		{audience: "https://subscriber.ns.svc.cluster.local/"},
+		{audience: "https://subscriber.ns.svc.cluster.local/events"},
+		{audience: "https://kubernetes.default.svc", wantErr: true},
+		{audience: "subscriber.ns.svc.cluster.local", wantErr: true},
+		{audience: "http://subscriber.ns.svc.cluster.local", wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.audience, func(t *testing.T) {
+			creds := r.Credentials("ns", subscriber, &eventingduckv1.DeliveryAuth{Audience: pointer.StringPtr(tc.audience)})
+			if _, err := creds.AuthorizationHeader(context.Background()); tc.wantErr != (err != nil) {
+				t.Errorf("Unexpected error %v", err)
+			}
+		})
+	}
+}
+
+func TestCredentialsResolverAudienceOtherNamespace(t *testing.T) {
+	r := NewCredentialsResolver(fake.NewSimpleClientset(), types.NamespacedName{Namespace: "knative-eventing", Name: "filter"})
+
+	// A namespace can't get tokens for the subscribers of other namespaces, nor external ones.
+	for _, subscriber := range []string{
+		"http://protected.other.svc.cluster.local/events",
+		"http://protected.other.svc",
+		"https://subscriber.example.com",
+	} {
+		u, _ := url.Parse(subscriber)
+		creds := r.Credentials("ns", u, &eventingduckv1.DeliveryAuth{Audience: pointer.StringPtr(subscriber)})
+		if _, err := creds.AuthorizationHeader(context.Background()); err == nil {
+			t.Errorf("Expected an error minting a token for %s", subscriber)
+		}
+	}
+}
+
+func TestIsNamespaceService(t *testing.T) {
+	tests := map[string]bool{
+		"http://subscriber.ns.svc.cluster.local/path": true,
+		"http://subscriber.ns.svc:8080":               true,
+		"http://subscriber.ns":                        false,
+		"http://subscriber.other.svc.cluster.local":   false,
+		"http://a.subscriber.ns.svc.cluster.local":    false,
+		"http://subscriber.ns.svc.example.com":        false,
+	}
+	for subscriber, want := range tests {
+		u, _ := url.Parse(subscriber)
+		if got := IsNamespaceService(u, "ns"); got != want {
+			t.Errorf("IsNamespaceService(%s) = %v, want %v", subscriber, got, want)
+		}
+	}
+}
@@ -0,0 +1,319 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// clockSkew is the leeway applied when checking exp and nbf.
+const clockSkew = 30 * time.Second
+
+// minRSAKeyBits is the size of the smallest RSA key accepted in a JWKS.
+const minRSAKeyBits = 2048
+
+// errUnsupportedKey is returned for JWKS keys which can't be used to verify RS256 or ES256 tokens.
+var errUnsupportedKey = errors.New("unsupported key")
+
+type jwtAuthenticator struct {
+	issuer    string
+	audiences []string
+	jwksFile  string
+	now       func() time.Time
+
+	mu      sync.Mutex
+	keys    map[string]crypto.PublicKey
+	modTime time.Time
+}
+
+// NewJWTAuthenticator returns an Authenticator validating RS256 and ES256 signed JWTs issued by
+// issuer, using the JSON Web Key Set stored in jwksFile. The file is reloaded when it changes.
+// Tokens must name their signing key with the kid header. When audiences is not empty, tokens
+// must be issued for one of them.
+func NewJWTAuthenticator(issuer string, audiences []string, jwksFile string) (Authenticator, error) {
+	a := &jwtAuthenticator{
+		issuer:    issuer,
+		audiences: audiences,
+		jwksFile:  jwksFile,
+		now:       time.Now,
+	}
+	if _, err := a.publicKeys(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+type jwtClaims struct {
+	Issuer    string   `json:"iss"`
+	Subject   string   `json:"sub"`
+	Audience  audience `json:"aud"`
+	Expiry    int64    `json:"exp"`
+	NotBefore int64    `json:"nbf"`
+}
+
+// audience is either a single string or an array of strings.
+type audience []string
+
+func (a *audience) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*a = audience{s}
+		return nil
+	}
+	var ss []string
+	if err := json.Unmarshal(b, &ss); err != nil {
+		return err
+	}
+	*a = ss
+	return nil
+}
+
+func (a *jwtAuthenticator) Authenticate(_ context.Context, token string) (*Identity, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed JWT", ErrInvalidToken)
+	}
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: malformed JWT header: %v", ErrInvalidToken, err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed JWT signature: %v", ErrInvalidToken, err)
+	}
+
+	keys, err := a.publicKeys()
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySignature(header, keys, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: malformed JWT claims: %v", ErrInvalidToken, err)
+	}
+	if err := a.validateClaims(claims); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	return &Identity{Issuer: claims.Issuer, Subject: claims.Subject}, nil
+}
+
+func (a *jwtAuthenticator) validateClaims(claims jwtClaims) error {
+	if claims.Issuer != a.issuer {
+		return fmt.Errorf("unexpected issuer %q", claims.Issuer)
+	}
+	if claims.Subject == "" {
+		return fmt.Errorf("missing subject")
+	}
+	now := a.now()
+	if claims.Expiry == 0 || now.After(time.Unix(claims.Expiry, 0).Add(clockSkew)) {
+		return fmt.Errorf("token expired")
+	}
+	if claims.NotBefore != 0 && now.Add(clockSkew).Before(time.Unix(claims.NotBefore, 0)) {
+		return fmt.Errorf("token not valid yet")
+	}
+	if len(a.audiences) == 0 {
+		return nil
+	}
+	for _, want := range a.audiences {
+		for _, got := range claims.Audience {
+			if want == got {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("unexpected audience %v", []string(claims.Audience))
+}
+
+func verifySignature(header jwtHeader, keys map[string]crypto.PublicKey, signed string, signature []byte) error {
+	if header.Kid == "" {
+		return fmt.Errorf("missing key id")
+	}
+	key, ok := keys[header.Kid]
+	if !ok {
+		return fmt.Errorf("unknown key id %q", header.Kid)
+	}
+
+	digest := sha256.Sum256([]byte(signed))
+	switch header.Alg {
+	case "RS256":
+		if k, ok := key.(*rsa.PublicKey); ok && rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], signature) == nil {
+			return nil
+		}
+	case "ES256":
+		if k, ok := key.(*ecdsa.PublicKey); ok && len(signature) == 64 {
+			r := new(big.Int).SetBytes(signature[:32])
+			s := new(big.Int).SetBytes(signature[32:])
+			if ecdsa.Verify(k, digest[:], r, s) {
+				return nil
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported algorithm %q", header.Alg)
+	}
+	return fmt.Errorf("invalid signature")
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// publicKeys returns the keys in the JWKS file, reloading it if it changed.
+func (a *jwtAuthenticator) publicKeys() (map[string]crypto.PublicKey, error) {
+	info, statErr := os.Stat(a.jwksFile)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.keys != nil && (statErr != nil || !info.ModTime().After(a.modTime)) {
+		return a.keys, nil
+	}
+	if statErr != nil {
+		return nil, fmt.Errorf("failed to read JWKS: %w", statErr)
+	}
+	b, err := os.ReadFile(a.jwksFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS: %w", err)
+	}
+	keys, err := ParseJWKS(b)
+	if err != nil {
+		if a.keys != nil {
+			// Keep the previous keys while the file is being updated.
+			return a.keys, nil
+		}
+		return nil, err
+	}
+	a.keys = keys
+	a.modTime = info.ModTime()
+	return keys, nil
+}
+
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// ParseJWKS parses the RSA and P-256 signing keys of a JSON Web Key Set, indexed by key id. Keys
+// without a key id are ignored as tokens can't name them.
+func ParseJWKS(b []byte) (map[string]crypto.PublicKey, error) {
+	var set jwks
+	if err := json.Unmarshal(b, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS: %w", err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for i, k := range set.Keys {
+		if (k.Use != "" && k.Use != "sig") || k.Kid == "" {
+			continue
+		}
+		key, err := k.publicKey()
+		if errors.Is(err, errUnsupportedKey) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse JWKS key %d: %w", i, err)
+		}
+		if _, ok := keys[k.Kid]; ok {
+			return nil, fmt.Errorf("duplicate JWKS key id %q", k.Kid)
+		}
+		keys[k.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no signing key found in JWKS")
+	}
+	return keys, nil
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		key := &rsa.PublicKey{N: new(big.Int).SetBytes(n)}
+		if bits := key.N.BitLen(); bits < minRSAKeyBits {
+			return nil, fmt.Errorf("RSA key too small: %d bits", bits)
+		}
+		// The exponent must be odd and greater than 1, and fit the int32 range Go supports.
+		exp := new(big.Int).SetBytes(e)
+		if exp.BitLen() > 31 || exp.Int64() < 3 || exp.Bit(0) == 0 {
+			return nil, fmt.Errorf("invalid RSA exponent")
+		}
+		key.E = int(exp.Int64())
+		return key, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("%w: curve %q", errUnsupportedKey, k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !key.Curve.IsOnCurve(key.X, key.Y) {
+			return nil, fmt.Errorf("invalid EC key")
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("%w: key type %q", errUnsupportedKey, k.Kty)
+	}
+}
@@ -0,0 +1,285 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testIssuer = "https://issuer.example.com"
+
+func TestJWTAuthenticator(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwksFile := writeJWKS(t, []jwk{ecJWK("ec", &ecKey.PublicKey), rsaJWK("rsa", &rsaKey.PublicKey), {Kty: "oct", Kid: "sym"}})
+
+	a, err := NewJWTAuthenticator(testIssuer, []string{"broker"}, jwksFile)
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+
+	now := time.Now().Unix()
+	valid := map[string]interface{}{"iss": testIssuer, "sub": "sender", "aud": "broker", "exp": now + 60}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{{
+		name:  "ES256",
+		token: signES256(t, "ec", ecKey, valid),
+	}, {
+		name:  "RS256",
+		token: signRS256(t, "rsa", rsaKey, valid),
+	}, {
+		name:  "audience list",
+		token: signES256(t, "ec", ecKey, with(valid, "aud", []string{"other", "broker"})),
+	}, {
+		name:    "unknown signer",
+		token:   signES256(t, "ec", otherKey, valid),
+		wantErr: true,
+	}, {
+		name:    "unknown key id",
+		token:   signES256(t, "missing", ecKey, valid),
+		wantErr: true,
+	}, {
+		name:    "missing key id",
+		token:   signES256(t, "", ecKey, valid),
+		wantErr: true,
+	}, {
+		name:    "wrong issuer",
+		token:   signES256(t, "ec", ecKey, with(valid, "iss", "https://other.example.com")),
+		wantErr: true,
+	}, {
+		name:    "wrong audience",
+		token:   signES256(t, "ec", ecKey, with(valid, "aud", "other")),
+		wantErr: true,
+	}, {
+		name:    "expired",
+		token:   signES256(t, "ec", ecKey, with(valid, "exp", now-120)),
+		wantErr: true,
+	}, {
+		name:    "not valid yet",
+		token:   signES256(t, "ec", ecKey, with(valid, "nbf", now+120)),
+		wantErr: true,
+	}, {
+		name:    "malformed",
+		token:   "not-a-jwt",
+		wantErr: true,
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			id, err := a.Authenticate(context.Background(), tc.token)
+			if tc.wantErr {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Errorf("Expected ErrInvalidToken, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("Unexpected error:", err)
+			}
+			if id.Issuer != testIssuer || id.Subject != "sender" {
+				t.Errorf("Unexpected identity %+v", id)
+			}
+		})
+	}
+}
+
+func TestJWTAuthenticatorReload(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwksFile := writeJWKS(t, []jwk{ecJWK("1", &key.PublicKey)})
+	a, err := NewJWTAuthenticator(testIssuer, nil, jwksFile)
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	claims := map[string]interface{}{"iss": testIssuer, "sub": "sender", "exp": time.Now().Unix() + 60}
+
+	token := signES256(t, "2", rotated, claims)
+	if _, err := a.Authenticate(context.Background(), token); err == nil {
+		t.Fatal("Expected an error before the key is rotated")
+	}
+
+	b, _ := json.Marshal(jwks{Keys: []jwk{ecJWK("2", &rotated.PublicKey)}})
+	if err := os.WriteFile(jwksFile, b, 0600); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(jwksFile, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Authenticate(context.Background(), token); err != nil {
+		t.Error("Unexpected error after the key is rotated:", err)
+	}
+}
+
+func TestParseJWKS(t *testing.T) {
+	if _, err := ParseJWKS([]byte("{")); err == nil {
+		t.Error("Expected an error for invalid JSON")
+	}
+	if _, err := ParseJWKS([]byte(`{"keys":[{"kty":"oct"}]}`)); err == nil {
+		t.Error("Expected an error when no signing key is found")
+	}
+	if _, err := ParseJWKS([]byte(`{"keys":[{"kty":"EC","kid":"ec","crv":"P-256","x":"AQ","y":"AQ"}]}`)); err == nil {
+		t.Error("Expected an error for a point not on the curve")
+	}
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseJWKS(mustMarshalJWKS(t, ecJWK("", &ecKey.PublicKey))); err == nil {
+		t.Error("Expected an error when no key has a key id")
+	}
+	if _, err := ParseJWKS(mustMarshalJWKS(t, ecJWK("ec", &ecKey.PublicKey), ecJWK("ec", &ecKey.PublicKey))); err == nil {
+		t.Error("Expected an error for a duplicate key id")
+	}
+
+	smallKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseJWKS(mustMarshalJWKS(t, rsaJWK("rsa", &smallKey.PublicKey))); err == nil {
+		t.Error("Expected an error for an RSA key smaller than 2048 bits")
+	}
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range []string{"AQ", "BA", "AQAAAAAB"} {
+		k := rsaJWK("rsa", &rsaKey.PublicKey)
+		k.E = e
+		if _, err := ParseJWKS(mustMarshalJWKS(t, k)); err == nil {
+			t.Errorf("Expected an error for the RSA exponent %q", e)
+		}
+	}
+}
+
+func mustMarshalJWKS(t *testing.T, keys ...jwk) []byte {
+	t.Helper()
+	b, err := json.Marshal(jwks{Keys: keys})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func with(claims map[string]interface{}, key string, value interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(claims))
+	for k, v := range claims {
+		c[k] = v
+	}
+	c[key] = value
+	return c
+}
+
+func writeJWKS(t *testing.T, keys []jwk) string {
+	t.Helper()
+	b, err := json.Marshal(jwks{Keys: keys})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, b, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func ecJWK(kid string, key *ecdsa.PublicKey) jwk {
+	return jwk{
+		Kty: "EC",
+		Kid: kid,
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+		Y:   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+	}
+}
+
+func rsaJWK(kid string, key *rsa.PublicKey) jwk {
+	return jwk{
+		Kty: "RSA",
+		Kid: kid,
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func signingInput(t *testing.T, alg, kid string, claims map[string]interface{}) (string, [32]byte) {
+	t.Helper()
+	header, err := json.Marshal(jwtHeader{Alg: alg, Kid: kid})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signed, sha256.Sum256([]byte(signed))
+}
+
+func signES256(t *testing.T, kid string, key *ecdsa.PrivateKey, claims map[string]interface{}) string {
+	signed, digest := signingInput(t, "ES256", kid, claims)
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func signRS256(t *testing.T, kid string, key *rsa.PrivateKey, claims map[string]interface{}) string {
+	signed, digest := signingInput(t, "RS256", kid, claims)
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	authv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// DefaultTokenReviewCacheTTL is how long successful token reviews are cached.
+	DefaultTokenReviewCacheTTL = time.Minute
+)
+
+type tokenReviewAuthenticator struct {
+	client    kubernetes.Interface
+	issuer    string
+	audiences []string
+	ttl       time.Duration
+
+	mu sync.Mutex
+	// cache is indexed by the hash of the tokens.
+	cache map[[sha256.Size]byte]cachedIdentity
+	now   func() time.Time
+}
+
+type cachedIdentity struct {
+	identity *Identity
+	expires  time.Time
+}
+
+// NewTokenReviewAuthenticator returns an Authenticator validating Kubernetes (projected) service
+// account tokens, issued by the cluster issuer, through the TokenReview API. When audiences is not
+// empty, tokens must be issued for one of them. Successful reviews are cached for ttl, at most
+// until the token expires.
+func NewTokenReviewAuthenticator(client kubernetes.Interface, issuer string, audiences []string, ttl time.Duration) Authenticator {
+	return &tokenReviewAuthenticator{
+		client:    client,
+		issuer:    issuer,
+		audiences: audiences,
+		ttl:       ttl,
+		cache:     make(map[[sha256.Size]byte]cachedIdentity),
+		now:       time.Now,
+	}
+}
+
+func (a *tokenReviewAuthenticator) Authenticate(ctx context.Context, token string) (*Identity, error) {
+	now := a.now()
+	key := sha256.Sum256([]byte(token))
+	if id := a.cached(key, now); id != nil {
+		return id, nil
+	}
+
+	tr, err := a.client.AuthenticationV1().TokenReviews().Create(ctx, &authv1.TokenReview{
+		Spec: authv1.TokenReviewSpec{
+			Token:     token,
+			Audiences: a.audiences,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("token review failed: %w", err)
+	}
+	if !tr.Status.Authenticated {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidToken, tr.Status.Error)
+	}
+
+	id := &Identity{Issuer: a.issuer, Subject: tr.Status.User.Username}
+	expires := now.Add(a.ttl)
+	if exp, ok := tokenExpiry(token); ok && exp.Before(expires) {
+		expires = exp
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	// Drop expired entries, so that the cache does not grow unbounded.
+	for k, c := range a.cache {
+		if !now.Before(c.expires) {
+			delete(a.cache, k)
+		}
+	}
+	a.cache[key] = cachedIdentity{identity: id, expires: expires}
+	return id, nil
+}
+
+func (a *tokenReviewAuthenticator) cached(key [sha256.Size]byte, now time.Time) *Identity {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if c, ok := a.cache[key]; ok && now.Before(c.expires) {
+		return c.identity
+	}
+	return nil
+}
+
+// tokenExpiry returns the expiry of a JWT, which has been validated by the TokenReview API.
+func tokenExpiry(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil || claims.Expiry == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(claims.Expiry, 0), true
+}
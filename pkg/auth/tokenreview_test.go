@@ -0,0 +1,118 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	authv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clientgotesting "k8s.io/client-go/testing"
+)
+
+func TestTokenReviewAuthenticator(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	reviews := 0
+	client.PrependReactor("create", "tokenreviews", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		reviews++
+		tr := action.(clientgotesting.CreateAction).GetObject().(*authv1.TokenReview)
+		if len(tr.Spec.Audiences) != 1 || tr.Spec.Audiences[0] != "broker" {
+			t.Errorf("Unexpected audiences %v", tr.Spec.Audiences)
+		}
+		if tr.Spec.Token != "invalid" {
+			tr.Status.Authenticated = true
+			tr.Status.User.Username = "system:serviceaccount:ns:sa"
+		} else {
+			tr.Status.Error = "invalid"
+		}
+		return true, tr, nil
+	})
+
+	now := time.Now()
+	a := NewTokenReviewAuthenticator(client, DefaultClusterIssuer, []string{"broker"}, time.Minute).(*tokenReviewAuthenticator)
+	a.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		id, err := a.Authenticate(context.Background(), "valid")
+		if err != nil {
+			t.Fatal("Unexpected error:", err)
+		}
+		if id.Subject != "system:serviceaccount:ns:sa" || id.Issuer != DefaultClusterIssuer {
+			t.Errorf("Identity = %v", id)
+		}
+	}
+	if reviews != 1 {
+		t.Errorf("Expected the review to be cached, got %d reviews", reviews)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := a.Authenticate(context.Background(), "valid"); err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if reviews != 2 {
+		t.Errorf("Expected the cached review to expire, got %d reviews", reviews)
+	}
+
+	if _, err := a.Authenticate(context.Background(), "invalid"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken, got %v", err)
+	}
+}
+
+func TestTokenReviewAuthenticatorTokenExpiry(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	reviews := 0
+	client.PrependReactor("create", "tokenreviews", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		reviews++
+		tr := action.(clientgotesting.CreateAction).GetObject().(*authv1.TokenReview)
+		tr.Status.Authenticated = true
+		tr.Status.User.Username = "system:serviceaccount:ns:sa"
+		return true, tr, nil
+	})
+
+	now := time.Now()
+	a := NewTokenReviewAuthenticator(client, DefaultClusterIssuer, nil, time.Hour).(*tokenReviewAuthenticator)
+	a.now = func() time.Time { return now }
+
+	claims, _ := json.Marshal(map[string]interface{}{"exp": now.Add(time.Minute).Unix()})
+	token := "e30." + base64.RawURLEncoding.EncodeToString(claims) + ".c2ln"
+
+	if _, err := a.Authenticate(context.Background(), token); err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	now = now.Add(30 * time.Second)
+	if _, err := a.Authenticate(context.Background(), token); err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if reviews != 1 {
+		t.Errorf("Expected the review to be cached, got %d reviews", reviews)
+	}
+
+	// The token expired before the cache TTL.
+	now = now.Add(time.Minute)
+	if _, err := a.Authenticate(context.Background(), token); err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if reviews != 2 {
+		t.Errorf("Expected the token expiry to bound the cache TTL, got %d reviews", reviews)
+	}
+}
@@ -33,6 +33,7 @@ import (
 	"knative.dev/pkg/logging"
 
 	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/eventing/pkg/auth"
 	broker "knative.dev/eventing/pkg/broker"
 	eventinglisters "knative.dev/eventing/pkg/client/listers/eventing/v1"
 	"knative.dev/eventing/pkg/eventfilter"
@@ -63,17 +64,66 @@ type Handler struct {
 	// reporter reports stats of status code and dispatch time
 	reporter StatsReporter
 
+	// credentials resolves the credentials attached when delivering to a Trigger's subscriber
+	credentials *auth.CredentialsResolver
+
 	triggerLister eventinglisters.TriggerLister
 	logger        *zap.Logger
 }
 
+// HandlerOption customizes the connection args used to send events to subscribers
+// and the receiver options.
+type HandlerOption func(*handlerOptions)
+
+type handlerOptions struct {
+	connectionArgs  kncloudevents.ConnectionArgs
+	receiverOptions []kncloudevents.HTTPMessageReceiverOption
+	credentials     *auth.CredentialsResolver
+}
+
+// WithHTTP2 enables or disables HTTP/2 (h2c for plain http subscribers) by default.
+func WithHTTP2(enable bool) HandlerOption {
+	return func(o *handlerOptions) {
+		o.connectionArgs.EnableHTTP2 = enable
+	}
+}
+
+// WithDestinations overrides the transport settings for specific subscriber hosts.
+func WithDestinations(destinations map[string]kncloudevents.DestinationArgs) HandlerOption {
+	return func(o *handlerOptions) {
+		o.connectionArgs.Destinations = destinations
+	}
+}
+
+// WithTLS serves HTTPS on the given port, and uses the same certificate and CA bundle
+// when delivering events to https subscribers.
+func WithTLS(port int, args kncloudevents.TLSArgs) HandlerOption {
+	return func(o *handlerOptions) {
+		o.connectionArgs.TLS = args
+		o.receiverOptions = append(o.receiverOptions, kncloudevents.WithTLS(port, args))
+	}
+}
+
+// WithCredentials resolves the credentials configured in the Triggers' delivery spec.
+func WithCredentials(credentials *auth.CredentialsResolver) HandlerOption {
+	return func(o *handlerOptions) {
+		o.credentials = credentials
+	}
+}
+
 // NewHandler creates a new Handler and its associated MessageReceiver. The caller is responsible for
 // Start()ing the returned Handler.
-func NewHandler(logger *zap.Logger, triggerLister eventinglisters.TriggerLister, reporter StatsReporter, port int) (*Handler, error) {
-	kncloudevents.ConfigureConnectionArgs(&kncloudevents.ConnectionArgs{
-		MaxIdleConns:        defaultMaxIdleConnections,
-		MaxIdleConnsPerHost: defaultMaxIdleConnectionsPerHost,
-	})
+func NewHandler(logger *zap.Logger, triggerLister eventinglisters.TriggerLister, reporter StatsReporter, port int, opts ...HandlerOption) (*Handler, error) {
+	o := &handlerOptions{
+		connectionArgs: kncloudevents.ConnectionArgs{
+			MaxIdleConns:        defaultMaxIdleConnections,
+			MaxIdleConnsPerHost: defaultMaxIdleConnectionsPerHost,
+		},
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	kncloudevents.ConfigureConnectionArgs(&o.connectionArgs)
 
 	sender, err := kncloudevents.NewHTTPMessageSenderWithTarget("")
 	if err != nil {
@@ -81,9 +131,10 @@ func NewHandler(logger *zap.Logger, triggerLister eventinglisters.TriggerLister,
 	}
 
 	return &Handler{
-		receiver:      kncloudevents.NewHTTPMessageReceiver(port),
+		receiver:      kncloudevents.NewHTTPMessageReceiver(port, o.receiverOptions...),
 		sender:        sender,
 		reporter:      reporter,
+		credentials:   o.credentials,
 		triggerLister: triggerLister,
 		logger:        logger,
 	}, nil
@@ -195,6 +246,10 @@ func (h *Handler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 
 	h.reportArrivalTime(event, reportArgs)
 
+	if t.Spec.Delivery != nil {
+		ctx = kncloudevents.WithCredentials(ctx, subscriberURI.URL(), h.credentials.Credentials(t.Namespace, subscriberURI.URL(), t.Spec.Delivery.Auth))
+	}
+
 	h.send(ctx, writer, request.Header, subscriberURI.String(), reportArgs, event, ttl)
 }
 
@@ -35,11 +35,16 @@ import (
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	kubefake "k8s.io/client-go/kubernetes/fake"
 	"knative.dev/pkg/apis"
 
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/eventing/pkg/auth"
 	broker "knative.dev/eventing/pkg/broker"
 	reconcilertesting "knative.dev/eventing/pkg/reconciler/testing/v1"
 )
@@ -394,6 +399,19 @@ func TestReceiver(t *testing.T) {
 			returnedEvent:             makeDifferentEvent(),
 			responseHeaders:           http.Header{"Test-Header": []string{"TestValue"}},
 		},
+		"Attach delivery credentials": {
+			triggers: []*eventingv1.Trigger{
+				makeTriggerWithDeliveryAuth(&eventingduckv1.DeliveryAuth{
+					SecretRef: &corev1.LocalObjectReference{Name: "credentials"},
+				}),
+			},
+			expectedHeaders: http.Header{
+				"Authorization": []string{"Bearer token"},
+			},
+			expectedDispatch:          true,
+			expectedEventCount:        true,
+			expectedEventDispatchTime: true,
+		},
 		"Proxy empty non event response headers": {
 			triggers: []*eventingv1.Trigger{
 				makeTrigger(makeTriggerFilterWithAttributes("", "")),
@@ -440,7 +458,11 @@ func TestReceiver(t *testing.T) {
 				zaptest.NewLogger(t, zaptest.WrapOptions(zap.AddCaller())),
 				listers.GetTriggerLister(),
 				reporter,
-				8080)
+				8080,
+				WithCredentials(auth.NewCredentialsResolver(kubefake.NewSimpleClientset(&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: testNS, Name: "credentials"},
+					Data:       map[string][]byte{auth.SecretTokenKey: []byte("token")},
+				}), types.NamespacedName{})))
 			if tc.expectNewToFail {
 				if err == nil {
 					t.Fatal("Expected New to fail, it didn't")
@@ -684,6 +706,12 @@ func makeTrigger(filter *eventingv1.TriggerFilter) *eventingv1.Trigger {
 	}
 }
 
+func makeTriggerWithDeliveryAuth(a *eventingduckv1.DeliveryAuth) *eventingv1.Trigger {
+	t := makeTrigger(makeTriggerFilterWithAttributes("", ""))
+	t.Spec.Delivery = &eventingduckv1.DeliverySpec{Auth: a}
+	return t
+}
+
 func makeTriggerWithoutFilter() *eventingv1.Trigger {
 	t := makeTrigger(makeTriggerFilterWithAttributes("", ""))
 	t.Spec.Filter = nil
@@ -18,6 +18,7 @@ package ingress
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -31,10 +32,12 @@ import (
 	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
 	"go.opencensus.io/trace"
 	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 
 	"knative.dev/eventing/pkg/apis/eventing"
 	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/eventing/pkg/auth"
 	broker "knative.dev/eventing/pkg/broker"
 	eventinglisters "knative.dev/eventing/pkg/client/listers/eventing/v1"
 	"knative.dev/eventing/pkg/kncloudevents"
@@ -59,10 +62,25 @@ type Handler struct {
 	Reporter StatsReporter
 	// BrokerLister gets broker objects
 	BrokerLister eventinglisters.BrokerLister
+	// Authenticator authenticates the senders of events. When set, requests must carry a valid
+	// bearer token, whose subject is allowed by the Broker's allowed subjects annotation if present.
+	Authenticator auth.Authenticator
+	// ClusterIssuer is the issuer of the Kubernetes service account tokens, only those may
+	// authenticate Kubernetes users. Defaults to auth.DefaultClusterIssuer.
+	ClusterIssuer string
+	// RateLimiter throttles the events sent from a namespace or to a Broker, when set.
+	RateLimiter *RateLimiter
 
 	Logger *zap.Logger
 }
 
+func (h *Handler) clusterIssuer() string {
+	if h.ClusterIssuer == "" {
+		return auth.DefaultClusterIssuer
+	}
+	return h.ClusterIssuer
+}
+
 func (h *Handler) getBroker(name, namespace string) (*eventingv1.Broker, error) {
 	broker, err := h.BrokerLister.Brokers(namespace).Get(name)
 	if err != nil {
@@ -72,6 +90,8 @@ func (h *Handler) getBroker(name, namespace string) (*eventingv1.Broker, error)
 	return broker, nil
 }
 
+// guessChannelAddress guesses the address of the channel of a Broker whose status doesn't have
+// it yet. The channels serving https are only reached once their address is in the status.
 func guessChannelAddress(name, namespace, domain string) string {
 	url := url.URL{
 		Scheme: "http",
@@ -104,12 +124,14 @@ func (h *Handler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 	writer.Header().Set("Allow", "POST, OPTIONS")
 	// validate request method
 	if request.Method == http.MethodOptions {
-		writer.Header().Set("WebHook-Allowed-Origin", "*") // Accept from any Origin:
-		writer.Header().Set("WebHook-Allowed-Rate", "*")   // Unlimited requests/minute
-		writer.WriteHeader(http.StatusOK)
-		return
-	}
-	if request.Method != http.MethodPost {
+		if h.Authenticator == nil {
+			writer.Header().Set("WebHook-Allowed-Origin", "*") // Accept from any Origin:
+			writer.Header().Set("WebHook-Allowed-Rate", "*")   // Unlimited requests/minute
+			writer.WriteHeader(http.StatusOK)
+			return
+		}
+		// The origin of authenticated senders is allowed once they are authorized below.
+	} else if request.Method != http.MethodPost {
 		h.Logger.Warn("unexpected request method", zap.String("method", request.Method))
 		writer.WriteHeader(http.StatusMethodNotAllowed)
 		return
@@ -129,6 +151,31 @@ func (h *Handler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 
 	ctx := request.Context()
 
+	brokerNamespace := nsBrokerName[1]
+	brokerName := nsBrokerName[2]
+
+	var sender *auth.Identity
+	if h.Authenticator != nil {
+		id, statusCode := h.authorize(ctx, request, brokerNamespace, brokerName)
+		if statusCode != http.StatusOK {
+			if statusCode == http.StatusUnauthorized {
+				writer.Header().Set("WWW-Authenticate", "Bearer")
+			}
+			writer.WriteHeader(statusCode)
+			return
+		}
+		sender = id
+	}
+
+	if request.Method == http.MethodOptions {
+		if origin := request.Header.Get("WebHook-Request-Origin"); origin != "" {
+			writer.Header().Set("WebHook-Allowed-Origin", origin)
+		}
+		writer.Header().Set("WebHook-Allowed-Rate", "*")
+		writer.WriteHeader(http.StatusOK)
+		return
+	}
+
 	message := cehttp.NewMessageFromHttpRequest(request)
 	defer message.Finish(nil)
 
@@ -147,8 +194,6 @@ func (h *Handler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 		return
 	}
 
-	brokerNamespace := nsBrokerName[1]
-	brokerName := nsBrokerName[2]
 	brokerNamespacedName := types.NamespacedName{
 		Name:      brokerName,
 		Namespace: brokerNamespace,
@@ -173,7 +218,7 @@ func (h *Handler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 		eventType: event.Type(),
 	}
 
-	statusCode, dispatchTime := h.receive(ctx, request.Header, event, brokerNamespace, brokerName)
+	statusCode, dispatchTime := h.receive(ctx, request.Header, writer.Header(), event, h.senderNamespace(sender, brokerNamespace, brokerName), reporterArgs)
 	if dispatchTime > noDuration {
 		_ = h.Reporter.ReportEventDispatchTime(reporterArgs, statusCode, dispatchTime)
 	}
@@ -182,7 +227,55 @@ func (h *Handler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 	writer.WriteHeader(statusCode)
 }
 
-func (h *Handler) receive(ctx context.Context, headers http.Header, event *cloudevents.Event, brokerNamespace, brokerName string) (int, time.Duration) {
+// authorize authenticates the sender of the request and checks it is allowed to send events
+// to the Broker, returning its identity and http.StatusOK if so. The senders allowed by default
+// are the service accounts of the Broker's namespace.
+func (h *Handler) authorize(ctx context.Context, request *http.Request, brokerNamespace, brokerName string) (*auth.Identity, int) {
+	id, err := auth.AuthenticateRequest(ctx, h.Authenticator, request)
+	if err != nil {
+		h.Logger.Info("Failed to authenticate sender", zap.String("namespace", brokerNamespace),
+			zap.String("broker", brokerName), zap.Error(err))
+		if errors.Is(err, auth.ErrNoToken) || errors.Is(err, auth.ErrInvalidToken) {
+			return nil, http.StatusUnauthorized
+		}
+		return nil, http.StatusInternalServerError
+	}
+
+	broker, err := h.getBroker(brokerName, brokerNamespace)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, http.StatusNotFound
+		}
+		return nil, http.StatusInternalServerError
+	}
+	allowed, ok := broker.Annotations[eventing.BrokerAllowedSubjectsAnnotationKey]
+	if !ok {
+		allowed = "system:serviceaccount:" + brokerNamespace + ":*"
+	}
+	if !auth.SubjectAllowed(allowed, id, h.clusterIssuer()) {
+		h.Logger.Info("Sender not allowed", zap.String("namespace", brokerNamespace),
+			zap.String("broker", brokerName), zap.String("subject", id.Subject))
+		return nil, http.StatusForbidden
+	}
+	return id, http.StatusOK
+}
+
+// senderNamespace identifies the namespace an event is sent from, for the per namespace rate
+// limits. It is the namespace of the service account of an authenticated sender and the issuer
+// and subject of other authenticated senders. The senders which aren't authenticated set
+// everything the ingress could key them by, so they share the limits of their Broker.
+func (h *Handler) senderNamespace(id *auth.Identity, brokerNamespace, brokerName string) string {
+	if id == nil {
+		return "unauthenticated:" + brokerNamespace + "/" + brokerName
+	}
+	if ns, ok := auth.ServiceAccountNamespace(id, h.clusterIssuer()); ok {
+		return ns
+	}
+	return "subject:" + id.Issuer + " " + id.Subject
+}
+
+func (h *Handler) receive(ctx context.Context, headers http.Header, responseHeaders http.Header, event *cloudevents.Event, sender string, reporterArgs *ReportArgs) (int, time.Duration) {
+	brokerNamespace, brokerName := reporterArgs.ns, reporterArgs.broker
 
 	// Setting the extension as a string as the CloudEvents sdk does not support non-string extensions.
 	event.SetExtension(broker.EventArrivalTime, cloudevents.Timestamp{Time: time.Now()})
@@ -196,6 +289,21 @@ func (h *Handler) receive(ctx context.Context, headers http.Header, event *cloud
 		return http.StatusBadRequest, noDuration
 	}
 
+	if h.RateLimiter != nil {
+		var annotations map[string]string
+		if b, err := h.getBroker(brokerName, brokerNamespace); err == nil {
+			annotations = b.Annotations
+		}
+		brokerKey := types.NamespacedName{Namespace: brokerNamespace, Name: brokerName}
+		if reason, retryAfter := h.RateLimiter.Admit(sender, brokerKey, annotations, len(event.Data())); reason != "" {
+			h.Logger.Debug("Throttling event", zap.String("namespace", brokerNamespace), zap.String("broker", brokerName),
+				zap.String("sender", sender), zap.String("reason", reason), zap.Duration("retryAfter", retryAfter))
+			_ = h.Reporter.ReportEventThrottled(reporterArgs, reason)
+			responseHeaders.Set(kncloudevents.RetryAfterHeader, retryAfterSeconds(retryAfter))
+			return http.StatusTooManyRequests, noDuration
+		}
+	}
+
 	channelAddress, err := h.getChannelAddress(brokerName, brokerNamespace)
 	if err != nil {
 		h.Logger.Warn("Failed to get channel address, falling back on guess", zap.Error(err))
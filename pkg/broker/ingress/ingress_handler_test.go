@@ -18,6 +18,7 @@ package ingress
 
 import (
 	"bytes"
+	"context"
 	"io"
 	nethttp "net/http"
 	"net/http/httptest"
@@ -35,6 +36,7 @@ import (
 
 	"knative.dev/eventing/pkg/apis/eventing"
 	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/eventing/pkg/auth"
 	broker "knative.dev/eventing/pkg/broker"
 	"knative.dev/eventing/pkg/kncloudevents"
 	reconcilertestingv1 "knative.dev/eventing/pkg/reconciler/testing/v1"
@@ -62,6 +64,7 @@ func TestHandler_ServeHTTP(t *testing.T) {
 		reporter        StatsReporter
 		defaulter       client.EventDefaulter
 		brokers         []*eventingv1.Broker
+		authenticator   auth.Authenticator
 	}{
 		{
 			name:       "invalid method PATCH",
@@ -232,6 +235,95 @@ func TestHandler_ServeHTTP(t *testing.T) {
 				makeBroker("name", "ns"),
 			},
 		},
+		{
+			name:          "no bearer token",
+			method:        nethttp.MethodPost,
+			uri:           "/ns/name",
+			body:          getValidEvent(),
+			statusCode:    nethttp.StatusUnauthorized,
+			handler:       handler(),
+			reporter:      &mockReporter{},
+			defaulter:     broker.TTLDefaulter(logger, 100),
+			brokers:       []*eventingv1.Broker{makeBroker("name", "ns")},
+			authenticator: staticAuthenticator{"token": "system:serviceaccount:ns:sa"},
+		},
+		{
+			name:          "invalid bearer token",
+			method:        nethttp.MethodPost,
+			uri:           "/ns/name",
+			body:          getValidEvent(),
+			headers:       bearerHeaders("invalid"),
+			statusCode:    nethttp.StatusUnauthorized,
+			handler:       handler(),
+			reporter:      &mockReporter{},
+			defaulter:     broker.TTLDefaulter(logger, 100),
+			brokers:       []*eventingv1.Broker{makeBroker("name", "ns")},
+			authenticator: staticAuthenticator{"token": "system:serviceaccount:ns:sa"},
+		},
+		{
+			name:          "authenticated sender, broker not found",
+			method:        nethttp.MethodPost,
+			uri:           "/ns/name",
+			body:          getValidEvent(),
+			headers:       bearerHeaders("token"),
+			statusCode:    nethttp.StatusNotFound,
+			handler:       handler(),
+			reporter:      &mockReporter{},
+			defaulter:     broker.TTLDefaulter(logger, 100),
+			authenticator: staticAuthenticator{"token": "system:serviceaccount:ns:sa"},
+		},
+		{
+			name:          "authenticated sender not allowed",
+			method:        nethttp.MethodPost,
+			uri:           "/ns/name",
+			body:          getValidEvent(),
+			headers:       bearerHeaders("token"),
+			statusCode:    nethttp.StatusForbidden,
+			handler:       handler(),
+			reporter:      &mockReporter{},
+			defaulter:     broker.TTLDefaulter(logger, 100),
+			brokers:       []*eventingv1.Broker{withAllowedSubjects(makeBroker("name", "ns"), "system:serviceaccount:other:*")},
+			authenticator: staticAuthenticator{"token": "system:serviceaccount:ns:sa"},
+		},
+		{
+			name:          "authenticated sender allowed",
+			method:        nethttp.MethodPost,
+			uri:           "/ns/name",
+			body:          getValidEvent(),
+			headers:       bearerHeaders("token"),
+			statusCode:    senderResponseStatusCode,
+			handler:       handler(),
+			reporter:      &mockReporter{StatusCode: senderResponseStatusCode, EventDispatchTimeReported: true},
+			defaulter:     broker.TTLDefaulter(logger, 100),
+			brokers:       []*eventingv1.Broker{withAllowedSubjects(makeBroker("name", "ns"), "other, system:serviceaccount:ns:*")},
+			authenticator: staticAuthenticator{"token": "system:serviceaccount:ns:sa"},
+		},
+		{
+			name:          "authenticated sender, no allowed subjects",
+			method:        nethttp.MethodPost,
+			uri:           "/ns/name",
+			body:          getValidEvent(),
+			headers:       bearerHeaders("token"),
+			statusCode:    senderResponseStatusCode,
+			handler:       handler(),
+			reporter:      &mockReporter{StatusCode: senderResponseStatusCode, EventDispatchTimeReported: true},
+			defaulter:     broker.TTLDefaulter(logger, 100),
+			brokers:       []*eventingv1.Broker{makeBroker("name", "ns")},
+			authenticator: staticAuthenticator{"token": "system:serviceaccount:ns:sa"},
+		},
+		{
+			name:          "authenticated sender of another namespace, no allowed subjects",
+			method:        nethttp.MethodPost,
+			uri:           "/ns/name",
+			body:          getValidEvent(),
+			headers:       bearerHeaders("token"),
+			statusCode:    nethttp.StatusForbidden,
+			handler:       handler(),
+			reporter:      &mockReporter{},
+			defaulter:     broker.TTLDefaulter(logger, 100),
+			brokers:       []*eventingv1.Broker{makeBroker("name", "ns")},
+			authenticator: staticAuthenticator{"token": "system:serviceaccount:other:sa"},
+		},
 	}
 
 	for _, tc := range tt {
@@ -266,11 +358,12 @@ func TestHandler_ServeHTTP(t *testing.T) {
 			listers := reconcilertestingv1.NewListers(brokers)
 			sender, _ := kncloudevents.NewHTTPMessageSenderWithTarget("")
 			h := &Handler{
-				Sender:       sender,
-				Defaulter:    tc.defaulter,
-				Reporter:     &mockReporter{},
-				Logger:       logger,
-				BrokerLister: listers.GetBrokerLister(),
+				Sender:        sender,
+				Defaulter:     tc.defaulter,
+				Reporter:      &mockReporter{},
+				Logger:        logger,
+				BrokerLister:  listers.GetBrokerLister(),
+				Authenticator: tc.authenticator,
 			}
 
 			h.ServeHTTP(recorder, request)
@@ -315,6 +408,7 @@ func handler() nethttp.Handler {
 type mockReporter struct {
 	StatusCode                int
 	EventDispatchTimeReported bool
+	ThrottleReason            string
 }
 
 func (r *mockReporter) ReportEventCount(_ *ReportArgs, responseCode int) error {
@@ -327,6 +421,11 @@ func (r *mockReporter) ReportEventDispatchTime(_ *ReportArgs, _ int, _ time.Dura
 	return nil
 }
 
+func (r *mockReporter) ReportEventThrottled(_ *ReportArgs, reason string) error {
+	r.ThrottleReason = reason
+	return nil
+}
+
 func getValidEvent() io.Reader {
 	e := event.New()
 	e.SetType("type")
@@ -363,7 +462,189 @@ func makeBroker(name, namespace string) *eventingv1.Broker {
 	}
 }
 
+func withAllowedSubjects(b *eventingv1.Broker, subjects string) *eventingv1.Broker {
+	b.Annotations = map[string]string{eventing.BrokerAllowedSubjectsAnnotationKey: subjects}
+	return b
+}
+
+func bearerHeaders(token string) nethttp.Header {
+	return nethttp.Header{
+		cehttp.ContentType:       []string{event.ApplicationCloudEventsJSON},
+		auth.AuthorizationHeader: []string{"Bearer " + token},
+	}
+}
+
+type staticAuthenticator map[string]string
+
+func (s staticAuthenticator) Authenticate(_ context.Context, token string) (*auth.Identity, error) {
+	if sub, ok := s[token]; ok {
+		return &auth.Identity{Issuer: auth.DefaultClusterIssuer, Subject: sub}, nil
+	}
+	return nil, auth.ErrInvalidToken
+}
+
 func withUninitializedAnnotations(b *eventingv1.Broker) *eventingv1.Broker {
 	b.Status.Annotations = nil
 	return b
 }
+
+func TestHandler_ServeHTTPThrottled(t *testing.T) {
+	s := httptest.NewServer(handler())
+	defer s.Close()
+
+	b := makeBroker("name", "ns")
+	b.Annotations = map[string]string{eventing.BrokerEventsPerSecondAnnotationKey: "1"}
+	b.Status.Annotations = map[string]string{eventing.BrokerChannelAddressStatusAnnotationKey: s.URL}
+	listers := reconcilertestingv1.NewListers([]runtime.Object{b})
+	sender, _ := kncloudevents.NewHTTPMessageSenderWithTarget("")
+	reporter := &mockReporter{}
+	h := &Handler{
+		Sender:       sender,
+		Defaulter:    broker.TTLDefaulter(zap.NewNop(), 100),
+		Reporter:     reporter,
+		Logger:       zap.NewNop(),
+		BrokerLister: listers.GetBrokerLister(),
+		RateLimiter:  NewRateLimiter(LimitsConfig{}),
+	}
+
+	send := func() *nethttp.Response {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(nethttp.MethodPost, "/ns/name", getValidEvent())
+		request.Header.Add(cehttp.ContentType, event.ApplicationCloudEventsJSON)
+		h.ServeHTTP(recorder, request)
+		return recorder.Result()
+	}
+
+	if resp := send(); resp.StatusCode != senderResponseStatusCode {
+		t.Fatalf("expected status code %d got %d", senderResponseStatusCode, resp.StatusCode)
+	}
+	resp := send()
+	if resp.StatusCode != nethttp.StatusTooManyRequests {
+		t.Fatalf("expected status code %d got %d", nethttp.StatusTooManyRequests, resp.StatusCode)
+	}
+	if got := resp.Header.Get(kncloudevents.RetryAfterHeader); got != "1" {
+		t.Errorf("expected Retry-After 1 got %q", got)
+	}
+	if reporter.ThrottleReason != ThrottleReasonBrokerEvents {
+		t.Errorf("expected throttle reason %q got %q", ThrottleReasonBrokerEvents, reporter.ThrottleReason)
+	}
+}
+
+func TestHandler_ServeHTTPThrottledPerSender(t *testing.T) {
+	s := httptest.NewServer(handler())
+	defer s.Close()
+
+	b := withAllowedSubjects(makeBroker("name", "ns"), "system:serviceaccount:*")
+	b.Status.Annotations = map[string]string{eventing.BrokerChannelAddressStatusAnnotationKey: s.URL}
+	listers := reconcilertestingv1.NewListers([]runtime.Object{b})
+	sender, _ := kncloudevents.NewHTTPMessageSenderWithTarget("")
+	h := &Handler{
+		Sender:       sender,
+		Defaulter:    broker.TTLDefaulter(zap.NewNop(), 100),
+		Reporter:     &mockReporter{},
+		Logger:       zap.NewNop(),
+		BrokerLister: listers.GetBrokerLister(),
+		Authenticator: staticAuthenticator{
+			"noisy": "system:serviceaccount:noisy:sa",
+			"quiet": "system:serviceaccount:quiet:sa",
+		},
+		RateLimiter: NewRateLimiter(LimitsConfig{NamespaceEventsPerSecond: 1}),
+	}
+
+	send := func(token string) int {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(nethttp.MethodPost, "/ns/name", getValidEvent())
+		request.Header = bearerHeaders(token)
+		h.ServeHTTP(recorder, request)
+		return recorder.Result().StatusCode
+	}
+
+	if got := send("noisy"); got != senderResponseStatusCode {
+		t.Fatalf("expected status code %d got %d", senderResponseStatusCode, got)
+	}
+	if got := send("noisy"); got != nethttp.StatusTooManyRequests {
+		t.Errorf("expected status code %d got %d", nethttp.StatusTooManyRequests, got)
+	}
+	// The other senders of the Broker are not throttled.
+	if got := send("quiet"); got != senderResponseStatusCode {
+		t.Errorf("expected status code %d got %d", senderResponseStatusCode, got)
+	}
+}
+
+func TestHandler_ServeHTTPThrottledUnauthenticated(t *testing.T) {
+	s := httptest.NewServer(handler())
+	defer s.Close()
+
+	b := makeBroker("name", "ns")
+	b.Status.Annotations = map[string]string{eventing.BrokerChannelAddressStatusAnnotationKey: s.URL}
+	listers := reconcilertestingv1.NewListers([]runtime.Object{b})
+	sender, _ := kncloudevents.NewHTTPMessageSenderWithTarget("")
+	h := &Handler{
+		Sender:       sender,
+		Defaulter:    broker.TTLDefaulter(zap.NewNop(), 100),
+		Reporter:     &mockReporter{},
+		Logger:       zap.NewNop(),
+		BrokerLister: listers.GetBrokerLister(),
+		RateLimiter:  NewRateLimiter(LimitsConfig{NamespaceEventsPerSecond: 1}),
+	}
+
+	send := func(source string) int {
+		e := event.New()
+		e.SetID("1234")
+		e.SetType("type")
+		e.SetSource(source)
+		body, err := e.MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(nethttp.MethodPost, "/ns/name", bytes.NewReader(body))
+		request.Header.Add(cehttp.ContentType, event.ApplicationCloudEventsJSON)
+		h.ServeHTTP(recorder, request)
+		return recorder.Result().StatusCode
+	}
+
+	if got := send("source-1"); got != senderResponseStatusCode {
+		t.Fatalf("expected status code %d got %d", senderResponseStatusCode, got)
+	}
+	// Changing the source doesn't escape the limit of the unauthenticated senders.
+	if got := send("source-2"); got != nethttp.StatusTooManyRequests {
+		t.Errorf("expected status code %d got %d", nethttp.StatusTooManyRequests, got)
+	}
+}
+
+func TestHandler_ServeHTTPOptionsWithAuthentication(t *testing.T) {
+	listers := reconcilertestingv1.NewListers([]runtime.Object{makeBroker("name", "ns")})
+	h := &Handler{
+		Reporter:      &mockReporter{},
+		Logger:        zap.NewNop(),
+		BrokerLister:  listers.GetBrokerLister(),
+		Authenticator: staticAuthenticator{"token": "system:serviceaccount:ns:sa"},
+	}
+
+	options := func(headers nethttp.Header) *nethttp.Response {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(nethttp.MethodOptions, "/ns/name", nil)
+		request.Header = headers
+		h.ServeHTTP(recorder, request)
+		return recorder.Result()
+	}
+
+	resp := options(nethttp.Header{"Webhook-Request-Origin": []string{"sender.example.com"}})
+	if resp.StatusCode != nethttp.StatusUnauthorized {
+		t.Errorf("expected status code %d got %d", nethttp.StatusUnauthorized, resp.StatusCode)
+	}
+	if got := resp.Header.Get("WebHook-Allowed-Origin"); got != "" {
+		t.Errorf("expected no allowed origin got %q", got)
+	}
+
+	headers := bearerHeaders("token")
+	headers.Set("WebHook-Request-Origin", "sender.example.com")
+	resp = options(headers)
+	if resp.StatusCode != nethttp.StatusOK {
+		t.Errorf("expected status code %d got %d", nethttp.StatusOK, resp.StatusCode)
+	}
+	if got := resp.Header.Get("WebHook-Allowed-Origin"); got != "sender.example.com" {
+		t.Errorf("expected allowed origin sender.example.com got %q", got)
+	}
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	cm "knative.dev/pkg/configmap"
+
+	"knative.dev/eventing/pkg/apis/eventing"
+)
+
+const (
+	// LimitsConfigName is the name of the ConfigMap configuring the ingress rate limits.
+	LimitsConfigName = "config-ingress-limits"
+
+	NamespaceEventsPerSecondKey = "namespace-events-per-second"
+	NamespaceBytesPerSecondKey  = "namespace-bytes-per-second"
+	BrokerEventsPerSecondKey    = "broker-events-per-second"
+	BrokerBytesPerSecondKey     = "broker-bytes-per-second"
+
+	// Throttle reasons, recorded by the throttled events metric.
+	ThrottleReasonNamespaceEvents = "namespace_events"
+	ThrottleReasonNamespaceBytes  = "namespace_bytes"
+	ThrottleReasonBrokerEvents    = "broker_events"
+	ThrottleReasonBrokerBytes     = "broker_bytes"
+
+	// idleLimiterTTL is how long the limiter of an idle sender or Broker is kept.
+	idleLimiterTTL = 5 * time.Minute
+)
+
+// LimitsConfig are the rate limits applied by the ingress. Zero disables a limit.
+type LimitsConfig struct {
+	// NamespaceEventsPerSecond and NamespaceBytesPerSecond limit the events sent to all the
+	// Brokers by the senders of a namespace.
+	NamespaceEventsPerSecond float64
+	NamespaceBytesPerSecond  float64
+	// BrokerEventsPerSecond and BrokerBytesPerSecond limit the events sent to each Broker,
+	// unless lowered by the Broker's annotations.
+	BrokerEventsPerSecond float64
+	BrokerBytesPerSecond  float64
+}
+
+// NewLimitsConfigFromConfigMap creates a LimitsConfig from the supplied ConfigMap.
+func NewLimitsConfigFromConfigMap(config *corev1.ConfigMap) (*LimitsConfig, error) {
+	c := &LimitsConfig{}
+	if err := cm.Parse(config.Data,
+		cm.AsFloat64(NamespaceEventsPerSecondKey, &c.NamespaceEventsPerSecond),
+		cm.AsFloat64(NamespaceBytesPerSecondKey, &c.NamespaceBytesPerSecond),
+		cm.AsFloat64(BrokerEventsPerSecondKey, &c.BrokerEventsPerSecond),
+		cm.AsFloat64(BrokerBytesPerSecondKey, &c.BrokerBytesPerSecond),
+	); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// RateLimiter admits events based on per sender namespace and per Broker token buckets, whose
+// burst is one second worth of events or bytes. The buckets are independent, so that a sender
+// can't use up the capacity of the other senders of a shared Broker.
+type RateLimiter struct {
+	now func() time.Time
+
+	mu     sync.Mutex
+	config LimitsConfig
+	// senders are keyed by the namespace of the authenticated senders, and by Broker for the
+	// other senders, see Admit.
+	senders   map[string]*limiter
+	brokers   map[types.NamespacedName]*limiter
+	lastPrune time.Time
+}
+
+// limiter limits the events and bytes per second sent by a sender or to a Broker.
+type limiter struct {
+	eventsPerSecond float64
+	bytesPerSecond  float64
+	events          *rate.Limiter
+	bytes           *rate.Limiter
+	lastUsed        time.Time
+}
+
+// NewRateLimiter creates a RateLimiter enforcing config.
+func NewRateLimiter(config LimitsConfig) *RateLimiter {
+	return &RateLimiter{
+		now:     time.Now,
+		config:  config,
+		senders: make(map[string]*limiter),
+		brokers: make(map[types.NamespacedName]*limiter),
+	}
+}
+
+// UpdateFromConfigMap returns a configmap.Observer updating the limits.
+func (r *RateLimiter) UpdateFromConfigMap(logger *zap.Logger) func(*corev1.ConfigMap) {
+	return func(config *corev1.ConfigMap) {
+		c, err := NewLimitsConfigFromConfigMap(config)
+		if err != nil {
+			logger.Error("Failed to parse the ingress limits config, keeping the current limits", zap.Error(err))
+			return
+		}
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.config = *c
+		logger.Info("Updated the ingress limits", zap.Any("limits", c))
+	}
+}
+
+// Admit reserves capacity for an event with size bytes of data sent by sender to a Broker with
+// the given annotations. sender identifies the namespace the event is sent from, whose limits
+// apply to all the Brokers. The senders which aren't authenticated can't be told apart, so they
+// share one sender per Broker. When the event must be throttled, it returns the reason and how long
+// the sender should wait before retrying.
+func (r *RateLimiter) Admit(sender string, broker types.NamespacedName, annotations map[string]string, size int) (string, time.Duration) {
+	now := r.now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.prune(now)
+
+	ns := r.senders[sender]
+	ns = updateLimiter(ns, r.config.NamespaceEventsPerSecond, r.config.NamespaceBytesPerSecond, now)
+	r.senders[sender] = ns
+
+	b := r.brokers[broker]
+	b = updateLimiter(b,
+		annotationLimit(annotations, eventing.BrokerEventsPerSecondAnnotationKey, r.config.BrokerEventsPerSecond),
+		annotationLimit(annotations, eventing.BrokerBytesPerSecondAnnotationKey, r.config.BrokerBytesPerSecond),
+		now)
+	r.brokers[broker] = b
+
+	checks := []struct {
+		limiter *rate.Limiter
+		n       int
+		reason  string
+	}{
+		{ns.events, 1, ThrottleReasonNamespaceEvents},
+		{ns.bytes, size, ThrottleReasonNamespaceBytes},
+		{b.events, 1, ThrottleReasonBrokerEvents},
+		{b.bytes, size, ThrottleReasonBrokerBytes},
+	}
+	reservations := make([]*rate.Reservation, 0, len(checks))
+	for _, c := range checks {
+		if c.limiter == nil || c.n == 0 {
+			continue
+		}
+		// An event larger than the burst consumes the whole burst, instead of never being admitted.
+		n := c.n
+		if n > c.limiter.Burst() {
+			n = c.limiter.Burst()
+		}
+		res := c.limiter.ReserveN(now, n)
+		if delay := res.DelayFrom(now); delay > 0 {
+			res.CancelAt(now)
+			for _, prev := range reservations {
+				prev.CancelAt(now)
+			}
+			return c.reason, delay
+		}
+		reservations = append(reservations, res)
+	}
+	return "", 0
+}
+
+// prune drops the limiters of senders and Brokers which haven't sent or received events for a
+// while.
+func (r *RateLimiter) prune(now time.Time) {
+	if now.Sub(r.lastPrune) < idleLimiterTTL {
+		return
+	}
+	r.lastPrune = now
+	for k, l := range r.senders {
+		if now.Sub(l.lastUsed) > idleLimiterTTL {
+			delete(r.senders, k)
+		}
+	}
+	for k, l := range r.brokers {
+		if now.Sub(l.lastUsed) > idleLimiterTTL {
+			delete(r.brokers, k)
+		}
+	}
+}
+
+// updateLimiter returns l, or a new limiter if the limits changed.
+func updateLimiter(l *limiter, eventsPerSecond, bytesPerSecond float64, now time.Time) *limiter {
+	if l == nil || l.eventsPerSecond != eventsPerSecond || l.bytesPerSecond != bytesPerSecond {
+		l = &limiter{
+			eventsPerSecond: eventsPerSecond,
+			bytesPerSecond:  bytesPerSecond,
+			events:          newRateLimiter(eventsPerSecond),
+			bytes:           newRateLimiter(bytesPerSecond),
+		}
+	}
+	l.lastUsed = now
+	return l
+}
+
+func newRateLimiter(perSecond float64) *rate.Limiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Limit(perSecond), int(math.Ceil(perSecond)))
+}
+
+// annotationLimit returns the limit set by the annotation when it is lower than the configured
+// limit, which is zero when disabled. Broker owners can only lower the configured limit, so the
+// annotations which are missing, invalid, not positive or higher than the configured limit are
+// ignored.
+func annotationLimit(annotations map[string]string, key string, configured float64) float64 {
+	v, ok := annotations[key]
+	if !ok {
+		return configured
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f <= 0 || math.IsNaN(f) || math.IsInf(f, 0) {
+		return configured
+	}
+	if configured > 0 && f > configured {
+		return configured
+	}
+	return f
+}
+
+// retryAfterSeconds rounds d up to whole seconds, as expected by the Retry-After header.
+func retryAfterSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
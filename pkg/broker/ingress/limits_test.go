@@ -0,0 +1,182 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"knative.dev/eventing/pkg/apis/eventing"
+)
+
+func TestNewLimitsConfigFromConfigMap(t *testing.T) {
+	c, err := NewLimitsConfigFromConfigMap(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: LimitsConfigName},
+		Data: map[string]string{
+			NamespaceEventsPerSecondKey: "1000",
+			NamespaceBytesPerSecondKey:  "1e7",
+			BrokerEventsPerSecondKey:    "100.5",
+		},
+	})
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	want := &LimitsConfig{
+		NamespaceEventsPerSecond: 1000,
+		NamespaceBytesPerSecond:  1e7,
+		BrokerEventsPerSecond:    100.5,
+	}
+	if diff := cmp.Diff(want, c); diff != "" {
+		t.Error("Unexpected config (-want, +got):", diff)
+	}
+
+	if _, err := NewLimitsConfigFromConfigMap(&corev1.ConfigMap{
+		Data: map[string]string{BrokerBytesPerSecondKey: "lots"},
+	}); err == nil {
+		t.Error("Expected an error for an invalid value")
+	}
+}
+
+func TestRateLimiterAdmit(t *testing.T) {
+	now := time.Now()
+	r := NewRateLimiter(LimitsConfig{
+		NamespaceEventsPerSecond: 3,
+		BrokerBytesPerSecond:     100,
+	})
+	r.now = func() time.Time { return now }
+
+	admit := func(name string, annotations map[string]string, size int, wantReason string) time.Duration {
+		t.Helper()
+		reason, retryAfter := r.Admit("ns", types.NamespacedName{Namespace: "ns", Name: name}, annotations, size)
+		if reason != wantReason {
+			t.Fatalf("Admit(%s, %d) = %q, want %q", name, size, reason, wantReason)
+		}
+		return retryAfter
+	}
+
+	// Broker bytes: the burst is one second worth of bytes.
+	admit("a", nil, 60, "")
+	if retryAfter := admit("a", nil, 60, ThrottleReasonBrokerBytes); retryAfter <= 0 || retryAfter > time.Second {
+		t.Errorf("Unexpected retry after %v", retryAfter)
+	}
+	// The throttled event did not consume the namespace capacity.
+	admit("b", nil, 10, "")
+	admit("c", map[string]string{eventing.BrokerBytesPerSecondAnnotationKey: "0"}, 1000, "")
+	admit("d", nil, 1, ThrottleReasonNamespaceEvents)
+
+	// Capacity is refilled over time.
+	now = now.Add(time.Second)
+	admit("a", nil, 60, "")
+
+	// Broker annotations override the defaults.
+	now = now.Add(time.Second)
+	annotations := map[string]string{eventing.BrokerEventsPerSecondAnnotationKey: "1"}
+	admit("e", annotations, 0, "")
+	if retryAfter := admit("e", annotations, 0, ThrottleReasonBrokerEvents); retryAfter != time.Second {
+		t.Errorf("Unexpected retry after %v", retryAfter)
+	}
+}
+
+func TestRateLimiterAdmitPerSender(t *testing.T) {
+	r := NewRateLimiter(LimitsConfig{NamespaceEventsPerSecond: 1})
+	r.now = func() time.Time { return time.Unix(0, 0) }
+
+	// The senders of a shared Broker are limited separately.
+	shared := types.NamespacedName{Namespace: "broker-ns", Name: "shared"}
+	if reason, _ := r.Admit("noisy", shared, nil, 0); reason != "" {
+		t.Fatal("Unexpected throttling:", reason)
+	}
+	if reason, _ := r.Admit("noisy", shared, nil, 0); reason != ThrottleReasonNamespaceEvents {
+		t.Errorf("Expected the noisy sender to be throttled, got %q", reason)
+	}
+	if reason, _ := r.Admit("quiet", shared, nil, 0); reason != "" {
+		t.Error("Unexpected throttling of another sender:", reason)
+	}
+
+	// The limits of a sender apply to all the Brokers.
+	other := types.NamespacedName{Namespace: "noisy", Name: "default"}
+	if reason, _ := r.Admit("noisy", other, nil, 0); reason != ThrottleReasonNamespaceEvents {
+		t.Errorf("Expected the noisy sender to be throttled, got %q", reason)
+	}
+}
+
+func TestRateLimiterUpdateFromConfigMap(t *testing.T) {
+	r := NewRateLimiter(LimitsConfig{})
+	b := types.NamespacedName{Namespace: "ns", Name: "name"}
+	if reason, _ := r.Admit("ns", b, nil, 0); reason != "" {
+		t.Fatal("Unexpected throttling without limits:", reason)
+	}
+	r.UpdateFromConfigMap(zap.NewNop())(&corev1.ConfigMap{
+		Data: map[string]string{BrokerEventsPerSecondKey: "1"},
+	})
+	r.Admit("ns", b, nil, 0)
+	if reason, _ := r.Admit("ns", b, nil, 0); reason != ThrottleReasonBrokerEvents {
+		t.Errorf("Expected the new limits to apply, got %q", reason)
+	}
+
+	// Invalid configs are ignored.
+	r.UpdateFromConfigMap(zap.NewNop())(&corev1.ConfigMap{
+		Data: map[string]string{BrokerEventsPerSecondKey: "lots"},
+	})
+	if reason, _ := r.Admit("ns", b, nil, 0); reason != ThrottleReasonBrokerEvents {
+		t.Errorf("Expected the previous limits to apply, got %q", reason)
+	}
+}
+
+func TestAnnotationLimit(t *testing.T) {
+	key := eventing.BrokerEventsPerSecondAnnotationKey
+	tests := map[string]struct {
+		annotations map[string]string
+		configured  float64
+		want        float64
+	}{
+		"missing":               {configured: 10, want: 10},
+		"lower":                 {annotations: map[string]string{key: "5"}, configured: 10, want: 5},
+		"higher":                {annotations: map[string]string{key: "50"}, configured: 10, want: 10},
+		"zero":                  {annotations: map[string]string{key: "0"}, configured: 10, want: 10},
+		"negative":              {annotations: map[string]string{key: "-1"}, configured: 10, want: 10},
+		"invalid":               {annotations: map[string]string{key: "lots"}, configured: 10, want: 10},
+		"infinite":              {annotations: map[string]string{key: "+Inf"}, configured: 0, want: 0},
+		"limit without default": {annotations: map[string]string{key: "5"}, configured: 0, want: 5},
+	}
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			if got := annotationLimit(tc.annotations, key, tc.configured); got != tc.want {
+				t.Errorf("annotationLimit() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRetryAfterSeconds(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		time.Millisecond:                "1",
+		time.Second:                     "1",
+		1500 * time.Millisecond:         "2",
+		2*time.Second + time.Nanosecond: "3",
+	} {
+		if got := retryAfterSeconds(d); got != want {
+			t.Errorf("retryAfterSeconds(%v) = %s, want %s", d, got, want)
+		}
+	}
+}
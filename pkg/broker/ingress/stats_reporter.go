@@ -49,6 +49,14 @@ var (
 		stats.UnitMilliseconds,
 	)
 
+	// eventThrottledCountM is a counter which records the number of events
+	// rejected by the ingress because a rate limit was exceeded.
+	eventThrottledCountM = stats.Int64(
+		"event_throttled_count",
+		"Number of events throttled by the Broker ingress",
+		stats.UnitDimensionless,
+	)
+
 	// Create the tag keys that will be used to add tags to our measurements.
 	// Tag keys must conform to the restrictions described in
 	// go.opencensus.io/tag/validate.go. Currently those restrictions are:
@@ -57,6 +65,7 @@ var (
 	eventTypeKey         = tag.MustNewKey(eventingmetrics.LabelEventType)
 	responseCodeKey      = tag.MustNewKey(eventingmetrics.LabelResponseCode)
 	responseCodeClassKey = tag.MustNewKey(eventingmetrics.LabelResponseCodeClass)
+	throttleReasonKey    = tag.MustNewKey(eventingmetrics.LabelThrottleReason)
 )
 
 type ReportArgs struct {
@@ -73,6 +82,7 @@ func init() {
 type StatsReporter interface {
 	ReportEventCount(args *ReportArgs, responseCode int) error
 	ReportEventDispatchTime(args *ReportArgs, responseCode int, d time.Duration) error
+	ReportEventThrottled(args *ReportArgs, reason string) error
 }
 
 var _ StatsReporter = (*reporter)(nil)
@@ -114,6 +124,16 @@ func register() {
 			Aggregation: view.Distribution(metrics.Buckets125(1, 10000)...), // 1, 2, 5, 10, 20, 50, 100, 500, 1000, 5000, 10000
 			TagKeys:     tagKeys,
 		},
+		&view.View{
+			Description: eventThrottledCountM.Description(),
+			Measure:     eventThrottledCountM,
+			Aggregation: view.Count(),
+			TagKeys: []tag.Key{
+				eventTypeKey,
+				throttleReasonKey,
+				broker.ContainerTagKey,
+				broker.UniqueTagKey},
+		},
 	)
 	if err != nil {
 		log.Printf("failed to register opencensus views, %s", err)
@@ -141,16 +161,34 @@ func (r *reporter) ReportEventDispatchTime(args *ReportArgs, responseCode int, d
 	return nil
 }
 
-func (r *reporter) generateTag(args *ReportArgs, responseCode int) (context.Context, error) {
-	ctx := metricskey.WithResource(emptyContext, resource.Resource{
+// ReportEventThrottled captures the count of throttled events.
+func (r *reporter) ReportEventThrottled(args *ReportArgs, reason string) error {
+	ctx, err := tag.New(
+		r.resourceContext(args),
+		tag.Insert(broker.ContainerTagKey, r.container),
+		tag.Insert(broker.UniqueTagKey, r.uniqueName),
+		tag.Insert(eventTypeKey, args.eventType),
+		tag.Insert(throttleReasonKey, reason))
+	if err != nil {
+		return err
+	}
+	metrics.Record(ctx, eventThrottledCountM.M(1))
+	return nil
+}
+
+func (r *reporter) resourceContext(args *ReportArgs) context.Context {
+	return metricskey.WithResource(emptyContext, resource.Resource{
 		Type: eventingmetrics.ResourceTypeKnativeBroker,
 		Labels: map[string]string{
 			eventingmetrics.LabelNamespaceName: args.ns,
 			eventingmetrics.LabelBrokerName:    args.broker,
 		},
 	})
+}
+
+func (r *reporter) generateTag(args *ReportArgs, responseCode int) (context.Context, error) {
 	return tag.New(
-		ctx,
+		r.resourceContext(args),
 		tag.Insert(broker.ContainerTagKey, r.container),
 		tag.Insert(broker.UniqueTagKey, r.uniqueName),
 		tag.Insert(eventTypeKey, args.eventType),
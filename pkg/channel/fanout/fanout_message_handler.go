@@ -47,6 +47,8 @@ type Subscription struct {
 	Reply       *url.URL
 	DeadLetter  *url.URL
 	RetryConfig *kncloudevents.RetryConfig
+	// Credentials are attached to the requests sent to the Subscriber, if set.
+	Credentials kncloudevents.Credentials
 }
 
 // Config for a fanout.MessageHandler.
@@ -296,7 +298,7 @@ func (f *FanoutMessageHandler) dispatch(ctx context.Context, subs []Subscription
 // the `sink` portions of the subscription.
 func (f *FanoutMessageHandler) makeFanoutRequest(ctx context.Context, message binding.Message, additionalHeaders nethttp.Header, sub Subscription) (*channel.DispatchExecutionInfo, error) {
 	return f.dispatcher.DispatchMessageWithRetries(
-		ctx,
+		kncloudevents.WithCredentials(ctx, sub.Subscriber, sub.Credentials),
 		message,
 		additionalHeaders,
 		sub.Subscriber,
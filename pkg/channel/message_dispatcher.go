@@ -264,6 +264,8 @@ func (d *MessageDispatcherImpl) sanitizeURL(u *url.URL) *url.URL {
 		// Already a URL with a known scheme.
 		return u
 	}
+	// A host-only URL carries no scheme: destinations served over TLS are addressed with a full
+	// https URL, which is kept as is above.
 	return &url.URL{
 		Scheme: "http",
 		Host:   u.Host,
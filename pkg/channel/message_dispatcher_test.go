@@ -954,3 +954,21 @@ func canonicalizeHeaders(rvs ...requestValidation) {
 		}
 	}
 }
+
+func TestSanitizeURL(t *testing.T) {
+	d := NewMessageDispatcher(zap.NewNop())
+	for in, want := range map[string]string{
+		"https://subscriber.ns.svc/path": "https://subscriber.ns.svc/path",
+		"http://subscriber.ns.svc/path":  "http://subscriber.ns.svc/path",
+		"//subscriber.ns.svc":            "http://subscriber.ns.svc/",
+		"//subscriber.ns.svc:443":        "http://subscriber.ns.svc:443/",
+	} {
+		u, err := url.Parse(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := d.sanitizeURL(u).String(); got != want {
+			t.Errorf("sanitizeURL(%s) = %s, want %s", in, got, want)
+		}
+	}
+}
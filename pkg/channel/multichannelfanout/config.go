@@ -30,4 +30,6 @@ type ChannelConfig struct {
 	Name         string
 	HostName     string
 	FanoutConfig fanout.Config
+	// Path tells apart the channels served under the same host, see MakeChannelKey.
+	Path string
 }
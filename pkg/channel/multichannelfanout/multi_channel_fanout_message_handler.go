@@ -29,6 +29,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"sync"
 
 	"go.uber.org/zap"
@@ -48,7 +49,14 @@ type MultiChannelMessageHandler interface {
 // makeChannelKeyFromConfig creates the channel key for a given channelConfig. It is a helper around
 // MakeChannelKey.
 func makeChannelKeyFromConfig(config ChannelConfig) string {
-	return config.HostName
+	return MakeChannelKey(config.HostName, config.Path)
+}
+
+// MakeChannelKey creates the key of the channel reached at host and path. The channels served
+// under the host of the dispatcher, whose certificate doesn't cover the host of each channel, are
+// told apart by their path.
+func MakeChannelKey(host, path string) string {
+	return host + strings.TrimSuffix(path, "/")
 }
 
 // Handler is an http.Handler that introspects the incoming request to determine what Channel it is
@@ -121,6 +129,12 @@ func (h *MessageHandler) CountChannelHandlers() int {
 func (h *MessageHandler) ServeHTTP(response http.ResponseWriter, request *http.Request) {
 	channelKey := request.Host
 	fh := h.GetChannelHandler(channelKey)
+	if key := MakeChannelKey(request.Host, request.URL.Path); key != channelKey {
+		if pfh := h.GetChannelHandler(key); pfh != nil {
+			channelKey, fh = key, pfh
+			request = channelRequest(request)
+		}
+	}
 	if fh == nil {
 		h.logger.Info("Unable to find a handler for request", zap.String("channelKey", channelKey))
 		response.WriteHeader(http.StatusInternalServerError)
@@ -128,3 +142,16 @@ func (h *MessageHandler) ServeHTTP(response http.ResponseWriter, request *http.R
 	}
 	fh.ServeHTTP(response, request)
 }
+
+// channelRequest returns the request sent to a channel told apart by its /namespace/name path,
+// as if it was sent to the root path of the channel's host: the fanout handler only accepts
+// requests to the root path and resolves the channel from the host.
+func channelRequest(request *http.Request) *http.Request {
+	r := request.Clone(request.Context())
+	if parts := strings.Split(strings.Trim(request.URL.Path, "/"), "/"); len(parts) == 2 {
+		r.Host = parts[1] + "." + parts[0]
+	}
+	r.URL.Path = "/"
+	r.URL.RawPath = ""
+	return r
+}
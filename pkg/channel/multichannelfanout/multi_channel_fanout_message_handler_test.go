@@ -187,6 +187,55 @@ func TestServeHTTPMessageHandler(t *testing.T) {
 			key:                "second-channel.default",
 			expectedStatusCode: http.StatusAccepted,
 		},
+		"choose channel by path": {
+			config: Config{
+				ChannelConfigs: []ChannelConfig{
+					{
+						Namespace: "default",
+						Name:      "first-channel",
+						HostName:  "imc-dispatcher.knative-eventing.svc",
+						Path:      "/default/first-channel",
+						FanoutConfig: fanout.Config{
+							Subscriptions: []fanout.Subscription{
+								{
+									Reply: replaceDomain,
+								},
+							},
+						},
+					},
+					{
+						Namespace: "default",
+						Name:      "second-channel",
+						HostName:  "imc-dispatcher.knative-eventing.svc",
+						Path:      "/default/second-channel",
+						FanoutConfig: fanout.Config{
+							Subscriptions: []fanout.Subscription{
+								{
+									Subscriber: replaceDomain,
+								},
+							},
+						},
+					},
+				},
+			},
+			respStatusCode:     http.StatusOK,
+			key:                "imc-dispatcher.knative-eventing.svc/default/second-channel",
+			expectedStatusCode: http.StatusAccepted,
+		},
+		"unknown path": {
+			config: Config{
+				ChannelConfigs: []ChannelConfig{
+					{
+						Namespace: "default",
+						Name:      "first-channel",
+						HostName:  "imc-dispatcher.knative-eventing.svc",
+						Path:      "/default/first-channel",
+					},
+				},
+			},
+			key:                "imc-dispatcher.knative-eventing.svc/default/does-not-exist",
+			expectedStatusCode: http.StatusInternalServerError,
+		},
 	}
 	for n, tc := range testCases {
 		t.Run(n, func(t *testing.T) {
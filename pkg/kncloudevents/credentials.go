@@ -0,0 +1,62 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kncloudevents
+
+import (
+	"context"
+	"fmt"
+	nethttp "net/http"
+	"net/url"
+)
+
+// Credentials provides the value of the Authorization header attached to the requests sent
+// to a subscriber.
+type Credentials interface {
+	AuthorizationHeader(ctx context.Context) (string, error)
+}
+
+type credentialsKey struct{}
+
+type targetCredentials struct {
+	target      *url.URL
+	credentials Credentials
+}
+
+// WithCredentials returns a context in which HTTPMessageSender attaches credentials to the
+// requests sent to target. Requests sent to other URLs, e.g. replies or dead letter sinks,
+// are left untouched.
+func WithCredentials(ctx context.Context, target *url.URL, credentials Credentials) context.Context {
+	if target == nil || credentials == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, credentialsKey{}, targetCredentials{target: target, credentials: credentials})
+}
+
+// attachCredentials sets the Authorization header of req, when the credentials in its context
+// are meant for its URL.
+func attachCredentials(req *nethttp.Request) error {
+	tc, ok := req.Context().Value(credentialsKey{}).(targetCredentials)
+	if !ok || tc.target.Host != req.URL.Host || tc.target.Path != req.URL.Path {
+		return nil
+	}
+	v, err := tc.credentials.AuthorizationHeader(req.Context())
+	if err != nil {
+		return fmt.Errorf("failed to get credentials for %s: %w", tc.target, err)
+	}
+	req.Header.Set("Authorization", v)
+	return nil
+}
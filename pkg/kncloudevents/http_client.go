@@ -17,17 +17,33 @@ limitations under the License.
 package kncloudevents
 
 import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
 	nethttp "net/http"
+	"reflect"
 	"sync"
 	"time"
 
 	"go.opencensus.io/plugin/ochttp"
+	"golang.org/x/net/http2"
 	"knative.dev/pkg/tracing/propagation/tracecontextb3"
 )
 
 const (
 	defaultRetryWaitMin = 1 * time.Second
 	defaultRetryWaitMax = 30 * time.Second
+
+	// The h2c connections are dialed like those of nethttp.DefaultTransport.
+	dialTimeout   = 30 * time.Second
+	dialKeepAlive = 30 * time.Second
+
+	// HTTP/2 connections which haven't received a frame for http2ReadIdleTimeout are health
+	// checked with a ping, and closed when it isn't answered within http2PingTimeout, so that
+	// requests don't hang on dead connections.
+	http2ReadIdleTimeout = 30 * time.Second
+	http2PingTimeout     = 15 * time.Second
 )
 
 type holder struct {
@@ -45,13 +61,10 @@ func getClient() *nethttp.Client {
 	defer clientHolder.clientMutex.Unlock()
 
 	if clientHolder.client == nil {
-		// Add connection options to the default transport.
-		var base = nethttp.DefaultTransport.(*nethttp.Transport).Clone()
-		clientHolder.connectionArgs.configureTransport(base)
 		c := &nethttp.Client{
 			// Add output tracing.
 			Transport: &ochttp.Transport{
-				Base:        base,
+				Base:        clientHolder.connectionArgs.newTransport(),
 				Propagation: tracecontextb3.TraceContextEgress,
 			},
 		}
@@ -71,8 +84,7 @@ func ConfigureConnectionArgs(ca *ConnectionArgs) {
 	// Check if same config
 	if clientHolder.connectionArgs != nil &&
 		ca != nil &&
-		ca.MaxIdleConns == clientHolder.connectionArgs.MaxIdleConns &&
-		ca.MaxIdleConnsPerHost == clientHolder.connectionArgs.MaxIdleConnsPerHost {
+		reflect.DeepEqual(*ca, *clientHolder.connectionArgs) {
 		return
 	}
 
@@ -95,6 +107,38 @@ type ConnectionArgs struct {
 	MaxIdleConns int
 	// MaxIdleConnsPerHost refers to the max idle connections per host, as in net/http/transport.
 	MaxIdleConnsPerHost int
+	// EnableHTTP2 makes the client speak HTTP/2, multiplexing requests to the same host
+	// over a single connection. Plain http destinations are reached using h2c with prior
+	// knowledge, so they must support it, unless overridden in Destinations.
+	EnableHTTP2 bool
+	// Destinations overrides the transport settings for specific destinations,
+	// keyed by host (host[:port], as in url.URL.Host).
+	Destinations map[string]DestinationArgs
+	// TLS configures the CA bundle trusted for https destinations and the client
+	// certificate presented to them.
+	TLS TLSArgs
+}
+
+// DestinationArgs configures the transport used for a single destination host.
+type DestinationArgs struct {
+	// MaxIdleConnsPerHost refers to the max idle connections per host, as in net/http/transport.
+	// When 0, ConnectionArgs.MaxIdleConnsPerHost is used.
+	MaxIdleConnsPerHost int `json:"maxIdleConnsPerHost,omitempty"`
+	// EnableHTTP2 makes the client speak HTTP/2 (h2c for plain http) to this destination.
+	EnableHTTP2 bool `json:"enableHTTP2,omitempty"`
+}
+
+// ParseDestinationArgs parses a JSON object mapping destination hosts to their DestinationArgs,
+// e.g. {"subscriber.ns.svc.cluster.local": {"enableHTTP2": true}}.
+func ParseDestinationArgs(s string) (map[string]DestinationArgs, error) {
+	if s == "" {
+		return nil, nil
+	}
+	destinations := make(map[string]DestinationArgs)
+	if err := json.Unmarshal([]byte(s), &destinations); err != nil {
+		return nil, fmt.Errorf("failed to parse destination args: %w", err)
+	}
+	return destinations, nil
 }
 
 func (ca *ConnectionArgs) configureTransport(transport *nethttp.Transport) {
@@ -103,4 +147,110 @@ func (ca *ConnectionArgs) configureTransport(transport *nethttp.Transport) {
 	}
 	transport.MaxIdleConns = ca.MaxIdleConns
 	transport.MaxIdleConnsPerHost = ca.MaxIdleConnsPerHost
+	if cfg := ca.TLS.newClientTLSConfig(); cfg != nil {
+		transport.TLSClientConfig = cfg
+	}
+}
+
+// newTransport builds the base transport for the given connection args.
+// When neither HTTP/2 nor per destination settings are configured, this is a plain
+// *nethttp.Transport.
+func (ca *ConnectionArgs) newTransport() nethttp.RoundTripper {
+	// Add connection options to the default transport.
+	base := nethttp.DefaultTransport.(*nethttp.Transport).Clone()
+	ca.configureTransport(base)
+	if ca == nil || (!ca.EnableHTTP2 && len(ca.Destinations) == 0) {
+		return base
+	}
+
+	rt := &destinationTransport{
+		def:          newProtocolTransport(base.Clone(), ca.EnableHTTP2),
+		destinations: make(map[string]nethttp.RoundTripper, len(ca.Destinations)),
+	}
+	for host, da := range ca.Destinations {
+		t := base.Clone()
+		if da.MaxIdleConnsPerHost != 0 {
+			t.MaxIdleConnsPerHost = da.MaxIdleConnsPerHost
+		}
+		rt.destinations[host] = newProtocolTransport(t, da.EnableHTTP2)
+	}
+	return rt
+}
+
+type closeIdler interface {
+	CloseIdleConnections()
+}
+
+// destinationTransport routes requests to the transport configured for their destination host.
+type destinationTransport struct {
+	def          nethttp.RoundTripper
+	destinations map[string]nethttp.RoundTripper
+}
+
+func (t *destinationTransport) RoundTrip(req *nethttp.Request) (*nethttp.Response, error) {
+	if rt, ok := t.destinations[req.URL.Host]; ok {
+		return rt.RoundTrip(req)
+	}
+	return t.def.RoundTrip(req)
+}
+
+func (t *destinationTransport) CloseIdleConnections() {
+	if ci, ok := t.def.(closeIdler); ok {
+		ci.CloseIdleConnections()
+	}
+	for _, rt := range t.destinations {
+		if ci, ok := rt.(closeIdler); ok {
+			ci.CloseIdleConnections()
+		}
+	}
+}
+
+// newProtocolTransport returns base, or, when enableHTTP2 is set, a transport which
+// negotiates HTTP/2 over TLS and uses h2c with prior knowledge for plain http.
+func newProtocolTransport(base *nethttp.Transport, enableHTTP2 bool) nethttp.RoundTripper {
+	if !enableHTTP2 {
+		return base
+	}
+	base.ForceAttemptHTTP2 = true
+	// Register a new HTTP/2 transport for TLS connections rather than the one of the transport
+	// base was cloned from, to health check its connections.
+	base.TLSNextProto = nil
+	if h2, err := http2.ConfigureTransports(base); err == nil {
+		h2.ReadIdleTimeout = http2ReadIdleTimeout
+		h2.PingTimeout = http2PingTimeout
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   dialTimeout,
+		KeepAlive: dialKeepAlive,
+	}
+	return &http2Transport{
+		tls: base,
+		h2c: &http2.Transport{
+			AllowHTTP: true,
+			DialTLS: func(network, addr string, _ *tls.Config) (net.Conn, error) {
+				return dialer.Dial(network, addr)
+			},
+			ReadIdleTimeout: http2ReadIdleTimeout,
+			PingTimeout:     http2PingTimeout,
+		},
+	}
+}
+
+// http2Transport speaks HTTP/2 over TLS using the tls transport, and h2c over plain connections.
+type http2Transport struct {
+	tls *nethttp.Transport
+	h2c *http2.Transport
+}
+
+func (t *http2Transport) RoundTrip(req *nethttp.Request) (*nethttp.Response, error) {
+	if req.URL.Scheme == "http" {
+		return t.h2c.RoundTrip(req)
+	}
+	return t.tls.RoundTrip(req)
+}
+
+func (t *http2Transport) CloseIdleConnections() {
+	t.tls.CloseIdleConnections()
+	t.h2c.CloseIdleConnections()
 }
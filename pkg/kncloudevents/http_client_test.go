@@ -18,10 +18,14 @@ package kncloudevents
 
 import (
 	nethttp "net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	"go.opencensus.io/plugin/ochttp"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
 func TestConfigureConnectionArgs(t *testing.T) {
@@ -67,6 +71,65 @@ func TestConfigureConnectionArgs(t *testing.T) {
 	require.NotSame(t, client2, client3)
 }
 
+func TestConfigureConnectionArgsHTTP2(t *testing.T) {
+	server := httptest.NewServer(h2c.NewHandler(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		w.WriteHeader(nethttp.StatusAccepted)
+	}), &http2.Server{}))
+	defer server.Close()
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	ConfigureConnectionArgs(&ConnectionArgs{
+		MaxIdleConnsPerHost: 100,
+		MaxIdleConns:        1000,
+		EnableHTTP2:         true,
+	})
+	resp, err := getClient().Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, nethttp.StatusAccepted, resp.StatusCode)
+	require.Equal(t, 2, resp.ProtoMajor)
+
+	// HTTP/1.1 for this destination only.
+	ConfigureConnectionArgs(&ConnectionArgs{
+		MaxIdleConnsPerHost: 100,
+		MaxIdleConns:        1000,
+		EnableHTTP2:         true,
+		Destinations: map[string]DestinationArgs{
+			u.Host: {MaxIdleConnsPerHost: 10},
+		},
+	})
+	resp, err = getClient().Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, nethttp.StatusAccepted, resp.StatusCode)
+	require.Equal(t, 1, resp.ProtoMajor)
+
+	ConfigureConnectionArgs(nil)
+}
+
+func TestNewProtocolTransportHealthChecks(t *testing.T) {
+	rt := newProtocolTransport(nethttp.DefaultTransport.(*nethttp.Transport).Clone(), true).(*http2Transport)
+	require.Equal(t, http2ReadIdleTimeout, rt.h2c.ReadIdleTimeout)
+	require.Equal(t, http2PingTimeout, rt.h2c.PingTimeout)
+	require.Contains(t, rt.tls.TLSNextProto, "h2")
+}
+
+func TestParseDestinationArgs(t *testing.T) {
+	destinations, err := ParseDestinationArgs(`{"a.ns.svc.cluster.local": {"maxIdleConnsPerHost": 10, "enableHTTP2": true}}`)
+	require.NoError(t, err)
+	require.Equal(t, map[string]DestinationArgs{
+		"a.ns.svc.cluster.local": {MaxIdleConnsPerHost: 10, EnableHTTP2: true},
+	}, destinations)
+
+	destinations, err = ParseDestinationArgs("")
+	require.NoError(t, err)
+	require.Nil(t, destinations)
+
+	_, err = ParseDestinationArgs("{")
+	require.Error(t, err)
+}
+
 func castToTransport(client *nethttp.Client) *nethttp.Transport {
 	return client.Transport.(*ochttp.Transport).Base.(*nethttp.Transport)
 }
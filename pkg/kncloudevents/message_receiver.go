@@ -18,12 +18,15 @@ package kncloudevents
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net"
 	"net/http"
 	"time"
 
 	"go.opencensus.io/plugin/ochttp"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 	"knative.dev/pkg/network/handlers"
 	"knative.dev/pkg/tracing/propagation/tracecontextb3"
 )
@@ -38,6 +41,11 @@ type HTTPMessageReceiver struct {
 	server   *http.Server
 	listener net.Listener
 
+	tlsPort     int
+	tlsArgs     TLSArgs
+	tlsServer   *http.Server
+	tlsListener net.Listener
+
 	checker          http.HandlerFunc
 	drainQuietPeriod time.Duration
 
@@ -78,12 +86,33 @@ func WithDrainQuietPeriod(duration time.Duration) HTTPMessageReceiverOption {
 	}
 }
 
+// WithTLS makes the receiver serve HTTPS on the given port too, using the certificate in args.
+// The certificate is reloaded when it changes on disk. When args.ClientAuth is set, senders must
+// present a certificate signed by args.CAFile.
+func WithTLS(port int, args TLSArgs) HTTPMessageReceiverOption {
+	return func(h *HTTPMessageReceiver) {
+		h.tlsPort = port
+		h.tlsArgs = args
+	}
+}
+
 // Blocking
 func (recv *HTTPMessageReceiver) StartListen(ctx context.Context, handler http.Handler) error {
 	var err error
 	if recv.listener, err = net.Listen("tcp", fmt.Sprintf(":%d", recv.port)); err != nil {
 		return err
 	}
+	var tlsConfig *tls.Config
+	if recv.tlsArgs.Enabled() {
+		if tlsConfig, err = recv.tlsArgs.newServerTLSConfig(); err != nil {
+			recv.listener.Close()
+			return err
+		}
+		if recv.tlsListener, err = net.Listen("tcp", fmt.Sprintf(":%d", recv.tlsPort)); err != nil {
+			recv.listener.Close()
+			return err
+		}
+	}
 
 	drainer := &handlers.Drainer{
 		Inner:       CreateHandler(handler),
@@ -91,32 +120,70 @@ func (recv *HTTPMessageReceiver) StartListen(ctx context.Context, handler http.H
 		QuietPeriod: recv.drainQuietPeriod,
 	}
 	recv.server = &http.Server{
-		Addr:    recv.listener.Addr().String(),
-		Handler: drainer,
+		Addr: recv.listener.Addr().String(),
+		// Accept h2c as well, so senders with HTTP/2 enabled can multiplex requests over a single connection.
+		Handler: h2c.NewHandler(drainer, &http2.Server{}),
+	}
+
+	servers := []*http.Server{recv.server}
+	if recv.tlsListener != nil {
+		recv.tlsServer = &http.Server{
+			Addr:      recv.tlsListener.Addr().String(),
+			Handler:   drainer,
+			TLSConfig: tlsConfig,
+		}
+		servers = append(servers, recv.tlsServer)
 	}
 
-	errChan := make(chan error, 1)
+	errChan := make(chan error, len(servers))
 	go func() {
-		close(recv.Ready)
 		errChan <- recv.server.Serve(recv.listener)
 	}()
+	if recv.tlsServer != nil {
+		go func() {
+			// The certificate is provided by TLSConfig.GetCertificate.
+			errChan <- recv.tlsServer.ServeTLS(recv.tlsListener, "", "")
+		}()
+	}
+	close(recv.Ready)
 
-	// wait for the server to return or ctx.Done().
+	// wait for the servers to return or ctx.Done().
 	select {
 	case <-ctx.Done():
 		// As we start to shutdown, disable keep-alives to avoid clients hanging onto connections.
-		recv.server.SetKeepAlivesEnabled(false)
+		for _, s := range servers {
+			s.SetKeepAlivesEnabled(false)
+		}
 		drainer.Drain()
 		ctx, cancel := context.WithTimeout(context.Background(), getShutdownTimeout(ctx))
 		defer cancel()
-		err := recv.server.Shutdown(ctx)
-		<-errChan // Wait for server goroutine to exit
-		return err
+		return shutdown(ctx, servers, errChan)
 	case err := <-errChan:
+		if len(servers) > 1 {
+			// One of the servers stopped, stop the other one as well.
+			for _, s := range servers {
+				s.Close()
+			}
+			<-errChan
+		}
 		return err
 	}
 }
 
+// shutdown gracefully shuts down all the servers, waiting for their goroutines to exit.
+func shutdown(ctx context.Context, servers []*http.Server, errChan <-chan error) error {
+	var err error
+	for _, s := range servers {
+		if serr := s.Shutdown(ctx); serr != nil && err == nil {
+			err = serr
+		}
+	}
+	for range servers {
+		<-errChan // Wait for server goroutines to exit
+	}
+	return err
+}
+
 type shutdownTimeoutKey struct{}
 
 func getShutdownTimeout(ctx context.Context) time.Duration {
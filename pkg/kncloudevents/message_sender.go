@@ -52,7 +52,11 @@ func (s *HTTPMessageSender) NewCloudEventRequestWithTarget(ctx context.Context,
 	return nethttp.NewRequestWithContext(ctx, "POST", target, nil)
 }
 
+// Send sends the request, attaching the credentials set with WithCredentials, if any.
 func (s *HTTPMessageSender) Send(req *nethttp.Request) (*nethttp.Response, error) {
+	if err := attachCredentials(req); err != nil {
+		return nil, err
+	}
 	return s.Client.Do(req)
 }
 
@@ -60,6 +64,9 @@ func (s *HTTPMessageSender) SendWithRetries(req *nethttp.Request, config *RetryC
 	if config == nil {
 		return s.Send(req)
 	}
+	if err := attachCredentials(req); err != nil {
+		return nil, err
+	}
 
 	client := s.Client
 	if config.RequestTimeout != 0 {
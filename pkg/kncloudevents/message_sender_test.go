@@ -604,3 +604,35 @@ func TestHTTPMessageSender_NewCloudEventRequest(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, req.URL, expectedUrl)
 }
+
+type staticCredentials string
+
+func (c staticCredentials) AuthorizationHeader(context.Context) (string, error) {
+	return string(c), nil
+}
+
+func TestHTTPMessageSenderWithCredentials(t *testing.T) {
+	t.Parallel()
+
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.URL.Path+" "+r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	subscriber, err := url.Parse(server.URL + "/subscriber")
+	require.NoError(t, err)
+	ctx := WithCredentials(context.Background(), subscriber, staticCredentials("Bearer token"))
+
+	sender := &HTTPMessageSender{Client: http.DefaultClient}
+	for _, target := range []string{server.URL + "/subscriber", server.URL + "/reply"} {
+		req, err := sender.NewCloudEventRequestWithTarget(ctx, target)
+		require.NoError(t, err)
+		resp, err := sender.SendWithRetries(req, &RetryConfig{CheckRetry: RetryIfGreaterThan300, Backoff: func(int, *http.Response) time.Duration { return 0 }})
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	require.Equal(t, []string{"/subscriber Bearer token", "/reply "}, got)
+}
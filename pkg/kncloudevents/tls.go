@@ -0,0 +1,186 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kncloudevents
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// TLSArgs configures TLS for the data plane, both when receiving and when sending events.
+// Files are usually mounted from a Secret, certificates are reloaded when they change on disk.
+type TLSArgs struct {
+	// CertFile is the PEM encoded certificate served by the receiver and presented as client
+	// certificate by the sender.
+	CertFile string `envconfig:"TLS_CERT_FILE"`
+	// KeyFile is the PEM encoded private key of CertFile.
+	KeyFile string `envconfig:"TLS_KEY_FILE"`
+	// CAFile is a PEM encoded CA bundle trusted, in addition to the system roots, when sending
+	// events, and used to verify client certificates when ClientAuth is set.
+	CAFile string `envconfig:"TLS_CA_FILE"`
+	// ClientAuth makes the receiver require and verify client certificates (mTLS).
+	ClientAuth bool `envconfig:"TLS_CLIENT_AUTH" default:"false"`
+}
+
+// Enabled returns true if a certificate is configured.
+func (t TLSArgs) Enabled() bool {
+	return t.CertFile != "" && t.KeyFile != ""
+}
+
+// Validate checks that the configured files can be loaded.
+func (t TLSArgs) Validate() error {
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return fmt.Errorf("both TLS cert file and key file must be set")
+	}
+	if t.Enabled() {
+		if _, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile); err != nil {
+			return fmt.Errorf("failed to load TLS certificate: %w", err)
+		}
+	}
+	if t.CAFile != "" {
+		if _, err := loadCertPool(t.CAFile, false); err != nil {
+			return err
+		}
+	}
+	if t.ClientAuth && t.CAFile == "" {
+		return fmt.Errorf("TLS client auth requires a CA file")
+	}
+	return nil
+}
+
+// newServerTLSConfig returns the tls.Config used by a receiver.
+func (t TLSArgs) newServerTLSConfig() (*tls.Config, error) {
+	reloader := newCertificateReloader(t.CertFile, t.KeyFile)
+	cfg := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: reloader.getCertificate,
+	}
+	if t.ClientAuth {
+		pool, err := loadCertPool(t.CAFile, false)
+		if err != nil {
+			return nil, err
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return cfg, nil
+}
+
+// newClientTLSConfig returns the tls.Config used by a sender, or nil when the defaults should be used.
+func (t TLSArgs) newClientTLSConfig() *tls.Config {
+	if !t.Enabled() && t.CAFile == "" {
+		return nil
+	}
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	if t.CAFile != "" {
+		// An invalid bundle is reported by Validate, here we can only fall back to the system roots.
+		if pool, err := loadCertPool(t.CAFile, true); err == nil {
+			cfg.RootCAs = pool
+		}
+	}
+	if t.Enabled() {
+		cfg.GetClientCertificate = newCertificateReloader(t.CertFile, t.KeyFile).getClientCertificate
+	}
+	return cfg
+}
+
+// loadCertPool loads the PEM encoded certificates in caFile, optionally on top of the system roots.
+func loadCertPool(caFile string, withSystemRoots bool) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if withSystemRoots {
+		if sp, err := x509.SystemCertPool(); err == nil {
+			pool = sp
+		}
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificate found in CA file %s", caFile)
+	}
+	return pool, nil
+}
+
+// certificateReloader serves a key pair from disk, reloading it whenever the files are modified,
+// so that rotated Secrets are picked up without restarting.
+type certificateReloader struct {
+	certFile string
+	keyFile  string
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+func newCertificateReloader(certFile, keyFile string) *certificateReloader {
+	return &certificateReloader{certFile: certFile, keyFile: keyFile}
+}
+
+func (r *certificateReloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.load()
+}
+
+func (r *certificateReloader) getClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return r.load()
+}
+
+func (r *certificateReloader) load() (*tls.Certificate, error) {
+	modTime, statErr := r.lastModified()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cert != nil && (statErr != nil || !modTime.After(r.modTime)) {
+		// Either unchanged, or the files are in the middle of an update: keep serving the
+		// previous certificate.
+		return r.cert, nil
+	}
+	if statErr != nil {
+		return nil, statErr
+	}
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		if r.cert != nil {
+			return r.cert, nil
+		}
+		return nil, err
+	}
+	r.cert = &cert
+	r.modTime = modTime
+	return r.cert, nil
+}
+
+func (r *certificateReloader) lastModified() (time.Time, error) {
+	var last time.Time
+	for _, f := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(f)
+		if err != nil {
+			return last, err
+		}
+		if info.ModTime().After(last) {
+			last = info.ModTime()
+		}
+	}
+	return last, nil
+}
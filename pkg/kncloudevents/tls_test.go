@@ -0,0 +1,171 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kncloudevents
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	nethttp "net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLSArgsValidate(t *testing.T) {
+	args := writeTestCertificates(t)
+
+	require.NoError(t, args.Validate())
+	require.NoError(t, TLSArgs{}.Validate())
+	require.Error(t, TLSArgs{CertFile: args.CertFile}.Validate())
+	require.Error(t, TLSArgs{CertFile: args.CertFile, KeyFile: args.KeyFile, ClientAuth: true}.Validate())
+	require.Error(t, TLSArgs{CAFile: args.KeyFile}.Validate())
+}
+
+func TestHTTPMessageReceiverWithTLS(t *testing.T) {
+	args := writeTestCertificates(t)
+	args.ClientAuth = true
+
+	receiver := NewHTTPMessageReceiver(0, WithTLS(0, args), WithDrainQuietPeriod(time.Millisecond))
+	ctx, cancel := context.WithCancel(context.Background())
+	errChan := make(chan error)
+	go func() {
+		errChan <- receiver.StartListen(ctx, nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+			w.WriteHeader(nethttp.StatusAccepted)
+		}))
+	}()
+	<-receiver.Ready
+	target := fmt.Sprintf("https://127.0.0.1:%d", receiver.tlsListener.Addr().(*net.TCPAddr).Port)
+
+	// Trusting the CA and presenting a client certificate.
+	ConfigureConnectionArgs(&ConnectionArgs{TLS: args})
+	resp, err := getClient().Post(target, "application/json", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, nethttp.StatusAccepted, resp.StatusCode)
+
+	// Trusting the CA without a client certificate.
+	ConfigureConnectionArgs(&ConnectionArgs{TLS: TLSArgs{CAFile: args.CAFile}})
+	_, err = getClient().Post(target, "application/json", nil)
+	require.Error(t, err)
+
+	ConfigureConnectionArgs(nil)
+	cancel()
+	require.NoError(t, <-errChan)
+}
+
+func TestCertificateReloader(t *testing.T) {
+	args := writeTestCertificates(t)
+	reloader := newCertificateReloader(args.CertFile, args.KeyFile)
+
+	first, err := reloader.getCertificate(nil)
+	require.NoError(t, err)
+	same, err := reloader.getCertificate(nil)
+	require.NoError(t, err)
+	require.Same(t, first, same)
+
+	// Rotate the certificate.
+	rotated := writeTestCertificates(t)
+	copyFile(t, rotated.CertFile, args.CertFile)
+	copyFile(t, rotated.KeyFile, args.KeyFile)
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(args.CertFile, later, later))
+
+	second, err := reloader.getCertificate(nil)
+	require.NoError(t, err)
+	require.NotEqual(t, first.Certificate, second.Certificate)
+
+	// Keep serving the last certificate while the files are missing.
+	require.NoError(t, os.Remove(args.KeyFile))
+	third, err := reloader.getCertificate(nil)
+	require.NoError(t, err)
+	require.Same(t, second, third)
+}
+
+// writeTestCertificates writes a CA and a certificate for 127.0.0.1, signed by the CA and usable
+// both as server and client certificate.
+func writeTestCertificates(t *testing.T) TLSArgs {
+	t.Helper()
+	dir := t.TempDir()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1"), net.IPv6loopback},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	args := TLSArgs{
+		CertFile: filepath.Join(dir, "tls.crt"),
+		KeyFile:  filepath.Join(dir, "tls.key"),
+		CAFile:   filepath.Join(dir, "ca.crt"),
+	}
+	writePEM(t, args.CertFile, "CERTIFICATE", der)
+	writePEM(t, args.KeyFile, "EC PRIVATE KEY", keyDER)
+	writePEM(t, args.CAFile, "CERTIFICATE", caDER)
+	_, err = tls.LoadX509KeyPair(args.CertFile, args.KeyFile)
+	require.NoError(t, err)
+	return args
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600))
+}
+
+func copyFile(t *testing.T, from, to string) {
+	t.Helper()
+	b, err := os.ReadFile(from)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(to, b, 0600))
+}
@@ -68,4 +68,7 @@ const (
 
 	// LabelResponseTimeout is the label timeout.
 	LabelResponseTimeout = metricskey.LabelResponseTimeout
+
+	// LabelThrottleReason is the label for the limit which caused an event to be throttled.
+	LabelThrottleReason = "throttle_reason"
 )
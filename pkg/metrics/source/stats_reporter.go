@@ -41,6 +41,14 @@ var (
 		"Number of retry events sent",
 		stats.UnitDimensionless,
 	)
+
+	// droppedEventCountM is a counter which records the number of events dropped by the source
+	// after they failed to be sent.
+	droppedEventCountM = stats.Int64(
+		"dropped_event_count",
+		"Number of events dropped",
+		stats.UnitDimensionless,
+	)
 	// Create the tag keys that will be used to add tags to our measurements.
 	// Tag keys must conform to the restrictions described in
 	// go.opencensus.io/tag/validate.go. Currently those restrictions are:
@@ -79,7 +87,16 @@ type StatsReporter interface {
 	ReportRetryEventCount(args *ReportArgs, responseCode int) error
 }
 
-var _ StatsReporter = (*reporter)(nil)
+// DroppedEventReporter is implemented by the StatsReporters reporting the events dropped by the source.
+type DroppedEventReporter interface {
+	// ReportDroppedEventCount captures the dropped event count. It records one per call.
+	ReportDroppedEventCount(args *ReportArgs) error
+}
+
+var (
+	_ StatsReporter        = (*reporter)(nil)
+	_ DroppedEventReporter = (*reporter)(nil)
+)
 
 // reporter holds cached metric objects to report source metrics.
 type reporter struct {
@@ -115,6 +132,15 @@ func (r *reporter) ReportRetryEventCount(args *ReportArgs, responseCode int) err
 	return nil
 }
 
+func (r *reporter) ReportDroppedEventCount(args *ReportArgs) error {
+	ctx, err := r.generateTag(args, 0)
+	if err != nil {
+		return err
+	}
+	metrics.Record(ctx, droppedEventCountM.M(1))
+	return nil
+}
+
 func (r *reporter) generateTag(args *ReportArgs, responseCode int) (context.Context, error) {
 	return tag.New(
 		r.ctx,
@@ -155,6 +181,12 @@ func register() {
 			Aggregation: view.Count(),
 			TagKeys:     tagKeys,
 		},
+		&view.View{
+			Description: droppedEventCountM.Description(),
+			Measure:     droppedEventCountM,
+			Aggregation: view.Count(),
+			TagKeys:     tagKeys,
+		},
 	); err != nil {
 		panic(err)
 	}
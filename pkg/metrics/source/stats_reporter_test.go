@@ -76,6 +76,21 @@ func TestStatsReporter(t *testing.T) {
 	})
 	metricstest.CheckCountData(t, "event_count", wantTags, 2)
 	metricstest.CheckCountData(t, "retry_event_count", retryWantTags, 2)
+
+	droppedArgs := *args
+	droppedArgs.Error = "sink unavailable"
+	expectSuccess(t, func() error {
+		return r.(DroppedEventReporter).ReportDroppedEventCount(&droppedArgs)
+	})
+	metricstest.CheckCountData(t, "dropped_event_count", map[string]string{
+		metrics.LabelNamespaceName:   "testns",
+		metrics.LabelEventType:       "dev.knative.event",
+		metrics.LabelEventSource:     "unit-test",
+		metrics.LabelName:            "testsource",
+		metrics.LabelResourceGroup:   "testresourcegroup",
+		metrics.LabelResponseError:   "sink unavailable",
+		metrics.LabelResponseTimeout: "false",
+	}, 1)
 }
 
 func TestBadValues(t *testing.T) {
@@ -112,5 +127,6 @@ func resetMetrics() {
 	// OpenCensus metrics carry global state that need to be reset between unit tests.
 	metricstest.Unregister("event_count")
 	metricstest.Unregister("retry_event_count")
+	metricstest.Unregister("dropped_event_count")
 	register()
 }
@@ -49,6 +49,7 @@ const (
 	// Name of the corev1.Events emitted from the reconciliation process
 	apiserversourceDeploymentCreated = "ApiServerSourceDeploymentCreated"
 	apiserversourceDeploymentUpdated = "ApiServerSourceDeploymentUpdated"
+	apiserversourceCheckpointCreated = "ApiServerSourceCheckpointCreated"
 
 	component = "apiserversource"
 )
@@ -104,6 +105,13 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, source *v1.ApiServerSour
 		return err
 	}
 
+	if source.Spec.ResumeWatches {
+		if err := r.createCheckpoint(ctx, source); err != nil {
+			logging.FromContext(ctx).Errorw("Unable to create the checkpoint ConfigMap", zap.Error(err))
+			return err
+		}
+	}
+
 	ra, err := r.createReceiveAdapter(ctx, source, sinkURI.String())
 	if err != nil {
 		logging.FromContext(ctx).Errorw("Unable to create the receive adapter", zap.Error(err))
@@ -165,6 +173,26 @@ func (r *Reconciler) createReceiveAdapter(ctx context.Context, src *v1.ApiServer
 	return ra, nil
 }
 
+// createCheckpoint creates the ConfigMap where the receive adapter persists the
+// resource versions of its watches, unless it exists.
+func (r *Reconciler) createCheckpoint(ctx context.Context, src *v1.ApiServerSource) error {
+	expected := resources.MakeCheckpointConfigMap(src)
+
+	cm, err := r.kubeClientSet.CoreV1().ConfigMaps(src.Namespace).Get(ctx, expected.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		if _, err := r.kubeClientSet.CoreV1().ConfigMaps(src.Namespace).Create(ctx, expected, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+		controller.GetEventRecorder(ctx).Eventf(src, corev1.EventTypeNormal, apiserversourceCheckpointCreated, "ConfigMap %q created", expected.Name)
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("error getting checkpoint ConfigMap: %v", err)
+	} else if !metav1.IsControlledBy(cm, src) {
+		return fmt.Errorf("configmap %q is not owned by ApiServerSource %q", cm.Name, src.Name)
+	}
+	return nil
+}
+
 func (r *Reconciler) podSpecChanged(oldPodSpec corev1.PodSpec, newPodSpec corev1.PodSpec) bool {
 	if !equality.Semantic.DeepDerivative(newPodSpec, oldPodSpec) {
 		return true
@@ -196,46 +224,45 @@ func (r *Reconciler) runAccessCheck(ctx context.Context, src *v1.ApiServerSource
 	verbs := []string{"get", "list", "watch"}
 	lastReason := ""
 
+	namespace := src.Namespace
+	resources := src.Spec.Resources
+	if src.Spec.NamespaceSelector != nil {
+		// The resources are watched in all the selected namespaces, which are watched too.
+		namespace = ""
+		if !watchesNamespaces(resources) {
+			resources = append(resources[:len(resources):len(resources)], v1.APIVersionKindSelector{APIVersion: "v1", Kind: "Namespace"})
+		}
+	}
+
 	// Collect all missing permissions.
 	missing := ""
 	sep := ""
 
-	for _, res := range src.Spec.Resources {
+	for _, res := range resources {
 		gv, err := schema.ParseGroupVersion(res.APIVersion)
 		if err != nil {
 			return err
 		}
 		gvr, _ := meta.UnsafeGuessKindToResource(schema.GroupVersionKind{Kind: res.Kind, Group: gv.Group, Version: gv.Version}) // TODO: Test for nil Kind.
-		missingVerbs := ""
-		sep1 := ""
-		for _, verb := range verbs {
-			sar := &authorizationv1.SubjectAccessReview{
-				Spec: authorizationv1.SubjectAccessReviewSpec{
-					ResourceAttributes: &authorizationv1.ResourceAttributes{
-						Namespace: src.Namespace,
-						Verb:      verb,
-						Group:     gv.Group,
-						Resource:  gvr.Resource,
-					},
-					User: user,
-				},
-			}
-
-			response, err := r.kubeClientSet.AuthorizationV1().SubjectAccessReviews().Create(ctx, sar, metav1.CreateOptions{})
-			if err != nil {
-				return err
-			}
-
-			if !response.Status.Allowed {
-				missingVerbs += sep1 + verb
-				sep1 = ", "
-			}
+		missingVerbs, err := r.missingVerbs(ctx, user, namespace, gvr.GroupResource(), verbs)
+		if err != nil {
+			return err
 		}
 		if missingVerbs != "" {
 			missing += sep + missingVerbs + ` resource "` + gvr.Resource + `" in API group "` + gv.Group + `"`
 			sep = ", "
 		}
 	}
+	if src.Spec.ResumeWatches {
+		// The receive adapter persists the resource versions in a ConfigMap.
+		missingVerbs, err := r.missingVerbs(ctx, user, src.Namespace, schema.GroupResource{Resource: "configmaps"}, []string{"get", "update"})
+		if err != nil {
+			return err
+		}
+		if missingVerbs != "" {
+			missing += sep + missingVerbs + ` resource "configmaps" in API group ""`
+		}
+	}
 	if missing == "" {
 		src.Status.MarkSufficientPermissions()
 		return nil
@@ -246,15 +273,80 @@ func (r *Reconciler) runAccessCheck(ctx context.Context, src *v1.ApiServerSource
 
 }
 
+// missingVerbs returns the verbs user cannot use on resource in namespace, separated by commas.
+func (r *Reconciler) missingVerbs(ctx context.Context, user, namespace string, resource schema.GroupResource, verbs []string) (string, error) {
+	missingVerbs := ""
+	sep := ""
+	for _, verb := range verbs {
+		sar := &authorizationv1.SubjectAccessReview{
+			Spec: authorizationv1.SubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Namespace: namespace,
+					Verb:      verb,
+					Group:     resource.Group,
+					Resource:  resource.Resource,
+				},
+				User: user,
+			},
+		}
+
+		response, err := r.kubeClientSet.AuthorizationV1().SubjectAccessReviews().Create(ctx, sar, metav1.CreateOptions{})
+		if err != nil {
+			return "", err
+		}
+
+		if !response.Status.Allowed {
+			missingVerbs += sep + verb
+			sep = ", "
+		}
+	}
+	return missingVerbs, nil
+}
+
+// watchesNamespaces returns whether resources include the namespaces.
+func watchesNamespaces(resources []v1.APIVersionKindSelector) bool {
+	for _, res := range resources {
+		if res.APIVersion == "v1" && res.Kind == "Namespace" {
+			return true
+		}
+	}
+	return false
+}
+
+// watchesKubernetesEvents returns whether resources include the Kubernetes Events.
+func watchesKubernetesEvents(resources []v1.APIVersionKindSelector) bool {
+	for _, res := range resources {
+		if res.Kind == "Event" && (res.APIVersion == "v1" || res.APIVersion == "events.k8s.io/v1") {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *Reconciler) createCloudEventAttributes(src *v1.ApiServerSource) ([]duckv1.CloudEventAttributes, error) {
 	var eventTypes []string
 	if src.Spec.EventMode == v1.ReferenceMode {
 		eventTypes = apisources.ApiServerSourceEventReferenceModeTypes
 	} else if src.Spec.EventMode == v1.ResourceMode {
 		eventTypes = apisources.ApiServerSourceEventResourceModeTypes
+	} else if src.Spec.EventMode == v1.DiffMode {
+		eventTypes = apisources.ApiServerSourceEventDiffModeTypes
 	} else {
 		return []duckv1.CloudEventAttributes{}, fmt.Errorf("no EventType available for EventMode: %s", src.Spec.EventMode)
 	}
+	if src.Spec.KubernetesEvents && watchesKubernetesEvents(src.Spec.Resources) {
+		switch src.Spec.EventMode {
+		case v1.ReferenceMode:
+			eventTypes = append(eventTypes[:len(eventTypes):len(eventTypes)], apisources.ApiServerSourceKubernetesEventReferenceModeTypes...)
+		case v1.ResourceMode:
+			eventTypes = append(eventTypes[:len(eventTypes):len(eventTypes)], apisources.ApiServerSourceKubernetesEventResourceModeTypes...)
+		case v1.DiffMode:
+			eventTypes = append(eventTypes[:len(eventTypes):len(eventTypes)], apisources.ApiServerSourceKubernetesEventDiffModeTypes...)
+		}
+	}
+	if src.Spec.ResumeWatches {
+		eventTypes = append(eventTypes[:len(eventTypes):len(eventTypes)], apisources.ApiServerSourceSyncEventType)
+	}
 	ceAttributes := make([]duckv1.CloudEventAttributes, 0, len(eventTypes))
 	for _, apiServerSourceType := range eventTypes {
 		ceAttributes = append(ceAttributes, duckv1.CloudEventAttributes{
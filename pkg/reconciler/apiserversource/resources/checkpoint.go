@@ -0,0 +1,49 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "knative.dev/eventing/pkg/apis/sources/v1"
+	"knative.dev/pkg/kmeta"
+)
+
+// CheckpointConfigMapName returns the name of the ConfigMap where the receive
+// adapter of source persists the resource versions of its watches.
+func CheckpointConfigMapName(source *v1.ApiServerSource) string {
+	return kmeta.ChildName(fmt.Sprintf("apiserversource-%s-checkpoint-", source.Name), string(source.GetUID()))
+}
+
+// MakeCheckpointConfigMap generates (but does not insert into K8s) the
+// ConfigMap where the receive adapter of source persists the resource versions
+// of its watches. It is garbage collected with source.
+func MakeCheckpointConfigMap(source *v1.ApiServerSource) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: source.Namespace,
+			Name:      CheckpointConfigMapName(source),
+			Labels:    Labels(source.Name),
+			OwnerReferences: []metav1.OwnerReference{
+				*kmeta.NewControllerRef(source),
+			},
+		},
+	}
+}
@@ -114,6 +114,21 @@ func makeEnv(args *ReceiveAdapterArgs) ([]corev1.EnvVar, error) {
 		Resources:     make([]apiserver.ResourceWatch, 0, len(args.Source.Spec.Resources)),
 		ResourceOwner: args.Source.Spec.ResourceOwner,
 		EventMode:     args.Source.Spec.EventMode,
+		IgnoredFields: args.Source.Spec.IgnoredFields,
+		Filters:       args.Source.Spec.Filters,
+
+		KubernetesEvents: args.Source.Spec.KubernetesEvents,
+	}
+
+	if args.Source.Spec.ResumeWatches {
+		cfg.CheckpointConfigMap = CheckpointConfigMapName(args.Source)
+	}
+
+	if args.Source.Spec.NamespaceSelector != nil {
+		// An empty selector renders to "", which selects all the namespaces.
+		selector, _ := metav1.LabelSelectorAsSelector(args.Source.Spec.NamespaceSelector)
+		namespaceSelector := selector.String()
+		cfg.NamespaceSelector = &namespaceSelector
 	}
 
 	for _, r := range args.Source.Spec.Resources {
@@ -123,7 +138,7 @@ func makeEnv(args *ReceiveAdapterArgs) ([]corev1.EnvVar, error) {
 		}
 		gvr, _ := meta.UnsafeGuessKindToResource(gv.WithKind(r.Kind))
 
-		rw := apiserver.ResourceWatch{GVR: gvr}
+		rw := apiserver.ResourceWatch{GVR: gvr, FieldSelector: r.FieldSelector}
 
 		if r.LabelSelector != nil {
 			selector, _ := metav1.LabelSelectorAsSelector(r.LabelSelector)
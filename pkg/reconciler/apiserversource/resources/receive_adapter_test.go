@@ -178,6 +178,25 @@ func TestMakeReceiveAdapters(t *testing.T) {
 		Value: `{"extensions":{"1":"one"}}`,
 	})
 
+	selectorSrc := src.DeepCopy()
+	selectorSrc.Spec.Resources[2].FieldSelector = "status.phase=Failed"
+	selectorSrc.Spec.Filters = []string{`object.metadata.name != "foo"`}
+	selectorSrc.Spec.NamespaceSelector = &metav1.LabelSelector{
+		MatchLabels: map[string]string{"team": "payments"},
+	}
+	selectorWant := want.DeepCopy()
+	selectorWant.Spec.Template.Spec.Containers[0].Env[1].Value = `{"namespace":"source-namespace","namespaceSelector":"team=payments","resources":[{"gvr":{"Group":"","Version":"","Resource":"namespaces"}},{"gvr":{"Group":"batch","Version":"v1","Resource":"jobs"}},{"gvr":{"Group":"","Version":"","Resource":"pods"},"selector":"test-key1=test-value1","fieldSelector":"status.phase=Failed"}],"owner":{"apiVersion":"custom/v1","kind":"Parent"},"mode":"Resource","filters":["object.metadata.name != \"foo\""]}`
+
+	allNamespacesSrc := src.DeepCopy()
+	allNamespacesSrc.Spec.NamespaceSelector = &metav1.LabelSelector{}
+	allNamespacesWant := want.DeepCopy()
+	allNamespacesWant.Spec.Template.Spec.Containers[0].Env[1].Value = `{"namespace":"source-namespace","namespaceSelector":"","resources":[{"gvr":{"Group":"","Version":"","Resource":"namespaces"}},{"gvr":{"Group":"batch","Version":"v1","Resource":"jobs"}},{"gvr":{"Group":"","Version":"","Resource":"pods"},"selector":"test-key1=test-value1"}],"owner":{"apiVersion":"custom/v1","kind":"Parent"},"mode":"Resource"}`
+
+	resumeSrc := src.DeepCopy()
+	resumeSrc.Spec.ResumeWatches = true
+	resumeWant := want.DeepCopy()
+	resumeWant.Spec.Template.Spec.Containers[0].Env[1].Value = `{"namespace":"source-namespace","resources":[{"gvr":{"Group":"","Version":"","Resource":"namespaces"}},{"gvr":{"Group":"batch","Version":"v1","Resource":"jobs"}},{"gvr":{"Group":"","Version":"","Resource":"pods"},"selector":"test-key1=test-value1"}],"owner":{"apiVersion":"custom/v1","kind":"Parent"},"mode":"Resource","checkpoint":"` + CheckpointConfigMapName(src) + `"}`
+
 	testCases := map[string]struct {
 		want *appsv1.Deployment
 		src  *v1.ApiServerSource
@@ -189,6 +208,15 @@ func TestMakeReceiveAdapters(t *testing.T) {
 		}, "TestMakeReceiveAdapterWithExtensionOverride": {
 			src:  ceSrc,
 			want: ceWant,
+		}, "TestMakeReceiveAdapterWithSelectors": {
+			src:  selectorSrc,
+			want: selectorWant,
+		}, "TestMakeReceiveAdapterWithAllNamespaces": {
+			src:  allNamespacesSrc,
+			want: allNamespacesWant,
+		}, "TestMakeReceiveAdapterWithResumedWatches": {
+			src:  resumeSrc,
+			want: resumeWant,
 		},
 	}
 	for n, tc := range testCases {
@@ -31,6 +31,7 @@ import (
 
 	"knative.dev/eventing/pkg/apis/eventing"
 	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/eventing/pkg/apis/feature"
 	messagingv1 "knative.dev/eventing/pkg/apis/messaging/v1"
 	clientset "knative.dev/eventing/pkg/client/clientset/versioned"
 	eventinglisters "knative.dev/eventing/pkg/client/listers/eventing/v1"
@@ -185,8 +186,12 @@ func (r *Reconciler) resolveDeadLetterSink(ctx context.Context, b *eventingv1.Br
 // subscribeToBrokerChannel subscribes service 'svc' to the Broker's channels.
 func (r *Reconciler) subscribeToBrokerChannel(ctx context.Context, b *eventingv1.Broker, t *eventingv1.Trigger, brokerTrigger *corev1.ObjectReference) (*messagingv1.Subscription, error) {
 	recorder := controller.GetEventRecorder(ctx)
+	scheme := "http"
+	if feature.FromContext(ctx).IsEnabled(feature.TransportEncryption) {
+		scheme = "https"
+	}
 	uri := &apis.URL{
-		Scheme: "http",
+		Scheme: scheme,
 		Host:   network.GetServiceHostname("broker-filter", system.Namespace()),
 		Path:   path.Generate(t),
 	}
@@ -204,6 +209,12 @@ func (r *Reconciler) subscribeToBrokerChannel(ctx context.Context, b *eventingv1
 	if delivery == nil {
 		delivery = b.Spec.Delivery
 	}
+	if delivery != nil && delivery.Auth != nil {
+		// The filter attaches the credentials when delivering to the subscriber, the channel
+		// delivers to the filter which doesn't need them.
+		delivery = delivery.DeepCopy()
+		delivery.Auth = nil
+	}
 
 	expected := resources.NewSubscription(t, brokerTrigger, brokerObjRef, uri, delivery)
 
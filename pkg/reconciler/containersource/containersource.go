@@ -28,6 +28,9 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	appsv1listers "k8s.io/client-go/listers/apps/v1"
+	autoscalingv2beta2listers "k8s.io/client-go/listers/autoscaling/v2beta2"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	policyv1beta1listers "k8s.io/client-go/listers/policy/v1beta1"
 	v1 "knative.dev/eventing/pkg/apis/sources/v1"
 	clientset "knative.dev/eventing/pkg/client/clientset/versioned"
 	"knative.dev/eventing/pkg/client/injection/reconciler/sources/v1/containersource"
@@ -45,6 +48,12 @@ const (
 	deploymentUpdated  = "ContainerSourceDeploymentUpdated"
 	sinkBindingCreated = "ContainerSourceSinkBindingCreated"
 	sinkBindingUpdated = "ContainerSourceSinkBindingUpdated"
+	autoscalerCreated  = "ContainerSourceHorizontalPodAutoscalerCreated"
+	autoscalerUpdated  = "ContainerSourceHorizontalPodAutoscalerUpdated"
+	autoscalerDeleted  = "ContainerSourceHorizontalPodAutoscalerDeleted"
+	pdbCreated         = "ContainerSourcePodDisruptionBudgetCreated"
+	pdbUpdated         = "ContainerSourcePodDisruptionBudgetUpdated"
+	pdbDeleted         = "ContainerSourcePodDisruptionBudgetDeleted"
 )
 
 // newReconciledNormal makes a new reconciler event with event type Normal, and
@@ -59,9 +68,12 @@ type Reconciler struct {
 	eventingClientSet clientset.Interface
 
 	// listers index properties about resources
-	containerSourceLister listers.ContainerSourceLister
-	sinkBindingLister     listers.SinkBindingLister
-	deploymentLister      appsv1listers.DeploymentLister
+	containerSourceLister  listers.ContainerSourceLister
+	sinkBindingLister      listers.SinkBindingLister
+	deploymentLister       appsv1listers.DeploymentLister
+	podLister              corev1listers.PodLister
+	autoscalerLister       autoscalingv2beta2listers.HorizontalPodAutoscalerLister
+	disruptionBudgetLister policyv1beta1listers.PodDisruptionBudgetLister
 }
 
 // Check that our Reconciler implements Interface
@@ -81,6 +93,21 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, source *v1.ContainerSour
 		return err
 	}
 
+	if err := r.reconcilePods(ctx, source); err != nil {
+		logging.FromContext(ctx).Errorw("Error reconciling pods", zap.Error(err))
+		return err
+	}
+
+	if err := r.reconcileAutoscaler(ctx, source); err != nil {
+		logging.FromContext(ctx).Errorw("Error reconciling HorizontalPodAutoscaler", zap.Error(err))
+		return err
+	}
+
+	if err := r.reconcileDisruptionBudget(ctx, source); err != nil {
+		logging.FromContext(ctx).Errorw("Error reconciling PodDisruptionBudget", zap.Error(err))
+		return err
+	}
+
 	return newReconciledNormal(source.Namespace, source.Name)
 }
 
@@ -144,6 +171,82 @@ func (r *Reconciler) reconcileSinkBinding(ctx context.Context, source *v1.Contai
 	return sb, nil
 }
 
+// reconcileAutoscaler makes the ContainerSource's HorizontalPodAutoscaler match
+// its scaling spec, deleting it once the source no longer autoscales.
+func (r *Reconciler) reconcileAutoscaler(ctx context.Context, source *v1.ContainerSource) error {
+	expected := resources.MakeHorizontalPodAutoscaler(source)
+	name := resources.HorizontalPodAutoscalerName(source)
+	hpas := r.kubeClientSet.AutoscalingV2beta2().HorizontalPodAutoscalers(source.Namespace)
+
+	hpa, err := r.autoscalerLister.HorizontalPodAutoscalers(source.Namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		if expected == nil {
+			return nil
+		}
+		hpa, err = hpas.Create(ctx, expected, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("creating new HorizontalPodAutoscaler: %v", err)
+		}
+		controller.GetEventRecorder(ctx).Eventf(source, corev1.EventTypeNormal, autoscalerCreated, "HorizontalPodAutoscaler created %q", hpa.Name)
+	} else if err != nil {
+		return fmt.Errorf("getting HorizontalPodAutoscaler: %v", err)
+	} else if !metav1.IsControlledBy(hpa, source) {
+		return fmt.Errorf("HorizontalPodAutoscaler %q is not owned by ContainerSource %q", hpa.Name, source.Name)
+	} else if expected == nil {
+		if err := hpas.Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("deleting HorizontalPodAutoscaler: %v", err)
+		}
+		controller.GetEventRecorder(ctx).Eventf(source, corev1.EventTypeNormal, autoscalerDeleted, "HorizontalPodAutoscaler deleted %q", name)
+	} else if !equality.Semantic.DeepDerivative(expected.Spec, hpa.Spec) {
+		hpa = hpa.DeepCopy()
+		hpa.Spec = expected.Spec
+		hpa, err = hpas.Update(ctx, hpa, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("updating HorizontalPodAutoscaler: %v", err)
+		}
+		controller.GetEventRecorder(ctx).Eventf(source, corev1.EventTypeNormal, autoscalerUpdated, "HorizontalPodAutoscaler updated %q", hpa.Name)
+	}
+	return nil
+}
+
+// reconcileDisruptionBudget makes the ContainerSource's PodDisruptionBudget
+// match its scaling spec, deleting it once minAvailable is unset.
+func (r *Reconciler) reconcileDisruptionBudget(ctx context.Context, source *v1.ContainerSource) error {
+	expected := resources.MakePodDisruptionBudget(source)
+	name := resources.PodDisruptionBudgetName(source)
+	pdbs := r.kubeClientSet.PolicyV1beta1().PodDisruptionBudgets(source.Namespace)
+
+	pdb, err := r.disruptionBudgetLister.PodDisruptionBudgets(source.Namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		if expected == nil {
+			return nil
+		}
+		pdb, err = pdbs.Create(ctx, expected, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("creating new PodDisruptionBudget: %v", err)
+		}
+		controller.GetEventRecorder(ctx).Eventf(source, corev1.EventTypeNormal, pdbCreated, "PodDisruptionBudget created %q", pdb.Name)
+	} else if err != nil {
+		return fmt.Errorf("getting PodDisruptionBudget: %v", err)
+	} else if !metav1.IsControlledBy(pdb, source) {
+		return fmt.Errorf("PodDisruptionBudget %q is not owned by ContainerSource %q", pdb.Name, source.Name)
+	} else if expected == nil {
+		if err := pdbs.Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("deleting PodDisruptionBudget: %v", err)
+		}
+		controller.GetEventRecorder(ctx).Eventf(source, corev1.EventTypeNormal, pdbDeleted, "PodDisruptionBudget deleted %q", name)
+	} else if !equality.Semantic.DeepDerivative(expected.Spec, pdb.Spec) {
+		pdb = pdb.DeepCopy()
+		pdb.Spec = expected.Spec
+		pdb, err = pdbs.Update(ctx, pdb, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("updating PodDisruptionBudget: %v", err)
+		}
+		controller.GetEventRecorder(ctx).Eventf(source, corev1.EventTypeNormal, pdbUpdated, "PodDisruptionBudget updated %q", pdb.Name)
+	}
+	return nil
+}
+
 func (r *Reconciler) podSpecChanged(have *corev1.PodSpec, want *corev1.PodSpec) bool {
 	// TODO this won't work, SinkBinding messes with this. n3wscott working on a fix.
 	return !equality.Semantic.DeepDerivative(want, have)
@@ -25,14 +25,18 @@ import (
 	"knative.dev/pkg/tracker"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	clientgotesting "k8s.io/client-go/testing"
 	fakeeventingclient "knative.dev/eventing/pkg/client/injection/client/fake"
 	"knative.dev/pkg/apis"
 	fakekubeclient "knative.dev/pkg/client/injection/kube/client/fake"
 	"knative.dev/pkg/logging"
+	"knative.dev/pkg/ptr"
 
 	sourcesv1 "knative.dev/eventing/pkg/apis/sources/v1"
 	"knative.dev/eventing/pkg/client/injection/reconciler/sources/v1/containersource"
@@ -63,8 +67,59 @@ var (
 
 	deploymentName  = fmt.Sprintf("%s-deployment", sourceName)
 	sinkBindingName = fmt.Sprintf("%s-sinkbinding", sourceName)
+	autoscalerName  = fmt.Sprintf("%s-hpa", sourceName)
+	pdbName         = fmt.Sprintf("%s-pdb", sourceName)
 
-	conditionTrue = corev1.ConditionTrue
+	conditionTrue  = corev1.ConditionTrue
+	conditionFalse = corev1.ConditionFalse
+
+	crashLoopingPod = &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sourceName + "-pod",
+			Namespace: testNS,
+			Labels:    resources.Labels(sourceName),
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "source",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff", Message: "back-off 40s"}},
+			}},
+		},
+	}
+
+	runningPod = &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sourceName + "-pod",
+			Namespace: testNS,
+			Labels:    resources.Labels(sourceName),
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			PodIP: "10.0.0.1",
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "source",
+				State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+				Ready: true,
+			}},
+		},
+	}
+
+	unreadyPod = &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sourceName + "-pod",
+			Namespace: testNS,
+			Labels:    resources.Labels(sourceName),
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			PodIP: "10.0.0.1",
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "source",
+				State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			}},
+		},
+	}
 
 	sinkDest = duckv1.Destination{
 		Ref: &duckv1.KReference{
@@ -181,6 +236,7 @@ func TestAllCases(t *testing.T) {
 						WithContainerSourceSpec(makeContainerSourceSpec(sinkDest)),
 						WithContainerSourceUID(sourceUID),
 					), nil)),
+					WithContainerSourcePropagatePodStatuses(),
 				),
 			}},
 			WantCreates: []runtime.Object{
@@ -226,6 +282,218 @@ func TestAllCases(t *testing.T) {
 						WithContainerSourceSpec(makeContainerSourceSpec(sinkDest)),
 						WithContainerSourceUID(sourceUID),
 					), &conditionTrue)),
+					WithContainerSourcePropagatePodStatuses(),
+				),
+			}},
+		}, {
+			Name: "scaling creates autoscaler and disruption budget",
+			Objects: []runtime.Object{
+				NewContainerSource(sourceName, testNS,
+					WithContainerSourceUID(sourceUID),
+					WithContainerSourceSpec(makeScaledContainerSourceSpec(sinkDest)),
+					WithContainerSourceObjectMetaGeneration(generation),
+				),
+				makeSinkBinding(NewContainerSource(sourceName, testNS,
+					WithContainerSourceSpec(makeScaledContainerSourceSpec(sinkDest)),
+					WithContainerSourceUID(sourceUID),
+				), &conditionTrue),
+				makeDeployment(NewContainerSource(sourceName, testNS,
+					WithContainerSourceSpec(makeScaledContainerSourceSpec(sinkDest)),
+					WithContainerSourceUID(sourceUID),
+				), &conditionTrue),
+			},
+			Key: testNS + "/" + sourceName,
+			WantEvents: []string{
+				Eventf(corev1.EventTypeNormal, autoscalerCreated, "HorizontalPodAutoscaler created %q", autoscalerName),
+				Eventf(corev1.EventTypeNormal, pdbCreated, "PodDisruptionBudget created %q", pdbName),
+				Eventf(corev1.EventTypeNormal, sourceReconciled, `ContainerSource reconciled: "%s/%s"`, testNS, sourceName),
+			},
+			WantCreates: []runtime.Object{
+				resources.MakeHorizontalPodAutoscaler(NewContainerSource(sourceName, testNS,
+					WithContainerSourceSpec(makeScaledContainerSourceSpec(sinkDest)),
+					WithContainerSourceUID(sourceUID),
+				)),
+				resources.MakePodDisruptionBudget(NewContainerSource(sourceName, testNS,
+					WithContainerSourceSpec(makeScaledContainerSourceSpec(sinkDest)),
+					WithContainerSourceUID(sourceUID),
+				)),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewContainerSource(sourceName, testNS,
+					WithContainerSourceUID(sourceUID),
+					WithContainerSourceSpec(makeScaledContainerSourceSpec(sinkDest)),
+					WithContainerSourceObjectMetaGeneration(generation),
+					WithInitContainerSourceConditions,
+					WithContainerSourceStatusObservedGeneration(generation),
+					WithContainerSourcePropagateSinkbindingStatus(makeSinkBindingStatus(&conditionTrue)),
+					WithContainerSourcePropagateReceiveAdapterStatus(makeDeployment(NewContainerSource(sourceName, testNS,
+						WithContainerSourceSpec(makeScaledContainerSourceSpec(sinkDest)),
+						WithContainerSourceUID(sourceUID),
+					), &conditionTrue)),
+					WithContainerSourcePropagatePodStatuses(),
+				),
+			}},
+		}, {
+			Name: "removing scaling deletes autoscaler and disruption budget",
+			Objects: []runtime.Object{
+				NewContainerSource(sourceName, testNS,
+					WithContainerSourceUID(sourceUID),
+					WithContainerSourceSpec(makeContainerSourceSpec(sinkDest)),
+					WithContainerSourceObjectMetaGeneration(generation),
+				),
+				makeSinkBinding(NewContainerSource(sourceName, testNS,
+					WithContainerSourceSpec(makeContainerSourceSpec(sinkDest)),
+					WithContainerSourceUID(sourceUID),
+				), &conditionTrue),
+				makeDeployment(NewContainerSource(sourceName, testNS,
+					WithContainerSourceSpec(makeContainerSourceSpec(sinkDest)),
+					WithContainerSourceUID(sourceUID),
+				), &conditionTrue),
+				resources.MakeHorizontalPodAutoscaler(NewContainerSource(sourceName, testNS,
+					WithContainerSourceSpec(makeScaledContainerSourceSpec(sinkDest)),
+					WithContainerSourceUID(sourceUID),
+				)),
+				resources.MakePodDisruptionBudget(NewContainerSource(sourceName, testNS,
+					WithContainerSourceSpec(makeScaledContainerSourceSpec(sinkDest)),
+					WithContainerSourceUID(sourceUID),
+				)),
+			},
+			Key: testNS + "/" + sourceName,
+			WantEvents: []string{
+				Eventf(corev1.EventTypeNormal, autoscalerDeleted, "HorizontalPodAutoscaler deleted %q", autoscalerName),
+				Eventf(corev1.EventTypeNormal, pdbDeleted, "PodDisruptionBudget deleted %q", pdbName),
+				Eventf(corev1.EventTypeNormal, sourceReconciled, `ContainerSource reconciled: "%s/%s"`, testNS, sourceName),
+			},
+			WantDeletes: []clientgotesting.DeleteActionImpl{{
+				ActionImpl: clientgotesting.ActionImpl{
+					Namespace: testNS,
+					Verb:      "delete",
+					Resource:  autoscalingv2beta2.SchemeGroupVersion.WithResource("horizontalpodautoscalers"),
+				},
+				Name: autoscalerName,
+			}, {
+				ActionImpl: clientgotesting.ActionImpl{
+					Namespace: testNS,
+					Verb:      "delete",
+					Resource:  policyv1beta1.SchemeGroupVersion.WithResource("poddisruptionbudgets"),
+				},
+				Name: pdbName,
+			}},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewContainerSource(sourceName, testNS,
+					WithContainerSourceUID(sourceUID),
+					WithContainerSourceSpec(makeContainerSourceSpec(sinkDest)),
+					WithContainerSourceObjectMetaGeneration(generation),
+					WithInitContainerSourceConditions,
+					WithContainerSourceStatusObservedGeneration(generation),
+					WithContainerSourcePropagateSinkbindingStatus(makeSinkBindingStatus(&conditionTrue)),
+					WithContainerSourcePropagateReceiveAdapterStatus(makeDeployment(NewContainerSource(sourceName, testNS,
+						WithContainerSourceSpec(makeContainerSourceSpec(sinkDest)),
+						WithContainerSourceUID(sourceUID),
+					), &conditionTrue)),
+					WithContainerSourcePropagatePodStatuses(),
+				),
+			}},
+		}, {
+			Name: "crash looping pod",
+			Objects: []runtime.Object{
+				NewContainerSource(sourceName, testNS,
+					WithContainerSourceUID(sourceUID),
+					WithContainerSourceSpec(makeContainerSourceSpec(sinkDest)),
+					WithContainerSourceObjectMetaGeneration(generation),
+				),
+				makeSinkBinding(NewContainerSource(sourceName, testNS,
+					WithContainerSourceSpec(makeContainerSourceSpec(sinkDest)),
+					WithContainerSourceUID(sourceUID),
+				), &conditionTrue),
+				makeDeployment(NewContainerSource(sourceName, testNS,
+					WithContainerSourceSpec(makeContainerSourceSpec(sinkDest)),
+					WithContainerSourceUID(sourceUID),
+				), &conditionFalse),
+				crashLoopingPod,
+			},
+			Key: testNS + "/" + sourceName,
+			WantEvents: []string{
+				Eventf(corev1.EventTypeNormal, sourceReconciled, `ContainerSource reconciled: "%s/%s"`, testNS, sourceName),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewContainerSource(sourceName, testNS,
+					WithContainerSourceUID(sourceUID),
+					WithContainerSourceSpec(makeContainerSourceSpec(sinkDest)),
+					WithContainerSourceObjectMetaGeneration(generation),
+					WithInitContainerSourceConditions,
+					WithContainerSourceStatusObservedGeneration(generation),
+					WithContainerSourcePropagateSinkbindingStatus(makeSinkBindingStatus(&conditionTrue)),
+					WithContainerSourcePropagateReceiveAdapterStatus(makeDeployment(NewContainerSource(sourceName, testNS,
+						WithContainerSourceSpec(makeContainerSourceSpec(sinkDest)),
+						WithContainerSourceUID(sourceUID),
+					), &conditionFalse)),
+					WithContainerSourcePropagatePodStatuses(crashLoopingPod),
+				),
+			}},
+		}, {
+			Name: "health endpoint reports the sink reachable",
+			Objects: []runtime.Object{
+				NewContainerSource(sourceName, testNS,
+					WithContainerSourceUID(sourceUID),
+					WithContainerSourceSpec(makeHealthContainerSourceSpec(sinkDest)),
+					WithContainerSourceObjectMetaGeneration(generation),
+				),
+				makeSinkBinding(NewContainerSource(sourceName, testNS,
+					WithContainerSourceSpec(makeHealthContainerSourceSpec(sinkDest)),
+					WithContainerSourceUID(sourceUID),
+				), &conditionTrue),
+				makeHealthDeployment(),
+				runningPod,
+			},
+			Key: testNS + "/" + sourceName,
+			WantEvents: []string{
+				Eventf(corev1.EventTypeNormal, sourceReconciled, `ContainerSource reconciled: "%s/%s"`, testNS, sourceName),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewContainerSource(sourceName, testNS,
+					WithContainerSourceUID(sourceUID),
+					WithContainerSourceSpec(makeHealthContainerSourceSpec(sinkDest)),
+					WithContainerSourceObjectMetaGeneration(generation),
+					WithInitContainerSourceConditions,
+					WithContainerSourceStatusObservedGeneration(generation),
+					WithContainerSourcePropagateSinkbindingStatus(makeSinkBindingStatus(&conditionTrue)),
+					WithContainerSourcePropagateReceiveAdapterStatus(makeHealthDeployment()),
+					WithContainerSourcePropagatePodStatuses(runningPod),
+					WithContainerSourceEventsSent,
+				),
+			}},
+		}, {
+			Name: "health endpoint reports the sink unreachable",
+			Objects: []runtime.Object{
+				NewContainerSource(sourceName, testNS,
+					WithContainerSourceUID(sourceUID),
+					WithContainerSourceSpec(makeHealthContainerSourceSpec(sinkDest)),
+					WithContainerSourceObjectMetaGeneration(generation),
+				),
+				makeSinkBinding(NewContainerSource(sourceName, testNS,
+					WithContainerSourceSpec(makeHealthContainerSourceSpec(sinkDest)),
+					WithContainerSourceUID(sourceUID),
+				), &conditionTrue),
+				makeHealthDeployment(),
+				unreadyPod,
+			},
+			Key: testNS + "/" + sourceName,
+			WantEvents: []string{
+				Eventf(corev1.EventTypeNormal, sourceReconciled, `ContainerSource reconciled: "%s/%s"`, testNS, sourceName),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewContainerSource(sourceName, testNS,
+					WithContainerSourceUID(sourceUID),
+					WithContainerSourceSpec(makeHealthContainerSourceSpec(sinkDest)),
+					WithContainerSourceObjectMetaGeneration(generation),
+					WithInitContainerSourceConditions,
+					WithContainerSourceStatusObservedGeneration(generation),
+					WithContainerSourcePropagateSinkbindingStatus(makeSinkBindingStatus(&conditionTrue)),
+					WithContainerSourcePropagateReceiveAdapterStatus(makeHealthDeployment()),
+					WithContainerSourcePropagatePodStatuses(unreadyPod),
+					WithContainerSourceEventsNotSent("SinkUnreachable",
+						"Container %q in pod %q is not ready: its health endpoint reports the sink unreachable.", "source", unreadyPod.Name),
 				),
 			}},
 		},
@@ -235,11 +503,14 @@ func TestAllCases(t *testing.T) {
 	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher) controller.Reconciler {
 		ctx = addressable.WithDuck(ctx)
 		r := &Reconciler{
-			kubeClientSet:         fakekubeclient.Get(ctx),
-			eventingClientSet:     fakeeventingclient.Get(ctx),
-			containerSourceLister: listers.GetContainerSourceLister(),
-			deploymentLister:      listers.GetDeploymentLister(),
-			sinkBindingLister:     listers.GetSinkBindingLister(),
+			kubeClientSet:          fakekubeclient.Get(ctx),
+			eventingClientSet:      fakeeventingclient.Get(ctx),
+			containerSourceLister:  listers.GetContainerSourceLister(),
+			deploymentLister:       listers.GetDeploymentLister(),
+			sinkBindingLister:      listers.GetSinkBindingLister(),
+			podLister:              listers.GetPodLister(),
+			autoscalerLister:       listers.GetHorizontalPodAutoscalerLister(),
+			disruptionBudgetLister: listers.GetPodDisruptionBudgetLister(),
 		}
 		return containersource.NewReconciler(ctx, logging.FromContext(ctx), fakeeventingclient.Get(ctx), listers.GetContainerSourceLister(), controller.GetEventRecorder(ctx), r)
 	},
@@ -349,6 +620,37 @@ func makeContainerSourceSpec(sink duckv1.Destination) sourcesv1.ContainerSourceS
 	}
 }
 
+func makeScaledContainerSourceSpec(sink duckv1.Destination) sourcesv1.ContainerSourceSpec {
+	spec := makeContainerSourceSpec(sink)
+	minAvailable := intstr.FromInt(1)
+	spec.Scaling = &sourcesv1.ContainerSourceScaling{
+		MaxReplicas:  ptr.Int32(3),
+		MinAvailable: &minAvailable,
+	}
+	return spec
+}
+
+func makeHealthContainerSourceSpec(sink duckv1.Destination) sourcesv1.ContainerSourceSpec {
+	spec := makeContainerSourceSpec(sink)
+	spec.Template.Spec.Containers[0].Ports = []corev1.ContainerPort{{
+		Name:          resources.HealthPortName,
+		ContainerPort: 8081,
+	}}
+	return spec
+}
+
+func makeHealthDeployment() *appsv1.Deployment {
+	d := resources.MakeDeployment(NewContainerSource(sourceName, testNS,
+		WithContainerSourceSpec(makeHealthContainerSourceSpec(sinkDest)),
+		WithContainerSourceUID(sourceUID),
+	))
+	d.Status.Conditions = []appsv1.DeploymentCondition{{
+		Type:   appsv1.DeploymentAvailable,
+		Status: corev1.ConditionTrue,
+	}}
+	return d
+}
+
 func makeSinkBindingStatus(ready *corev1.ConditionStatus) *sourcesv1.SinkBindingStatus {
 	return &sourcesv1.SinkBindingStatus{
 		SourceStatus: duckv1.SourceStatus{
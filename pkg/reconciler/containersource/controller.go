@@ -25,8 +25,12 @@ import (
 	containersourceinformer "knative.dev/eventing/pkg/client/injection/informers/sources/v1/containersource"
 	sinkbindinginformer "knative.dev/eventing/pkg/client/injection/informers/sources/v1/sinkbinding"
 	v1containersource "knative.dev/eventing/pkg/client/injection/reconciler/sources/v1/containersource"
+	"knative.dev/eventing/pkg/reconciler/containersource/resources"
 	kubeclient "knative.dev/pkg/client/injection/kube/client"
 	deploymentinformer "knative.dev/pkg/client/injection/kube/informers/apps/v1/deployment"
+	hpainformer "knative.dev/pkg/client/injection/kube/informers/autoscaling/v2beta2/horizontalpodautoscaler"
+	filteredpodinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/pod/filtered"
+	pdbinformer "knative.dev/pkg/client/injection/kube/informers/policy/v1beta1/poddisruptionbudget"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 )
@@ -42,23 +46,42 @@ func NewController(
 	containersourceInformer := containersourceinformer.Get(ctx)
 	sinkbindingInformer := sinkbindinginformer.Get(ctx)
 	deploymentInformer := deploymentinformer.Get(ctx)
+	podInformer := filteredpodinformer.Get(ctx, resources.Selector)
+	hpaInformer := hpainformer.Get(ctx)
+	pdbInformer := pdbinformer.Get(ctx)
 
 	r := &Reconciler{
-		kubeClientSet:         kubeClient,
-		eventingClientSet:     eventingClient,
-		containerSourceLister: containersourceInformer.Lister(),
-		deploymentLister:      deploymentInformer.Lister(),
-		sinkBindingLister:     sinkbindingInformer.Lister(),
+		kubeClientSet:          kubeClient,
+		eventingClientSet:      eventingClient,
+		containerSourceLister:  containersourceInformer.Lister(),
+		deploymentLister:       deploymentInformer.Lister(),
+		sinkBindingLister:      sinkbindingInformer.Lister(),
+		podLister:              podInformer.Lister(),
+		autoscalerLister:       hpaInformer.Lister(),
+		disruptionBudgetLister: pdbInformer.Lister(),
 	}
 	impl := v1containersource.NewImpl(ctx, r)
 
 	containersourceInformer.Informer().AddEventHandler(controller.HandleAll(impl.Enqueue))
 
+	podInformer.Informer().AddEventHandler(controller.HandleAll(
+		impl.EnqueueLabelOfNamespaceScopedResource("", resources.NameLabelKey)))
+
 	deploymentInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
 		FilterFunc: controller.FilterControllerGVK(v1.SchemeGroupVersion.WithKind("ContainerSource")),
 		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
 	})
 
+	hpaInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.FilterControllerGVK(v1.SchemeGroupVersion.WithKind("ContainerSource")),
+		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
+	})
+
+	pdbInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.FilterControllerGVK(v1.SchemeGroupVersion.WithKind("ContainerSource")),
+		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
+	})
+
 	sinkbindingInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
 		FilterFunc: controller.FilterController(&v1.ContainerSource{}),
 		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
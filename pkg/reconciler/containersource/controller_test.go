@@ -17,20 +17,30 @@ limitations under the License.
 package containersource
 
 import (
+	"context"
 	"testing"
 
+	filteredFactory "knative.dev/pkg/client/injection/kube/informers/factory/filtered"
 	"knative.dev/pkg/configmap"
 	. "knative.dev/pkg/reconciler/testing"
 
+	"knative.dev/eventing/pkg/reconciler/containersource/resources"
+
 	// Fake injection informers
 	_ "knative.dev/eventing/pkg/client/injection/informers/sources/v1/containersource/fake"
 	_ "knative.dev/eventing/pkg/client/injection/informers/sources/v1/sinkbinding/fake"
 	_ "knative.dev/pkg/client/injection/kube/informers/apps/v1/deployment/fake"
+	_ "knative.dev/pkg/client/injection/kube/informers/autoscaling/v2beta2/horizontalpodautoscaler/fake"
+	_ "knative.dev/pkg/client/injection/kube/informers/core/v1/pod/filtered/fake"
+	_ "knative.dev/pkg/client/injection/kube/informers/factory/filtered/fake"
+	_ "knative.dev/pkg/client/injection/kube/informers/policy/v1beta1/poddisruptionbudget/fake"
 	_ "knative.dev/pkg/injection/clients/dynamicclient/fake"
 )
 
 func TestNew(t *testing.T) {
-	ctx, _ := SetupFakeContext(t)
+	ctx, _ := SetupFakeContext(t, func(ctx context.Context) context.Context {
+		return filteredFactory.WithSelectors(ctx, resources.Selector)
+	})
 
 	c := NewController(ctx, configmap.NewStaticWatcher())
 
@@ -0,0 +1,73 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containersource
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	v1 "knative.dev/eventing/pkg/apis/sources/v1"
+	"knative.dev/eventing/pkg/reconciler/containersource/resources"
+)
+
+// reconcilePods surfaces why the ContainerSource's pods are not running, and
+// whether they can reach their sink.
+func (r *Reconciler) reconcilePods(ctx context.Context, source *v1.ContainerSource) error {
+	pods, err := r.podLister.Pods(source.Namespace).List(labels.SelectorFromSet(resources.Labels(source.Name)))
+	if err != nil {
+		return fmt.Errorf("listing pods: %v", err)
+	}
+	source.Status.PropagatePodStatuses(pods)
+
+	container := resources.HealthContainerName(source)
+	if container == "" {
+		source.Status.ClearEventsSent()
+		return nil
+	}
+	propagateHealth(source, container, pods)
+	return nil
+}
+
+// propagateHealth aggregates the readiness of the container serving the health
+// endpoints in each running pod. Its readiness probe fails while the sink is
+// unreachable, so the sink is unreachable if any started container is not ready.
+func propagateHealth(source *v1.ContainerSource, container string, pods []*corev1.Pod) {
+	started := 0
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.Name != container || cs.State.Running == nil {
+				continue
+			}
+			if !cs.Ready {
+				source.Status.MarkEventsNotSent("SinkUnreachable",
+					"Container %q in pod %q is not ready: its health endpoint reports the sink unreachable.", container, pod.Name)
+				return
+			}
+			started++
+		}
+	}
+	if started == 0 {
+		source.Status.MarkEventsSentUnknown("NoRunningPods", "No pod is running to report its health.")
+		return
+	}
+	source.Status.MarkEventsSent()
+}
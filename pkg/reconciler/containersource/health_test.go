@@ -0,0 +1,99 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containersource
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v1 "knative.dev/eventing/pkg/apis/sources/v1"
+)
+
+func TestPropagateHealth(t *testing.T) {
+	pod := func(name string, phase corev1.PodPhase, statuses ...corev1.ContainerStatus) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status:     corev1.PodStatus{Phase: phase, ContainerStatuses: statuses},
+		}
+	}
+	running := corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	waiting := corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"}}
+
+	tests := []struct {
+		name        string
+		pods        []*corev1.Pod
+		wantStatus  corev1.ConditionStatus
+		wantReason  string
+		wantMessage string
+	}{{
+		name:       "no pods",
+		wantStatus: corev1.ConditionUnknown,
+		wantReason: "NoRunningPods",
+	}, {
+		name: "pending pod",
+		pods: []*corev1.Pod{
+			pod("a", corev1.PodPending, corev1.ContainerStatus{Name: "source", State: waiting}),
+		},
+		wantStatus: corev1.ConditionUnknown,
+		wantReason: "NoRunningPods",
+	}, {
+		name: "ready pods",
+		pods: []*corev1.Pod{
+			pod("a", corev1.PodRunning, corev1.ContainerStatus{Name: "source", State: running, Ready: true}),
+			pod("b", corev1.PodRunning, corev1.ContainerStatus{Name: "source", State: running, Ready: true}),
+		},
+		wantStatus: corev1.ConditionTrue,
+		wantReason: "SinkReachable",
+	}, {
+		name: "unready pod",
+		pods: []*corev1.Pod{
+			pod("a", corev1.PodRunning, corev1.ContainerStatus{Name: "source", State: running, Ready: true}),
+			pod("b", corev1.PodRunning, corev1.ContainerStatus{Name: "source", State: running}),
+		},
+		wantStatus:  corev1.ConditionFalse,
+		wantReason:  "SinkUnreachable",
+		wantMessage: `Container "source" in pod "b" is not ready: its health endpoint reports the sink unreachable.`,
+	}, {
+		name: "unready sidecar",
+		pods: []*corev1.Pod{
+			pod("a", corev1.PodRunning,
+				corev1.ContainerStatus{Name: "sidecar", State: running},
+				corev1.ContainerStatus{Name: "source", State: running, Ready: true}),
+		},
+		wantStatus: corev1.ConditionTrue,
+		wantReason: "SinkReachable",
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			source := &v1.ContainerSource{}
+			source.Status.InitializeConditions()
+			propagateHealth(source, "source", tc.pods)
+
+			got := source.Status.GetCondition(v1.ContainerSourceConditionEventsSent)
+			if got == nil {
+				t.Fatal("Expected the EventsSent condition to be set")
+			}
+			if got.Status != tc.wantStatus || got.Reason != tc.wantReason {
+				t.Errorf("Unexpected condition %+v, want status %s and reason %s", got, tc.wantStatus, tc.wantReason)
+			}
+			if tc.wantMessage != "" && got.Message != tc.wantMessage {
+				t.Errorf("Unexpected message, want %q, got %q", tc.wantMessage, got.Message)
+			}
+		})
+	}
+}
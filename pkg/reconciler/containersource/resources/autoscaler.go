@@ -0,0 +1,99 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v1 "knative.dev/eventing/pkg/apis/sources/v1"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/ptr"
+)
+
+// SendRateMetricName is the per-pod custom metric the autoscaler reads for
+// the sendRate metric type. The adapters only report event_count, a counter:
+// the rule in config/monitoring/metrics/prometheus-adapter makes the Prometheus
+// adapter serve its per-second rate under this name.
+const SendRateMetricName = "event_send_rate"
+
+// MakeHorizontalPodAutoscaler returns the HorizontalPodAutoscaler scaling the
+// ContainerSource's Deployment, or nil if the source does not autoscale.
+func MakeHorizontalPodAutoscaler(source *v1.ContainerSource) *autoscalingv2beta2.HorizontalPodAutoscaler {
+	scaling := source.Spec.Scaling
+	if scaling == nil || scaling.MaxReplicas == nil {
+		return nil
+	}
+
+	metric := scaling.Metric
+	if metric == nil {
+		metric = &v1.ContainerSourceScalingMetric{
+			Type:   v1.ContainerSourceScalingMetricCPU,
+			Target: v1.DefaultContainerSourceCPUTarget,
+		}
+	}
+
+	var spec autoscalingv2beta2.MetricSpec
+	switch metric.Type {
+	case v1.ContainerSourceScalingMetricSendRate:
+		spec = autoscalingv2beta2.MetricSpec{
+			Type: autoscalingv2beta2.PodsMetricSourceType,
+			Pods: &autoscalingv2beta2.PodsMetricSource{
+				Metric: autoscalingv2beta2.MetricIdentifier{
+					Name: SendRateMetricName,
+				},
+				Target: autoscalingv2beta2.MetricTarget{
+					Type:         autoscalingv2beta2.AverageValueMetricType,
+					AverageValue: resource.NewQuantity(int64(metric.Target), resource.DecimalSI),
+				},
+			},
+		}
+	default:
+		spec = autoscalingv2beta2.MetricSpec{
+			Type: autoscalingv2beta2.ResourceMetricSourceType,
+			Resource: &autoscalingv2beta2.ResourceMetricSource{
+				Name: corev1.ResourceCPU,
+				Target: autoscalingv2beta2.MetricTarget{
+					Type:               autoscalingv2beta2.UtilizationMetricType,
+					AverageUtilization: ptr.Int32(metric.Target),
+				},
+			},
+		}
+	}
+
+	return &autoscalingv2beta2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      HorizontalPodAutoscalerName(source),
+			Namespace: source.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*kmeta.NewControllerRef(source),
+			},
+			Labels: Labels(source.Name),
+		},
+		Spec: autoscalingv2beta2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2beta2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       DeploymentName(source),
+			},
+			MinReplicas: scaling.MinReplicas,
+			MaxReplicas: *scaling.MaxReplicas,
+			Metrics:     []autoscalingv2beta2.MetricSpec{spec},
+		},
+	}
+}
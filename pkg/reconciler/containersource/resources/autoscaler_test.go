@@ -0,0 +1,133 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v1 "knative.dev/eventing/pkg/apis/sources/v1"
+	"knative.dev/pkg/ptr"
+)
+
+func TestMakeHorizontalPodAutoscaler(t *testing.T) {
+	yes := true
+	tests := []struct {
+		name    string
+		scaling *v1.ContainerSourceScaling
+		want    []autoscalingv2beta2.MetricSpec
+	}{{
+		name: "no scaling",
+	}, {
+		name: "no max replicas",
+		scaling: &v1.ContainerSourceScaling{
+			MinReplicas: ptr.Int32(2),
+		},
+	}, {
+		name: "default cpu metric",
+		scaling: &v1.ContainerSourceScaling{
+			MinReplicas: ptr.Int32(2),
+			MaxReplicas: ptr.Int32(4),
+		},
+		want: []autoscalingv2beta2.MetricSpec{{
+			Type: autoscalingv2beta2.ResourceMetricSourceType,
+			Resource: &autoscalingv2beta2.ResourceMetricSource{
+				Name: corev1.ResourceCPU,
+				Target: autoscalingv2beta2.MetricTarget{
+					Type:               autoscalingv2beta2.UtilizationMetricType,
+					AverageUtilization: ptr.Int32(v1.DefaultContainerSourceCPUTarget),
+				},
+			},
+		}},
+	}, {
+		name: "send rate metric",
+		scaling: &v1.ContainerSourceScaling{
+			MinReplicas: ptr.Int32(2),
+			MaxReplicas: ptr.Int32(4),
+			Metric: &v1.ContainerSourceScalingMetric{
+				Type:   v1.ContainerSourceScalingMetricSendRate,
+				Target: 200,
+			},
+		},
+		want: []autoscalingv2beta2.MetricSpec{{
+			Type: autoscalingv2beta2.PodsMetricSourceType,
+			Pods: &autoscalingv2beta2.PodsMetricSource{
+				Metric: autoscalingv2beta2.MetricIdentifier{
+					Name: SendRateMetricName,
+				},
+				Target: autoscalingv2beta2.MetricTarget{
+					Type:         autoscalingv2beta2.AverageValueMetricType,
+					AverageValue: resource.NewQuantity(200, resource.DecimalSI),
+				},
+			},
+		}},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			source := &v1.ContainerSource{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-namespace", UID: uid},
+				Spec: v1.ContainerSourceSpec{
+					Scaling: test.scaling,
+				},
+			}
+			got := MakeHorizontalPodAutoscaler(source)
+			if test.want == nil {
+				if got != nil {
+					t.Fatal("Expected no HorizontalPodAutoscaler, got", got)
+				}
+				return
+			}
+
+			want := &autoscalingv2beta2.HorizontalPodAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name + "-hpa",
+					Namespace: "test-namespace",
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion:         "sources.knative.dev/v1",
+						Kind:               "ContainerSource",
+						Name:               name,
+						UID:                uid,
+						Controller:         &yes,
+						BlockOwnerDeletion: &yes,
+					}},
+					Labels: map[string]string{
+						"sources.knative.dev/containerSource": name,
+						"sources.knative.dev/source":          "container-source-controller",
+					},
+				},
+				Spec: autoscalingv2beta2.HorizontalPodAutoscalerSpec{
+					ScaleTargetRef: autoscalingv2beta2.CrossVersionObjectReference{
+						APIVersion: "apps/v1",
+						Kind:       "Deployment",
+						Name:       name + "-deployment",
+					},
+					MinReplicas: test.scaling.MinReplicas,
+					MaxReplicas: *test.scaling.MaxReplicas,
+					Metrics:     test.want,
+				},
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Error("unexpected HorizontalPodAutoscaler (-want, +got) =", diff)
+			}
+		})
+	}
+}
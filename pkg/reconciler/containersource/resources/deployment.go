@@ -32,6 +32,9 @@ func MakeDeployment(source *v1.ContainerSource) *appsv1.Deployment {
 	for k, v := range labels {
 		template.Labels[k] = v
 	}
+	if port := HealthPort(source); port != 0 {
+		template.Spec.Containers = withHealthContract(template.Spec.Containers, port)
+	}
 
 	deploy := &appsv1.Deployment{
 		TypeMeta: metav1.TypeMeta{
@@ -53,5 +56,10 @@ func MakeDeployment(source *v1.ContainerSource) *appsv1.Deployment {
 			Template: template,
 		},
 	}
+	if source.Spec.Scaling != nil && source.Spec.Scaling.MinReplicas != nil {
+		// The HorizontalPodAutoscaler owns the replica count afterwards, this
+		// only avoids starting below the lower bound.
+		deploy.Spec.Replicas = source.Spec.Scaling.MinReplicas
+	}
 	return deploy
 }
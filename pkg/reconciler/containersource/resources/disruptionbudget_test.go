@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	v1 "knative.dev/eventing/pkg/apis/sources/v1"
+	"knative.dev/pkg/ptr"
+)
+
+func TestMakePodDisruptionBudget(t *testing.T) {
+	source := &v1.ContainerSource{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-namespace", UID: uid},
+		Spec: v1.ContainerSourceSpec{
+			Scaling: &v1.ContainerSourceScaling{
+				MaxReplicas: ptr.Int32(3),
+			},
+		},
+	}
+	if got := MakePodDisruptionBudget(source); got != nil {
+		t.Fatal("Expected no PodDisruptionBudget without minAvailable, got", got)
+	}
+
+	minAvailable := intstr.FromString("50%")
+	source.Spec.Scaling.MinAvailable = &minAvailable
+
+	yes := true
+	labels := map[string]string{
+		"sources.knative.dev/containerSource": name,
+		"sources.knative.dev/source":          "container-source-controller",
+	}
+	want := &policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name + "-pdb",
+			Namespace: "test-namespace",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion:         "sources.knative.dev/v1",
+				Kind:               "ContainerSource",
+				Name:               name,
+				UID:                uid,
+				Controller:         &yes,
+				BlockOwnerDeletion: &yes,
+			}},
+			Labels: labels,
+		},
+		Spec: policyv1beta1.PodDisruptionBudgetSpec{
+			MinAvailable: &minAvailable,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+		},
+	}
+	if diff := cmp.Diff(want, MakePodDisruptionBudget(source)); diff != "" {
+		t.Error("unexpected PodDisruptionBudget (-want, +got) =", diff)
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	v1 "knative.dev/eventing/pkg/apis/sources/v1"
+
+	"knative.dev/eventing/pkg/adapter/v2/util/health"
+)
+
+const (
+	// HealthPortName is the name of the container port on which a ContainerSource
+	// built with the adapter SDK serves its health endpoints.
+	HealthPortName = "health"
+
+	// healthPortEnvVar tells the adapter SDK which port to serve the health endpoints on.
+	healthPortEnvVar = "K_HEALTH_PORT"
+)
+
+// HealthPort returns the port of the first container declaring a HealthPortName
+// port, or 0 if the ContainerSource does not expose its health.
+func HealthPort(source *v1.ContainerSource) int32 {
+	for _, c := range source.Spec.Template.Spec.Containers {
+		for _, p := range c.Ports {
+			if p.Name == HealthPortName {
+				return p.ContainerPort
+			}
+		}
+	}
+	return 0
+}
+
+// HealthContainerName returns the name of the first container declaring a
+// HealthPortName port, or "" if the ContainerSource does not expose its health.
+func HealthContainerName(source *v1.ContainerSource) string {
+	for i := range source.Spec.Template.Spec.Containers {
+		if c := &source.Spec.Template.Spec.Containers[i]; hasPort(c, HealthPortName) {
+			return c.Name
+		}
+	}
+	return ""
+}
+
+// withHealthContract makes the container declaring the health port serve the
+// adapter SDK health endpoints, and probes its liveness and its readiness unless
+// they already are. The readiness probe fails while the sink is unreachable, so
+// that the pod conditions tell whether the adapter can send events.
+func withHealthContract(containers []corev1.Container, port int32) []corev1.Container {
+	out := make([]corev1.Container, len(containers))
+	copy(out, containers)
+	for i := range out {
+		c := &out[i]
+		if !hasPort(c, HealthPortName) {
+			continue
+		}
+		if !hasEnv(c, healthPortEnvVar) {
+			c.Env = append(append([]corev1.EnvVar(nil), c.Env...), corev1.EnvVar{
+				Name:  healthPortEnvVar,
+				Value: strconv.Itoa(int(port)),
+			})
+		}
+		if c.LivenessProbe == nil {
+			c.LivenessProbe = &corev1.Probe{
+				Handler: corev1.Handler{
+					HTTPGet: &corev1.HTTPGetAction{
+						Path: health.LivenessPath,
+						Port: intstr.FromString(HealthPortName),
+					},
+				},
+			}
+		}
+		if c.ReadinessProbe == nil {
+			c.ReadinessProbe = &corev1.Probe{
+				Handler: corev1.Handler{
+					HTTPGet: &corev1.HTTPGetAction{
+						Path: health.StatusPath,
+						Port: intstr.FromString(HealthPortName),
+					},
+				},
+			}
+		}
+		break
+	}
+	return out
+}
+
+func hasPort(c *corev1.Container, name string) bool {
+	for _, p := range c.Ports {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasEnv(c *corev1.Container, name string) bool {
+	for _, e := range c.Env {
+		if e.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	v1 "knative.dev/eventing/pkg/apis/sources/v1"
+)
+
+func TestMakeDeploymentHealthContract(t *testing.T) {
+	source := &v1.ContainerSource{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-namespace", UID: uid},
+		Spec: v1.ContainerSourceSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:  "sidecar",
+						Image: "sidecar-image",
+					}, {
+						Name:  "source",
+						Image: "test-image",
+						Env:   []corev1.EnvVar{{Name: "test1", Value: "arg1"}},
+						Ports: []corev1.ContainerPort{{Name: HealthPortName, ContainerPort: 8081}},
+					}},
+				},
+			},
+		},
+	}
+
+	if got := HealthPort(source); got != 8081 {
+		t.Errorf("Expected health port 8081, got %d", got)
+	}
+	if got := HealthContainerName(source); got != "source" {
+		t.Errorf("Expected health container %q, got %q", "source", got)
+	}
+
+	got := MakeDeployment(source).Spec.Template.Spec.Containers
+	want := []corev1.Container{{
+		Name:  "sidecar",
+		Image: "sidecar-image",
+	}, {
+		Name:  "source",
+		Image: "test-image",
+		Env: []corev1.EnvVar{
+			{Name: "test1", Value: "arg1"},
+			{Name: "K_HEALTH_PORT", Value: "8081"},
+		},
+		Ports: []corev1.ContainerPort{{Name: HealthPortName, ContainerPort: 8081}},
+		LivenessProbe: &corev1.Probe{
+			Handler: corev1.Handler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Path: "/healthz",
+					Port: intstr.FromString(HealthPortName),
+				},
+			},
+		},
+		ReadinessProbe: &corev1.Probe{
+			Handler: corev1.Handler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Path: "/health",
+					Port: intstr.FromString(HealthPortName),
+				},
+			},
+		},
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error("unexpected containers (-want, +got) =", diff)
+	}
+
+	if len(source.Spec.Template.Spec.Containers[1].Env) != 1 || source.Spec.Template.Spec.Containers[1].LivenessProbe != nil ||
+		source.Spec.Template.Spec.Containers[1].ReadinessProbe != nil {
+		t.Error("MakeDeployment must not modify the ContainerSource")
+	}
+}
+
+func TestMakeDeploymentWithoutHealthPort(t *testing.T) {
+	source := &v1.ContainerSource{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-namespace", UID: uid},
+		Spec: v1.ContainerSourceSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:  "source",
+						Image: "test-image",
+					}},
+				},
+			},
+		},
+	}
+
+	if got := HealthPort(source); got != 0 {
+		t.Errorf("Expected no health port, got %d", got)
+	}
+	if got := HealthContainerName(source); got != "" {
+		t.Errorf("Expected no health container, got %q", got)
+	}
+	if diff := cmp.Diff(source.Spec.Template.Spec.Containers, MakeDeployment(source).Spec.Template.Spec.Containers); diff != "" {
+		t.Error("unexpected containers (-want, +got) =", diff)
+	}
+}
@@ -18,11 +18,19 @@ package resources
 
 const (
 	containerSourceController = "container-source-controller"
+
+	// SourceLabelKey is the label of the objects of every ContainerSource.
+	SourceLabelKey = "sources.knative.dev/source"
+	// NameLabelKey is the label of the objects of a ContainerSource, set to its name.
+	NameLabelKey = "sources.knative.dev/containerSource"
+
+	// Selector selects the objects labeled by Labels, for any ContainerSource.
+	Selector = SourceLabelKey + "=" + containerSourceController
 )
 
 func Labels(name string) map[string]string {
 	return map[string]string{
-		"sources.knative.dev/source":          containerSourceController,
-		"sources.knative.dev/containerSource": name,
+		SourceLabelKey: containerSourceController,
+		NameLabelKey:   name,
 	}
 }
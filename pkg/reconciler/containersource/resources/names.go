@@ -28,3 +28,11 @@ func DeploymentName(source *v1.ContainerSource) string {
 func SinkBindingName(source *v1.ContainerSource) string {
 	return kmeta.ChildName(source.Name, "-sinkbinding")
 }
+
+func HorizontalPodAutoscalerName(source *v1.ContainerSource) string {
+	return kmeta.ChildName(source.Name, "-hpa")
+}
+
+func PodDisruptionBudgetName(source *v1.ContainerSource) string {
+	return kmeta.ChildName(source.Name, "-pdb")
+}
@@ -55,10 +55,17 @@ func NewEventDisPatcherConfigFromConfigMap(config *corev1.ConfigMap) (EventDispa
 			MaxIdleConnsPerHost: defaultMaxIdleConnectionsPerHost,
 		},
 	}
+	var destinations string
 	err := configmap.Parse(
 		config.Data,
 		configmap.AsInt("MaxIdleConnections", &c.MaxIdleConns),
-		configmap.AsInt("MaxIdleConnectionsPerHost", &c.MaxIdleConnsPerHost))
+		configmap.AsInt("MaxIdleConnectionsPerHost", &c.MaxIdleConnsPerHost),
+		configmap.AsBool("EnableHTTP2", &c.EnableHTTP2),
+		configmap.AsString("Destinations", &destinations))
+	if err != nil {
+		return c, err
+	}
+	c.Destinations, err = kncloudevents.ParseDestinationArgs(destinations)
 	return c, err
 }
 
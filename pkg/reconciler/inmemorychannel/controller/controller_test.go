@@ -24,6 +24,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/pkg/configmap"
 
+	"knative.dev/eventing/pkg/apis/feature"
 	v1addr "knative.dev/pkg/client/injection/ducks/duck/v1/addressable"
 
 	. "knative.dev/pkg/reconciler/testing"
@@ -50,6 +51,11 @@ func TestNew(t *testing.T) {
 			Name:      config.EventDispatcherConfigMap,
 			Namespace: "knative-eventing",
 		},
+	}, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      feature.FlagsConfigName,
+			Namespace: "knative-eventing",
+		},
 	})
 	c := NewController(ctx, cmw)
 
@@ -38,6 +38,7 @@ import (
 	pkgreconciler "knative.dev/pkg/reconciler"
 
 	"knative.dev/eventing/pkg/apis/eventing"
+	"knative.dev/eventing/pkg/apis/feature"
 	v1 "knative.dev/eventing/pkg/apis/messaging/v1"
 	inmemorychannelreconciler "knative.dev/eventing/pkg/client/injection/reconciler/messaging/v1/inmemorychannel"
 	"knative.dev/eventing/pkg/reconciler/inmemorychannel/controller/config"
@@ -157,7 +158,17 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, imc *v1.InMemoryChannel)
 		return err
 	}
 	imc.Status.MarkChannelServiceTrue()
-	imc.Status.SetAddress(apis.HTTP(network.GetServiceHostname(svc.Name, svc.Namespace)))
+	address := apis.HTTP(network.GetServiceHostname(svc.Name, svc.Namespace))
+	if feature.FromContext(ctx).IsEnabled(feature.TransportEncryption) {
+		// The certificate of the dispatcher only covers its own hostname, so the channels are
+		// served under it and told apart by their path.
+		address = &apis.URL{
+			Scheme: "https",
+			Host:   network.GetServiceHostname(dispatcherName, dispatcherNamespace),
+			Path:   "/" + imc.Namespace + "/" + imc.Name,
+		}
+	}
+	imc.Status.SetAddress(address)
 
 	// If a DeadLetterSink is defined in Spec.Delivery then whe resolve its URI and update the stauts
 	if imc.Spec.Delivery != nil && imc.Spec.Delivery.DeadLetterSink != nil {
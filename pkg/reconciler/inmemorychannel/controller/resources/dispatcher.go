@@ -17,6 +17,7 @@ limitations under the License.
 package resources
 
 import (
+	"encoding/json"
 	"strconv"
 
 	v1 "k8s.io/api/apps/v1"
@@ -109,7 +110,7 @@ func MakeDispatcher(args DispatcherArgs) *v1.Deployment {
 }
 
 func makeEnv(dispatcherConfig config.EventDispatcherConfig) []corev1.EnvVar {
-	return []corev1.EnvVar{{
+	vars := []corev1.EnvVar{{
 		Name:  system.NamespaceEnvKey,
 		Value: system.Namespace(),
 	}, {
@@ -145,4 +146,19 @@ func makeEnv(dispatcherConfig config.EventDispatcherConfig) []corev1.EnvVar {
 		Name:  "MAX_IDLE_CONNS_PER_HOST",
 		Value: strconv.Itoa(dispatcherConfig.MaxIdleConnsPerHost),
 	}}
+	if dispatcherConfig.EnableHTTP2 {
+		vars = append(vars, corev1.EnvVar{
+			Name:  "ENABLE_HTTP2",
+			Value: strconv.FormatBool(dispatcherConfig.EnableHTTP2),
+		})
+	}
+	if len(dispatcherConfig.Destinations) > 0 {
+		// Marshalling a map of plain structs cannot fail.
+		destinations, _ := json.Marshal(dispatcherConfig.Destinations)
+		vars = append(vars, corev1.EnvVar{
+			Name:  "HTTP_DESTINATIONS",
+			Value: string(destinations),
+		})
+	}
+	return vars
 }
@@ -22,6 +22,7 @@ import (
 
 	"knative.dev/pkg/injection"
 
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/cache"
 
 	"github.com/google/uuid"
@@ -34,15 +35,18 @@ import (
 	"knative.dev/pkg/logging"
 
 	"go.uber.org/zap"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
 	"knative.dev/pkg/configmap"
 	configmapinformer "knative.dev/pkg/configmap/informer"
 	"knative.dev/pkg/controller"
 	pkgreconciler "knative.dev/pkg/reconciler"
+	"knative.dev/pkg/system"
 
 	"knative.dev/pkg/tracing"
 	tracingconfig "knative.dev/pkg/tracing/config"
 
 	"knative.dev/eventing/pkg/apis/eventing"
+	"knative.dev/eventing/pkg/auth"
 	"knative.dev/eventing/pkg/channel"
 	eventingclient "knative.dev/eventing/pkg/client/injection/client"
 	inmemorychannelinformer "knative.dev/eventing/pkg/client/injection/informers/messaging/v1/inmemorychannel"
@@ -54,6 +58,7 @@ const (
 	readTimeout   = 15 * time.Minute
 	writeTimeout  = 15 * time.Minute
 	port          = 8080
+	tlsPort       = 8443
 	finalizerName = "imc-dispatcher"
 )
 
@@ -66,6 +71,17 @@ type envConfig struct {
 	MaxIdleConns int `envconfig:"MAX_IDLE_CONNS" required:"true"`
 	// MaxIdleConnsPerHost refers to the max idle connections per host, as in net/http/transport.
 	MaxIdleConnsPerHost int `envconfig:"MAX_IDLE_CONNS_PER_HOST" required:"true"`
+	// EnableHTTP2 makes the dispatcher deliver events using HTTP/2 (h2c for plain http).
+	EnableHTTP2 bool `envconfig:"ENABLE_HTTP2" default:"false"`
+	// HTTPDestinations is a JSON object with per subscriber host transport settings.
+	HTTPDestinations string `envconfig:"HTTP_DESTINATIONS"`
+
+	// TLS configuration, used both to serve HTTPS on tlsPort and to deliver to https subscribers.
+	kncloudevents.TLSArgs
+
+	// ServiceAccountName is the service account the dispatcher runs as, for which tokens are
+	// minted when a subscriber requires an audience.
+	ServiceAccountName string `envconfig:"SERVICE_ACCOUNT_NAME"`
 }
 
 // NewController initializes the controller and is called by the generated code.
@@ -93,9 +109,19 @@ func NewController(
 	if env.MaxIdleConnsPerHost <= 0 {
 		logger.Panicf("MAX_IDLE_CONNS_PER_HOST = %d. It must be greater than 0", env.MaxIdleConnsPerHost)
 	}
+	if err := env.TLSArgs.Validate(); err != nil {
+		logger.Panicw("Invalid TLS configuration", zap.Error(err))
+	}
+	destinations, err := kncloudevents.ParseDestinationArgs(env.HTTPDestinations)
+	if err != nil {
+		logger.Panicw("Invalid HTTP_DESTINATIONS", zap.Error(err))
+	}
 	kncloudevents.ConfigureConnectionArgs(&kncloudevents.ConnectionArgs{
 		MaxIdleConns:        env.MaxIdleConns,
 		MaxIdleConnsPerHost: env.MaxIdleConnsPerHost,
+		EnableHTTP2:         env.EnableHTTP2,
+		Destinations:        destinations,
+		TLS:                 env.TLSArgs,
 	})
 
 	reporter := channel.NewStatsReporter(env.ContainerName, kmeta.ChildName(env.PodName, uuid.New().String()))
@@ -116,6 +142,7 @@ func NewController(
 
 		HTTPMessageReceiverOptions: []kncloudevents.HTTPMessageReceiverOption{
 			kncloudevents.WithChecker(readinessCheckerHTTPHandler(readinessChecker)),
+			kncloudevents.WithTLS(tlsPort, env.TLSArgs),
 		},
 	}
 	inMemoryDispatcher := inmemorychannel.NewMessageDispatcher(args)
@@ -126,6 +153,10 @@ func NewController(
 		multiChannelMessageHandler: sh,
 		reporter:                   reporter,
 		messagingClientSet:         eventingclient.Get(ctx).MessagingV1(),
+		credentials: auth.NewCredentialsResolver(kubeclient.Get(ctx), types.NamespacedName{
+			Namespace: system.Namespace(),
+			Name:      env.ServiceAccountName,
+		}),
 	}
 	impl := inmemorychannelreconciler.NewImpl(ctx, r, func(impl *controller.Impl) controller.Options {
 		return controller.Options{SkipStatusUpdates: true, FinalizerName: finalizerName}
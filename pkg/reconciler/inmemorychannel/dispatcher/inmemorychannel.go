@@ -35,6 +35,7 @@ import (
 
 	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	v1 "knative.dev/eventing/pkg/apis/messaging/v1"
+	"knative.dev/eventing/pkg/auth"
 	"knative.dev/eventing/pkg/channel"
 	"knative.dev/eventing/pkg/channel/fanout"
 	"knative.dev/eventing/pkg/channel/multichannelfanout"
@@ -48,6 +49,7 @@ type Reconciler struct {
 	multiChannelMessageHandler multichannelfanout.MultiChannelMessageHandler
 	reporter                   channel.StatsReporter
 	messagingClientSet         messagingv1.MessagingV1Interface
+	credentials                *auth.CredentialsResolver
 }
 
 // Check the interfaces Reconciler should implement
@@ -79,14 +81,15 @@ func (r *Reconciler) reconcile(ctx context.Context, imc *v1.InMemoryChannel) rec
 		return nil
 	}
 
-	config, err := newConfigForInMemoryChannel(imc)
+	config, err := newConfigForInMemoryChannel(imc, r.credentials)
 	if err != nil {
 		logging.FromContext(ctx).Error("Error creating config for in memory channels", zap.Error(err))
 		return err
 	}
 
 	// First grab the MultiChannelFanoutMessage handler
-	handler := r.multiChannelMessageHandler.GetChannelHandler(config.HostName)
+	channelKey := multichannelfanout.MakeChannelKey(config.HostName, config.Path)
+	handler := r.multiChannelMessageHandler.GetChannelHandler(channelKey)
 	if handler == nil {
 		// No handler yet, create one.
 		fanoutHandler, err := fanout.NewFanoutMessageHandler(
@@ -99,7 +102,7 @@ func (r *Reconciler) reconcile(ctx context.Context, imc *v1.InMemoryChannel) rec
 			logging.FromContext(ctx).Error("Failed to create a new fanout.MessageHandler", err)
 			return err
 		}
-		r.multiChannelMessageHandler.SetChannelHandler(config.HostName, fanoutHandler)
+		r.multiChannelMessageHandler.SetChannelHandler(channelKey, fanoutHandler)
 	} else {
 		// Just update the config if necessary.
 		haveSubs := handler.GetSubscriptions(ctx)
@@ -148,7 +151,7 @@ func (r *Reconciler) patchSubscriberStatus(ctx context.Context, imc *v1.InMemory
 }
 
 // newConfigForInMemoryChannel creates a new Config for a single inmemory channel.
-func newConfigForInMemoryChannel(imc *v1.InMemoryChannel) (*multichannelfanout.ChannelConfig, error) {
+func newConfigForInMemoryChannel(imc *v1.InMemoryChannel, credentials *auth.CredentialsResolver) (*multichannelfanout.ChannelConfig, error) {
 	subs := make([]fanout.Subscription, len(imc.Spec.Subscribers))
 
 	for i, sub := range imc.Spec.Subscribers {
@@ -156,6 +159,9 @@ func newConfigForInMemoryChannel(imc *v1.InMemoryChannel) (*multichannelfanout.C
 		if err != nil {
 			return nil, err
 		}
+		if sub.Delivery != nil {
+			conf.Credentials = credentials.Credentials(imc.Namespace, conf.Subscriber, sub.Delivery.Auth)
+		}
 		subs[i] = *conf
 	}
 
@@ -163,6 +169,7 @@ func newConfigForInMemoryChannel(imc *v1.InMemoryChannel) (*multichannelfanout.C
 		Namespace: imc.Namespace,
 		Name:      imc.Name,
 		HostName:  imc.Status.Address.URL.Host,
+		Path:      imc.Status.Address.URL.Path,
 		FanoutConfig: fanout.Config{
 			AsyncHandler:  true,
 			Subscriptions: subs,
@@ -184,7 +191,7 @@ func (r *Reconciler) deleteFunc(obj interface{}) {
 	}
 	if imc.Status.Address != nil && imc.Status.Address.URL != nil {
 		if hostName := imc.Status.Address.URL.Host; hostName != "" {
-			r.multiChannelMessageHandler.DeleteChannelHandler(hostName)
+			r.multiChannelMessageHandler.DeleteChannelHandler(multichannelfanout.MakeChannelKey(hostName, imc.Status.Address.URL.Path))
 		}
 	}
 }
@@ -42,6 +42,7 @@ import (
 	"knative.dev/eventing/pkg/adapter/mtping"
 	"knative.dev/eventing/pkg/adapter/v2"
 	sourcesv1 "knative.dev/eventing/pkg/apis/sources/v1"
+	"knative.dev/eventing/pkg/client/clientset/versioned"
 	pingsourcereconciler "knative.dev/eventing/pkg/client/injection/reconciler/sources/v1/pingsource"
 	"knative.dev/eventing/pkg/reconciler/pingsource/resources"
 	reconcilersource "knative.dev/eventing/pkg/reconciler/source"
@@ -62,9 +63,17 @@ func newWarningSinkNotFound(sink *duckv1.Destination) pkgreconciler.Event {
 	return pkgreconciler.NewEvent(corev1.EventTypeWarning, "SinkNotFound", "Sink not found: %s", string(b))
 }
 
+func newWarningDeadLetterSinkNotFound(sink *duckv1.Destination) pkgreconciler.Event {
+	b, _ := json.Marshal(sink)
+	return pkgreconciler.NewEvent(corev1.EventTypeWarning, "DeadLetterSinkNotFound", "Dead letter sink not found: %s", string(b))
+}
+
 type Reconciler struct {
 	kubeClientSet kubernetes.Interface
 
+	// eventingClientSet gets the schedule times recorded by the mt receive adapter
+	eventingClientSet versioned.Interface
+
 	// tracking mt adapter deployment changes
 	tracker tracker.Interface
 
@@ -89,6 +98,10 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, source *sourcesv1.PingSo
 	// 3. Create the EventType that it can emit.
 	//     - Will be garbage collected by K8s when this PingSource is deleted.
 
+	// The status is updated from source once reconciled, keep the schedule times the adapter
+	// recorded in the meantime.
+	defer r.carryOverScheduleTimes(ctx, source)
+
 	dest := source.Spec.Sink.DeepCopy()
 	if dest.Ref != nil {
 		// To call URIFromDestination(), dest.Ref must have a Namespace. If there is
@@ -107,6 +120,10 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, source *sourcesv1.PingSo
 	}
 	source.Status.MarkSink(sinkURI)
 
+	if err := r.resolveDeadLetterSink(ctx, source); err != nil {
+		return err
+	}
+
 	// Make sure the global mt receive adapter is running
 	d, err := r.reconcileReceiveAdapter(ctx, source)
 	if err != nil {
@@ -136,6 +153,39 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, source *sourcesv1.PingSo
 	return nil
 }
 
+// carryOverScheduleTimes copies the schedule times of the latest version of source, which are
+// recorded by the mt receive adapter and may be more recent than the listed ones.
+func (r *Reconciler) carryOverScheduleTimes(ctx context.Context, source *sourcesv1.PingSource) {
+	latest, err := r.eventingClientSet.SourcesV1().PingSources(source.Namespace).Get(ctx, source.Name, metav1.GetOptions{})
+	if err != nil {
+		logging.FromContext(ctx).Warnw("Unable to get the latest schedule times", zap.Error(err))
+		return
+	}
+	source.Status.LastScheduleTime = latest.Status.LastScheduleTime
+	source.Status.NextScheduleTime = latest.Status.NextScheduleTime
+}
+
+func (r *Reconciler) resolveDeadLetterSink(ctx context.Context, source *sourcesv1.PingSource) error {
+	if source.Spec.Delivery == nil || source.Spec.Delivery.DeadLetterSink == nil {
+		source.Status.MarkDeadLetterSinkNotConfigured()
+		return nil
+	}
+
+	dest := source.Spec.Delivery.DeadLetterSink.DeepCopy()
+	if dest.Ref != nil && dest.Ref.Namespace == "" {
+		dest.Ref.Namespace = source.GetNamespace()
+	}
+
+	deadLetterSinkURI, err := r.sinkResolver.URIFromDestinationV1(ctx, *dest, source)
+	if err != nil {
+		logging.FromContext(ctx).Errorw("Unable to get the dead letter sink's URI", zap.Error(err))
+		source.Status.MarkDeadLetterSinkResolvedFailed("Unable to get the dead letter sink's URI", "%v", err)
+		return newWarningDeadLetterSinkNotFound(dest)
+	}
+	source.Status.MarkDeadLetterSinkResolvedSucceeded(deadLetterSinkURI)
+	return nil
+}
+
 func (r *Reconciler) reconcileReceiveAdapter(ctx context.Context, source *sourcesv1.PingSource) (*appsv1.Deployment, error) {
 	args := resources.Args{
 		ConfigEnvVars:   r.configAcc.ToEnvVars(),
@@ -143,6 +193,7 @@ func (r *Reconciler) reconcileReceiveAdapter(ctx context.Context, source *source
 		NoShutdownAfter: mtping.GetNoShutDownAfterValue(),
 		SinkTimeout:     adapter.GetSinkTimeout(logging.FromContext(ctx)),
 	}
+	args.JitterPolicy, args.JitterWindow = mtping.GetJitterValue()
 	expected := resources.MakeReceiveAdapterEnvVar(args)
 
 	d, err := r.kubeClientSet.AppsV1().Deployments(system.Namespace()).Get(ctx, mtadapterName, metav1.GetOptions{})
@@ -20,6 +20,7 @@ import (
 	"context"
 	"os"
 	"testing"
+	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 
@@ -45,6 +46,7 @@ import (
 
 	"knative.dev/eventing/pkg/adapter/mtping"
 	"knative.dev/eventing/pkg/adapter/v2"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	sourcesv1 "knative.dev/eventing/pkg/apis/sources/v1"
 	fakeeventingclient "knative.dev/eventing/pkg/client/injection/client/fake"
 	"knative.dev/eventing/pkg/client/injection/reconciler/sources/v1/pingsource"
@@ -76,6 +78,18 @@ var (
 	}
 	sinkDNS = "sink.mynamespace.svc." + network.GetClusterDomainName()
 	sinkURI = apis.HTTP(sinkDNS)
+
+	dlsURI  = apis.HTTP("dls.example.com")
+	dlsDest = duckv1.Destination{
+		URI: dlsURI,
+	}
+	dlsDestNotFound = duckv1.Destination{
+		Ref: &duckv1.KReference{
+			Name:       "dls",
+			Kind:       "Channel",
+			APIVersion: "messaging.knative.dev/v1",
+		},
+	}
 )
 
 const (
@@ -219,6 +233,7 @@ func TestAllCases(t *testing.T) {
 					// Status Update:
 					rtv1.WithInitPingSourceConditions,
 					rtv1.WithPingSourceSink(sinkURI),
+					rtv1.WithPingSourceDeadLetterSinkNotConfigured,
 					rtv1.WithPingSourceStatusObservedGeneration(generation),
 				),
 			}},
@@ -263,6 +278,7 @@ func TestAllCases(t *testing.T) {
 					rtv1.WithInitPingSourceConditions,
 					rtv1.WithPingSourceDeployed,
 					rtv1.WithPingSourceSink(sinkURI),
+					rtv1.WithPingSourceDeadLetterSinkNotConfigured,
 					rtv1.WithPingSourceCloudEventAttributes,
 					rtv1.WithPingSourceStatusObservedGeneration(generation),
 				),
@@ -308,10 +324,110 @@ func TestAllCases(t *testing.T) {
 					rtv1.WithInitPingSourceConditions,
 					rtv1.WithPingSourceDeployed,
 					rtv1.WithPingSourceSink(sinkURI),
+					rtv1.WithPingSourceDeadLetterSinkNotConfigured,
+					rtv1.WithPingSourceCloudEventAttributes,
+					rtv1.WithPingSourceStatusObservedGeneration(generation),
+				),
+			}},
+		}, {
+			Name: "valid with dead letter sink",
+			Objects: []runtime.Object{
+				rtv1.NewPingSource(sourceName, testNS,
+					rtv1.WithPingSourceSpec(sourcesv1.PingSourceSpec{
+						Schedule:    testSchedule,
+						ContentType: testContentType,
+						Data:        testData,
+						SourceSpec: duckv1.SourceSpec{
+							Sink: sinkDest,
+						},
+						Delivery: &eventingduckv1.DeliverySpec{
+							DeadLetterSink: &dlsDest,
+						},
+					}),
+					rtv1.WithPingSource(sourceUID),
+					rtv1.WithPingSourceObjectMetaGeneration(generation),
+				),
+				rtv1.NewChannel(sinkName, testNS,
+					rtv1.WithInitChannelConditions,
+					rtv1.WithChannelAddress(sinkDNS),
+				),
+				makeAvailableMTAdapter(),
+			},
+			Key: testNS + "/" + sourceName,
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: rtv1.NewPingSource(sourceName, testNS,
+					rtv1.WithPingSourceSpec(sourcesv1.PingSourceSpec{
+						Schedule:    testSchedule,
+						ContentType: testContentType,
+						Data:        testData,
+						SourceSpec: duckv1.SourceSpec{
+							Sink: sinkDest,
+						},
+						Delivery: &eventingduckv1.DeliverySpec{
+							DeadLetterSink: &dlsDest,
+						},
+					}),
+					rtv1.WithPingSource(sourceUID),
+					rtv1.WithPingSourceObjectMetaGeneration(generation),
+					// Status Update:
+					rtv1.WithInitPingSourceConditions,
+					rtv1.WithPingSourceDeployed,
+					rtv1.WithPingSourceSink(sinkURI),
+					rtv1.WithPingSourceDeadLetterSinkResolved(dlsURI),
 					rtv1.WithPingSourceCloudEventAttributes,
 					rtv1.WithPingSourceStatusObservedGeneration(generation),
 				),
 			}},
+		}, {
+			Name: "dead letter sink not found",
+			Objects: []runtime.Object{
+				rtv1.NewPingSource(sourceName, testNS,
+					rtv1.WithPingSourceSpec(sourcesv1.PingSourceSpec{
+						Schedule:    testSchedule,
+						ContentType: testContentType,
+						Data:        testData,
+						SourceSpec: duckv1.SourceSpec{
+							Sink: sinkDest,
+						},
+						Delivery: &eventingduckv1.DeliverySpec{
+							DeadLetterSink: &dlsDestNotFound,
+						},
+					}),
+					rtv1.WithPingSource(sourceUID),
+					rtv1.WithPingSourceObjectMetaGeneration(generation),
+				),
+				rtv1.NewChannel(sinkName, testNS,
+					rtv1.WithInitChannelConditions,
+					rtv1.WithChannelAddress(sinkDNS),
+				),
+			},
+			Key: testNS + "/" + sourceName,
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: rtv1.NewPingSource(sourceName, testNS,
+					rtv1.WithPingSourceSpec(sourcesv1.PingSourceSpec{
+						Schedule:    testSchedule,
+						ContentType: testContentType,
+						Data:        testData,
+						SourceSpec: duckv1.SourceSpec{
+							Sink: sinkDest,
+						},
+						Delivery: &eventingduckv1.DeliverySpec{
+							DeadLetterSink: &dlsDestNotFound,
+						},
+					}),
+					rtv1.WithPingSource(sourceUID),
+					rtv1.WithPingSourceObjectMetaGeneration(generation),
+					// Status Update:
+					rtv1.WithInitPingSourceConditions,
+					rtv1.WithPingSourceSink(sinkURI),
+					rtv1.WithPingSourceDeadLetterSinkResolvedFailed("Unable to get the dead letter sink's URI", `channels.messaging.knative.dev "dls" not found`),
+					rtv1.WithPingSourceStatusObservedGeneration(generation),
+				),
+			}},
+			WantEvents: []string{
+				Eventf(corev1.EventTypeWarning, "DeadLetterSinkNotFound",
+					`Dead letter sink not found: {"ref":{"kind":"Channel","namespace":"testnamespace","name":"dls","apiVersion":"messaging.knative.dev/v1"}}`),
+			},
 		}, {
 			Name: "valid with dataBase64",
 			Objects: []runtime.Object{
@@ -350,6 +466,7 @@ func TestAllCases(t *testing.T) {
 					rtv1.WithInitPingSourceConditions,
 					rtv1.WithPingSourceDeployed,
 					rtv1.WithPingSourceSink(sinkURI),
+					rtv1.WithPingSourceDeadLetterSinkNotConfigured,
 					rtv1.WithPingSourceCloudEventAttributes,
 					rtv1.WithPingSourceStatusObservedGeneration(generation),
 				),
@@ -361,9 +478,10 @@ func TestAllCases(t *testing.T) {
 	table.Test(t, rtv1.MakeFactory(func(ctx context.Context, listers *rtv1.Listers, cmw configmap.Watcher) controller.Reconciler {
 		ctx = addressable.WithDuck(ctx)
 		r := &Reconciler{
-			configAcc:     &reconcilersource.EmptyVarsGenerator{},
-			kubeClientSet: fakekubeclient.Get(ctx),
-			tracker:       tracker.New(func(types.NamespacedName) {}, 0),
+			configAcc:         &reconcilersource.EmptyVarsGenerator{},
+			kubeClientSet:     fakekubeclient.Get(ctx),
+			eventingClientSet: fakeeventingclient.Get(ctx),
+			tracker:           tracker.New(func(types.NamespacedName) {}, 0),
 		}
 		r.sinkResolver = resolver.NewURIResolverFromTracker(ctx, tracker.New(func(types.NamespacedName) {}, 0))
 
@@ -376,12 +494,36 @@ func TestAllCases(t *testing.T) {
 	))
 }
 
+func TestCarryOverScheduleTimes(t *testing.T) {
+	ctx, _ := SetupFakeContext(t)
+	last := metav1.NewTime(time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC))
+	next := metav1.NewTime(last.Add(2 * time.Minute))
+
+	// The adapter recorded the schedule times after the PingSource was listed.
+	listed := rtv1.NewPingSource(sourceName, testNS)
+	latest := listed.DeepCopy()
+	latest.Status.LastScheduleTime, latest.Status.NextScheduleTime = &last, &next
+	if _, err := fakeeventingclient.Get(ctx).SourcesV1().PingSources(testNS).Create(ctx, latest, metav1.CreateOptions{}); err != nil {
+		t.Fatal("Failed to create the PingSource:", err)
+	}
+
+	r := &Reconciler{eventingClientSet: fakeeventingclient.Get(ctx)}
+	r.carryOverScheduleTimes(ctx, listed)
+	if got := listed.Status.LastScheduleTime; got == nil || !got.Equal(&last) {
+		t.Errorf("LastScheduleTime = %v, want %v", got, last)
+	}
+	if got := listed.Status.NextScheduleTime; got == nil || !got.Equal(&next) {
+		t.Errorf("NextScheduleTime = %v, want %v", got, next)
+	}
+}
+
 func MakeMTAdapter() *appsv1.Deployment {
 	args := resources.Args{
 		ConfigEnvVars:   (&reconcilersource.EmptyVarsGenerator{}).ToEnvVars(),
 		NoShutdownAfter: mtping.GetNoShutDownAfterValue(),
 		SinkTimeout:     adapter.GetSinkTimeout(nil),
 	}
+	args.JitterPolicy, args.JitterWindow = mtping.GetJitterValue()
 	return &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: system.Namespace(),
@@ -18,10 +18,12 @@ package resources
 
 import (
 	"strconv"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"knative.dev/eventing/pkg/adapter/mtping"
 	"knative.dev/eventing/pkg/adapter/v2"
+	sourcesv1 "knative.dev/eventing/pkg/apis/sources/v1"
 	"knative.dev/pkg/system"
 )
 
@@ -30,6 +32,8 @@ type Args struct {
 	LeConfig        string
 	NoShutdownAfter int
 	SinkTimeout     int
+	JitterPolicy    sourcesv1.PingJitterPolicy
+	JitterWindow    time.Duration
 }
 
 // MakeReceiveAdapterEnvVar generates the environment variables for the pingsources
@@ -50,6 +54,12 @@ func MakeReceiveAdapterEnvVar(args Args) []corev1.EnvVar {
 	}, {
 		Name:  adapter.EnvSinkTimeout,
 		Value: strconv.Itoa(args.SinkTimeout),
+	}, {
+		Name:  mtping.EnvJitterPolicy,
+		Value: string(args.JitterPolicy),
+	}, {
+		Name:  mtping.EnvJitterWindow,
+		Value: args.JitterWindow.String(),
 	}}
 
 	return append(envs, args.ConfigEnvVars...)
@@ -18,9 +18,11 @@ package resources
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
+	sourcesv1 "knative.dev/eventing/pkg/apis/sources/v1"
 	reconcilersource "knative.dev/eventing/pkg/reconciler/source"
 	"knative.dev/pkg/system"
 	_ "knative.dev/pkg/system/testing"
@@ -31,6 +33,8 @@ func TestMakePingAdapter(t *testing.T) {
 		ConfigEnvVars:   (&reconcilersource.EmptyVarsGenerator{}).ToEnvVars(),
 		NoShutdownAfter: 40,
 		SinkTimeout:     48,
+		JitterPolicy:    sourcesv1.PingJitterSpread,
+		JitterWindow:    100 * time.Millisecond,
 	}
 
 	want := []corev1.EnvVar{{
@@ -49,6 +53,12 @@ func TestMakePingAdapter(t *testing.T) {
 	}, {
 		Name:  "K_SINK_TIMEOUT",
 		Value: "48",
+	}, {
+		Name:  "K_JITTER_POLICY",
+		Value: "Spread",
+	}, {
+		Name:  "K_JITTER_WINDOW",
+		Value: "100ms",
 	}, {
 		Name:  "K_LOGGING_CONFIG",
 		Value: "",
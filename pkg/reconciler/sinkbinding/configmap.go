@@ -0,0 +1,114 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sinkbinding
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/kmeta"
+
+	"knative.dev/eventing/pkg/apis/sources"
+	v1 "knative.dev/eventing/pkg/apis/sources/v1"
+)
+
+const (
+	// sinkConfigMapLabelKey labels the sink ConfigMaps with the name of
+	// their SinkBinding.
+	sinkConfigMapLabelKey = sources.GroupName + "/sinkbinding"
+
+	// ConfigMapSelector selects the sink ConfigMaps of SinkBindings, the
+	// only ConfigMaps the controller watches.
+	ConfigMapSelector = sinkConfigMapLabelKey
+)
+
+// makeConfigMap returns the ConfigMap holding the resolved sink of a
+// SinkBinding that projects its sink as files.
+func makeConfigMap(sb *v1.SinkBinding, uri *apis.URL) (*corev1.ConfigMap, error) {
+	data := map[string]string{
+		v1.SinkBindingSinkKey:        uri.String(),
+		v1.SinkBindingCEOverridesKey: "",
+	}
+	if sb.Spec.CloudEventOverrides != nil {
+		co, err := json.Marshal(sb.Spec.CloudEventOverrides)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal CloudEventOverrides: %w", err)
+		}
+		data[v1.SinkBindingCEOverridesKey] = string(co)
+	}
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            v1.SinkBindingConfigMapName(sb),
+			Namespace:       sb.Namespace,
+			Labels:          map[string]string{sinkConfigMapLabelKey: sb.Name},
+			OwnerReferences: []metav1.OwnerReference{*kmeta.NewControllerRef(sb)},
+		},
+		Data: data,
+	}, nil
+}
+
+// reconcileConfigMap keeps the sink ConfigMap of the SinkBinding in sync with
+// the resolved sink. Subjects read it through a projected volume, so sink
+// changes reach them without rolling their pods. The ConfigMap is read from
+// the informer of the labeled sink ConfigMaps.
+func (s *SinkBindingSubResourcesReconciler) reconcileConfigMap(ctx context.Context, sb *v1.SinkBinding, uri *apis.URL) error {
+	cms := s.kubeClientSet.CoreV1().ConfigMaps(sb.Namespace)
+	name := v1.SinkBindingConfigMapName(sb)
+
+	current, err := s.configMapLister.ConfigMaps(sb.Namespace).Get(name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("getting sink ConfigMap %q: %w", name, err)
+	}
+
+	if sb.Spec.Files == nil || !sb.Spec.Files.Sink {
+		if err == nil && metav1.IsControlledBy(current, sb) {
+			if err := cms.Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+				return fmt.Errorf("deleting sink ConfigMap %q: %w", name, err)
+			}
+		}
+		return nil
+	}
+
+	expected, merr := makeConfigMap(sb, uri)
+	if merr != nil {
+		return merr
+	}
+	if apierrors.IsNotFound(err) {
+		if _, err := cms.Create(ctx, expected, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("creating sink ConfigMap %q: %w", name, err)
+		}
+		return nil
+	}
+	if !metav1.IsControlledBy(current, sb) {
+		return fmt.Errorf("sink ConfigMap %q is not owned by SinkBinding %q", name, sb.Name)
+	}
+	if equality.Semantic.DeepEqual(current.Data, expected.Data) {
+		return nil
+	}
+	desired := current.DeepCopy()
+	desired.Data = expected.Data
+	if _, err := cms.Update(ctx, desired, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("updating sink ConfigMap %q: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sinkbinding
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	v1 "knative.dev/eventing/pkg/apis/sources/v1"
+)
+
+func TestReconcileConfigMap(t *testing.T) {
+	ctx := context.Background()
+	sb := &v1.SinkBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "binding",
+			Namespace: "ns",
+			UID:       "1234",
+		},
+		Spec: v1.SinkBindingSpec{
+			SourceSpec: duckv1.SourceSpec{
+				CloudEventOverrides: &duckv1.CloudEventOverrides{
+					Extensions: map[string]string{"foo": "bar"},
+				},
+			},
+			Files: &v1.SinkBindingFiles{Sink: true},
+		},
+	}
+	kc := fake.NewSimpleClientset()
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	r := &SinkBindingSubResourcesReconciler{
+		kubeClientSet:   kc,
+		configMapLister: corev1listers.NewConfigMapLister(indexer),
+	}
+	cms := kc.CoreV1().ConfigMaps("ns")
+	// reconcile runs reconcileConfigMap and then syncs the lister with the
+	// clientset, as the informer would.
+	reconcile := func(uri *apis.URL) {
+		t.Helper()
+		if err := r.reconcileConfigMap(ctx, sb, uri); err != nil {
+			t.Fatal("reconcileConfigMap() =", err)
+		}
+		list, err := cms.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			t.Fatal("List() =", err)
+		}
+		objs := make([]interface{}, 0, len(list.Items))
+		for i := range list.Items {
+			objs = append(objs, &list.Items[i])
+		}
+		if err := indexer.Replace(objs, ""); err != nil {
+			t.Fatal("Replace() =", err)
+		}
+	}
+
+	first := apis.HTTP("first.ns.svc.cluster.local")
+	reconcile(first)
+	cm, err := cms.Get(ctx, "binding-sink", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal("Get() =", err)
+	}
+	want := map[string]string{
+		"sink":         "http://first.ns.svc.cluster.local",
+		"ce-overrides": `{"extensions":{"foo":"bar"}}`,
+	}
+	if !cmp.Equal(cm.Data, want) {
+		t.Error("Created data (-want, +got):", cmp.Diff(want, cm.Data))
+	}
+	if !metav1.IsControlledBy(cm, sb) {
+		t.Error("ConfigMap is not controlled by the SinkBinding")
+	}
+	if got := cm.Labels[sinkConfigMapLabelKey]; got != "binding" {
+		t.Errorf("Label %s = %q, want binding", sinkConfigMapLabelKey, got)
+	}
+	selector, err := labels.Parse(ConfigMapSelector)
+	if err != nil {
+		t.Fatal("Parse() =", err)
+	}
+	if !selector.Matches(labels.Set(cm.Labels)) {
+		t.Errorf("ConfigMapSelector %q does not select labels %v", ConfigMapSelector, cm.Labels)
+	}
+
+	second := apis.HTTP("second.ns.svc.cluster.local")
+	reconcile(second)
+	cm, err = cms.Get(ctx, "binding-sink", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal("Get() =", err)
+	}
+	if got, want := cm.Data["sink"], "http://second.ns.svc.cluster.local"; got != want {
+		t.Errorf("Updated sink = %q, want %q", got, want)
+	}
+
+	sb.Spec.Files = nil
+	reconcile(second)
+	if _, err := cms.Get(ctx, "binding-sink", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Error("Get() after disabling files =", err)
+	}
+}
@@ -22,14 +22,19 @@ import (
 
 	sbinformer "knative.dev/eventing/pkg/client/injection/informers/sources/v1/sinkbinding"
 	"knative.dev/pkg/client/injection/ducks/duck/v1/podspecable"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	filteredconfigmapinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/configmap/filtered"
 	"knative.dev/pkg/client/injection/kube/informers/core/v1/namespace"
 	"knative.dev/pkg/reconciler"
 	"knative.dev/pkg/resolver"
 
+	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
+	corev1listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	v1 "knative.dev/eventing/pkg/apis/sources/v1"
@@ -47,8 +52,12 @@ const (
 )
 
 type SinkBindingSubResourcesReconciler struct {
-	res     *resolver.URIResolver
-	tracker tracker.Interface
+	res           *resolver.URIResolver
+	tracker       tracker.Interface
+	factory       duck.InformerFactory
+	kubeClientSet kubernetes.Interface
+
+	configMapLister corev1listers.ConfigMapLister
 }
 
 // NewController returns a new SinkBinding reconciler.
@@ -62,6 +71,7 @@ func NewController(
 	dc := dynamicclient.Get(ctx)
 	psInformerFactory := podspecable.Get(ctx)
 	namespaceInformer := namespace.Get(ctx)
+	configMapInformer := filteredconfigmapinformer.Get(ctx, ConfigMapSelector)
 	c := &psbinding.BaseReconciler{
 		LeaderAwareFuncs: reconciler.LeaderAwareFuncs{
 			PromoteFunc: func(bkt reconciler.Bucket, enq func(reconciler.Bucket, types.NamespacedName)) error {
@@ -82,7 +92,7 @@ func NewController(
 		Get: func(namespace string, name string) (psbinding.Bindable, error) {
 			return sbInformer.Lister().SinkBindings(namespace).Get(name)
 		},
-		DynamicClient: dc,
+		DynamicClient: newSubjectClient(dc),
 		Recorder: record.NewBroadcaster().NewRecorder(
 			scheme.Scheme, corev1.EventSource{Component: controllerAgentName}),
 		NamespaceLister: namespaceInformer.Lister(),
@@ -94,23 +104,37 @@ func NewController(
 
 	sbInformer.Informer().AddEventHandler(controller.HandleAll(impl.Enqueue))
 	namespaceInformer.Informer().AddEventHandler(controller.HandleAll(impl.Enqueue))
+	configMapInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.FilterControllerGVK(v1.SchemeGroupVersion.WithKind("SinkBinding")),
+		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
+	})
 
 	sbResolver := resolver.NewURIResolverFromTracker(ctx, impl.Tracker)
-	c.SubResourcesReconciler = &SinkBindingSubResourcesReconciler{
-		res:     sbResolver,
-		tracker: impl.Tracker,
-	}
-
 	c.WithContext = func(ctx context.Context, b psbinding.Bindable) (context.Context, error) {
-		return v1.WithURIResolver(ctx, sbResolver), nil
+		return withSubjectRecorder(v1.WithURIResolver(ctx, sbResolver), b.(*v1.SinkBinding)), nil
 	}
 	c.Tracker = impl.Tracker
+	kubeClient := kubeclient.Get(ctx)
 	c.Factory = &duck.CachedInformerFactory{
 		Delegate: &duck.EnqueueInformerFactory{
-			Delegate:     psInformerFactory,
+			// CronJobs are listed with their job template as pod template.
+			Delegate: &cronJobInformerFactory{
+				Delegate:     psInformerFactory,
+				Client:       kubeClient,
+				ResyncPeriod: controller.GetResyncPeriod(ctx),
+				StopChannel:  ctx.Done(),
+			},
 			EventHandler: controller.HandleAll(c.Tracker.OnChanged),
 		},
 	}
+	c.SubResourcesReconciler = &SinkBindingSubResourcesReconciler{
+		res:           sbResolver,
+		tracker:       impl.Tracker,
+		factory:       c.Factory,
+		kubeClientSet: kubeClient,
+
+		configMapLister: configMapInformer.Lister(),
+	}
 
 	return impl
 }
@@ -166,6 +190,15 @@ func (s *SinkBindingSubResourcesReconciler) Reconcile(ctx context.Context, b psb
 		return err
 	}
 	sb.Status.MarkSink(uri)
+	if err := s.reconcileSubjects(ctx, sb); err != nil {
+		logging.FromContext(ctx).Errorw("Failed to report the subjects of the binding", zap.Error(err))
+		return err
+	}
+	if err := s.reconcileConfigMap(ctx, sb, uri); err != nil {
+		logging.FromContext(ctx).Errorw("Failed to reconcile sink ConfigMap", zap.Error(err))
+		sb.Status.MarkBindingUnavailable("SinkConfigMapFailed", err.Error())
+		return err
+	}
 	return nil
 }
 
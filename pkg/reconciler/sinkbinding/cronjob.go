@@ -0,0 +1,211 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sinkbinding
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	batchv1beta1informers "k8s.io/client-go/informers/batch/v1beta1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/apis/duck"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/webhook"
+	"knative.dev/pkg/webhook/psbinding"
+)
+
+const (
+	podTemplatePath        = "/spec/template"
+	cronJobPodTemplatePath = "/spec/jobTemplate/spec/template"
+)
+
+// cronJobAdmissionController lets the psbinding admission controller bind
+// CronJobs, which are not PodSpecable. It presents the job template of a
+// CronJob as the pod template of a PodSpecable and moves the resulting patch
+// back under the job template.
+type cronJobAdmissionController struct {
+	*psbinding.Reconciler
+}
+
+var _ webhook.AdmissionController = (*cronJobAdmissionController)(nil)
+
+// WithCronJobs extends the psbinding admission controller of impl to bind
+// the job template of CronJobs.
+func WithCronJobs(impl *controller.Impl) *controller.Impl {
+	impl.Reconciler = &cronJobAdmissionController{
+		Reconciler: impl.Reconciler.(*psbinding.Reconciler),
+	}
+	return impl
+}
+
+// Admit implements webhook.AdmissionController
+func (ac *cronJobAdmissionController) Admit(ctx context.Context, request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	if request.Kind.Group != batchv1beta1.GroupName || request.Kind.Kind != "CronJob" {
+		return ac.Reconciler.Admit(ctx, request)
+	}
+
+	// The job template of batch/v1 and batch/v1beta1 CronJobs is the same.
+	cj := &batchv1beta1.CronJob{}
+	if err := json.Unmarshal(request.Object.Raw, cj); err != nil {
+		return webhook.MakeErrorStatus("unable to decode object: %v", err)
+	}
+	raw, err := json.Marshal(cronJobAsPodSpecable(cj))
+	if err != nil {
+		return webhook.MakeErrorStatus("unable to encode job template: %v", err)
+	}
+	r := request.DeepCopy()
+	r.Object.Raw = raw
+
+	response := ac.Reconciler.Admit(ctx, r)
+	if len(response.Patch) == 0 {
+		return response
+	}
+	patch, err := moveCronJobPatch(response.Patch)
+	if err != nil {
+		return webhook.MakeErrorStatus("unable to create patch with binding: %v", err)
+	}
+	response.Patch = patch
+	return response
+}
+
+// moveCronJobPatch moves the operations of a JSON patch on the pod template
+// of a PodSpecable under the job template of a CronJob.
+func moveCronJobPatch(patch []byte) ([]byte, error) {
+	var ops []map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, err
+	}
+	for _, op := range ops {
+		for _, field := range []string{"path", "from"} {
+			raw, ok := op[field]
+			if !ok {
+				continue
+			}
+			var p string
+			if err := json.Unmarshal(raw, &p); err != nil {
+				return nil, err
+			}
+			if p == podTemplatePath || strings.HasPrefix(p, podTemplatePath+"/") {
+				p = cronJobPodTemplatePath + strings.TrimPrefix(p, podTemplatePath)
+			}
+			moved, err := json.Marshal(p)
+			if err != nil {
+				return nil, err
+			}
+			op[field] = moved
+		}
+	}
+	return json.Marshal(ops)
+}
+
+// cronJobAsPodSpecable presents the job template of a CronJob as the pod
+// template of a PodSpecable.
+func cronJobAsPodSpecable(cj *batchv1beta1.CronJob) *duckv1.WithPod {
+	return &duckv1.WithPod{
+		TypeMeta:   cj.TypeMeta,
+		ObjectMeta: cj.ObjectMeta,
+		Spec: duckv1.WithPodSpec{
+			Template: duckv1.PodSpecable(cj.Spec.JobTemplate.Spec.Template),
+		},
+	}
+}
+
+// cronJobInformerFactory lists CronJobs with their job template as pod
+// template, other resources are listed by its Delegate. CronJobs are read
+// through batch/v1beta1, which is served by all the supported clusters.
+type cronJobInformerFactory struct {
+	Delegate     duck.InformerFactory
+	Client       kubernetes.Interface
+	ResyncPeriod time.Duration
+	StopChannel  <-chan struct{}
+}
+
+var _ duck.InformerFactory = (*cronJobInformerFactory)(nil)
+
+// Get implements duck.InformerFactory
+func (f *cronJobInformerFactory) Get(ctx context.Context, gvr schema.GroupVersionResource) (cache.SharedIndexInformer, cache.GenericLister, error) {
+	if gvr.GroupResource() != cronJobsResource {
+		return f.Delegate.Get(ctx, gvr)
+	}
+
+	inf := batchv1beta1informers.NewCronJobInformer(f.Client, metav1.NamespaceAll, f.ResyncPeriod, cache.Indexers{
+		cache.NamespaceIndex: cache.MetaNamespaceIndexFunc,
+	})
+	go inf.Run(f.StopChannel)
+	if ok := cache.WaitForCacheSync(f.StopChannel, inf.HasSynced); !ok {
+		return nil, nil, fmt.Errorf("failed starting shared index informer for %v", gvr)
+	}
+	return inf, &cronJobLister{cache.NewGenericLister(inf.GetIndexer(), gvr.GroupResource())}, nil
+}
+
+// cronJobLister returns the CronJobs of a GenericLister as PodSpecables.
+type cronJobLister struct {
+	lister cache.GenericLister
+}
+
+func (l *cronJobLister) List(selector labels.Selector) ([]runtime.Object, error) {
+	return asPodSpecables(l.lister.List(selector))
+}
+
+func (l *cronJobLister) Get(name string) (runtime.Object, error) {
+	return asPodSpecable(l.lister.Get(name))
+}
+
+func (l *cronJobLister) ByNamespace(namespace string) cache.GenericNamespaceLister {
+	return &cronJobNamespaceLister{l.lister.ByNamespace(namespace)}
+}
+
+type cronJobNamespaceLister struct {
+	lister cache.GenericNamespaceLister
+}
+
+func (l *cronJobNamespaceLister) List(selector labels.Selector) ([]runtime.Object, error) {
+	return asPodSpecables(l.lister.List(selector))
+}
+
+func (l *cronJobNamespaceLister) Get(name string) (runtime.Object, error) {
+	return asPodSpecable(l.lister.Get(name))
+}
+
+func asPodSpecable(obj runtime.Object, err error) (runtime.Object, error) {
+	if err != nil {
+		return nil, err
+	}
+	return cronJobAsPodSpecable(obj.(*batchv1beta1.CronJob)), nil
+}
+
+func asPodSpecables(objs []runtime.Object, err error) ([]runtime.Object, error) {
+	if err != nil {
+		return nil, err
+	}
+	ps := make([]runtime.Object, 0, len(objs))
+	for _, obj := range objs {
+		ps = append(ps, cronJobAsPodSpecable(obj.(*batchv1beta1.CronJob)))
+	}
+	return ps, nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sinkbinding
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+func TestMoveCronJobPatch(t *testing.T) {
+	patch := `[
+		{"op":"add","path":"/spec/template/spec/containers/0/env","value":[{"name":"K_SINK","value":"http://sink"}]},
+		{"op":"move","from":"/spec/template/spec/volumes/1","path":"/spec/template/spec/volumes/0"},
+		{"op":"replace","path":"/spec/templates","value":"untouched"}
+	]`
+	want := `[
+		{"op":"add","path":"/spec/jobTemplate/spec/template/spec/containers/0/env","value":[{"name":"K_SINK","value":"http://sink"}]},
+		{"op":"move","from":"/spec/jobTemplate/spec/template/spec/volumes/1","path":"/spec/jobTemplate/spec/template/spec/volumes/0"},
+		{"op":"replace","path":"/spec/templates","value":"untouched"}
+	]`
+
+	got, err := moveCronJobPatch([]byte(patch))
+	if err != nil {
+		t.Fatal("moveCronJobPatch() =", err)
+	}
+	var gotOps, wantOps []map[string]interface{}
+	if err := json.Unmarshal(got, &gotOps); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(want), &wantOps); err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(gotOps, wantOps) {
+		t.Error("moveCronJobPatch (-want, +got):", cmp.Diff(wantOps, gotOps))
+	}
+
+	if _, err := moveCronJobPatch([]byte("{")); err == nil {
+		t.Error("moveCronJobPatch() = nil, want an error for an invalid patch")
+	}
+}
+
+func TestCronJobLister(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	if err := indexer.Add(&batchv1beta1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "ns"},
+		Spec: batchv1beta1.CronJobSpec{
+			JobTemplate: batchv1beta1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{Template: podTemplate()},
+			},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	lister := &cronJobLister{cache.NewGenericLister(indexer, cronJobsResource)}
+
+	got, err := lister.ByNamespace("ns").Get("nightly")
+	if err != nil {
+		t.Fatal("Get() =", err)
+	}
+	want := withPod("nightly", podTemplate())
+	if !cmp.Equal(got, want) {
+		t.Error("Get (-want, +got):", cmp.Diff(want, got))
+	}
+
+	all, err := lister.ByNamespace("ns").List(labels.Everything())
+	if err != nil {
+		t.Fatal("List() =", err)
+	}
+	if len(all) != 1 || !cmp.Equal(all[0].(*duckv1.WithPod), want) {
+		t.Errorf("List() = %v, want %v", all, want)
+	}
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sinkbinding
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	v1 "knative.dev/eventing/pkg/apis/sources/v1"
+)
+
+// maxConcurrentPatches bounds the number of subjects of a binding patched at
+// the same time.
+const maxConcurrentPatches = 10
+
+var (
+	cronJobsResource = schema.GroupResource{Group: "batch", Resource: "cronjobs"}
+	jobsResource     = schema.GroupResource{Group: "batch", Resource: "jobs"}
+)
+
+const staleJobMessage = "The pod template of a Job is immutable, only Jobs created after the binding carry it"
+
+type subjectRecorderKey struct{}
+
+// subjectRecorder records the subjects the binding failed to be applied to
+// on the status of the binding.
+type subjectRecorder struct {
+	lock sync.Mutex
+	sb   *v1.SinkBinding
+}
+
+// withSubjectRecorder attaches a subjectRecorder for sb to the context the
+// psbinding reconciler patches its subjects with.
+func withSubjectRecorder(ctx context.Context, sb *v1.SinkBinding) context.Context {
+	return context.WithValue(ctx, subjectRecorderKey{}, &subjectRecorder{sb: sb})
+}
+
+func getSubjectRecorder(ctx context.Context) *subjectRecorder {
+	r, _ := ctx.Value(subjectRecorderKey{}).(*subjectRecorder)
+	return r
+}
+
+// failed records that the binding could not be applied to the named subject.
+func (r *subjectRecorder) failed(name string, err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	subject := r.sb.GetSubject()
+	failed := v1.SinkBindingSubject{
+		APIVersion: subject.APIVersion,
+		Kind:       subject.Kind,
+		Name:       name,
+		State:      v1.SinkBindingSubjectFailed,
+		Message:    err.Error(),
+	}
+	subjects := make([]v1.SinkBindingSubject, 0, len(r.sb.Status.Subjects)+1)
+	for _, s := range r.sb.Status.Subjects {
+		if s.Kind != failed.Kind || s.Name != failed.Name {
+			subjects = append(subjects, s)
+		}
+	}
+	r.sb.Status.PropagateSubjects(append(subjects, failed))
+}
+
+// subjectClient is the dynamic client the psbinding reconciler patches the
+// subjects of a binding with. It binds the job template of CronJobs, leaves
+// the immutable pod template of Jobs alone, records the subjects that could
+// not be patched and bounds the number of concurrent patches.
+type subjectClient struct {
+	dynamic.Interface
+
+	patches chan struct{}
+}
+
+func newSubjectClient(client dynamic.Interface) *subjectClient {
+	return &subjectClient{
+		Interface: client,
+		patches:   make(chan struct{}, maxConcurrentPatches),
+	}
+}
+
+// Resource implements dynamic.Interface
+func (c *subjectClient) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return &subjectResource{
+		NamespaceableResourceInterface: c.Interface.Resource(gvr),
+		client:                         c,
+		gvr:                            gvr,
+	}
+}
+
+type subjectResource struct {
+	dynamic.NamespaceableResourceInterface
+
+	client *subjectClient
+	gvr    schema.GroupVersionResource
+}
+
+// Namespace implements dynamic.NamespaceableResourceInterface
+func (r *subjectResource) Namespace(namespace string) dynamic.ResourceInterface {
+	return &namespacedSubjectResource{
+		ResourceInterface: r.NamespaceableResourceInterface.Namespace(namespace),
+		client:            r.client,
+		gvr:               r.gvr,
+	}
+}
+
+type namespacedSubjectResource struct {
+	dynamic.ResourceInterface
+
+	client *subjectClient
+	gvr    schema.GroupVersionResource
+}
+
+// Patch implements dynamic.ResourceInterface
+func (r *namespacedSubjectResource) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, options metav1.PatchOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	recorder := getSubjectRecorder(ctx)
+	if recorder == nil || len(subresources) != 0 {
+		return r.ResourceInterface.Patch(ctx, name, pt, data, options, subresources...)
+	}
+
+	select {
+	case r.client.patches <- struct{}{}:
+		defer func() { <-r.client.patches }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	switch r.gvr.GroupResource() {
+	case jobsResource:
+		// Jobs created before the binding are reported as stale.
+		return nil, nil
+	case cronJobsResource:
+		moved, err := moveCronJobPatch(data)
+		if err != nil {
+			recorder.failed(name, err)
+			return nil, err
+		}
+		data = moved
+	}
+
+	u, err := r.ResourceInterface.Patch(ctx, name, pt, data, options)
+	if apierrs.IsNotFound(err) {
+		// The subject was deleted in the meantime.
+		return nil, nil
+	} else if err != nil {
+		recorder.failed(name, err)
+		return nil, err
+	}
+	return u, nil
+}
+
+// reconcileSubjects records the state of the binding on each of the
+// resources matched by its subject, once they have all been patched.
+func (s *SinkBindingSubResourcesReconciler) reconcileSubjects(ctx context.Context, sb *v1.SinkBinding) error {
+	subject := sb.GetSubject()
+	gv, err := schema.ParseGroupVersion(subject.APIVersion)
+	if err != nil {
+		return err
+	}
+	gvr := apis.KindToResource(gv.WithKind(subject.Kind))
+
+	_, lister, err := s.factory.Get(ctx, gvr)
+	if err != nil {
+		return err
+	}
+
+	var referents []*duckv1.WithPod
+	if subject.Name != "" {
+		psObj, err := lister.ByNamespace(subject.Namespace).Get(subject.Name)
+		if err != nil {
+			return fmt.Errorf("error fetching Pod Speccable %v: %w", subject, err)
+		}
+		referents = append(referents, psObj.(*duckv1.WithPod))
+	} else {
+		selector, err := metav1.LabelSelectorAsSelector(subject.Selector)
+		if err != nil {
+			return err
+		}
+		psObjs, err := lister.ByNamespace(subject.Namespace).List(selector)
+		if err != nil {
+			return fmt.Errorf("error fetching Pod Speccable %v: %w", subject, err)
+		}
+		for _, psObj := range psObjs {
+			referents = append(referents, psObj.(*duckv1.WithPod))
+		}
+	}
+
+	ctx = v1.WithURIResolver(ctx, s.res)
+	subjects := make([]v1.SinkBindingSubject, 0, len(referents))
+	for _, ps := range referents {
+		state, message := v1.SinkBindingSubjectBound, ""
+		if gvr.GroupResource() == jobsResource {
+			bound := ps.DeepCopy()
+			sb.DeepCopy().Do(ctx, bound)
+			if !equality.Semantic.DeepEqual(ps, bound) {
+				state, message = v1.SinkBindingSubjectStale, staleJobMessage
+			}
+		}
+		subjects = append(subjects, v1.SinkBindingSubject{
+			APIVersion: subject.APIVersion,
+			Kind:       subject.Kind,
+			Name:       ps.Name,
+			State:      state,
+			Message:    message,
+		})
+	}
+	sb.Status.PropagateSubjects(subjects)
+	return nil
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sinkbinding
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/client/injection/ducks/duck/v1/addressable"
+	fakedynamicclient "knative.dev/pkg/injection/clients/dynamicclient/fake"
+	"knative.dev/pkg/resolver"
+	"knative.dev/pkg/tracker"
+
+	v1 "knative.dev/eventing/pkg/apis/sources/v1"
+)
+
+func podTemplate(env ...corev1.EnvVar) corev1.PodTemplateSpec {
+	return corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:  "user",
+				Image: "image",
+				Env:   env,
+			}},
+		},
+	}
+}
+
+func withPod(name string, template corev1.PodTemplateSpec) *duckv1.WithPod {
+	return &duckv1.WithPod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+		Spec: duckv1.WithPodSpec{
+			Template: duckv1.PodSpecable(template),
+		},
+	}
+}
+
+func sinkBinding(apiVersion, kind string) *v1.SinkBinding {
+	return &v1.SinkBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "binding", Namespace: "ns"},
+		Spec: v1.SinkBindingSpec{
+			SourceSpec: duckv1.SourceSpec{
+				Sink: duckv1.Destination{URI: apis.HTTP("sink")},
+			},
+			BindingSpec: duckv1.BindingSpec{
+				Subject: tracker.Reference{
+					APIVersion: apiVersion,
+					Kind:       kind,
+					Namespace:  "ns",
+					Selector:   &metav1.LabelSelector{},
+				},
+			},
+		},
+	}
+}
+
+const envPatch = `[{"op":"add","path":"/spec/template/spec/containers/0/env","value":[{"name":"K_SINK","value":"http://sink"}]}]`
+
+func TestSubjectClientPatch(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "ns"},
+		Spec:       appsv1.DeploymentSpec{Template: podTemplate()},
+	}
+	cronJob := &batchv1beta1.CronJob{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "batch/v1beta1", Kind: "CronJob"},
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "ns"},
+		Spec: batchv1beta1.CronJobSpec{
+			JobTemplate: batchv1beta1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{Template: podTemplate()},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		gvr        schema.GroupVersionResource
+		kind       string
+		subject    string
+		patch      string
+		wantError  bool
+		wantFailed bool
+	}{{
+		name:    "deployment is patched",
+		gvr:     appsv1.SchemeGroupVersion.WithResource("deployments"),
+		kind:    "Deployment",
+		subject: "web",
+		patch:   envPatch,
+	}, {
+		name:    "deleted deployment is ignored",
+		gvr:     appsv1.SchemeGroupVersion.WithResource("deployments"),
+		kind:    "Deployment",
+		subject: "api",
+		patch:   envPatch,
+	}, {
+		name:       "failed deployment is recorded",
+		gvr:        appsv1.SchemeGroupVersion.WithResource("deployments"),
+		kind:       "Deployment",
+		subject:    "web",
+		patch:      `[{"op":"replace","path":"/spec/missing/field","value":1}]`,
+		wantError:  true,
+		wantFailed: true,
+	}, {
+		name:    "job is not patched",
+		gvr:     batchv1.SchemeGroupVersion.WithResource("jobs"),
+		kind:    "Job",
+		subject: "once",
+		patch:   envPatch,
+	}, {
+		name:    "cronjob job template is patched",
+		gvr:     batchv1beta1.SchemeGroupVersion.WithResource("cronjobs"),
+		kind:    "CronJob",
+		subject: "nightly",
+		patch:   envPatch,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dc := dynamicfake.NewSimpleDynamicClient(scheme.Scheme, deployment.DeepCopy(), cronJob.DeepCopy())
+			sb := sinkBinding(test.gvr.GroupVersion().String(), test.kind)
+			ctx := withSubjectRecorder(context.Background(), sb)
+
+			_, err := newSubjectClient(dc).Resource(test.gvr).Namespace("ns").Patch(
+				ctx, test.subject, types.JSONPatchType, []byte(test.patch), metav1.PatchOptions{})
+			if (err != nil) != test.wantError {
+				t.Errorf("Patch() = %v, want error %v", err, test.wantError)
+			}
+
+			var failed []string
+			for _, s := range sb.Status.Subjects {
+				if s.State == v1.SinkBindingSubjectFailed {
+					failed = append(failed, s.Name)
+				}
+			}
+			if got := len(failed) != 0; got != test.wantFailed {
+				t.Errorf("failed subjects = %v, want failed %v", failed, test.wantFailed)
+			}
+
+			if test.wantError || test.subject == "api" {
+				return
+			}
+			got, err := dc.Resource(test.gvr).Namespace("ns").Get(ctx, test.subject, metav1.GetOptions{})
+			if apierrs.IsNotFound(err) {
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			switch test.kind {
+			case "Deployment":
+				d := &appsv1.Deployment{}
+				if err := runtime.DefaultUnstructuredConverter.FromUnstructured(got.Object, d); err != nil {
+					t.Fatal(err)
+				}
+				if env := d.Spec.Template.Spec.Containers[0].Env; len(env) != 1 || env[0].Name != "K_SINK" {
+					t.Errorf("Deployment env = %v, want K_SINK", env)
+				}
+			case "CronJob":
+				cj := &batchv1beta1.CronJob{}
+				if err := runtime.DefaultUnstructuredConverter.FromUnstructured(got.Object, cj); err != nil {
+					t.Fatal(err)
+				}
+				if env := cj.Spec.JobTemplate.Spec.Template.Spec.Containers[0].Env; len(env) != 1 || env[0].Name != "K_SINK" {
+					t.Errorf("CronJob job template env = %v, want K_SINK", env)
+				}
+			}
+		})
+	}
+}
+
+type fakeInformerFactory struct {
+	objs []runtime.Object
+}
+
+func (f *fakeInformerFactory) Get(ctx context.Context, gvr schema.GroupVersionResource) (cache.SharedIndexInformer, cache.GenericLister, error) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, obj := range f.objs {
+		if err := indexer.Add(obj); err != nil {
+			return nil, nil, err
+		}
+	}
+	return nil, cache.NewGenericLister(indexer, gvr.GroupResource()), nil
+}
+
+func TestReconcileSubjects(t *testing.T) {
+	ctx, _ := fakedynamicclient.With(context.Background(), scheme.Scheme)
+	ctx = addressable.WithDuck(ctx)
+	res := resolver.NewURIResolverFromTracker(ctx, tracker.New(func(types.NamespacedName) {}, 0))
+
+	sb := sinkBinding("batch/v1", "Job")
+	bound := withPod("bound", podTemplate())
+	sb.Do(v1.WithURIResolver(ctx, res), bound)
+
+	s := &SinkBindingSubResourcesReconciler{
+		res:     res,
+		factory: &fakeInformerFactory{objs: []runtime.Object{withPod("old", podTemplate()), bound}},
+	}
+	if err := s.reconcileSubjects(ctx, sb); err != nil {
+		t.Fatal("reconcileSubjects() =", err)
+	}
+
+	want := []v1.SinkBindingSubject{{
+		APIVersion: "batch/v1", Kind: "Job", Name: "bound", State: v1.SinkBindingSubjectBound,
+	}, {
+		APIVersion: "batch/v1", Kind: "Job", Name: "old", State: v1.SinkBindingSubjectStale, Message: staleJobMessage,
+	}}
+	if !cmp.Equal(sb.Status.Subjects, want) {
+		t.Error("Subjects (-want, +got):", cmp.Diff(want, sb.Status.Subjects))
+	}
+}
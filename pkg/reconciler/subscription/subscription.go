@@ -518,7 +518,8 @@ func deliverySpec(sub *v1.Subscription, channel *eventingduckv1.Channelable) (de
 			channel.Spec.Delivery.Retry != nil ||
 			channel.Spec.Delivery.BackoffPolicy != nil ||
 			channel.Spec.Delivery.Timeout != nil ||
-			channel.Spec.Delivery.RetryAfterMax != nil {
+			channel.Spec.Delivery.RetryAfterMax != nil ||
+			channel.Spec.Delivery.Auth != nil {
 			if delivery == nil {
 				delivery = &eventingduckv1.DeliverySpec{}
 			}
@@ -527,6 +528,7 @@ func deliverySpec(sub *v1.Subscription, channel *eventingduckv1.Channelable) (de
 			delivery.BackoffDelay = channel.Spec.Delivery.BackoffDelay
 			delivery.Timeout = channel.Spec.Delivery.Timeout
 			delivery.RetryAfterMax = channel.Spec.Delivery.RetryAfterMax
+			delivery.Auth = channel.Spec.Delivery.Auth
 		}
 		return
 	}
@@ -545,7 +547,8 @@ func deliverySpec(sub *v1.Subscription, channel *eventingduckv1.Channelable) (de
 			sub.Spec.Delivery.Retry != nil ||
 			sub.Spec.Delivery.BackoffPolicy != nil ||
 			sub.Spec.Delivery.Timeout != nil ||
-			sub.Spec.Delivery.RetryAfterMax != nil) {
+			sub.Spec.Delivery.RetryAfterMax != nil ||
+			sub.Spec.Delivery.Auth != nil) {
 		if delivery == nil {
 			delivery = &eventingduckv1.DeliverySpec{}
 		}
@@ -554,6 +557,7 @@ func deliverySpec(sub *v1.Subscription, channel *eventingduckv1.Channelable) (de
 		delivery.BackoffDelay = sub.Spec.Delivery.BackoffDelay
 		delivery.Timeout = sub.Spec.Delivery.Timeout
 		delivery.RetryAfterMax = sub.Spec.Delivery.RetryAfterMax
+		delivery.Auth = sub.Spec.Delivery.Auth
 	}
 	return
 }
@@ -1494,6 +1494,7 @@ func TestAllCases(t *testing.T) {
 			Ctx: feature.ToContext(context.TODO(), feature.Flags{
 				feature.DeliveryTimeout:    feature.Enabled,
 				feature.DeliveryRetryAfter: feature.Enabled,
+				feature.DeliveryAuth:       feature.Enabled,
 			}),
 			Objects: []runtime.Object{
 				NewSubscription("a-"+subscriptionName, testNS,
@@ -1512,6 +1513,7 @@ func TestAllCases(t *testing.T) {
 					WithInMemoryChannelDelivery(&eventingduck.DeliverySpec{
 						Timeout:       pointer.StringPtr("PT1S"),
 						RetryAfterMax: pointer.StringPtr("PT2S"),
+						Auth:          &eventingduck.DeliveryAuth{Audience: pointer.StringPtr("subscriber")},
 					}),
 					WithInMemoryChannelStatusDLSURI(dlcURI),
 				),
@@ -1543,6 +1545,7 @@ func TestAllCases(t *testing.T) {
 						Delivery: &eventingduck.DeliverySpec{
 							Timeout:       pointer.StringPtr("PT1S"),
 							RetryAfterMax: pointer.StringPtr("PT2S"),
+							Auth:          &eventingduck.DeliveryAuth{Audience: pointer.StringPtr("subscriber")},
 						},
 					},
 				}),
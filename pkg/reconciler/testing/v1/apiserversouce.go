@@ -106,6 +106,19 @@ func WithApiServerSourceResourceModeEventTypes(source string) ApiServerSourceOpt
 	}
 }
 
+func WithApiServerSourceDiffModeEventTypes(source string) ApiServerSourceOption {
+	return func(s *v1.ApiServerSource) {
+		ceAttributes := make([]duckv1.CloudEventAttributes, 0, len(apisources.ApiServerSourceEventDiffModeTypes))
+		for _, apiServerSourceType := range apisources.ApiServerSourceEventDiffModeTypes {
+			ceAttributes = append(ceAttributes, duckv1.CloudEventAttributes{
+				Type:   apiServerSourceType,
+				Source: source,
+			})
+		}
+		s.Status.CloudEventAttributes = ceAttributes
+	}
+}
+
 func WithApiServerSourceSufficientPermissions(s *v1.ApiServerSource) {
 	s.Status.MarkSufficientPermissions()
 }
@@ -136,3 +149,25 @@ func WithApiServerSourceObjectMetaGeneration(generation int64) ApiServerSourceOp
 		c.ObjectMeta.Generation = generation
 	}
 }
+
+// WithApiServerSourceKubernetesEventResourceModeTypes adds the event types of the Kubernetes Events in the Resource mode.
+func WithApiServerSourceKubernetesEventResourceModeTypes(source string) ApiServerSourceOption {
+	return func(s *v1.ApiServerSource) {
+		for _, apiServerSourceType := range apisources.ApiServerSourceKubernetesEventResourceModeTypes {
+			s.Status.CloudEventAttributes = append(s.Status.CloudEventAttributes, duckv1.CloudEventAttributes{
+				Type:   apiServerSourceType,
+				Source: source,
+			})
+		}
+	}
+}
+
+// WithApiServerSourceSyncEventType adds the sync event type of the sources resuming their watches.
+func WithApiServerSourceSyncEventType(source string) ApiServerSourceOption {
+	return func(s *v1.ApiServerSource) {
+		s.Status.CloudEventAttributes = append(s.Status.CloudEventAttributes, duckv1.CloudEventAttributes{
+			Type:   apisources.ApiServerSourceSyncEventType,
+			Source: source,
+		})
+	}
+}
@@ -22,6 +22,7 @@ import (
 	v1 "knative.dev/eventing/pkg/apis/sources/v1"
 
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -61,6 +62,22 @@ func WithContainerSourcePropagateReceiveAdapterStatus(d *appsv1.Deployment) Cont
 	}
 }
 
+func WithContainerSourcePropagatePodStatuses(pods ...*corev1.Pod) ContainerSourceOption {
+	return func(s *v1.ContainerSource) {
+		s.Status.PropagatePodStatuses(pods)
+	}
+}
+
+func WithContainerSourceEventsSent(s *v1.ContainerSource) {
+	s.Status.MarkEventsSent()
+}
+
+func WithContainerSourceEventsNotSent(reason, messageFormat string, messageA ...interface{}) ContainerSourceOption {
+	return func(s *v1.ContainerSource) {
+		s.Status.MarkEventsNotSent(reason, messageFormat, messageA...)
+	}
+}
+
 func WithContainerSourcePropagateSinkbindingStatus(status *v1.SinkBindingStatus) ContainerSourceOption {
 	return func(s *v1.ContainerSource) {
 		s.Status.PropagateSinkBindingStatus(status)
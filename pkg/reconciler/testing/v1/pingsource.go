@@ -68,6 +68,22 @@ func WithPingSourceSink(uri *apis.URL) PingSourceOption {
 	}
 }
 
+func WithPingSourceDeadLetterSinkNotConfigured(s *v1.PingSource) {
+	s.Status.MarkDeadLetterSinkNotConfigured()
+}
+
+func WithPingSourceDeadLetterSinkResolved(uri *apis.URL) PingSourceOption {
+	return func(s *v1.PingSource) {
+		s.Status.MarkDeadLetterSinkResolvedSucceeded(uri)
+	}
+}
+
+func WithPingSourceDeadLetterSinkResolvedFailed(reason, message string) PingSourceOption {
+	return func(s *v1.PingSource) {
+		s.Status.MarkDeadLetterSinkResolvedFailed(reason, message)
+	}
+}
+
 func WithPingSourceDeployed(s *v1.PingSource) {
 	s.Status.PropagateDeploymentAvailability(testing.NewDeployment("any", "any", testing.WithDeploymentAvailable()))
 }
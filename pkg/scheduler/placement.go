@@ -17,6 +17,8 @@ limitations under the License.
 package scheduler
 
 import (
+	"strconv"
+
 	"k8s.io/apimachinery/pkg/util/sets"
 	duckv1alpha1 "knative.dev/eventing/pkg/apis/duck/v1alpha1"
 )
@@ -50,3 +52,16 @@ func GetPodCount(placements []duckv1alpha1.Placement) int {
 	}
 	return set.Len()
 }
+
+// GetVReplicaCost returns the cost of each vreplica of vpod, as given by its
+// VReplicaCostAnnotationKey annotation. Defaults to 1.
+func GetVReplicaCost(vpod VPod) int64 {
+	if annotated, ok := vpod.(interface{ GetAnnotations() map[string]string }); ok {
+		if value, ok := annotated.GetAnnotations()[VReplicaCostAnnotationKey]; ok {
+			if cost, err := strconv.ParseInt(value, 10, 64); err == nil && cost > 0 {
+				return cost
+			}
+		}
+	}
+	return 1
+}
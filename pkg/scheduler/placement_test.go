@@ -154,3 +154,50 @@ func TestPodCount(t *testing.T) {
 		})
 	}
 }
+
+type costVPod struct {
+	VPod
+	annotations map[string]string
+}
+
+func (v *costVPod) GetAnnotations() map[string]string {
+	return v.annotations
+}
+
+func TestGetVReplicaCost(t *testing.T) {
+	testCases := []struct {
+		name     string
+		vpod     VPod
+		expected int64
+	}{
+		{
+			name:     "no annotations",
+			vpod:     &costVPod{},
+			expected: 1,
+		},
+		{
+			name:     "cost annotation",
+			vpod:     &costVPod{annotations: map[string]string{VReplicaCostAnnotationKey: "5"}},
+			expected: 5,
+		},
+		{
+			name:     "invalid cost annotation",
+			vpod:     &costVPod{annotations: map[string]string{VReplicaCostAnnotationKey: "a lot"}},
+			expected: 1,
+		},
+		{
+			name:     "negative cost annotation",
+			vpod:     &costVPod{annotations: map[string]string{VReplicaCostAnnotationKey: "-2"}},
+			expected: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := GetVReplicaCost(tc.vpod)
+			if got != tc.expected {
+				t.Errorf("got %v, want %v", got, tc.expected)
+			}
+		})
+	}
+}
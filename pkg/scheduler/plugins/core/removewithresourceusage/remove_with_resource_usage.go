@@ -0,0 +1,97 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package removewithresourceusage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/eventing/pkg/scheduler/factory"
+	state "knative.dev/eventing/pkg/scheduler/state"
+	"knative.dev/pkg/logging"
+)
+
+// RemoveWithResourceUsage is a score plugin that favors removing vreplicas from pods with the
+// highest load, measured as the ResourceUsage plugins do
+type RemoveWithResourceUsage struct {
+}
+
+// Verify RemoveWithResourceUsage Implements ScorePlugin Interface
+var _ state.ScorePlugin = &RemoveWithResourceUsage{}
+
+// Name of the plugin
+const (
+	Name                = state.RemoveWithResourceUsage
+	ErrReasonInvalidArg = "invalid arguments"
+)
+
+func init() {
+	factory.RegisterSP(Name, &RemoveWithResourceUsage{})
+}
+
+// Name returns name of the plugin
+func (pl *RemoveWithResourceUsage) Name() string {
+	return Name
+}
+
+// Score invoked at the score extension point. The "score" returned in this function is higher for pods with a higher load.
+func (pl *RemoveWithResourceUsage) Score(ctx context.Context, args interface{}, states *state.State, feasiblePods []int32, key types.NamespacedName, podID int32) (uint64, *state.Status) {
+	logger := logging.FromContext(ctx).With("Score", pl.Name())
+
+	usageArgs, err := decodeArgs(args)
+	if err != nil {
+		logger.Errorf("Scoring args %v for priority %q are not valid", args, pl.Name())
+		return 0, state.NewStatus(state.Unschedulable, ErrReasonInvalidArg)
+	}
+
+	podName := state.PodNameFromOrdinal(states.StatefulSetName, podID)
+	load, err := states.PodLoad(ctx, podName, usageArgs)
+	if err != nil {
+		logger.Errorf("Scoring args %v for priority %q are not valid: %v", args, pl.Name(), err)
+		return 0, state.NewStatus(state.Unschedulable, ErrReasonInvalidArg)
+	}
+
+	score := uint64(load) //higher loads get higher score
+	return score, state.NewStatus(state.Success)
+}
+
+// ScoreExtensions of the Score plugin. Scores do not need to be normalized.
+func (pl *RemoveWithResourceUsage) ScoreExtensions() state.ScoreExtensions {
+	return nil
+}
+
+func decodeArgs(args interface{}) (*state.ResourceUsageArgs, error) {
+	usageArgs := &state.ResourceUsageArgs{}
+	if args == nil {
+		return usageArgs, nil
+	}
+
+	rawArgs, ok := args.(string)
+	if !ok {
+		return nil, errors.New(ErrReasonInvalidArg)
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(rawArgs))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(usageArgs); err != nil {
+		return nil, err
+	}
+	return usageArgs, nil
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceusage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/eventing/pkg/scheduler/factory"
+	state "knative.dev/eventing/pkg/scheduler/state"
+	"knative.dev/pkg/logging"
+)
+
+// ResourceUsage is a filter or score plugin that picks/favors pods with the lowest load, where
+// vreplicas are weighted by the cost of their vpod and the observed pod CPU and memory usage is
+// taken into account
+type ResourceUsage struct {
+}
+
+// Verify ResourceUsage Implements FilterPlugin and ScorePlugin Interface
+var _ state.FilterPlugin = &ResourceUsage{}
+var _ state.ScorePlugin = &ResourceUsage{}
+
+// Name of the plugin
+const (
+	Name                   = state.ResourceUsage
+	ErrReasonInvalidArg    = "invalid arguments"
+	ErrReasonUnschedulable = "pod at full load"
+)
+
+func init() {
+	factory.RegisterFP(Name, &ResourceUsage{})
+	factory.RegisterSP(Name, &ResourceUsage{})
+}
+
+// Name returns name of the plugin
+func (pl *ResourceUsage) Name() string {
+	return Name
+}
+
+// Filter invoked at the filter extension point.
+func (pl *ResourceUsage) Filter(ctx context.Context, args interface{}, states *state.State, key types.NamespacedName, podID int32) *state.Status {
+	logger := logging.FromContext(ctx).With("Filter", pl.Name())
+
+	usageArgs, err := decodeArgs(args)
+	if err != nil {
+		logger.Errorf("Filter args %v for predicate %q are not valid", args, pl.Name())
+		return state.NewStatus(state.Unschedulable, ErrReasonInvalidArg)
+	}
+
+	if usageArgs.MaxCost <= 0 { //no limit
+		return state.NewStatus(state.Success)
+	}
+
+	podName := state.PodNameFromOrdinal(states.StatefulSetName, podID)
+	load, err := states.PodLoad(ctx, podName, usageArgs)
+	if err != nil {
+		logger.Errorf("Filter args %v for predicate %q are not valid: %v", args, pl.Name(), err)
+		return state.NewStatus(state.Unschedulable, ErrReasonInvalidArg)
+	}
+
+	if load+states.VReplicaCost(key) > usageArgs.MaxCost {
+		logger.Infof("Unschedulable! Pod %d with load %d cannot hold a vreplica of cost %d", podID, load, states.VReplicaCost(key))
+		return state.NewStatus(state.Unschedulable, ErrReasonUnschedulable)
+	}
+
+	return state.NewStatus(state.Success)
+}
+
+// Score invoked at the score extension point. The "score" returned in this function is higher for pods with a lower load.
+func (pl *ResourceUsage) Score(ctx context.Context, args interface{}, states *state.State, feasiblePods []int32, key types.NamespacedName, podID int32) (uint64, *state.Status) {
+	logger := logging.FromContext(ctx).With("Score", pl.Name())
+
+	usageArgs, err := decodeArgs(args)
+	if err != nil {
+		logger.Errorf("Scoring args %v for priority %q are not valid", args, pl.Name())
+		return 0, state.NewStatus(state.Unschedulable, ErrReasonInvalidArg)
+	}
+
+	podName := state.PodNameFromOrdinal(states.StatefulSetName, podID)
+	load, err := states.PodLoad(ctx, podName, usageArgs)
+	if err != nil {
+		logger.Errorf("Scoring args %v for priority %q are not valid: %v", args, pl.Name(), err)
+		return 0, state.NewStatus(state.Unschedulable, ErrReasonInvalidArg)
+	}
+
+	score := math.MaxUint64 - uint64(load) //lesser loads get higher score
+	return score, state.NewStatus(state.Success)
+}
+
+// ScoreExtensions of the Score plugin. Scores do not need to be normalized.
+func (pl *ResourceUsage) ScoreExtensions() state.ScoreExtensions {
+	return nil
+}
+
+func decodeArgs(args interface{}) (*state.ResourceUsageArgs, error) {
+	usageArgs := &state.ResourceUsageArgs{}
+	if args == nil {
+		return usageArgs, nil
+	}
+
+	rawArgs, ok := args.(string)
+	if !ok {
+		return nil, errors.New(ErrReasonInvalidArg)
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(rawArgs))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(usageArgs); err != nil {
+		return nil, err
+	}
+	return usageArgs, nil
+}
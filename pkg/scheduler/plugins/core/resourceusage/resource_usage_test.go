@@ -0,0 +1,179 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceusage
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	state "knative.dev/eventing/pkg/scheduler/state"
+	tscheduler "knative.dev/eventing/pkg/scheduler/testing"
+)
+
+type podMetrics map[string]state.PodUsage
+
+func (m podMetrics) PodUsage(ctx context.Context, podName string) (state.PodUsage, bool) {
+	usage, ok := m[podName]
+	return usage, ok
+}
+
+func TestFilterAndScore(t *testing.T) {
+	hot := types.NamespacedName{Name: "vpod-hot", Namespace: "vpod-ns"}
+	idle := types.NamespacedName{Name: "vpod-idle", Namespace: "vpod-ns"}
+
+	spread := map[types.NamespacedName]map[string]int32{
+		hot:  {"pod-name-0": 2},
+		idle: {"pod-name-0": 1, "pod-name-1": 4},
+	}
+	costs := map[types.NamespacedName]int64{hot: 5, idle: 1}
+
+	testCases := []struct {
+		name       string
+		state      *state.State
+		vpod       types.NamespacedName
+		podID      int32
+		expScore   uint64
+		expected   *state.Status
+		onlyFilter bool
+		args       interface{}
+	}{
+		{
+			name:     "no vpods, no pods",
+			vpod:     types.NamespacedName{},
+			state:    &state.State{StatefulSetName: "pod-name", PodSpread: map[types.NamespacedName]map[string]int32{}},
+			podID:    0,
+			expected: state.NewStatus(state.Success),
+			expScore: math.MaxUint64,
+			args:     "{\"MaxCost\": 10}",
+		},
+		{
+			name:     "bad arg",
+			vpod:     types.NamespacedName{},
+			state:    &state.State{StatefulSetName: "pod-name", PodSpread: map[types.NamespacedName]map[string]int32{}},
+			podID:    0,
+			expected: state.NewStatus(state.Unschedulable, ErrReasonInvalidArg),
+			expScore: 0,
+			args:     "{\"MaxLoad\": 10}",
+		},
+		{
+			name:     "bad quantity",
+			vpod:     idle,
+			state:    &state.State{StatefulSetName: "pod-name", PodSpread: spread, VReplicaCosts: costs, PodMetrics: podMetrics{"pod-name-0": {}}},
+			podID:    0,
+			expected: state.NewStatus(state.Unschedulable, ErrReasonInvalidArg),
+			expScore: 0,
+			args:     "{\"MaxCost\": 20, \"CPUPerCost\": \"lots\"}",
+		},
+		{
+			name:     "vreplicas weighted by cost",
+			vpod:     idle,
+			state:    &state.State{StatefulSetName: "pod-name", PodSpread: spread, VReplicaCosts: costs},
+			podID:    0,
+			expected: state.NewStatus(state.Success),
+			expScore: math.MaxUint64 - 11,
+			args:     "{\"MaxCost\": 12}",
+		},
+		{
+			name:       "vreplica cost exceeds max cost",
+			vpod:       hot,
+			state:      &state.State{StatefulSetName: "pod-name", PodSpread: spread, VReplicaCosts: costs},
+			podID:      0,
+			expected:   state.NewStatus(state.Unschedulable, ErrReasonUnschedulable),
+			onlyFilter: true,
+			args:       "{\"MaxCost\": 12}",
+		},
+		{
+			name:     "no max cost",
+			vpod:     hot,
+			state:    &state.State{StatefulSetName: "pod-name", PodSpread: spread, VReplicaCosts: costs},
+			podID:    0,
+			expected: state.NewStatus(state.Success),
+			expScore: math.MaxUint64 - 11,
+			args:     nil,
+		},
+		{
+			name: "observed cpu usage above cost",
+			vpod: idle,
+			state: &state.State{StatefulSetName: "pod-name", PodSpread: spread, VReplicaCosts: costs,
+				PodMetrics: podMetrics{"pod-name-1": {CPU: resource.MustParse("1500m"), Memory: resource.MustParse("64Mi")}}},
+			podID:      1,
+			expected:   state.NewStatus(state.Unschedulable, ErrReasonUnschedulable),
+			onlyFilter: true,
+			args:       "{\"MaxCost\": 15, \"CPUPerCost\": \"100m\", \"MemoryPerCost\": \"64Mi\"}",
+		},
+		{
+			name: "observed memory usage above cost",
+			vpod: idle,
+			state: &state.State{StatefulSetName: "pod-name", PodSpread: spread, VReplicaCosts: costs,
+				PodMetrics: podMetrics{"pod-name-1": {CPU: resource.MustParse("100m"), Memory: resource.MustParse("640Mi")}}},
+			podID:    1,
+			expected: state.NewStatus(state.Success),
+			expScore: math.MaxUint64 - 10,
+			args:     "{\"MaxCost\": 15, \"CPUPerCost\": \"100m\", \"MemoryPerCost\": \"64Mi\"}",
+		},
+		{
+			name: "observed usage ignored without usage per cost",
+			vpod: idle,
+			state: &state.State{StatefulSetName: "pod-name", PodSpread: spread, VReplicaCosts: costs,
+				PodMetrics: podMetrics{"pod-name-1": {CPU: resource.MustParse("1500m"), Memory: resource.MustParse("640Mi")}}},
+			podID:    1,
+			expected: state.NewStatus(state.Success),
+			expScore: math.MaxUint64 - 4,
+			args:     "{\"MaxCost\": 15}",
+		},
+		{
+			name: "no observed usage",
+			vpod: idle,
+			state: &state.State{StatefulSetName: "pod-name", PodSpread: spread, VReplicaCosts: costs,
+				PodMetrics: podMetrics{}},
+			podID:    1,
+			expected: state.NewStatus(state.Success),
+			expScore: math.MaxUint64 - 4,
+			args:     "{\"MaxCost\": 15, \"CPUPerCost\": \"100m\"}",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, _ := tscheduler.SetupFakeContext(t)
+			var plugin = &ResourceUsage{}
+
+			name := plugin.Name()
+			assert.Equal(t, name, state.ResourceUsage)
+
+			status := plugin.Filter(ctx, tc.args, tc.state, tc.vpod, tc.podID)
+			if !reflect.DeepEqual(status, tc.expected) {
+				t.Errorf("unexpected status, got %v, want %v", status, tc.expected)
+			}
+
+			if !tc.onlyFilter {
+				score, status := plugin.Score(ctx, tc.args, tc.state, tc.state.SchedulablePods, tc.vpod, tc.podID)
+				if !reflect.DeepEqual(status, tc.expected) {
+					t.Errorf("unexpected status, got %v, want %v", status, tc.expected)
+				}
+				if score != tc.expScore {
+					t.Errorf("unexpected score, got %v, want %v", score, tc.expScore)
+				}
+			}
+		})
+	}
+}
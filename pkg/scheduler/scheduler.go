@@ -18,6 +18,7 @@ package scheduler
 
 import (
 	"errors"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -38,6 +39,10 @@ const (
 	// PodAnnotationKey is an annotation used by the scheduler to be informed of pods
 	// being evicted and not use it for placing vreplicas
 	PodAnnotationKey = "eventing.knative.dev/unschedulable"
+
+	// VReplicaCostAnnotationKey is an annotation on VPods giving the relative cost
+	// of each of their vreplicas, for instance their expected throughput
+	VReplicaCostAnnotationKey = "eventing.knative.dev/vreplica-cost"
 )
 
 const (
@@ -57,6 +62,22 @@ type SchedulerPolicy struct {
 	Predicates []PredicatePolicy
 	// Holds the information to configure the priority functions.
 	Priorities []PriorityPolicy
+	// Rebalancer enables the rebalancer of the statefulset scheduler. It is
+	// only used in the scheduling policy and only when the scheduling policy
+	// configures the AvailabilityZonePriority priority.
+	Rebalancer *RebalancerPolicy
+}
+
+// RebalancerPolicy configures the rebalancer moving vreplicas back toward an
+// even spread across zones.
+type RebalancerPolicy struct {
+	// DryRun only logs the evictions the rebalancer would do.
+	DryRun bool
+	// MaxMoves is the maximum number of evictions per period. Defaults to 10.
+	MaxMoves int
+	// Period is how often the rebalancer looks for skewed vpods. Defaults to
+	// the refresh period of the scheduler.
+	Period time.Duration
 }
 
 // PredicatePolicy describes a struct of a predicate policy.
@@ -35,6 +35,8 @@ const (
 	RemoveWithAvailabilityNodePriority = "RemoveWithAvailabilityNodePriority"
 	RemoveWithAvailabilityZonePriority = "RemoveWithAvailabilityZonePriority"
 	RemoveWithHighestOrdinalPriority   = "RemoveWithHighestOrdinalPriority"
+	RemoveWithResourceUsage            = "RemoveWithResourceUsage"
+	ResourceUsage                      = "ResourceUsage"
 )
 
 // Plugin is the parent type for all the scheduling framework plugins.
@@ -88,6 +90,18 @@ type AvailabilityNodePriorityArgs struct {
 	MaxSkew int32
 }
 
+// ResourceUsageArgs holds arguments used to configure the ResourceUsage plugin.
+type ResourceUsageArgs struct {
+	// MaxCost is the maximum load of a pod, in cost units. Only used for filtering.
+	MaxCost int64
+	// CPUPerCost is the CPU usage expected for a cost of 1 (for example "100m").
+	// Observed CPU usage is ignored when empty.
+	CPUPerCost string
+	// MemoryPerCost is the memory usage expected for a cost of 1 (for example "64Mi").
+	// Observed memory usage is ignored when empty.
+	MemoryPerCost string
+}
+
 // Code is the Status code/type which is returned from plugins.
 type Code int
 
@@ -0,0 +1,226 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	"knative.dev/pkg/logging"
+)
+
+// PodUsage is the resource usage observed for a pod.
+type PodUsage struct {
+	CPU    resource.Quantity
+	Memory resource.Quantity
+}
+
+// PodMetricsSource provides the resource usage observed for the statefulset pods.
+type PodMetricsSource interface {
+	// PodUsage returns the resource usage of the given pod, and false when it is unknown.
+	PodUsage(ctx context.Context, podName string) (PodUsage, bool)
+}
+
+type podMetricsSourceKey struct{}
+
+// WithPodMetricsSource attaches the source of pod resource usage the scheduler state
+// is built with to the context.
+func WithPodMetricsSource(ctx context.Context, source PodMetricsSource) context.Context {
+	return context.WithValue(ctx, podMetricsSourceKey{}, source)
+}
+
+// GetPodMetricsSource returns the source of pod resource usage attached to the context, if any.
+func GetPodMetricsSource(ctx context.Context) PodMetricsSource {
+	source, _ := ctx.Value(podMetricsSourceKey{}).(PodMetricsSource)
+	return source
+}
+
+// apiPodMetricsSource reads pod resource usage from the metrics.k8s.io API. The
+// usage is refreshed in the background, so that the plugins reading it never
+// wait for the API.
+type apiPodMetricsSource struct {
+	logger        *zap.SugaredLogger
+	restClient    rest.Interface
+	namespace     string
+	refreshPeriod time.Duration
+
+	lock  sync.RWMutex
+	usage map[string]PodUsage
+}
+
+// NewPodMetricsSource returns a PodMetricsSource reading the resource usage of the pods in
+// namespace from the metrics.k8s.io API every refreshPeriod, until ctx is done. The usage of
+// all pods is unknown until the first refresh.
+func NewPodMetricsSource(ctx context.Context, namespace string, refreshPeriod time.Duration) PodMetricsSource {
+	source := newAPIPodMetricsSource(ctx, kubeclient.Get(ctx).CoreV1().RESTClient(), namespace, refreshPeriod)
+	go source.Start(ctx)
+	return source
+}
+
+func newAPIPodMetricsSource(ctx context.Context, restClient rest.Interface, namespace string, refreshPeriod time.Duration) *apiPodMetricsSource {
+	return &apiPodMetricsSource{
+		logger:        logging.FromContext(ctx),
+		restClient:    restClient,
+		namespace:     namespace,
+		refreshPeriod: refreshPeriod,
+	}
+}
+
+// Start refreshes the pod usage every refresh period until ctx is done.
+func (s *apiPodMetricsSource) Start(ctx context.Context) {
+	for {
+		s.refresh(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.refreshPeriod):
+		}
+	}
+}
+
+// refresh reads the pod usage from the API. On errors, the last known usage
+// is kept.
+func (s *apiPodMetricsSource) refresh(ctx context.Context) {
+	usage, err := s.fetch(ctx)
+	if err != nil {
+		s.logger.Infow("failed to get pod metrics", zap.Error(err))
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.usage = usage
+}
+
+// podMetricsList is the subset of the metrics.k8s.io PodMetricsList used by the scheduler.
+type podMetricsList struct {
+	Items []struct {
+		metav1.ObjectMeta `json:"metadata"`
+		Containers        []struct {
+			Usage v1.ResourceList `json:"usage"`
+		} `json:"containers"`
+	} `json:"items"`
+}
+
+func (s *apiPodMetricsSource) PodUsage(ctx context.Context, podName string) (PodUsage, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	usage, ok := s.usage[podName]
+	return usage, ok
+}
+
+func (s *apiPodMetricsSource) fetch(ctx context.Context) (map[string]PodUsage, error) {
+	body, err := s.restClient.Get().AbsPath("/apis/metrics.k8s.io/v1beta1/namespaces", s.namespace, "pods").DoRaw(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list := podMetricsList{}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("failed to decode pod metrics: %w", err)
+	}
+
+	usage := make(map[string]PodUsage, len(list.Items))
+	for _, item := range list.Items {
+		podUsage := PodUsage{}
+		for _, container := range item.Containers {
+			podUsage.CPU.Add(*container.Usage.Cpu())
+			podUsage.Memory.Add(*container.Usage.Memory())
+		}
+		usage[item.Name] = podUsage
+	}
+	return usage, nil
+}
+
+// PodCost returns the total cost of the vreplicas placed on the given pod.
+func (s *State) PodCost(podName string) int64 {
+	if s.podCosts == nil {
+		// Computed once per state, for all pods.
+		s.podCosts = make(map[string]int64)
+		for key, spread := range s.PodSpread {
+			for name, vreplicas := range spread {
+				s.podCosts[name] += int64(vreplicas) * s.VReplicaCost(key)
+			}
+		}
+	}
+	return s.podCosts[podName]
+}
+
+// VReplicaCost returns the cost of each vreplica of the given vpod.
+func (s *State) VReplicaCost(key types.NamespacedName) int64 {
+	if cost, ok := s.VReplicaCosts[key]; ok {
+		return cost
+	}
+	return 1
+}
+
+// PodLoad returns the load of the given pod in cost units: the largest of the cost
+// of the vreplicas placed on it and of its observed CPU and memory usage divided by
+// the usage expected per cost unit, plus the cost of the vreplicas pending on it.
+func (s *State) PodLoad(ctx context.Context, podName string, args *ResourceUsageArgs) (int64, error) {
+	load := s.PodCost(podName)
+	if s.PodMetrics == nil || (args.CPUPerCost == "" && args.MemoryPerCost == "") {
+		return load, nil
+	}
+
+	usage, ok := s.PodMetrics.PodUsage(ctx, podName)
+	if !ok {
+		return load, nil
+	}
+	// The observed usage does not reflect the vreplicas placed during the current
+	// scheduling pass yet.
+	pending := s.PendingCosts[podName]
+
+	if args.CPUPerCost != "" {
+		cpuPerCost, err := resource.ParseQuantity(args.CPUPerCost)
+		if err != nil || cpuPerCost.MilliValue() <= 0 {
+			return 0, fmt.Errorf("invalid CPUPerCost %q", args.CPUPerCost)
+		}
+		if l := ceilDiv(usage.CPU.MilliValue(), cpuPerCost.MilliValue()) + pending; l > load {
+			load = l
+		}
+	}
+
+	if args.MemoryPerCost != "" {
+		memoryPerCost, err := resource.ParseQuantity(args.MemoryPerCost)
+		if err != nil || memoryPerCost.Value() <= 0 {
+			return 0, fmt.Errorf("invalid MemoryPerCost %q", args.MemoryPerCost)
+		}
+		if l := ceilDiv(usage.Memory.Value(), memoryPerCost.Value()) + pending; l > load {
+			load = l
+		}
+	}
+
+	return load, nil
+}
+
+func ceilDiv(a, b int64) int64 {
+	return (a + b - 1) / b
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package state
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+
+	tscheduler "knative.dev/eventing/pkg/scheduler/testing"
+)
+
+const podMetricsResponse = `{
+  "kind": "PodMetricsList",
+  "apiVersion": "metrics.k8s.io/v1beta1",
+  "items": [
+    {
+      "metadata": {"name": "statefulset-name-0", "namespace": "test-ns"},
+      "containers": [
+        {"name": "adapter", "usage": {"cpu": "250m", "memory": "64Mi"}},
+        {"name": "sidecar", "usage": {"cpu": "50m", "memory": "16Mi"}}
+      ]
+    }
+  ]
+}`
+
+func TestAPIPodMetricsSource(t *testing.T) {
+	ctx, _ := tscheduler.SetupFakeContext(t)
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.URL.Path != "/apis/metrics.k8s.io/v1beta1/namespaces/test-ns/pods" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(podMetricsResponse))
+	}))
+	defer server.Close()
+
+	baseURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	restClient, err := rest.NewRESTClient(baseURL, "", rest.ClientContentConfig{}, nil, server.Client())
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+
+	source := newAPIPodMetricsSource(ctx, restClient, testNs, time.Hour)
+
+	// The usage is unknown until refreshed, and reading it never requests the API.
+	if _, ok := source.PodUsage(ctx, "statefulset-name-0"); ok {
+		t.Error("unexpected usage before the first refresh")
+	}
+	if got := atomic.LoadInt32(&requests); got != 0 {
+		t.Errorf("unexpected number of requests, got %d, want 0", got)
+	}
+
+	startCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		source.Start(startCtx)
+		close(done)
+	}()
+
+	var usage PodUsage
+	if err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		var ok bool
+		usage, ok = source.PodUsage(ctx, "statefulset-name-0")
+		return ok, nil
+	}); err != nil {
+		t.Fatal("expected usage for statefulset-name-0:", err)
+	}
+	cancel()
+	<-done
+
+	if want := resource.MustParse("300m"); usage.CPU.Cmp(want) != 0 {
+		t.Errorf("unexpected cpu usage, got %v, want %v", usage.CPU.String(), want.String())
+	}
+	if want := resource.MustParse("80Mi"); usage.Memory.Cmp(want) != 0 {
+		t.Errorf("unexpected memory usage, got %v, want %v", usage.Memory.String(), want.String())
+	}
+
+	if _, ok := source.PodUsage(ctx, "statefulset-name-1"); ok {
+		t.Error("unexpected usage for statefulset-name-1")
+	}
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("unexpected number of requests, got %d, want 1", got)
+	}
+}
+
+type podMetrics map[string]PodUsage
+
+func (m podMetrics) PodUsage(ctx context.Context, podName string) (PodUsage, bool) {
+	usage, ok := m[podName]
+	return usage, ok
+}
+
+func TestPodLoad(t *testing.T) {
+	hot := types.NamespacedName{Name: "vpod-hot", Namespace: "vpod-ns"}
+	idle := types.NamespacedName{Name: "vpod-idle", Namespace: "vpod-ns"}
+
+	state := &State{
+		PodSpread: map[types.NamespacedName]map[string]int32{
+			hot:  {"statefulset-name-0": 2},
+			idle: {"statefulset-name-0": 1, "statefulset-name-1": 3, "statefulset-name-2": 2},
+		},
+		VReplicaCosts: map[types.NamespacedName]int64{hot: 4},
+		PodMetrics: podMetrics{
+			"statefulset-name-1": {CPU: resource.MustParse("450m"), Memory: resource.MustParse("32Mi")},
+			"statefulset-name-2": {CPU: resource.MustParse("450m")},
+		},
+		PendingCosts: map[string]int64{"statefulset-name-2": 2},
+	}
+
+	testCases := []struct {
+		name    string
+		podName string
+		args    ResourceUsageArgs
+		want    int64
+		wantErr bool
+	}{
+		{
+			name:    "cost of placed vreplicas",
+			podName: "statefulset-name-0",
+			args:    ResourceUsageArgs{CPUPerCost: "100m"},
+			want:    9,
+		},
+		{
+			name:    "observed usage ignored",
+			podName: "statefulset-name-1",
+			args:    ResourceUsageArgs{},
+			want:    3,
+		},
+		{
+			name:    "observed cpu usage rounded up",
+			podName: "statefulset-name-1",
+			args:    ResourceUsageArgs{CPUPerCost: "100m", MemoryPerCost: "64Mi"},
+			want:    5,
+		},
+		{
+			name:    "pending vreplicas added to observed usage",
+			podName: "statefulset-name-2",
+			args:    ResourceUsageArgs{CPUPerCost: "100m"},
+			want:    7,
+		},
+		{
+			name:    "invalid memory per cost",
+			podName: "statefulset-name-1",
+			args:    ResourceUsageArgs{MemoryPerCost: "0"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := state.PodLoad(context.Background(), tc.podName, &tc.args)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("unexpected load, got %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
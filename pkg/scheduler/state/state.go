@@ -93,6 +93,20 @@ type State struct {
 
 	// Stores for each vpod, a map of zonename to total number of vreplicas placed on all pods located in that zone currently
 	ZoneSpread map[types.NamespacedName]map[string]int32
+
+	// Stores for each vpod, the cost of each of its vreplicas
+	VReplicaCosts map[types.NamespacedName]int64
+
+	// PodMetrics provides the observed resource usage of pods, if any
+	PodMetrics PodMetricsSource
+
+	// Stores for each pod, the cost of the vreplicas placed on it during the current scheduling
+	// pass and not committed to their vpods yet, hence not reflected by the observed usage.
+	// Nil when there are none.
+	PendingCosts map[string]int64
+
+	// podCosts caches the cost of the vreplicas placed on each pod.
+	podCosts map[string]int64
 }
 
 // Free safely returns the free capacity at the given ordinal
@@ -257,10 +271,24 @@ func (s *stateBuilder) State(reserved map[types.NamespacedName]map[string]int32)
 		}
 	}
 
+	vreplicaCosts := make(map[types.NamespacedName]int64, len(vpods))
+	var pendingCosts map[string]int64
+	addPendingCost := func(key types.NamespacedName, podName string, vreplicas int32) {
+		if vreplicas <= 0 {
+			return
+		}
+		if pendingCosts == nil {
+			pendingCosts = make(map[string]int64)
+		}
+		pendingCosts[podName] += int64(vreplicas) * vreplicaCosts[key]
+	}
+
 	// Getting current state from existing placements for all vpods
 	for _, vpod := range vpods {
 		ps := vpod.GetPlacements()
 
+		vreplicaCosts[vpod.GetKey()] = scheduler.GetVReplicaCost(vpod)
+
 		withPlacement[vpod.GetKey()] = make(map[string]bool)
 		podSpread[vpod.GetKey()] = make(map[string]int32)
 		nodeSpread[vpod.GetKey()] = make(map[string]int32)
@@ -272,6 +300,7 @@ func (s *stateBuilder) State(reserved map[types.NamespacedName]map[string]int32)
 
 			// Account for reserved vreplicas
 			vreplicas = withReserved(vpod.GetKey(), podName, vreplicas, reserved)
+			addPendingCost(vpod.GetKey(), podName, vreplicas-ps[i].VReplicas)
 
 			free, last = s.updateFreeCapacity(free, last, podName, vreplicas)
 
@@ -314,6 +343,7 @@ func (s *stateBuilder) State(reserved map[types.NamespacedName]map[string]int32)
 					podSpread[key][podName] = podSpread[key][podName] + rvreplicas
 					nodeSpread[key][nodeName] = nodeSpread[key][nodeName] + rvreplicas
 					zoneSpread[key][zoneName] = zoneSpread[key][zoneName] + rvreplicas
+					addPendingCost(key, podName, rvreplicas)
 				}
 			}
 
@@ -324,7 +354,7 @@ func (s *stateBuilder) State(reserved map[types.NamespacedName]map[string]int32)
 	s.logger.Infow("cluster state info", zap.String("NumPods", fmt.Sprint(scale.Spec.Replicas)), zap.String("NumZones", fmt.Sprint(len(zoneMap))), zap.String("NumNodes", fmt.Sprint(len(nodeToZoneMap))), zap.String("Schedulable", fmt.Sprint(schedulablePods)))
 	return &State{FreeCap: free, SchedulablePods: schedulablePods, LastOrdinal: last, Capacity: s.capacity, Replicas: scale.Spec.Replicas, NumZones: int32(len(zoneMap)), NumNodes: int32(len(nodeToZoneMap)),
 		SchedulerPolicy: s.schedulerPolicy, SchedPolicy: s.schedPolicy, DeschedPolicy: s.deschedPolicy, NodeToZoneMap: nodeToZoneMap, StatefulSetName: s.statefulSetName, PodLister: s.podLister,
-		PodSpread: podSpread, NodeSpread: nodeSpread, ZoneSpread: zoneSpread}, nil
+		PodSpread: podSpread, NodeSpread: nodeSpread, ZoneSpread: zoneSpread, VReplicaCosts: vreplicaCosts, PodMetrics: GetPodMetricsSource(s.ctx), PendingCosts: pendingCosts}, nil
 }
 
 func (s *stateBuilder) updateFreeCapacity(free []int32, last int32, podName string, vreplicas int32) ([]int32, int32) {
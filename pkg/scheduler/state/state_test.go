@@ -244,6 +244,7 @@ func TestStateBuilder(t *testing.T) {
 						"zone-1": 0,
 					},
 				},
+				PendingCosts: map[string]int64{"statefulset-name-0": 1},
 			},
 			freec: int32(28),
 			reserved: map[types.NamespacedName]map[string]int32{
@@ -373,6 +374,7 @@ func TestStateBuilder(t *testing.T) {
 						"zone-1": 0,
 					},
 				},
+				PendingCosts: map[string]int64{"statefulset-name-4": 8},
 			},
 			freec: int32(28),
 			reserved: map[types.NamespacedName]map[string]int32{
@@ -521,6 +523,12 @@ func TestStateBuilder(t *testing.T) {
 			if tc.expected.NodeToZoneMap == nil {
 				tc.expected.NodeToZoneMap = make(map[string]string)
 			}
+			if tc.expected.VReplicaCosts == nil {
+				tc.expected.VReplicaCosts = make(map[types.NamespacedName]int64)
+				for i := range tc.vpods {
+					tc.expected.VReplicaCosts[types.NamespacedName{Name: fmt.Sprint(vpodName+"-", i), Namespace: fmt.Sprint(vpodNs+"-", i)}] = 1
+				}
+			}
 			if !reflect.DeepEqual(*state, tc.expected) {
 				t.Errorf("unexpected state, got %v, want %v", *state, tc.expected)
 			}
@@ -258,6 +258,14 @@ func (a *autoscaler) compact(s *st.State, scaleUpFactor int32) error {
 	return nil
 }
 
+// compactionLock returns the lock the autoscaler holds while compacting vreplicas.
+func compactionLock(a Autoscaler) sync.Locker {
+	if a, ok := a.(*autoscaler); ok {
+		return a.lock
+	}
+	return new(sync.Mutex)
+}
+
 func contains(preds []scheduler.PredicatePolicy, priors []scheduler.PriorityPolicy, name string) bool {
 	for _, v := range preds {
 		if v.Name == name {
@@ -0,0 +1,322 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statefulset
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/types"
+
+	"knative.dev/pkg/logging"
+
+	duckv1alpha1 "knative.dev/eventing/pkg/apis/duck/v1alpha1"
+	"knative.dev/eventing/pkg/scheduler"
+	"knative.dev/eventing/pkg/scheduler/factory"
+	st "knative.dev/eventing/pkg/scheduler/state"
+)
+
+// defaultZoneMaxSkew is the zone skew tolerated when the scheduling policy
+// does not configure AvailabilityZonePriority.
+const defaultZoneMaxSkew = int32(1)
+
+// defaultRebalancerMaxMoves is the maximum number of evictions per period of
+// the rebalancer started by NewScheduler, unless its policy sets MaxMoves.
+const defaultRebalancerMaxMoves = 10
+
+type Rebalancer interface {
+	// Start runs the rebalancer until cancelled.
+	Start(ctx context.Context)
+}
+
+// rebalancer moves vreplicas back toward an even spread across zones, for
+// instance when a zone comes back after a failure. It evicts the placement
+// selected by the descheduling priorities in the zone holding the most
+// vreplicas, and lets the scheduler place its vreplicas again.
+type rebalancer struct {
+	logger        *zap.SugaredLogger
+	vpodLister    scheduler.VPodLister
+	stateAccessor st.StateAccessor
+	evictor       scheduler.Evictor
+
+	// lock is held by the autoscaler while compacting vreplicas, so that
+	// both do not evict the vreplicas of the same vpods at the same time.
+	lock sync.Locker
+
+	// refreshPeriod is how often the rebalancer looks for skewed vpods.
+	refreshPeriod time.Duration
+
+	// maxMoves is the maximum number of evictions per refresh period.
+	maxMoves int
+
+	// dryRun only logs the evictions the rebalancer would do.
+	dryRun bool
+}
+
+// rebalanceMove is an eviction decided by the rebalancer.
+type rebalanceMove struct {
+	vpod      types.NamespacedName
+	placement duckv1alpha1.Placement
+	zone      string
+}
+
+func NewRebalancer(ctx context.Context,
+	lister scheduler.VPodLister,
+	stateAccessor st.StateAccessor,
+	evictor scheduler.Evictor,
+	lock sync.Locker,
+	refreshPeriod time.Duration,
+	maxMoves int,
+	dryRun bool) Rebalancer {
+
+	return &rebalancer{
+		logger:        logging.FromContext(ctx).Named("rebalancer"),
+		vpodLister:    lister,
+		stateAccessor: stateAccessor,
+		evictor:       evictor,
+		lock:          lock,
+		refreshPeriod: refreshPeriod,
+		maxMoves:      maxMoves,
+		dryRun:        dryRun,
+	}
+}
+
+func (r *rebalancer) Start(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.refreshPeriod):
+		}
+
+		if _, err := r.rebalance(ctx); err != nil {
+			r.logger.Infow("rebalancing failed (will retry)", zap.Error(err))
+		}
+	}
+}
+
+// rebalance evicts vreplicas of up to maxMoves skewed vpods, from at most one
+// placement per vpod since the scheduler places the vreplicas of a vpod again
+// after an eviction.
+func (r *rebalancer) rebalance(ctx context.Context) ([]rebalanceMove, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	state, err := r.stateAccessor.State(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Descheduling priorities are needed to select the placements to evict.
+	if state.DeschedPolicy == nil || state.NumZones < 2 {
+		return nil, nil
+	}
+
+	zones := r.schedulableZones(state)
+	if len(zones) < 2 {
+		return nil, nil
+	}
+
+	vpods, err := r.vpodLister()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(vpods, func(i, j int) bool {
+		return vpods[i].GetKey().String() < vpods[j].GetKey().String()
+	})
+
+	maxSkew := zoneMaxSkew(state.SchedPolicy)
+	moves := make([]rebalanceMove, 0)
+	for _, vpod := range vpods {
+		if len(moves) >= r.maxMoves {
+			r.logger.Infow("maximum number of moves reached, deferring to the next period", zap.Int("moves", len(moves)))
+			break
+		}
+
+		move, ok := r.selectMove(ctx, state, zones, vpod, maxSkew)
+		if !ok {
+			continue
+		}
+
+		logger := r.logger.With(zap.Any("key", move.vpod), zap.String("podName", move.placement.PodName),
+			zap.Int32("vreplicas", move.placement.VReplicas), zap.String("zone", move.zone),
+			zap.Any("zoneSpread", state.ZoneSpread[move.vpod]))
+
+		if r.dryRun {
+			logger.Info("would evict vreplicas to rebalance zones (dry run)")
+			moves = append(moves, move)
+			continue
+		}
+
+		pod, err := state.PodLister.Get(move.placement.PodName)
+		if err != nil {
+			logger.Infow("failed to get the pod to evict vreplicas from", zap.Error(err))
+			continue
+		}
+		if err := r.evictor(pod, vpod, &move.placement); err != nil {
+			return moves, err
+		}
+		logger.Info("evicted vreplicas to rebalance zones")
+		moves = append(moves, move)
+	}
+	return moves, nil
+}
+
+// schedulableZones returns the zones with schedulable pods, mapped to the
+// ordinals of these pods.
+func (r *rebalancer) schedulableZones(state *st.State) map[string][]int32 {
+	zones := make(map[string][]int32)
+	for _, podID := range state.SchedulablePods {
+		zoneName, _, err := state.GetPodInfo(st.PodNameFromOrdinal(state.StatefulSetName, podID))
+		if err != nil {
+			continue
+		}
+		zones[zoneName] = append(zones[zoneName], podID)
+	}
+	return zones
+}
+
+// selectMove returns the placement to evict from vpod, if its vreplicas are
+// spread across zones with a skew greater than maxSkew. Evictors remove the
+// whole placement of a vpod on a pod, so all its vreplicas are evicted and
+// the scheduler spreads them again across zones.
+func (r *rebalancer) selectMove(ctx context.Context, state *st.State, zones map[string][]int32, vpod scheduler.VPod, maxSkew int32) (rebalanceMove, bool) {
+	placements := vpod.GetPlacements()
+	if scheduler.GetTotalVReplicas(placements) != vpod.GetVReplicas() {
+		// Leave vpods being scheduled alone.
+		return rebalanceMove{}, false
+	}
+
+	zoneNames := make([]string, 0, len(zones))
+	for zoneName := range zones {
+		zoneNames = append(zoneNames, zoneName)
+	}
+	sort.Strings(zoneNames)
+
+	spread := state.ZoneSpread[vpod.GetKey()]
+	busiest, quietest := zoneNames[0], zoneNames[0]
+	for _, zoneName := range zoneNames[1:] {
+		if spread[zoneName] > spread[busiest] {
+			busiest = zoneName
+		}
+		if spread[zoneName] < spread[quietest] {
+			quietest = zoneName
+		}
+	}
+	if spread[busiest]-spread[quietest] <= maxSkew {
+		return rebalanceMove{}, false
+	}
+
+	candidates := make([]int32, 0)
+	for _, p := range placements {
+		ordinal := st.OrdinalFromPodName(p.PodName)
+		for _, podID := range zones[busiest] {
+			if podID == ordinal {
+				candidates = append(candidates, ordinal)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return rebalanceMove{}, false
+	}
+
+	scores, err := r.scorePods(ctx, state, vpod, candidates)
+	if err != nil {
+		r.logger.Infow("error while scoring pods using descheduling priorities", zap.Any("key", vpod.GetKey()), zap.Error(err))
+		return rebalanceMove{}, false
+	}
+
+	// Evict from the pod with the highest score, preferring higher ordinals
+	// on ties.
+	selected := scores[0]
+	for _, ps := range scores[1:] {
+		if ps.Score > selected.Score || (ps.Score == selected.Score && ps.ID > selected.ID) {
+			selected = ps
+		}
+	}
+
+	podName := st.PodNameFromOrdinal(state.StatefulSetName, selected.ID)
+	placement := *scheduler.GetPlacementForPod(placements, podName)
+	return rebalanceMove{
+		vpod:      vpod.GetKey(),
+		placement: placement,
+		zone:      busiest,
+	}, true
+}
+
+// scorePods runs the descheduling priorities on pods.
+func (r *rebalancer) scorePods(ctx context.Context, state *st.State, vpod scheduler.VPod, pods []int32) (st.PodScoreList, error) {
+	result := make(st.PodScoreList, len(pods))
+	for i, podID := range pods {
+		result[i] = st.PodScore{ID: podID}
+	}
+
+	for _, priority := range state.DeschedPolicy.Priorities {
+		pl, err := factory.GetScorePlugin(priority.Name)
+		if err != nil {
+			r.logger.Error("Could not find score plugin in registry: ", priority.Name)
+			continue
+		}
+
+		scores := make(st.PodScoreList, len(pods))
+		for i, podID := range pods {
+			score, status := pl.Score(ctx, priority.Args, state, pods, vpod.GetKey(), podID)
+			if !status.IsSuccess() {
+				return nil, status.AsError()
+			}
+			scores[i] = st.PodScore{ID: podID, Score: score * priority.Weight}
+		}
+
+		if ext := pl.ScoreExtensions(); ext != nil {
+			if status := ext.NormalizeScore(ctx, state, scores); !status.IsSuccess() {
+				return nil, status.AsError()
+			}
+		}
+		for i := range scores {
+			result[i].Score += scores[i].Score
+		}
+	}
+	return result, nil
+}
+
+// zoneMaxSkew returns the zone skew allowed by the AvailabilityZonePriority
+// scheduling priority.
+func zoneMaxSkew(policy *scheduler.SchedulerPolicy) int32 {
+	if policy == nil {
+		return defaultZoneMaxSkew
+	}
+	for _, priority := range policy.Priorities {
+		if priority.Name != st.AvailabilityZonePriority {
+			continue
+		}
+		args, ok := priority.Args.(string)
+		if !ok {
+			break
+		}
+		skewVal := st.AvailabilityZonePriorityArgs{}
+		if err := json.NewDecoder(strings.NewReader(args)).Decode(&skewVal); err != nil || skewVal.MaxSkew < 1 {
+			break
+		}
+		return skewVal.MaxSkew
+	}
+	return defaultZoneMaxSkew
+}
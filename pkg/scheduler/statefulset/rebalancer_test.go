@@ -0,0 +1,325 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statefulset
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	listers "knative.dev/eventing/pkg/reconciler/testing/v1"
+	kubeclient "knative.dev/pkg/client/injection/kube/client/fake"
+
+	duckv1alpha1 "knative.dev/eventing/pkg/apis/duck/v1alpha1"
+	"knative.dev/eventing/pkg/scheduler"
+	"knative.dev/eventing/pkg/scheduler/state"
+	tscheduler "knative.dev/eventing/pkg/scheduler/testing"
+)
+
+func TestRebalancer(t *testing.T) {
+	deschedulerPolicy := &scheduler.SchedulerPolicy{
+		Priorities: []scheduler.PriorityPolicy{
+			{Name: "RemoveWithAvailabilityZonePriority", Weight: 10, Args: "{\"MaxSkew\": 1}"},
+			{Name: "RemoveWithHighestOrdinalPriority", Weight: 2},
+		},
+	}
+
+	// pods are spread across zones by ordinal: pod-0 and pod-3 are in zone0,
+	// pod-1 and pod-4 in zone1, pod-2 and pod-5 in zone2.
+	skewed := func(name string) scheduler.VPod {
+		return tscheduler.NewVPod(testNs, name, 6, []duckv1alpha1.Placement{
+			{PodName: "statefulset-name-0", VReplicas: int32(2)},
+			{PodName: "statefulset-name-1", VReplicas: int32(2)},
+			{PodName: "statefulset-name-3", VReplicas: int32(2)}})
+	}
+
+	testCases := []struct {
+		name              string
+		replicas          int32
+		vpods             []scheduler.VPod
+		schedulerPolicy   *scheduler.SchedulerPolicy
+		deschedulerPolicy *scheduler.SchedulerPolicy
+		maxMoves          int
+		dryRun            bool
+		wantMoves         []rebalanceMove
+		wantEvictions     map[types.NamespacedName][]duckv1alpha1.Placement
+	}{
+		{
+			name:     "no descheduling policy",
+			replicas: int32(6),
+			vpods:    []scheduler.VPod{skewed("vpod-1")},
+			maxMoves: 10,
+		},
+		{
+			name:     "even spread",
+			replicas: int32(6),
+			vpods: []scheduler.VPod{
+				tscheduler.NewVPod(testNs, "vpod-1", 6, []duckv1alpha1.Placement{
+					{PodName: "statefulset-name-0", VReplicas: int32(2)},
+					{PodName: "statefulset-name-1", VReplicas: int32(2)},
+					{PodName: "statefulset-name-5", VReplicas: int32(2)}}),
+			},
+			deschedulerPolicy: deschedulerPolicy,
+			maxMoves:          10,
+		},
+		{
+			name:     "skew within the scheduling policy max skew",
+			replicas: int32(6),
+			vpods:    []scheduler.VPod{skewed("vpod-1")},
+			schedulerPolicy: &scheduler.SchedulerPolicy{
+				Priorities: []scheduler.PriorityPolicy{
+					{Name: "AvailabilityZonePriority", Weight: 10, Args: "{\"MaxSkew\": 4}"},
+				},
+			},
+			deschedulerPolicy: deschedulerPolicy,
+			maxMoves:          10,
+		},
+		{
+			name:              "vreplicas skewed after zone recovery",
+			replicas:          int32(6),
+			vpods:             []scheduler.VPod{skewed("vpod-1")},
+			deschedulerPolicy: deschedulerPolicy,
+			maxMoves:          10,
+			wantMoves: []rebalanceMove{
+				{vpod: types.NamespacedName{Namespace: testNs, Name: "vpod-1"}, placement: duckv1alpha1.Placement{PodName: "statefulset-name-3", VReplicas: int32(2)}, zone: "zone0"},
+			},
+			wantEvictions: map[types.NamespacedName][]duckv1alpha1.Placement{
+				{Name: "vpod-1", Namespace: testNs}: {{PodName: "statefulset-name-3", VReplicas: int32(2)}},
+			},
+		},
+		{
+			name:     "the whole placement is evicted",
+			replicas: int32(6),
+			vpods: []scheduler.VPod{
+				tscheduler.NewVPod(testNs, "vpod-1", 8, []duckv1alpha1.Placement{
+					{PodName: "statefulset-name-0", VReplicas: int32(5)},
+					{PodName: "statefulset-name-1", VReplicas: int32(2)},
+					{PodName: "statefulset-name-2", VReplicas: int32(1)}}),
+			},
+			deschedulerPolicy: deschedulerPolicy,
+			maxMoves:          10,
+			wantMoves: []rebalanceMove{
+				{vpod: types.NamespacedName{Namespace: testNs, Name: "vpod-1"}, placement: duckv1alpha1.Placement{PodName: "statefulset-name-0", VReplicas: int32(5)}, zone: "zone0"},
+			},
+			wantEvictions: map[types.NamespacedName][]duckv1alpha1.Placement{
+				{Name: "vpod-1", Namespace: testNs}: {{PodName: "statefulset-name-0", VReplicas: int32(5)}},
+			},
+		},
+		{
+			name:     "descheduling priorities select the placement",
+			replicas: int32(6),
+			vpods:    []scheduler.VPod{skewed("vpod-1")},
+			deschedulerPolicy: &scheduler.SchedulerPolicy{
+				Priorities: []scheduler.PriorityPolicy{
+					{Name: "LowestOrdinalPriority", Weight: 2},
+				},
+			},
+			maxMoves: 10,
+			wantMoves: []rebalanceMove{
+				{vpod: types.NamespacedName{Namespace: testNs, Name: "vpod-1"}, placement: duckv1alpha1.Placement{PodName: "statefulset-name-0", VReplicas: int32(2)}, zone: "zone0"},
+			},
+			wantEvictions: map[types.NamespacedName][]duckv1alpha1.Placement{
+				{Name: "vpod-1", Namespace: testNs}: {{PodName: "statefulset-name-0", VReplicas: int32(2)}},
+			},
+		},
+		{
+			name:              "rate limited",
+			replicas:          int32(6),
+			vpods:             []scheduler.VPod{skewed("vpod-2"), skewed("vpod-1")},
+			deschedulerPolicy: deschedulerPolicy,
+			maxMoves:          1,
+			wantMoves: []rebalanceMove{
+				{vpod: types.NamespacedName{Namespace: testNs, Name: "vpod-1"}, placement: duckv1alpha1.Placement{PodName: "statefulset-name-3", VReplicas: int32(2)}, zone: "zone0"},
+			},
+			wantEvictions: map[types.NamespacedName][]duckv1alpha1.Placement{
+				{Name: "vpod-1", Namespace: testNs}: {{PodName: "statefulset-name-3", VReplicas: int32(2)}},
+			},
+		},
+		{
+			name:              "dry run",
+			replicas:          int32(6),
+			vpods:             []scheduler.VPod{skewed("vpod-1")},
+			deschedulerPolicy: deschedulerPolicy,
+			maxMoves:          10,
+			dryRun:            true,
+			wantMoves: []rebalanceMove{
+				{vpod: types.NamespacedName{Namespace: testNs, Name: "vpod-1"}, placement: duckv1alpha1.Placement{PodName: "statefulset-name-3", VReplicas: int32(2)}, zone: "zone0"},
+			},
+		},
+		{
+			name:     "vpod being scheduled",
+			replicas: int32(6),
+			vpods: []scheduler.VPod{
+				tscheduler.NewVPod(testNs, "vpod-1", 8, []duckv1alpha1.Placement{
+					{PodName: "statefulset-name-0", VReplicas: int32(2)},
+					{PodName: "statefulset-name-1", VReplicas: int32(2)},
+					{PodName: "statefulset-name-3", VReplicas: int32(2)}}),
+			},
+			deschedulerPolicy: deschedulerPolicy,
+			maxMoves:          10,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, _ := tscheduler.SetupFakeContext(t)
+
+			nodelist := make([]runtime.Object, 0, numNodes)
+			podlist := make([]runtime.Object, 0, tc.replicas)
+			vpodClient := tscheduler.NewVPodClient()
+
+			for i := int32(0); i < numZones; i++ {
+				for j := int32(0); j < numNodes/numZones; j++ {
+					nodeName := "node" + fmt.Sprint((j*((numNodes/numZones)+1))+i)
+					zoneName := "zone" + fmt.Sprint(i)
+					node, err := kubeclient.Get(ctx).CoreV1().Nodes().Create(ctx, tscheduler.MakeNode(nodeName, zoneName), metav1.CreateOptions{})
+					if err != nil {
+						t.Fatal("unexpected error", err)
+					}
+					nodelist = append(nodelist, node)
+				}
+			}
+			for i := int32(0); i < tc.replicas; i++ {
+				nodeName := "node" + fmt.Sprint(i)
+				podName := sfsName + "-" + fmt.Sprint(i)
+				pod, err := kubeclient.Get(ctx).CoreV1().Pods(testNs).Create(ctx, tscheduler.MakePod(testNs, podName, nodeName), metav1.CreateOptions{})
+				if err != nil {
+					t.Fatal("unexpected error", err)
+				}
+				podlist = append(podlist, pod)
+			}
+
+			_, err := kubeclient.Get(ctx).AppsV1().StatefulSets(testNs).Create(ctx, tscheduler.MakeStatefulset(testNs, sfsName, tc.replicas), metav1.CreateOptions{})
+			if err != nil {
+				t.Fatal("unexpected error", err)
+			}
+
+			lsp := listers.NewListers(podlist)
+			lsn := listers.NewListers(nodelist)
+			stateAccessor := state.NewStateBuilder(ctx, testNs, sfsName, vpodClient.List, 10, "", tc.schedulerPolicy, tc.deschedulerPolicy, lsp.GetPodLister().Pods(testNs), lsn.GetNodeLister())
+
+			evictions := make(map[types.NamespacedName][]duckv1alpha1.Placement)
+			recordEviction := func(pod *corev1.Pod, vpod scheduler.VPod, from *duckv1alpha1.Placement) error {
+				evictions[vpod.GetKey()] = append(evictions[vpod.GetKey()], *from)
+				return nil
+			}
+
+			rebalancer := NewRebalancer(ctx, vpodClient.List, stateAccessor, recordEviction, new(sync.Mutex), 10*time.Second, tc.maxMoves, tc.dryRun).(*rebalancer)
+
+			for _, vpod := range tc.vpods {
+				vpodClient.Append(vpod)
+			}
+
+			moves, err := rebalancer.rebalance(ctx)
+			if err != nil {
+				t.Fatal("unexpected error", err)
+			}
+
+			if len(tc.wantMoves) == 0 && len(moves) != 0 {
+				t.Fatalf("unexpected moves: %v", moves)
+			}
+			if len(tc.wantMoves) != 0 && !reflect.DeepEqual(tc.wantMoves, moves) {
+				t.Fatalf("expected moves to be %v, but got %v", tc.wantMoves, moves)
+			}
+
+			if len(tc.wantEvictions) == 0 && len(evictions) != 0 {
+				t.Fatalf("unexpected evictions: %v", evictions)
+			}
+			if len(tc.wantEvictions) != 0 && !reflect.DeepEqual(tc.wantEvictions, evictions) {
+				t.Fatalf("expected evictions to be %v, but got %v", tc.wantEvictions, evictions)
+			}
+		})
+	}
+}
+
+func TestPolicyRebalancer(t *testing.T) {
+	zonePriority := scheduler.PriorityPolicy{Name: "AvailabilityZonePriority", Weight: 10, Args: "{\"MaxSkew\": 1}"}
+
+	testCases := []struct {
+		name         string
+		policy       *scheduler.SchedulerPolicy
+		wantEnabled  bool
+		wantPeriod   time.Duration
+		wantMaxMoves int
+		wantDryRun   bool
+	}{
+		{
+			name: "no policy",
+		},
+		{
+			name:   "not enabled",
+			policy: &scheduler.SchedulerPolicy{Priorities: []scheduler.PriorityPolicy{zonePriority}},
+		},
+		{
+			name: "no AvailabilityZonePriority",
+			policy: &scheduler.SchedulerPolicy{
+				Priorities: []scheduler.PriorityPolicy{{Name: "LowestOrdinalPriority", Weight: 2}},
+				Rebalancer: &scheduler.RebalancerPolicy{},
+			},
+		},
+		{
+			name: "defaults",
+			policy: &scheduler.SchedulerPolicy{
+				Priorities: []scheduler.PriorityPolicy{zonePriority},
+				Rebalancer: &scheduler.RebalancerPolicy{},
+			},
+			wantEnabled:  true,
+			wantPeriod:   10 * time.Second,
+			wantMaxMoves: defaultRebalancerMaxMoves,
+		},
+		{
+			name: "configured",
+			policy: &scheduler.SchedulerPolicy{
+				Priorities: []scheduler.PriorityPolicy{zonePriority},
+				Rebalancer: &scheduler.RebalancerPolicy{DryRun: true, MaxMoves: 3, Period: time.Minute},
+			},
+			wantEnabled:  true,
+			wantPeriod:   time.Minute,
+			wantMaxMoves: 3,
+			wantDryRun:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, _ := tscheduler.SetupFakeContext(t)
+
+			r := newPolicyRebalancer(ctx, tc.policy, nil, nil, nil, new(sync.Mutex), 10*time.Second)
+			if !tc.wantEnabled {
+				if r != nil {
+					t.Fatal("expected no rebalancer, got", r)
+				}
+				return
+			}
+			if r == nil {
+				t.Fatal("expected a rebalancer")
+			}
+			got := r.(*rebalancer)
+			if got.refreshPeriod != tc.wantPeriod || got.maxMoves != tc.wantMaxMoves || got.dryRun != tc.wantDryRun {
+				t.Errorf("got period %v, maxMoves %d, dryRun %v, want %v, %d, %v",
+					got.refreshPeriod, got.maxMoves, got.dryRun, tc.wantPeriod, tc.wantMaxMoves, tc.wantDryRun)
+			}
+		})
+	}
+}
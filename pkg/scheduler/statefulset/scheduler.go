@@ -53,6 +53,8 @@ import (
 	_ "knative.dev/eventing/pkg/scheduler/plugins/core/removewithavailabilityzonepriority"
 	_ "knative.dev/eventing/pkg/scheduler/plugins/core/removewithevenpodspreadpriority"
 	_ "knative.dev/eventing/pkg/scheduler/plugins/core/removewithhighestordinalpriority"
+	_ "knative.dev/eventing/pkg/scheduler/plugins/core/removewithresourceusage"
+	_ "knative.dev/eventing/pkg/scheduler/plugins/core/resourceusage"
 	_ "knative.dev/eventing/pkg/scheduler/plugins/kafka/nomaxresourcecount"
 )
 
@@ -71,14 +73,60 @@ func NewScheduler(ctx context.Context,
 	podInformer := podinformer.Get(ctx)
 	podLister := podInformer.Lister().Pods(namespace)
 
+	if usesPlugin(deschedPolicy, st.ResourceUsage) {
+		logging.FromContext(ctx).Warnf("%s favors removing vreplicas from the least loaded pods, use %s for descheduling",
+			st.ResourceUsage, st.RemoveWithResourceUsage)
+	}
+
+	// The ResourceUsage and RemoveWithResourceUsage plugins account for the
+	// resource usage observed by the metrics.k8s.io API, unless another
+	// source is provided.
+	if st.GetPodMetricsSource(ctx) == nil && (usesPlugin(schedPolicy, st.ResourceUsage) || usesPlugin(deschedPolicy, st.RemoveWithResourceUsage)) {
+		ctx = st.WithPodMetricsSource(ctx, st.NewPodMetricsSource(ctx, namespace, refreshPeriod))
+	}
+
 	stateAccessor := st.NewStateBuilder(ctx, namespace, name, lister, capacity, schedulerPolicy, schedPolicy, deschedPolicy, podLister, nodeLister)
 	autoscaler := NewAutoscaler(ctx, namespace, name, lister, stateAccessor, evictor, refreshPeriod, capacity)
 
 	go autoscaler.Start(ctx)
 
+	if rebalancer := newPolicyRebalancer(ctx, schedPolicy, lister, stateAccessor, evictor, compactionLock(autoscaler), refreshPeriod); rebalancer != nil {
+		go rebalancer.Start(ctx)
+	}
+
 	return NewStatefulSetScheduler(ctx, namespace, name, lister, stateAccessor, autoscaler, podLister)
 }
 
+// newPolicyRebalancer returns the rebalancer configured by the scheduling
+// policy, or nil when the policy does not enable it. The rebalancer restores
+// the zone spread enforced by AvailabilityZonePriority, so it is only enabled
+// along with this priority.
+func newPolicyRebalancer(ctx context.Context,
+	policy *scheduler.SchedulerPolicy,
+	lister scheduler.VPodLister,
+	stateAccessor st.StateAccessor,
+	evictor scheduler.Evictor,
+	lock sync.Locker,
+	refreshPeriod time.Duration) Rebalancer {
+
+	if policy == nil || policy.Rebalancer == nil || !usesPlugin(policy, st.AvailabilityZonePriority) {
+		return nil
+	}
+	period := refreshPeriod
+	if policy.Rebalancer.Period > 0 {
+		period = policy.Rebalancer.Period
+	}
+	maxMoves := defaultRebalancerMaxMoves
+	if policy.Rebalancer.MaxMoves > 0 {
+		maxMoves = policy.Rebalancer.MaxMoves
+	}
+	return NewRebalancer(ctx, lister, stateAccessor, evictor, lock, period, maxMoves, policy.Rebalancer.DryRun)
+}
+
+func usesPlugin(policy *scheduler.SchedulerPolicy, name string) bool {
+	return policy != nil && contains(policy.Predicates, policy.Priorities, name)
+}
+
 // StatefulSetScheduler is a scheduler placing VPod into statefulset-managed set of pods
 type StatefulSetScheduler struct {
 	ctx               context.Context
@@ -570,6 +618,9 @@ func (s *StatefulSetScheduler) RunScorePlugins(ctx context.Context, states *st.S
 			}
 		}
 
+		if pl.ScoreExtensions() == nil {
+			continue
+		}
 		status := pl.ScoreExtensions().NormalizeScore(ctx, states, pluginToPodScores[pl.Name()]) //NORMALIZE SCORES FOR ALL FEASIBLE PODS
 		if !status.IsSuccess() {
 			errStatus := st.NewStatus(st.Error, fmt.Sprintf("running %q scoring plugin failed with: %v", pl.Name(), status.AsError()))
@@ -17,11 +17,13 @@ limitations under the License.
 package statefulset
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"testing"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -743,3 +745,109 @@ func TestStatefulsetScheduler(t *testing.T) {
 		})
 	}
 }
+
+type podMetrics map[string]state.PodUsage
+
+func (m podMetrics) PodUsage(ctx context.Context, podName string) (state.PodUsage, bool) {
+	usage, ok := m[podName]
+	return usage, ok
+}
+
+func TestStatefulsetSchedulerResourceUsage(t *testing.T) {
+	schedulerPolicy := &scheduler.SchedulerPolicy{
+		Predicates: []scheduler.PredicatePolicy{
+			{Name: "PodFitsResources"},
+			{Name: "ResourceUsage", Args: "{\"MaxCost\": 10, \"CPUPerCost\": \"100m\"}"},
+		},
+		Priorities: []scheduler.PriorityPolicy{
+			{Name: "ResourceUsage", Weight: 1, Args: "{\"CPUPerCost\": \"100m\"}"},
+		},
+	}
+
+	testCases := []struct {
+		name      string
+		vreplicas int32
+		metrics   podMetrics
+		expected  []duckv1alpha1.Placement
+		err       error
+	}{
+		{
+			name:      "avoid pod with hot source",
+			vreplicas: 2,
+			expected:  []duckv1alpha1.Placement{{PodName: "statefulset-name-1", VReplicas: 2}},
+		},
+		{
+			name:      "avoid pod with high observed usage",
+			vreplicas: 2,
+			metrics: podMetrics{
+				"statefulset-name-1": {CPU: resource.MustParse("800m")},
+			},
+			expected: []duckv1alpha1.Placement{{PodName: "statefulset-name-0", VReplicas: 2}},
+		},
+		{
+			name:      "pods at full load",
+			vreplicas: 6,
+			metrics: podMetrics{
+				"statefulset-name-1": {CPU: resource.MustParse("1")},
+			},
+			expected: []duckv1alpha1.Placement{{PodName: "statefulset-name-0", VReplicas: 5}},
+			err:      scheduler.ErrNotEnoughReplicas,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, _ := tscheduler.SetupFakeContext(t)
+			if tc.metrics != nil {
+				ctx = state.WithPodMetricsSource(ctx, tc.metrics)
+			}
+			replicas := int32(2)
+			nodelist := make([]runtime.Object, 0, replicas)
+			podlist := make([]runtime.Object, 0, replicas)
+			vpodClient := tscheduler.NewVPodClient()
+
+			for i := int32(0); i < replicas; i++ {
+				nodeName := "node" + fmt.Sprint(i)
+				node, err := kubeclient.Get(ctx).CoreV1().Nodes().Create(ctx, tscheduler.MakeNode(nodeName, "zone"+fmt.Sprint(i)), metav1.CreateOptions{})
+				if err != nil {
+					t.Fatal("unexpected error", err)
+				}
+				nodelist = append(nodelist, node)
+
+				pod, err := kubeclient.Get(ctx).CoreV1().Pods(testNs).Create(ctx, tscheduler.MakePod(testNs, sfsName+"-"+fmt.Sprint(i), nodeName), metav1.CreateOptions{})
+				if err != nil {
+					t.Fatal("unexpected error", err)
+				}
+				podlist = append(podlist, pod)
+			}
+
+			_, err := kubeclient.Get(ctx).AppsV1().StatefulSets(testNs).Create(ctx, tscheduler.MakeStatefulset(testNs, sfsName, replicas), metav1.CreateOptions{})
+			if err != nil {
+				t.Fatal("unexpected error", err)
+			}
+
+			// A hot source with a single vreplica costing 5 on the first pod.
+			vpodClient.Append(tscheduler.NewVPodWithAnnotations(vpodNamespace, "hot-source", 1,
+				[]duckv1alpha1.Placement{{PodName: "statefulset-name-0", VReplicas: 1}},
+				map[string]string{scheduler.VReplicaCostAnnotationKey: "5"}))
+
+			lsp := listers.NewListers(podlist)
+			lsn := listers.NewListers(nodelist)
+			sa := state.NewStateBuilder(ctx, testNs, sfsName, vpodClient.List, 10, "", schedulerPolicy, nil, lsp.GetPodLister().Pods(testNs), lsn.GetNodeLister())
+			s := NewStatefulSetScheduler(ctx, testNs, sfsName, vpodClient.List, sa, nil, lsp.GetPodLister().Pods(testNs)).(*StatefulSetScheduler)
+
+			// Give some time for the informer to notify the scheduler and set the number of replicas
+			time.Sleep(200 * time.Millisecond)
+
+			vpod := vpodClient.Create(vpodNamespace, vpodName, tc.vreplicas, nil)
+			placements, err := s.Schedule(vpod)
+			if err != tc.err {
+				t.Fatalf("got error %v, want %v", err, tc.err)
+			}
+
+			if !reflect.DeepEqual(placements, tc.expected) {
+				t.Errorf("got %v, want %v", placements, tc.expected)
+			}
+		})
+	}
+}
@@ -43,6 +43,7 @@ type sampleVPod struct {
 	vreplicas   int32
 	placements  []duckv1alpha1.Placement
 	rsrcversion string
+	annotations map[string]string
 }
 
 func NewVPod(ns, name string, vreplicas int32, placements []duckv1alpha1.Placement) *sampleVPod {
@@ -57,6 +58,12 @@ func NewVPod(ns, name string, vreplicas int32, placements []duckv1alpha1.Placeme
 	}
 }
 
+func NewVPodWithAnnotations(ns, name string, vreplicas int32, placements []duckv1alpha1.Placement, annotations map[string]string) *sampleVPod {
+	vpod := NewVPod(ns, name, vreplicas, placements)
+	vpod.annotations = annotations
+	return vpod
+}
+
 func (d *sampleVPod) GetKey() types.NamespacedName {
 	return d.key
 }
@@ -73,6 +80,10 @@ func (d *sampleVPod) GetResourceVersion() string {
 	return d.rsrcversion
 }
 
+func (d *sampleVPod) GetAnnotations() map[string]string {
+	return d.annotations
+}
+
 func MakeNode(name, zonename string) *v1.Node {
 	obj := &v1.Node{
 		ObjectMeta: metav1.ObjectMeta{